@@ -0,0 +1,5 @@
+package v1alpha1
+
+// Hub marks this type as the conversion hub for MCPServer.
+// v1alpha1 is the storage version, so other API versions convert to and from it.
+func (*MCPServer) Hub() {}
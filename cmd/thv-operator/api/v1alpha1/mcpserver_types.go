@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Condition types for MCPServer
@@ -15,6 +16,15 @@ const (
 
 	// ConditionPodTemplateValid indicates whether the PodTemplateSpec is valid
 	ConditionPodTemplateValid = "PodTemplateValid"
+
+	// ConditionTransportConfigValid indicates whether the transport matches the port configuration
+	ConditionTransportConfigValid = "TransportConfigValid"
+
+	// ConditionVaultConfigValid indicates whether the Vault Agent Injection configuration is valid
+	ConditionVaultConfigValid = "VaultConfigValid"
+
+	// ConditionSecretsValid indicates whether the values of the Kubernetes secrets meet their constraints
+	ConditionSecretsValid = "SecretsValid"
 )
 
 const (
@@ -47,12 +57,42 @@ const (
 	ConditionReasonPodTemplateInvalid = "InvalidPodTemplateSpec"
 )
 
+const (
+	// ConditionReasonTransportConfigValid indicates the transport and port configuration are consistent
+	ConditionReasonTransportConfigValid = "ValidTransportConfig"
+
+	// ConditionReasonTransportConfigInvalid indicates the transport and port configuration conflict
+	ConditionReasonTransportConfigInvalid = "InvalidTransportConfig"
+)
+
+const (
+	// ConditionReasonVaultConfigValid indicates the Vault Agent annotations and vault secrets are well-formed
+	ConditionReasonVaultConfigValid = "ValidVaultConfig"
+
+	// ConditionReasonVaultConfigInvalid indicates the Vault Agent annotations or vault secrets are malformed
+	ConditionReasonVaultConfigInvalid = "InvalidVaultConfig"
+)
+
+const (
+	// ConditionReasonSecretsValid indicates the secret values meet their length and pattern constraints
+	ConditionReasonSecretsValid = "ValidSecrets"
+
+	// ConditionReasonSecretsInvalid indicates a secret value does not meet its length or pattern constraints
+	ConditionReasonSecretsInvalid = "InvalidSecrets"
+)
+
 // MCPServerSpec defines the desired state of MCPServer
 type MCPServerSpec struct {
 	// Image is the container image for the MCP server
 	// +kubebuilder:validation:Required
 	Image string `json:"image"`
 
+	// ImagePullPolicy is the pull policy for the MCP server container image.
+	// Defaults to Always for images tagged :latest (or untagged) and IfNotPresent otherwise.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
 	// Transport is the transport method for the MCP server (stdio, streamable-http or sse)
 	// +kubebuilder:validation:Enum=stdio;streamable-http;sse
 	// +kubebuilder:default=stdio
@@ -95,6 +135,17 @@ type MCPServerSpec struct {
 	// +optional
 	Args []string `json:"args,omitempty"`
 
+	// ExtraArgs are additional arguments appended to the arguments of the MCP server
+	// container, after Args or the EntrypointOverride arguments. Flags already set by
+	// those arguments are rejected.
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+
+	// EntrypointOverride replaces the command and arguments of the MCP server container.
+	// When set, Args are not passed to the MCP server and the override arguments are used instead.
+	// +optional
+	EntrypointOverride *EntrypointOverride `json:"entrypointOverride,omitempty"`
+
 	// Env are environment variables to set in the MCP server container
 	// +optional
 	Env []EnvVar `json:"env,omitempty"`
@@ -111,6 +162,17 @@ type MCPServerSpec struct {
 	// +optional
 	Secrets []SecretRef `json:"secrets,omitempty"`
 
+	// RestartOnSecretChange enables rolling the MCP server pods when the data of any
+	// referenced secret changes. A checksum of the secrets is stamped on the pod template.
+	// +kubebuilder:default=false
+	// +optional
+	RestartOnSecretChange bool `json:"restartOnSecretChange,omitempty"`
+
+	// VaultAgent configures the Vault Agent injected into the proxy deployment pods to render the vault secrets.
+	// It only applies when vault secrets are referenced.
+	// +optional
+	VaultAgent *VaultAgentConfig `json:"vaultAgent,omitempty"`
+
 	// ServiceAccount is the name of an already existing service account to use by the MCP server.
 	// If not specified, a ServiceAccount will be created automatically and used by the MCP server.
 	// +optional
@@ -134,6 +196,38 @@ type MCPServerSpec struct {
 	// +optional
 	ResourceOverrides *ResourceOverrides `json:"resourceOverrides,omitempty"`
 
+	// CommonLabels are labels added to every resource the operator creates for this MCPServer,
+	// including the pod template of the proxy deployment. Labels managed by the operator take
+	// precedence, and labels set through ResourceOverrides win over common labels.
+	// +optional
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonAnnotations are annotations added to every resource the operator creates for this MCPServer,
+	// including the pod template of the proxy deployment. Annotations set through ResourceOverrides
+	// win over common annotations.
+	// +optional
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget to create for the proxy deployment.
+	// If not specified, no PodDisruptionBudget is created and any existing one is removed.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+
+	// Autoscaling defines the HorizontalPodAutoscaler to create for the proxy deployment.
+	// If not specified, autoscaling is disabled and any existing HorizontalPodAutoscaler is removed.
+	// +optional
+	Autoscaling *AutoscalingConfig `json:"autoscaling,omitempty"`
+
+	// Strategy defines how the proxy deployment replaces existing pods with new ones.
+	// If not specified, a RollingUpdate strategy with maxSurge and maxUnavailable of 25% is used.
+	// +optional
+	Strategy *DeploymentStrategy `json:"strategy,omitempty"`
+
+	// Ingress defines the Ingress to create for exposing the proxy service outside the cluster.
+	// If not specified, no Ingress is created and any existing one is removed.
+	// +optional
+	Ingress *IngressConfig `json:"ingress,omitempty"`
+
 	// OIDCConfig defines OIDC authentication configuration for the MCP server
 	// +optional
 	OIDCConfig *OIDCConfigRef `json:"oidcConfig,omitempty"`
@@ -216,6 +310,101 @@ type ResourceMetadataOverrides struct {
 	Labels map[string]string `json:"labels,omitempty"`
 }
 
+// PodDisruptionBudgetConfig defines the PodDisruptionBudget settings for the proxy deployment.
+// Exactly one of MinAvailable or MaxUnavailable must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.minAvailable) != has(self.maxUnavailable)",message="exactly one of minAvailable or maxUnavailable must be set"
+type PodDisruptionBudgetConfig struct {
+	// MinAvailable is the number or percentage of pods that must remain available during a disruption
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods that can be unavailable during a disruption
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// DeploymentStrategy defines the rollout strategy for the proxy deployment
+// +kubebuilder:validation:XValidation:rule="self.type != 'Recreate' || !has(self.rollingUpdate)",message="rollingUpdate must not be set when type is Recreate"
+type DeploymentStrategy struct {
+	// Type is the deployment strategy type (RollingUpdate or Recreate).
+	// Use Recreate for stateful MCP servers that must not run two instances at the same time.
+	// +kubebuilder:validation:Enum=RollingUpdate;Recreate
+	// +kubebuilder:default=RollingUpdate
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// RollingUpdate configures the rolling update parameters when Type is RollingUpdate
+	// +optional
+	RollingUpdate *RollingUpdateConfig `json:"rollingUpdate,omitempty"`
+}
+
+// RollingUpdateConfig defines the rolling update parameters for the proxy deployment
+type RollingUpdateConfig struct {
+	// MaxSurge is the number or percentage of pods that can be created above the desired replicas
+	// during an update. Defaults to 25%.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods that can be unavailable during an update.
+	// Defaults to 25%.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AutoscalingConfig defines the HorizontalPodAutoscaler settings for the proxy deployment
+type AutoscalingConfig struct {
+	// MinReplicas is the lower limit for the number of replicas
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Required
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the target average CPU utilization across all pods,
+	// expressed as a percentage of the requested CPU
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=80
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
+
+// IngressConfig defines the Ingress settings for exposing the proxy service
+type IngressConfig struct {
+	// Host is the fully qualified domain name the Ingress serves
+	// +kubebuilder:validation:Required
+	Host string `json:"host"`
+
+	// IngressClassName is the name of the IngressClass to use
+	// If not specified, the cluster default IngressClass is used
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Annotations to add to the Ingress, typically used to configure the ingress controller
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// TLSSecretName is the name of the secret holding the TLS certificate for Host
+	// If not specified, the Ingress serves plain HTTP
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+}
+
+// EntrypointOverride replaces the command and arguments of the MCP server container
+type EntrypointOverride struct {
+	// Command replaces the entrypoint of the MCP server container image
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args replace the arguments passed to the MCP server container
+	// +optional
+	Args []string `json:"args,omitempty"`
+}
+
 // EnvVar represents an environment variable in a container
 type EnvVar struct {
 	// Name of the environment variable
@@ -269,6 +458,28 @@ type ResourceList struct {
 	Memory string `json:"memory,omitempty"`
 }
 
+// VaultAgentConfig configures the Vault Agent sidecar rendering the vault secrets
+type VaultAgentConfig struct {
+	// Resources defines the resource requirements of the Vault Agent containers.
+	// Unset values keep the defaults of the Vault Agent injector.
+	// +optional
+	Resources ResourceRequirements `json:"resources,omitempty"`
+
+	// TLSCACert references the Kubernetes Secret and key holding the CA certificate the Vault Agent
+	// verifies the Vault server with. The secret is mounted into the Vault Agent containers at /vault/tls.
+	// +optional
+	TLSCACert *SecretKeyRef `json:"tlsCACert,omitempty"`
+
+	// TLSSkipVerify disables the verification of the Vault server certificate. Not recommended in production.
+	// +optional
+	TLSSkipVerify bool `json:"tlsSkipVerify,omitempty"`
+
+	// TLSServerName is the name the Vault server certificate is verified against, when it differs
+	// from the host of the Vault address
+	// +optional
+	TLSServerName string `json:"tlsServerName,omitempty"`
+}
+
 // SecretRef is a reference to a secret
 type SecretRef struct {
 	// Name is the name of the secret
@@ -283,6 +494,61 @@ type SecretRef struct {
 	// If left unspecified, it defaults to the key
 	// +optional
 	TargetEnvName string `json:"targetEnvName,omitempty"`
+
+	// Default is the value to use when the secret or its key does not exist.
+	// Kubernetes cannot fall back to a value for a secret reference, so the secret is made optional
+	// and the default is set in a separate environment variable named after the target one with
+	// a _DEFAULT suffix. If left unspecified, the secret is required.
+	// +optional
+	Default *string `json:"default,omitempty"`
+
+	// Type is where the secret is stored.
+	// A kubernetes secret is read from the Kubernetes Secret with the given name.
+	// A vault secret is rendered by the Vault Agent, in which case Name is the path of the
+	// secret in a KV version 2 engine and Key is the field within the secret data.
+	// Vault secrets require Vault Agent Injection on the proxy deployment pod template.
+	// +kubebuilder:validation:Enum=kubernetes;vault
+	// +kubebuilder:default=kubernetes
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Command is run by the Vault Agent each time it renders the secret, e.g. to make a process
+	// reload it. Only supported for vault secrets.
+	// +optional
+	Command string `json:"command,omitempty"`
+
+	// Role is the Vault role the Vault Agent authenticates as to read the secret. The Vault Agent uses
+	// a single role, so all the vault secrets declaring a role must declare the same one. It is used when
+	// the proxy deployment pod template does not set the vault.hashicorp.com/role annotation, and must
+	// match it otherwise. Only supported for vault secrets.
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// MinLength is the minimum length, in characters, of the secret value. The value is checked at
+	// reconcile time to catch misconfigured secrets early. Only supported for kubernetes secrets.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinLength int32 `json:"minLength,omitempty"`
+
+	// Pattern is a regular expression, in RE2 syntax, the secret value must match. It is not anchored,
+	// use ^ and $ to match the whole value. The value is checked at reconcile time to catch misconfigured
+	// secrets early. Only supported for kubernetes secrets.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// Secret reference types
+const (
+	// SecretRefTypeKubernetes is the type for secrets stored in Kubernetes Secrets
+	SecretRefTypeKubernetes = "kubernetes"
+
+	// SecretRefTypeVault is the type for secrets rendered by the Vault Agent
+	SecretRefTypeVault = "vault"
+)
+
+// IsVault returns true if the secret is rendered by the Vault Agent
+func (s SecretRef) IsVault() bool {
+	return s.Type == SecretRefTypeVault
 }
 
 // Permission profile types
@@ -600,10 +866,11 @@ type TelemetryConfig struct {
 
 // OpenTelemetryConfig defines pure OpenTelemetry configuration
 type OpenTelemetryConfig struct {
-	// Enabled controls whether OpenTelemetry is enabled
-	// +kubebuilder:default=false
+	// Enabled controls whether OpenTelemetry is enabled, it defaults to true when not set
+	// When false, no OpenTelemetry configuration is passed to the proxy regardless of the other fields,
+	// which allows disabling telemetry without removing its configuration
 	// +optional
-	Enabled bool `json:"enabled,omitempty"`
+	Enabled *bool `json:"enabled,omitempty"`
 
 	// Endpoint is the OTLP endpoint URL for tracing and metrics
 	// +optional
@@ -624,6 +891,13 @@ type OpenTelemetryConfig struct {
 	// +optional
 	Insecure bool `json:"insecure,omitempty"`
 
+	// EnvironmentVariables are the names of the environment variables of the proxy container included
+	// in the telemetry spans as attributes. A name ending with *, e.g. APP_*, is a prefix pattern which is
+	// expanded against the environment variables declared in resourceOverrides.proxyDeployment.env only,
+	// the environment variables set by other means must be listed by name.
+	// +optional
+	EnvironmentVariables []string `json:"environmentVariables,omitempty"`
+
 	// Metrics defines OpenTelemetry metrics-specific configuration
 	// +optional
 	Metrics *OpenTelemetryMetricsConfig `json:"metrics,omitempty"`
@@ -631,6 +905,29 @@ type OpenTelemetryConfig struct {
 	// Tracing defines OpenTelemetry tracing configuration
 	// +optional
 	Tracing *OpenTelemetryTracingConfig `json:"tracing,omitempty"`
+
+	// ConfigMapRef references a ConfigMap containing a shared OpenTelemetry configuration
+	// The fields set inline override those of the ConfigMap, and the boolean fields are enabled if enabled in either
+	// +optional
+	ConfigMapRef *ConfigMapOpenTelemetryRef `json:"configMapRef,omitempty"`
+}
+
+// IsEnabled returns whether OpenTelemetry is enabled, which is the case unless Enabled is explicitly false
+func (c *OpenTelemetryConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// ConfigMapOpenTelemetryRef references a ConfigMap containing OpenTelemetry configuration
+type ConfigMapOpenTelemetryRef struct {
+	// Name is the name of the ConfigMap
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Key is the key in the ConfigMap that contains the OpenTelemetry configuration,
+	// in the YAML or JSON format of the openTelemetry field
+	// +kubebuilder:default=opentelemetry.json
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
 // PrometheusConfig defines Prometheus-specific configuration
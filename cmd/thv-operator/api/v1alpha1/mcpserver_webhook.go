@@ -0,0 +1,267 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// vaultAgentInjectAnnotation enables Vault Agent Injection on a pod
+	vaultAgentInjectAnnotation = "vault.hashicorp.com/agent-inject"
+	// vaultRoleAnnotation is the Vault role the injected agent authenticates as
+	vaultRoleAnnotation = "vault.hashicorp.com/role"
+)
+
+// DefaultImagePullPolicy mirrors the Kubernetes defaulting rules: images tagged :latest
+// (or without a tag) are always pulled, pinned tags and digests are pulled if not present.
+func DefaultImagePullPolicy(image string) corev1.PullPolicy {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		// Let the container runtime decide how to handle an unparsable reference
+		return corev1.PullIfNotPresent
+	}
+
+	if tag, ok := ref.(name.Tag); ok && tag.TagStr() == name.DefaultTag {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
+// DefaultMCPServer fills in the defaults a mutating webhook would apply to an MCPServer.
+// Deprecated port fields are migrated to their replacements.
+func DefaultMCPServer(m *MCPServer) {
+	if m.Spec.Transport == "" {
+		m.Spec.Transport = "stdio"
+	}
+	if m.Spec.ProxyMode == "" {
+		m.Spec.ProxyMode = "streamable-http"
+	}
+
+	if m.Spec.ProxyPort == 0 {
+		m.Spec.ProxyPort = m.GetProxyPort()
+	}
+	// stdio servers do not listen on a port, so only HTTP-based transports get an MCP port
+	if m.Spec.Transport != "stdio" && m.Spec.McpPort == 0 {
+		m.Spec.McpPort = m.GetMcpPort()
+	}
+
+	if m.Spec.ImagePullPolicy == "" && m.Spec.Image != "" {
+		m.Spec.ImagePullPolicy = string(DefaultImagePullPolicy(m.Spec.Image))
+	}
+}
+
+// ValidateMCPServer performs the validation a validating webhook would apply to an MCPServer.
+// The same checks are otherwise only reported at reconcile time.
+func ValidateMCPServer(m *MCPServer) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if m.Spec.Image == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("image"), "image is required"))
+	}
+
+	allErrs = append(allErrs, validateMCPServerTransport(m, specPath)...)
+	allErrs = append(allErrs, validateMCPServerSecrets(m.Spec.Secrets, specPath.Child("secrets"))...)
+	allErrs = append(allErrs, validateMCPServerVault(m, specPath)...)
+
+	if m.Spec.Strategy != nil && m.Spec.Strategy.Type == "Recreate" && m.Spec.Strategy.RollingUpdate != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("strategy", "rollingUpdate"),
+			"rollingUpdate must not be set when type is Recreate"))
+	}
+
+	return allErrs
+}
+
+// validateMCPServerTransport checks the transport against the declared port configuration
+func validateMCPServerTransport(m *MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch m.Spec.Transport {
+	case "", "stdio":
+		if m.Spec.McpPort > 0 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("mcpPort"), m.Spec.McpPort,
+				"must not be set when transport is stdio"))
+		}
+		if m.Spec.TargetPort > 0 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("targetPort"), m.Spec.TargetPort,
+				"must not be set when transport is stdio"))
+		}
+	case "sse", "streamable-http":
+		// The MCP port defaults to the port of GetMcpPort when it is not set
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("transport"), m.Spec.Transport,
+			[]string{"stdio", "streamable-http", "sse"}))
+	}
+
+	return allErrs
+}
+
+// validateMCPServerSecrets checks that secret references are complete and map to unique env vars
+func validateMCPServerSecrets(secrets []SecretRef, secretsPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]bool, len(secrets))
+
+	for i, secret := range secrets {
+		secretPath := secretsPath.Index(i)
+		if secret.Name == "" {
+			allErrs = append(allErrs, field.Required(secretPath.Child("name"), "secret name is required"))
+		}
+		if secret.Type != "" && secret.Type != SecretRefTypeKubernetes && secret.Type != SecretRefTypeVault {
+			allErrs = append(allErrs, field.NotSupported(secretPath.Child("type"), secret.Type,
+				[]string{SecretRefTypeKubernetes, SecretRefTypeVault}))
+		}
+		if secret.Default != nil && secret.IsVault() {
+			allErrs = append(allErrs, field.Forbidden(secretPath.Child("default"),
+				"default values are not supported for vault secrets"))
+		}
+		if secret.Command != "" && !secret.IsVault() {
+			allErrs = append(allErrs, field.Forbidden(secretPath.Child("command"),
+				"commands are only supported for vault secrets"))
+		}
+		if secret.Role != "" && !secret.IsVault() {
+			allErrs = append(allErrs, field.Forbidden(secretPath.Child("role"),
+				"roles are only supported for vault secrets"))
+		}
+		if secret.IsVault() {
+			if secret.MinLength != 0 {
+				allErrs = append(allErrs, field.Forbidden(secretPath.Child("minLength"),
+					"value constraints are not supported for vault secrets"))
+			}
+			if secret.Pattern != "" {
+				allErrs = append(allErrs, field.Forbidden(secretPath.Child("pattern"),
+					"value constraints are not supported for vault secrets"))
+			}
+		}
+		if secret.MinLength < 0 {
+			allErrs = append(allErrs, field.Invalid(secretPath.Child("minLength"), secret.MinLength,
+				"must be greater than or equal to 0"))
+		}
+		if secret.Pattern != "" {
+			if _, err := regexp.Compile(secret.Pattern); err != nil {
+				allErrs = append(allErrs, field.Invalid(secretPath.Child("pattern"), secret.Pattern, err.Error()))
+			}
+		}
+		if secret.Key == "" {
+			allErrs = append(allErrs, field.Required(secretPath.Child("key"), "secret key is required"))
+			continue
+		}
+
+		envName := secret.Key
+		envPath := secretPath.Child("key")
+		if secret.TargetEnvName != "" {
+			envName = secret.TargetEnvName
+			envPath = secretPath.Child("targetEnvName")
+		}
+		for _, msg := range validation.IsEnvVarName(envName) {
+			allErrs = append(allErrs, field.Invalid(envPath, envName, msg))
+		}
+		if seen[envName] {
+			allErrs = append(allErrs, field.Duplicate(envPath, envName))
+		}
+		seen[envName] = true
+	}
+
+	return allErrs
+}
+
+// validateMCPServerVault checks that Vault Agent Injection, when enabled, names the Vault role to use,
+// and that it is enabled on the proxy deployment when vault-typed secrets are referenced
+func validateMCPServerVault(m *MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	var proxyAnnotations map[string]string
+	if m.Spec.ResourceOverrides != nil && m.Spec.ResourceOverrides.ProxyDeployment != nil &&
+		m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides != nil {
+		proxyAnnotations = m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Annotations
+	}
+	if proxyAnnotations[vaultAgentInjectAnnotation] != "true" {
+		for i, secret := range m.Spec.Secrets {
+			if secret.IsVault() {
+				allErrs = append(allErrs, field.Invalid(specPath.Child("secrets").Index(i).Child("type"), secret.Type,
+					"vault secrets require Vault Agent Injection on the proxy deployment pod template"))
+			}
+		}
+	}
+
+	// The Vault Agent authenticates with a single role, used for all the vault secrets
+	secretsRole := ""
+	for i, secret := range m.Spec.Secrets {
+		if !secret.IsVault() || secret.Role == "" {
+			continue
+		}
+		if secretsRole == "" {
+			secretsRole = secret.Role
+			continue
+		}
+		if secret.Role != secretsRole {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("secrets").Index(i).Child("role"), secret.Role,
+				fmt.Sprintf("conflicts with the role %q of another vault secret, the Vault Agent authenticates with a single role",
+					secretsRole)))
+		}
+	}
+
+	if m.Spec.PodTemplateSpec != nil && m.Spec.PodTemplateSpec.Raw != nil {
+		podTemplatePath := specPath.Child("podTemplateSpec")
+		var podTemplateSpec corev1.PodTemplateSpec
+		if err := json.Unmarshal(m.Spec.PodTemplateSpec.Raw, &podTemplateSpec); err != nil {
+			allErrs = append(allErrs, field.Invalid(podTemplatePath, string(m.Spec.PodTemplateSpec.Raw),
+				fmt.Sprintf("failed to parse PodTemplateSpec: %v", err)))
+		} else {
+			allErrs = append(allErrs, validateVaultAnnotations(podTemplateSpec.Annotations,
+				podTemplatePath.Child("metadata", "annotations"), "")...)
+		}
+	}
+
+	allErrs = append(allErrs, validateVaultAnnotations(proxyAnnotations,
+		specPath.Child("resourceOverrides", "proxyDeployment", "podTemplateMetadataOverrides", "annotations"),
+		secretsRole)...)
+
+	if m.Spec.VaultAgent != nil {
+		allErrs = append(allErrs, validateResourceRequirements(m.Spec.VaultAgent.Resources,
+			specPath.Child("vaultAgent", "resources"))...)
+	}
+
+	return allErrs
+}
+
+// validateResourceRequirements checks that the CPU and memory values are valid quantities
+func validateResourceRequirements(resources ResourceRequirements, resourcesPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for name, list := range map[string]ResourceList{"limits": resources.Limits, "requests": resources.Requests} {
+		for key, value := range map[string]string{"cpu": list.CPU, "memory": list.Memory} {
+			if value == "" {
+				continue
+			}
+			if _, err := resource.ParseQuantity(value); err != nil {
+				allErrs = append(allErrs, field.Invalid(resourcesPath.Child(name, key), value, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateVaultAnnotations requires a Vault role when Vault Agent Injection is enabled, either set by the
+// annotations or the secretsRole declared by the vault secrets, in which case both must match
+func validateVaultAnnotations(annotations map[string]string, annotationsPath *field.Path, secretsRole string) field.ErrorList {
+	if annotations[vaultAgentInjectAnnotation] != "true" {
+		return nil
+	}
+	role := annotations[vaultRoleAnnotation]
+	switch {
+	case role == "" && secretsRole == "":
+		return field.ErrorList{field.Required(annotationsPath.Key(vaultRoleAnnotation),
+			"a Vault role is required when Vault Agent Injection is enabled")}
+	case role != "" && secretsRole != "" && role != secretsRole:
+		return field.ErrorList{field.Invalid(annotationsPath.Key(vaultRoleAnnotation), role,
+			fmt.Sprintf("conflicts with the role %q of the vault secrets, the Vault Agent authenticates with a single role",
+				secretsRole))}
+	}
+	return nil
+}
@@ -0,0 +1,436 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestDefaultImagePullPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		image    string
+		expected corev1.PullPolicy
+	}{
+		{image: "ghcr.io/stackloklabs/mcp-fetch:latest", expected: corev1.PullAlways},
+		{image: "ghcr.io/stackloklabs/mcp-fetch", expected: corev1.PullAlways},
+		{image: "ghcr.io/stackloklabs/mcp-fetch:0.0.1", expected: corev1.PullIfNotPresent},
+		{image: "localhost:5000/mcp-fetch:v1", expected: corev1.PullIfNotPresent},
+		{
+			image:    "ghcr.io/stackloklabs/mcp-fetch@sha256:" + strings.Repeat("a", 64),
+			expected: corev1.PullIfNotPresent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, DefaultImagePullPolicy(tt.image))
+		})
+	}
+}
+
+func TestDefaultMCPServer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		spec     MCPServerSpec
+		expected MCPServerSpec
+	}{
+		{
+			name: "stdio server gets transport, proxy port and pull policy defaults",
+			spec: MCPServerSpec{Image: "ghcr.io/example/server:latest"},
+			expected: MCPServerSpec{
+				Image:           "ghcr.io/example/server:latest",
+				ImagePullPolicy: "Always",
+				Transport:       "stdio",
+				ProxyMode:       "streamable-http",
+				ProxyPort:       8080,
+			},
+		},
+		{
+			name: "HTTP server gets an MCP port and deprecated ports are migrated",
+			spec: MCPServerSpec{
+				Image:     "ghcr.io/example/server:1.0.0",
+				Transport: "sse",
+				Port:      9090,
+			},
+			expected: MCPServerSpec{
+				Image:           "ghcr.io/example/server:1.0.0",
+				ImagePullPolicy: "IfNotPresent",
+				Transport:       "sse",
+				ProxyMode:       "streamable-http",
+				Port:            9090,
+				ProxyPort:       9090,
+				McpPort:         8080,
+			},
+		},
+		{
+			name: "explicit values are kept",
+			spec: MCPServerSpec{
+				Image:           "ghcr.io/example/server:latest",
+				ImagePullPolicy: "Never",
+				Transport:       "streamable-http",
+				ProxyMode:       "sse",
+				ProxyPort:       8000,
+				McpPort:         3000,
+			},
+			expected: MCPServerSpec{
+				Image:           "ghcr.io/example/server:latest",
+				ImagePullPolicy: "Never",
+				Transport:       "streamable-http",
+				ProxyMode:       "sse",
+				ProxyPort:       8000,
+				McpPort:         3000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m := &MCPServer{Spec: tt.spec}
+			DefaultMCPServer(m)
+			assert.Equal(t, tt.expected, m.Spec)
+		})
+	}
+}
+
+func TestValidateMCPServer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		spec          MCPServerSpec
+		expectedField string
+		expectedType  field.ErrorType
+	}{
+		{
+			name: "valid stdio server",
+			spec: MCPServerSpec{
+				Image:     "ghcr.io/example/server:latest",
+				Transport: "stdio",
+				Secrets:   []SecretRef{{Name: "github", Key: "token", TargetEnvName: "GITHUB_TOKEN"}},
+			},
+		},
+		{
+			name: "streamable-http without MCP port uses the default port",
+			spec: MCPServerSpec{Image: "server", Transport: "streamable-http"},
+		},
+		{
+			name:          "missing image",
+			spec:          MCPServerSpec{Transport: "stdio"},
+			expectedField: "spec.image",
+			expectedType:  field.ErrorTypeRequired,
+		},
+		{
+			name:          "stdio with MCP port",
+			spec:          MCPServerSpec{Image: "server", Transport: "stdio", McpPort: 8080},
+			expectedField: "spec.mcpPort",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name:          "unsupported transport",
+			spec:          MCPServerSpec{Image: "server", Transport: "websocket"},
+			expectedField: "spec.transport",
+			expectedType:  field.ErrorTypeNotSupported,
+		},
+		{
+			name: "secret without key",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "github"}},
+			},
+			expectedField: "spec.secrets[0].key",
+			expectedType:  field.ErrorTypeRequired,
+		},
+		{
+			name: "secret with invalid env name",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "github", Key: "token", TargetEnvName: "1-TOKEN"}},
+			},
+			expectedField: "spec.secrets[0].targetEnvName",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name: "secrets mapping to the same env var",
+			spec: MCPServerSpec{
+				Image: "server",
+				Secrets: []SecretRef{
+					{Name: "github", Key: "token"},
+					{Name: "gitlab", Key: "api-token", TargetEnvName: "token"},
+				},
+			},
+			expectedField: "spec.secrets[1].targetEnvName",
+			expectedType:  field.ErrorTypeDuplicate,
+		},
+		{
+			name: "vault injection without role",
+			spec: MCPServerSpec{
+				Image: "server",
+				PodTemplateSpec: &runtime.RawExtension{
+					Raw: []byte(`{"metadata":{"annotations":{"vault.hashicorp.com/agent-inject":"true"}}}`),
+				},
+			},
+			expectedField: "spec.podTemplateSpec.metadata.annotations[vault.hashicorp.com/role]",
+			expectedType:  field.ErrorTypeRequired,
+		},
+		{
+			name: "vault injection through resource overrides without role",
+			spec: MCPServerSpec{
+				Image: "server",
+				ResourceOverrides: &ResourceOverrides{
+					ProxyDeployment: &ProxyDeploymentOverrides{
+						PodTemplateMetadataOverrides: &ResourceMetadataOverrides{
+							Annotations: map[string]string{"vault.hashicorp.com/agent-inject": "true"},
+						},
+					},
+				},
+			},
+			expectedField: "spec.resourceOverrides.proxyDeployment.podTemplateMetadataOverrides.annotations[vault.hashicorp.com/role]",
+			expectedType:  field.ErrorTypeRequired,
+		},
+		{
+			name: "secret with unsupported type",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "github", Key: "token", Type: "aws"}},
+			},
+			expectedField: "spec.secrets[0].type",
+			expectedType:  field.ErrorTypeNotSupported,
+		},
+		{
+			name: "vault secret without vault injection",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "workload-secrets/data/github", Key: "token", Type: SecretRefTypeVault}},
+			},
+			expectedField: "spec.secrets[0].type",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name: "vault secret with default",
+			spec: MCPServerSpec{
+				Image: "server",
+				Secrets: []SecretRef{{
+					Name: "workload-secrets/data/github", Key: "token", Type: SecretRefTypeVault, Default: ptr.To("fallback"),
+				}},
+				ResourceOverrides: &ResourceOverrides{
+					ProxyDeployment: &ProxyDeploymentOverrides{
+						PodTemplateMetadataOverrides: &ResourceMetadataOverrides{
+							Annotations: map[string]string{
+								"vault.hashicorp.com/agent-inject": "true",
+								"vault.hashicorp.com/role":         "toolhive-mcp-workloads",
+							},
+						},
+					},
+				},
+			},
+			expectedField: "spec.secrets[0].default",
+			expectedType:  field.ErrorTypeForbidden,
+		},
+		{
+			name: "kubernetes secret with command",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "github", Key: "token", Command: "kill -HUP 1"}},
+			},
+			expectedField: "spec.secrets[0].command",
+			expectedType:  field.ErrorTypeForbidden,
+		},
+		{
+			name: "secret with invalid pattern",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "github", Key: "token", Pattern: "^ghp_["}},
+			},
+			expectedField: "spec.secrets[0].pattern",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name: "secret with negative minimum length",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "github", Key: "token", MinLength: -1}},
+			},
+			expectedField: "spec.secrets[0].minLength",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name: "secret with value constraints",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "github", Key: "token", MinLength: 40, Pattern: "^ghp_"}},
+			},
+		},
+		{
+			name: "vault secret with pattern",
+			spec: MCPServerSpec{
+				Image: "server",
+				Secrets: []SecretRef{
+					{Name: "workload-secrets/data/github", Key: "token", Type: SecretRefTypeVault, Pattern: "^ghp_"},
+				},
+				ResourceOverrides: &ResourceOverrides{
+					ProxyDeployment: &ProxyDeploymentOverrides{
+						PodTemplateMetadataOverrides: &ResourceMetadataOverrides{
+							Annotations: map[string]string{
+								"vault.hashicorp.com/agent-inject": "true",
+								"vault.hashicorp.com/role":         "toolhive-mcp-workloads",
+							},
+						},
+					},
+				},
+			},
+			expectedField: "spec.secrets[0].pattern",
+			expectedType:  field.ErrorTypeForbidden,
+		},
+		{
+			name: "kubernetes secret with role",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "github", Key: "token", Role: "github-mcp"}},
+			},
+			expectedField: "spec.secrets[0].role",
+			expectedType:  field.ErrorTypeForbidden,
+		},
+		{
+			name: "vault secrets with conflicting roles",
+			spec: MCPServerSpec{
+				Image: "server",
+				Secrets: []SecretRef{
+					{
+						Name: "workload-secrets/data/github", Key: "token", TargetEnvName: "GITHUB_TOKEN",
+						Type: SecretRefTypeVault, Role: "github-mcp",
+					},
+					{
+						Name: "workload-secrets/data/slack", Key: "token", TargetEnvName: "SLACK_TOKEN",
+						Type: SecretRefTypeVault, Role: "slack-mcp",
+					},
+				},
+				ResourceOverrides: &ResourceOverrides{
+					ProxyDeployment: &ProxyDeploymentOverrides{
+						PodTemplateMetadataOverrides: &ResourceMetadataOverrides{
+							Annotations: map[string]string{"vault.hashicorp.com/agent-inject": "true"},
+						},
+					},
+				},
+			},
+			expectedField: "spec.secrets[1].role",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name: "vault secret role conflicting with the role annotation",
+			spec: MCPServerSpec{
+				Image: "server",
+				Secrets: []SecretRef{
+					{Name: "workload-secrets/data/github", Key: "token", Type: SecretRefTypeVault, Role: "github-mcp"},
+				},
+				ResourceOverrides: &ResourceOverrides{
+					ProxyDeployment: &ProxyDeploymentOverrides{
+						PodTemplateMetadataOverrides: &ResourceMetadataOverrides{
+							Annotations: map[string]string{
+								"vault.hashicorp.com/agent-inject": "true",
+								"vault.hashicorp.com/role":         "toolhive-mcp-workloads",
+							},
+						},
+					},
+				},
+			},
+			expectedField: "spec.resourceOverrides.proxyDeployment.podTemplateMetadataOverrides.annotations[vault.hashicorp.com/role]",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name: "vault secret declaring the role",
+			spec: MCPServerSpec{
+				Image: "server",
+				Secrets: []SecretRef{
+					{Name: "workload-secrets/data/github", Key: "token", Type: SecretRefTypeVault, Role: "github-mcp"},
+				},
+				ResourceOverrides: &ResourceOverrides{
+					ProxyDeployment: &ProxyDeploymentOverrides{
+						PodTemplateMetadataOverrides: &ResourceMetadataOverrides{
+							Annotations: map[string]string{"vault.hashicorp.com/agent-inject": "true"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "vault secret with vault injection",
+			spec: MCPServerSpec{
+				Image:   "server",
+				Secrets: []SecretRef{{Name: "workload-secrets/data/github", Key: "token", Type: SecretRefTypeVault}},
+				ResourceOverrides: &ResourceOverrides{
+					ProxyDeployment: &ProxyDeploymentOverrides{
+						PodTemplateMetadataOverrides: &ResourceMetadataOverrides{
+							Annotations: map[string]string{
+								"vault.hashicorp.com/agent-inject": "true",
+								"vault.hashicorp.com/role":         "toolhive-mcp-workloads",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "vault agent with invalid memory limit",
+			spec: MCPServerSpec{
+				Image: "server",
+				VaultAgent: &VaultAgentConfig{
+					Resources: ResourceRequirements{
+						Requests: ResourceList{CPU: "100m", Memory: "64Mi"},
+						Limits:   ResourceList{CPU: "500m", Memory: "lots"},
+					},
+				},
+			},
+			expectedField: "spec.vaultAgent.resources.limits.memory",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name: "invalid pod template spec",
+			spec: MCPServerSpec{
+				Image:           "server",
+				PodTemplateSpec: &runtime.RawExtension{Raw: []byte(`{invalid`)},
+			},
+			expectedField: "spec.podTemplateSpec",
+			expectedType:  field.ErrorTypeInvalid,
+		},
+		{
+			name: "recreate strategy with rolling update parameters",
+			spec: MCPServerSpec{
+				Image: "server",
+				Strategy: &DeploymentStrategy{
+					Type:          "Recreate",
+					RollingUpdate: &RollingUpdateConfig{},
+				},
+			},
+			expectedField: "spec.strategy.rollingUpdate",
+			expectedType:  field.ErrorTypeForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			errs := ValidateMCPServer(&MCPServer{Spec: tt.spec})
+
+			if tt.expectedField == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.Len(t, errs, 1, "unexpected errors: %v", errs)
+			assert.Equal(t, tt.expectedField, errs[0].Field)
+			assert.Equal(t, tt.expectedType, errs[0].Type)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Package v1alpha2 contains API Schema definitions for the toolhive v1alpha2 API group.
+// The version is not served yet: it exists so conversions from v1alpha1 can be developed
+// and tested ahead of the CRD change.
+// +kubebuilder:object:generate=true
+// +kubebuilder:skipversion
+// +groupName=toolhive.stacklok.dev
+package v1alpha2
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is group version used to register these objects
+	GroupVersion = schema.GroupVersion{Group: "toolhive.stacklok.dev", Version: "v1alpha2"}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the types in this group-version to the given scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
@@ -0,0 +1,131 @@
+package v1alpha2
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &MCPServer{}
+
+// ConvertTo converts this MCPServer to the hub version (v1alpha1)
+func (m *MCPServer) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.MCPServer)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", dstRaw)
+	}
+
+	m.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Spec = convertSpecToV1alpha1(m.Spec.DeepCopy())
+	m.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1alpha1) to this MCPServer
+func (m *MCPServer) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.MCPServer)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", srcRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&m.ObjectMeta)
+	m.Spec = convertSpecFromV1alpha1(src.Spec.DeepCopy())
+	src.Status.DeepCopyInto(&m.Status)
+	return nil
+}
+
+// convertSpecToV1alpha1 flattens the Secrets block back into the v1alpha1 secret fields
+func convertSpecToV1alpha1(in *MCPServerSpec) v1alpha1.MCPServerSpec {
+	out := v1alpha1.MCPServerSpec{
+		Image:                 in.Image,
+		ImagePullPolicy:       in.ImagePullPolicy,
+		Transport:             in.Transport,
+		ProxyMode:             in.ProxyMode,
+		Port:                  in.Port,
+		TargetPort:            in.TargetPort,
+		ProxyPort:             in.ProxyPort,
+		McpPort:               in.McpPort,
+		Args:                  in.Args,
+		ExtraArgs:             in.ExtraArgs,
+		EntrypointOverride:    in.EntrypointOverride,
+		Env:                   in.Env,
+		Volumes:               in.Volumes,
+		Resources:             in.Resources,
+		VaultAgent:            in.VaultAgent,
+		ServiceAccount:        in.ServiceAccount,
+		PermissionProfile:     in.PermissionProfile,
+		PodTemplateSpec:       in.PodTemplateSpec,
+		ResourceOverrides:     in.ResourceOverrides,
+		CommonLabels:          in.CommonLabels,
+		CommonAnnotations:     in.CommonAnnotations,
+		PodDisruptionBudget:   in.PodDisruptionBudget,
+		Autoscaling:           in.Autoscaling,
+		Strategy:              in.Strategy,
+		Ingress:               in.Ingress,
+		OIDCConfig:            in.OIDCConfig,
+		AuthzConfig:           in.AuthzConfig,
+		Audit:                 in.Audit,
+		ToolsFilter:           in.ToolsFilter,
+		ToolConfigRef:         in.ToolConfigRef,
+		ExternalAuthConfigRef: in.ExternalAuthConfigRef,
+		Telemetry:             in.Telemetry,
+		TrustProxyHeaders:     in.TrustProxyHeaders,
+		GroupRef:              in.GroupRef,
+	}
+
+	if in.Secrets != nil {
+		out.Secrets = in.Secrets.Env
+		out.RestartOnSecretChange = in.Secrets.RestartOnChange
+	}
+	return out
+}
+
+// convertSpecFromV1alpha1 groups the v1alpha1 secret fields into the Secrets block
+func convertSpecFromV1alpha1(in *v1alpha1.MCPServerSpec) MCPServerSpec {
+	out := MCPServerSpec{
+		Image:                 in.Image,
+		ImagePullPolicy:       in.ImagePullPolicy,
+		Transport:             in.Transport,
+		ProxyMode:             in.ProxyMode,
+		Port:                  in.Port,
+		TargetPort:            in.TargetPort,
+		ProxyPort:             in.ProxyPort,
+		McpPort:               in.McpPort,
+		Args:                  in.Args,
+		ExtraArgs:             in.ExtraArgs,
+		EntrypointOverride:    in.EntrypointOverride,
+		Env:                   in.Env,
+		Volumes:               in.Volumes,
+		Resources:             in.Resources,
+		VaultAgent:            in.VaultAgent,
+		ServiceAccount:        in.ServiceAccount,
+		PermissionProfile:     in.PermissionProfile,
+		PodTemplateSpec:       in.PodTemplateSpec,
+		ResourceOverrides:     in.ResourceOverrides,
+		CommonLabels:          in.CommonLabels,
+		CommonAnnotations:     in.CommonAnnotations,
+		PodDisruptionBudget:   in.PodDisruptionBudget,
+		Autoscaling:           in.Autoscaling,
+		Strategy:              in.Strategy,
+		Ingress:               in.Ingress,
+		OIDCConfig:            in.OIDCConfig,
+		AuthzConfig:           in.AuthzConfig,
+		Audit:                 in.Audit,
+		ToolsFilter:           in.ToolsFilter,
+		ToolConfigRef:         in.ToolConfigRef,
+		ExternalAuthConfigRef: in.ExternalAuthConfigRef,
+		Telemetry:             in.Telemetry,
+		TrustProxyHeaders:     in.TrustProxyHeaders,
+		GroupRef:              in.GroupRef,
+	}
+
+	if in.Secrets != nil || in.RestartOnSecretChange {
+		out.Secrets = &SecretsConfig{
+			Env:             in.Secrets,
+			RestartOnChange: in.RestartOnSecretChange,
+		}
+	}
+	return out
+}
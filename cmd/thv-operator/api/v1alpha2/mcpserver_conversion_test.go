@@ -0,0 +1,172 @@
+package v1alpha2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+// newPopulatedHubMCPServer returns a v1alpha1 MCPServer with every spec field set
+func newPopulatedHubMCPServer() *v1alpha1.MCPServer {
+	maxUnavailable := intstr.FromInt32(1)
+
+	return &v1alpha1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "github",
+			Namespace:   "toolhive",
+			Labels:      map[string]string{"team": "platform"},
+			Annotations: map[string]string{"owner": "platform"},
+		},
+		Spec: v1alpha1.MCPServerSpec{
+			Image:              "ghcr.io/github/github-mcp-server:1.0.0",
+			ImagePullPolicy:    "IfNotPresent",
+			Transport:          "streamable-http",
+			ProxyMode:          "sse",
+			Port:               8080,
+			TargetPort:         9000,
+			ProxyPort:          8081,
+			McpPort:            9001,
+			Args:               []string{"--verbose"},
+			ExtraArgs:          []string{"--debug"},
+			EntrypointOverride: &v1alpha1.EntrypointOverride{Command: []string{"/server"}, Args: []string{"serve"}},
+			Env:                []v1alpha1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+			Volumes:            []v1alpha1.Volume{{Name: "data", HostPath: "/data", MountPath: "/data", ReadOnly: true}},
+			Resources: v1alpha1.ResourceRequirements{
+				Limits:   v1alpha1.ResourceList{CPU: "500m", Memory: "256Mi"},
+				Requests: v1alpha1.ResourceList{CPU: "100m", Memory: "128Mi"},
+			},
+			Secrets: []v1alpha1.SecretRef{
+				{Name: "github-token", Key: "token", TargetEnvName: "GITHUB_PERSONAL_ACCESS_TOKEN"},
+			},
+			RestartOnSecretChange: true,
+			VaultAgent: &v1alpha1.VaultAgentConfig{
+				Resources: v1alpha1.ResourceRequirements{Limits: v1alpha1.ResourceList{Memory: "64Mi"}},
+				TLSCACert: &v1alpha1.SecretKeyRef{Name: "vault-ca", Key: "ca.crt"},
+			},
+			ServiceAccount:    ptr.To("github-sa"),
+			PermissionProfile: &v1alpha1.PermissionProfileRef{Type: "builtin", Name: "network"},
+			PodTemplateSpec:   &runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"high"}}`)},
+			ResourceOverrides: &v1alpha1.ResourceOverrides{
+				ProxyDeployment: &v1alpha1.ProxyDeploymentOverrides{
+					Env: []v1alpha1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}},
+				},
+			},
+			CommonLabels:        map[string]string{"app": "github"},
+			CommonAnnotations:   map[string]string{"contact": "platform"},
+			PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetConfig{MaxUnavailable: &maxUnavailable},
+			Autoscaling:         &v1alpha1.AutoscalingConfig{MinReplicas: ptr.To[int32](1), MaxReplicas: 3},
+			Strategy:            &v1alpha1.DeploymentStrategy{Type: "Recreate"},
+			Ingress:             &v1alpha1.IngressConfig{Host: "github.example.com"},
+			OIDCConfig: &v1alpha1.OIDCConfigRef{
+				Type:      "configMap",
+				ConfigMap: &v1alpha1.ConfigMapOIDCRef{Name: "oidc"},
+			},
+			AuthzConfig: &v1alpha1.AuthzConfigRef{
+				Type:      "configMap",
+				ConfigMap: &v1alpha1.ConfigMapAuthzRef{Name: "authz"},
+			},
+			Audit:                 &v1alpha1.AuditConfig{Enabled: true},
+			ToolsFilter:           []string{"create_issue"},
+			ToolConfigRef:         &v1alpha1.ToolConfigRef{Name: "tools"},
+			ExternalAuthConfigRef: &v1alpha1.ExternalAuthConfigRef{Name: "token-exchange"},
+			Telemetry:             &v1alpha1.TelemetryConfig{Prometheus: &v1alpha1.PrometheusConfig{Enabled: true}},
+			TrustProxyHeaders:     true,
+			GroupRef:              "engineering",
+		},
+		Status: v1alpha1.MCPServerStatus{
+			Conditions: []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Running"}},
+			URL:        "http://github.toolhive.svc:8081",
+			Phase:      v1alpha1.MCPServerPhaseRunning,
+			Message:    "running",
+		},
+	}
+}
+
+func TestMCPServerConversionFixtureIsComplete(t *testing.T) {
+	t.Parallel()
+
+	// Guard against new v1alpha1 fields being added without conversion coverage
+	spec := reflect.ValueOf(newPopulatedHubMCPServer().Spec)
+	for i := 0; i < spec.NumField(); i++ {
+		assert.False(t, spec.Field(i).IsZero(),
+			"v1alpha1 MCPServerSpec.%s is not set in the conversion fixture", spec.Type().Field(i).Name)
+	}
+}
+
+func TestMCPServerConversionRoundTripFromHub(t *testing.T) {
+	t.Parallel()
+
+	hub := newPopulatedHubMCPServer()
+
+	spoke := &MCPServer{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	require.NotNil(t, spoke.Spec.Secrets)
+	assert.Equal(t, hub.Spec.Secrets, spoke.Spec.Secrets.Env)
+	assert.True(t, spoke.Spec.Secrets.RestartOnChange)
+	assert.Equal(t, hub.Spec.VaultAgent, spoke.Spec.VaultAgent)
+
+	roundTripped := &v1alpha1.MCPServer{}
+	require.NoError(t, spoke.ConvertTo(roundTripped))
+	assert.Equal(t, hub, roundTripped)
+}
+
+func TestMCPServerConversionRoundTripFromSpoke(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		secrets *SecretsConfig
+	}{
+		{
+			name: "with secrets block",
+			secrets: &SecretsConfig{
+				Env:             []v1alpha1.SecretRef{{Name: "api-key", Key: "key"}},
+				RestartOnChange: true,
+			},
+		},
+		{
+			name:    "without secrets block",
+			secrets: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			spoke := &MCPServer{}
+			require.NoError(t, spoke.ConvertFrom(newPopulatedHubMCPServer()))
+			spoke.Spec.Secrets = tt.secrets
+
+			hub := &v1alpha1.MCPServer{}
+			require.NoError(t, spoke.ConvertTo(hub))
+
+			roundTripped := &MCPServer{}
+			require.NoError(t, roundTripped.ConvertFrom(hub))
+			assert.Equal(t, spoke, roundTripped)
+		})
+	}
+}
+
+func TestMCPServerConversionDoesNotAlias(t *testing.T) {
+	t.Parallel()
+
+	hub := newPopulatedHubMCPServer()
+	spoke := &MCPServer{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	spoke.Spec.Args[0] = "--changed"
+	spoke.Spec.Secrets.Env[0].Key = "changed"
+
+	assert.Equal(t, "--verbose", hub.Spec.Args[0])
+	assert.Equal(t, "token", hub.Spec.Secrets[0].Key)
+}
@@ -0,0 +1,235 @@
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+// MCPServerSpec defines the desired state of MCPServer.
+// Compared to v1alpha1, the secret references and the secret restart policy are grouped
+// under a single Secrets block. Nested types are shared with v1alpha1 until they diverge.
+type MCPServerSpec struct {
+	// Image is the container image for the MCP server
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// ImagePullPolicy is the pull policy for the MCP server container image.
+	// Defaults to Always for images tagged :latest (or untagged) and IfNotPresent otherwise.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// Transport is the transport method for the MCP server (stdio, streamable-http or sse)
+	// +kubebuilder:validation:Enum=stdio;streamable-http;sse
+	// +kubebuilder:default=stdio
+	Transport string `json:"transport,omitempty"`
+
+	// ProxyMode is the proxy mode for stdio transport (sse or streamable-http)
+	// This setting is only used when Transport is "stdio"
+	// +kubebuilder:validation:Enum=sse;streamable-http
+	// +kubebuilder:default=streamable-http
+	// +optional
+	ProxyMode string `json:"proxyMode,omitempty"`
+
+	// Port is the port to expose the MCP server on
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=8080
+	// Deprecated: Use ProxyPort instead
+	Port int32 `json:"port,omitempty"`
+
+	// TargetPort is the port that MCP server listens to
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	// Deprecated: Use McpPort instead
+	TargetPort int32 `json:"targetPort,omitempty"`
+
+	// ProxyPort is the port to expose the proxy runner on
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=8080
+	ProxyPort int32 `json:"proxyPort,omitempty"`
+
+	// McpPort is the port that MCP server listens to
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	McpPort int32 `json:"mcpPort,omitempty"`
+
+	// Args are additional arguments to pass to the MCP server
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// ExtraArgs are additional arguments appended to the arguments of the MCP server
+	// container, after Args or the EntrypointOverride arguments. Flags already set by
+	// those arguments are rejected.
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+
+	// EntrypointOverride replaces the command and arguments of the MCP server container.
+	// When set, Args are not passed to the MCP server and the override arguments are used instead.
+	// +optional
+	EntrypointOverride *v1alpha1.EntrypointOverride `json:"entrypointOverride,omitempty"`
+
+	// Env are environment variables to set in the MCP server container
+	// +optional
+	Env []v1alpha1.EnvVar `json:"env,omitempty"`
+
+	// Volumes are volumes to mount in the MCP server container
+	// +optional
+	Volumes []v1alpha1.Volume `json:"volumes,omitempty"`
+
+	// Resources defines the resource requirements for the MCP server container
+	// +optional
+	Resources v1alpha1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Secrets groups the secrets made available to the MCP server
+	// +optional
+	Secrets *SecretsConfig `json:"secrets,omitempty"`
+
+	// VaultAgent configures the Vault Agent injected into the proxy deployment pods to render the vault secrets.
+	// It only applies when vault secrets are referenced.
+	// +optional
+	VaultAgent *v1alpha1.VaultAgentConfig `json:"vaultAgent,omitempty"`
+
+	// ServiceAccount is the name of an already existing service account to use by the MCP server.
+	// If not specified, a ServiceAccount will be created automatically and used by the MCP server.
+	// +optional
+	ServiceAccount *string `json:"serviceAccount,omitempty"`
+
+	// PermissionProfile defines the permission profile to use
+	// +optional
+	PermissionProfile *v1alpha1.PermissionProfileRef `json:"permissionProfile,omitempty"`
+
+	// PodTemplateSpec defines the pod template to use for the MCP server
+	// This allows for customizing the pod configuration beyond what is provided by the other fields.
+	// Note that to modify the specific container the MCP server runs in, you must specify
+	// the `mcp` container name in the PodTemplateSpec.
+	// This field accepts a PodTemplateSpec object as JSON/YAML.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	PodTemplateSpec *runtime.RawExtension `json:"podTemplateSpec,omitempty"`
+
+	// ResourceOverrides allows overriding annotations and labels for resources created by the operator
+	// +optional
+	ResourceOverrides *v1alpha1.ResourceOverrides `json:"resourceOverrides,omitempty"`
+
+	// CommonLabels are labels added to every resource the operator creates for this MCPServer,
+	// including the pod template of the proxy deployment. Labels managed by the operator take
+	// precedence, and labels set through ResourceOverrides win over common labels.
+	// +optional
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonAnnotations are annotations added to every resource the operator creates for this MCPServer,
+	// including the pod template of the proxy deployment. Annotations set through ResourceOverrides
+	// win over common annotations.
+	// +optional
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget to create for the proxy deployment.
+	// If not specified, no PodDisruptionBudget is created and any existing one is removed.
+	// +optional
+	PodDisruptionBudget *v1alpha1.PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+
+	// Autoscaling defines the HorizontalPodAutoscaler to create for the proxy deployment.
+	// If not specified, autoscaling is disabled and any existing HorizontalPodAutoscaler is removed.
+	// +optional
+	Autoscaling *v1alpha1.AutoscalingConfig `json:"autoscaling,omitempty"`
+
+	// Strategy defines how the proxy deployment replaces existing pods with new ones.
+	// If not specified, a RollingUpdate strategy with maxSurge and maxUnavailable of 25% is used.
+	// +optional
+	Strategy *v1alpha1.DeploymentStrategy `json:"strategy,omitempty"`
+
+	// Ingress defines the Ingress to create for exposing the proxy service outside the cluster.
+	// If not specified, no Ingress is created and any existing one is removed.
+	// +optional
+	Ingress *v1alpha1.IngressConfig `json:"ingress,omitempty"`
+
+	// OIDCConfig defines OIDC authentication configuration for the MCP server
+	// +optional
+	OIDCConfig *v1alpha1.OIDCConfigRef `json:"oidcConfig,omitempty"`
+
+	// AuthzConfig defines authorization policy configuration for the MCP server
+	// +optional
+	AuthzConfig *v1alpha1.AuthzConfigRef `json:"authzConfig,omitempty"`
+
+	// Audit defines audit logging configuration for the MCP server
+	// +optional
+	Audit *v1alpha1.AuditConfig `json:"audit,omitempty"`
+
+	// ToolsFilter is the filter on tools applied to the MCP server
+	// Deprecated: Use ToolConfigRef instead
+	// +optional
+	ToolsFilter []string `json:"tools,omitempty"`
+
+	// ToolConfigRef references a MCPToolConfig resource for tool filtering and renaming.
+	// The referenced MCPToolConfig must exist in the same namespace as this MCPServer.
+	// Cross-namespace references are not supported for security and isolation reasons.
+	// If specified, this takes precedence over the inline ToolsFilter field.
+	// +optional
+	ToolConfigRef *v1alpha1.ToolConfigRef `json:"toolConfigRef,omitempty"`
+
+	// ExternalAuthConfigRef references a MCPExternalAuthConfig resource for external authentication.
+	// The referenced MCPExternalAuthConfig must exist in the same namespace as this MCPServer.
+	// +optional
+	ExternalAuthConfigRef *v1alpha1.ExternalAuthConfigRef `json:"externalAuthConfigRef,omitempty"`
+
+	// Telemetry defines observability configuration for the MCP server
+	// +optional
+	Telemetry *v1alpha1.TelemetryConfig `json:"telemetry,omitempty"`
+
+	// TrustProxyHeaders indicates whether to trust X-Forwarded-* headers from reverse proxies
+	// When enabled, the proxy will use X-Forwarded-Proto, X-Forwarded-Host, X-Forwarded-Port,
+	// and X-Forwarded-Prefix headers to construct endpoint URLs
+	// +kubebuilder:default=false
+	// +optional
+	TrustProxyHeaders bool `json:"trustProxyHeaders,omitempty"`
+
+	// GroupRef is the name of the MCPGroup this server belongs to
+	// Must reference an existing MCPGroup in the same namespace
+	// +optional
+	GroupRef string `json:"groupRef,omitempty"`
+}
+
+// SecretsConfig groups the secrets made available to the MCP server
+type SecretsConfig struct {
+	// Env are references to secrets exposed to the MCP server as environment variables
+	// +optional
+	Env []v1alpha1.SecretRef `json:"env,omitempty"`
+
+	// RestartOnChange enables rolling the MCP server pods when the data of any
+	// referenced secret changes. A checksum of the secrets is stamped on the pod template.
+	// +kubebuilder:default=false
+	// +optional
+	RestartOnChange bool `json:"restartOnChange,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// MCPServer is the Schema for the mcpservers API
+type MCPServer struct {
+	metav1.TypeMeta   `json:",inline"` // nolint:revive
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MCPServerSpec            `json:"spec,omitempty"`
+	Status v1alpha1.MCPServerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MCPServerList contains a list of MCPServer
+type MCPServerList struct {
+	metav1.TypeMeta `json:",inline"` // nolint:revive
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPServer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MCPServer{}, &MCPServerList{})
+}
@@ -635,6 +635,14 @@ func (r *MCPRemoteProxyReconciler) containerNeedsUpdate(
 		return true
 	}
 
+	// Check if the OpenTelemetry environment variables have changed
+	telemetryConfig := resolveTelemetryConfig(ctx, r.Client, proxy.Namespace, proxy.Spec.Telemetry)
+	if !equalOpenTelemetryEnvVars(
+		telemetryConfig, proxy.Name, proxy.Namespace, proxy.Spec.ResourceOverrides, container.Env,
+	) {
+		return true
+	}
+
 	// Check if environment variables have changed
 	expectedEnv := r.buildEnvVarsForProxy(ctx, proxy)
 	if !reflect.DeepEqual(container.Env, expectedEnv) {
@@ -755,6 +763,37 @@ func (*MCPRemoteProxyReconciler) serviceNeedsUpdate(service *corev1.Service, pro
 	return false
 }
 
+// mapOpenTelemetryConfigMapToMCPRemoteProxies maps a ConfigMap to the MCPRemoteProxies referencing it
+// for their OpenTelemetry configuration
+func (r *MCPRemoteProxyReconciler) mapOpenTelemetryConfigMapToMCPRemoteProxies(
+	ctx context.Context, obj client.Object,
+) []reconcile.Request {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return nil
+	}
+
+	proxyList := &mcpv1alpha1.MCPRemoteProxyList{}
+	if err := r.List(ctx, proxyList, client.InNamespace(configMap.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list MCPRemoteProxies for ConfigMap watch")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, proxy := range proxyList.Items {
+		if referencesOpenTelemetryConfigMap(proxy.Spec.Telemetry, configMap.Name) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      proxy.Name,
+					Namespace: proxy.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager
 func (r *MCPRemoteProxyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Create a handler that maps MCPExternalAuthConfig changes to MCPRemoteProxy reconciliation requests
@@ -829,5 +868,6 @@ func (r *MCPRemoteProxyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Service{}).
 		Watches(&mcpv1alpha1.MCPExternalAuthConfig{}, externalAuthConfigHandler).
 		Watches(&mcpv1alpha1.MCPToolConfig{}, toolConfigHandler).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapOpenTelemetryConfigMapToMCPRemoteProxies)).
 		Complete(r)
 }
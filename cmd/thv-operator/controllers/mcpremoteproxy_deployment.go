@@ -129,10 +129,8 @@ func (r *MCPRemoteProxyReconciler) buildEnvVarsForProxy(
 	env := []corev1.EnvVar{}
 
 	// Add OpenTelemetry environment variables
-	if proxy.Spec.Telemetry != nil && proxy.Spec.Telemetry.OpenTelemetry != nil {
-		otelEnvVars := ctrlutil.GenerateOpenTelemetryEnvVars(proxy.Spec.Telemetry, proxy.Name, proxy.Namespace)
-		env = append(env, otelEnvVars...)
-	}
+	telemetryConfig := resolveTelemetryConfig(ctx, r.Client, proxy.Namespace, proxy.Spec.Telemetry)
+	env = append(env, ctrlutil.GenerateOpenTelemetryEnvVars(telemetryConfig, proxy.Name, proxy.Namespace)...)
 
 	// Add token exchange environment variables
 	if proxy.Spec.ExternalAuthConfigRef != nil {
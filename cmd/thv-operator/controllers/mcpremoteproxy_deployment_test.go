@@ -26,6 +26,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
@@ -564,7 +565,7 @@ func TestBuildEnvVarsForProxy(t *testing.T) {
 					RemoteURL: "https://mcp.example.com",
 					Telemetry: &mcpv1alpha1.TelemetryConfig{
 						OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
-							Enabled:     true,
+							Enabled:     ptr.To(true),
 							ServiceName: "my-proxy",
 						},
 					},
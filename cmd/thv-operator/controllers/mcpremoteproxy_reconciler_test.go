@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -157,7 +158,7 @@ func TestMCPRemoteProxyFullReconciliation(t *testing.T) {
 					},
 					Telemetry: &mcpv1alpha1.TelemetryConfig{
 						OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
-							Enabled:     true,
+							Enabled:     ptr.To(true),
 							ServiceName: "full-proxy",
 						},
 					},
@@ -552,7 +553,7 @@ func TestCommonHelpers(t *testing.T) {
 
 		telemetryConfig := &mcpv1alpha1.TelemetryConfig{
 			OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
-				Enabled:     true,
+				Enabled:     ptr.To(true),
 				ServiceName: "test-service",
 			},
 		}
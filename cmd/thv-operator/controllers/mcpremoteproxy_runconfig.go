@@ -174,7 +174,12 @@ func (r *MCPRemoteProxyReconciler) createRunConfigFromMCPRemoteProxy(
 	defer cancel()
 
 	// Add telemetry configuration if specified
-	runconfig.AddTelemetryConfigOptions(ctx, &options, proxy.Spec.Telemetry, proxy.Name)
+	telemetryConfig, err := ctrlutil.ResolveTelemetryConfig(ctx, r.Client, proxy.Namespace, proxy.Spec.Telemetry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process OpenTelemetry config: %w", err)
+	}
+	runconfig.AddTelemetryConfigOptions(ctx, &options, telemetryConfig, proxy.Name,
+		proxyDeploymentEnv(proxy.Spec.ResourceOverrides))
 
 	// Add authorization configuration if specified
 
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/cmd/thv-operator/pkg/runconfig/configmap/checksum"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestCommonMetadataPropagation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		resourceOverrides *mcpv1alpha1.ResourceOverrides
+		expectedDeployLbl map[string]string
+		expectedPodLbl    map[string]string
+		expectedPodAnns   map[string]string
+		expectedSvcAnns   map[string]string
+	}{
+		{
+			name: "common metadata only",
+			expectedDeployLbl: map[string]string{
+				"cost-center": "cc-123",
+				"team":        "platform",
+			},
+			expectedPodLbl: map[string]string{
+				"cost-center": "cc-123",
+				"team":        "platform",
+			},
+			expectedPodAnns: map[string]string{
+				"owner": "platform@example.com",
+			},
+			expectedSvcAnns: map[string]string{
+				"owner": "platform@example.com",
+			},
+		},
+		{
+			name: "resource overrides win over common metadata",
+			resourceOverrides: &mcpv1alpha1.ResourceOverrides{
+				ProxyDeployment: &mcpv1alpha1.ProxyDeploymentOverrides{
+					ResourceMetadataOverrides: mcpv1alpha1.ResourceMetadataOverrides{
+						Labels: map[string]string{"team": "deployment-team"},
+					},
+					PodTemplateMetadataOverrides: &mcpv1alpha1.ResourceMetadataOverrides{
+						Labels:      map[string]string{"team": "pod-team"},
+						Annotations: map[string]string{"scrape": "true"},
+					},
+				},
+				ProxyService: &mcpv1alpha1.ResourceMetadataOverrides{
+					Annotations: map[string]string{"owner": "service@example.com"},
+				},
+			},
+			expectedDeployLbl: map[string]string{
+				"cost-center": "cc-123",
+				"team":        "deployment-team",
+			},
+			expectedPodLbl: map[string]string{
+				"cost-center": "cc-123",
+				"team":        "pod-team",
+			},
+			expectedPodAnns: map[string]string{
+				"owner":  "platform@example.com",
+				"scrape": "true",
+			},
+			expectedSvcAnns: map[string]string{
+				"owner": "service@example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("common-metadata", "default")
+			mcpServer.Spec.CommonLabels = map[string]string{
+				"cost-center": "cc-123",
+				"team":        "platform",
+				"toolhive":    "should-not-clobber",
+			}
+			mcpServer.Spec.CommonAnnotations = map[string]string{
+				"owner": "platform@example.com",
+			}
+			mcpServer.Spec.ResourceOverrides = tt.resourceOverrides
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+			require.NotNil(t, dep)
+			svc := r.serviceForMCPServer(ctx, mcpServer)
+			require.NotNil(t, svc)
+
+			// Operator-managed labels are never clobbered by common labels
+			for k, v := range labelsForMCPServer(mcpServer.Name) {
+				assert.Equal(t, v, dep.Labels[k])
+				assert.Equal(t, v, dep.Spec.Template.Labels[k])
+				assert.Equal(t, v, svc.Labels[k])
+			}
+			assert.Equal(t, labelsForMCPServer(mcpServer.Name), dep.Spec.Selector.MatchLabels)
+			assert.Equal(t, labelsForMCPServer(mcpServer.Name), svc.Spec.Selector)
+
+			for k, v := range tt.expectedDeployLbl {
+				assert.Equal(t, v, dep.Labels[k], "deployment label %s", k)
+			}
+			for k, v := range tt.expectedPodLbl {
+				assert.Equal(t, v, dep.Spec.Template.Labels[k], "pod template label %s", k)
+			}
+			assert.Equal(t, "cc-123", svc.Labels["cost-center"])
+
+			assert.Equal(t, "platform@example.com", dep.Annotations["owner"])
+			for k, v := range tt.expectedPodAnns {
+				assert.Equal(t, v, dep.Spec.Template.Annotations[k], "pod template annotation %s", k)
+			}
+			assert.Equal(t, "test-checksum", dep.Spec.Template.Annotations[checksum.RunConfigChecksumAnnotation])
+			for k, v := range tt.expectedSvcAnns {
+				assert.Equal(t, v, svc.Annotations[k], "service annotation %s", k)
+			}
+
+			// Freshly built resources must not be reported as drifted
+			assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+			assert.False(t, serviceNeedsUpdate(svc, mcpServer))
+
+			// Changing the common labels must be detected as drift
+			mcpServer.Spec.CommonLabels["cost-center"] = "cc-456"
+			assert.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+			assert.True(t, serviceNeedsUpdate(svc, mcpServer))
+		})
+	}
+}
+
+func TestCommonAnnotationRemoval(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("common-metadata", "default")
+	mcpServer.Spec.CommonAnnotations = map[string]string{
+		"owner":       "platform@example.com",
+		"cost-center": "cc-123",
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+	svc := r.serviceForMCPServer(ctx, mcpServer)
+	require.NotNil(t, svc)
+
+	delete(mcpServer.Spec.CommonAnnotations, "cost-center")
+	require.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+	require.True(t, serviceNeedsUpdate(svc, mcpServer))
+
+	// The stale annotation is dropped by the update, which then settles
+	r.updateDeploymentForMCPServer(ctx, dep, mcpServer, "test-checksum")
+	assert.NotContains(t, dep.Annotations, "cost-center")
+	assert.Equal(t, "platform@example.com", dep.Annotations["owner"])
+	assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+
+	svc.Annotations = r.serviceForMCPServer(ctx, mcpServer).Annotations
+	assert.NotContains(t, svc.Annotations, "cost-center")
+	assert.False(t, serviceNeedsUpdate(svc, mcpServer))
+}
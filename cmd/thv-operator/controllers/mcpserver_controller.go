@@ -14,7 +14,10 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -103,6 +106,9 @@ var remoteProxyRBACRules = []rbacv1.PolicyRule{
 // mcpContainerName is the name of the mcp container used in pod templates
 const mcpContainerName = "mcp"
 
+// MCPServerFinalizerName is the name of the finalizer for MCPServer
+const MCPServerFinalizerName = "mcpserver.toolhive.stacklok.dev/finalizer"
+
 // Restart annotation keys for triggering pod restart
 const (
 	RestartedAtAnnotationKey          = "mcpserver.toolhive.stacklok.dev/restarted-at"
@@ -147,6 +153,9 @@ func (r *MCPServerReconciler) detectPlatform(ctx context.Context) (kubernetes.Pl
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete;apply
 // +kubebuilder:rbac:groups="",resources=pods/attach,verbs=create;get
 // +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=create;delete;get;list;patch;update;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -182,6 +191,15 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Check if the GroupRef is valid if specified
 	r.validateGroupRef(ctx, mcpServer)
 
+	// Check that the transport matches the declared port configuration
+	r.validateTransportConfig(ctx, mcpServer)
+
+	// Check the Vault Agent configuration, which would otherwise only fail at pod injection time
+	r.validateVaultAgentConfig(ctx, mcpServer)
+
+	// Check the values of the Kubernetes secrets declaring a minimum length or a pattern
+	r.validateSecretValues(ctx, mcpServer)
+
 	// Validate PodTemplateSpec early - before other validations
 	// This ensures we fail fast if the spec is invalid
 	if !r.validateAndUpdatePodTemplateStatus(ctx, mcpServer) {
@@ -264,7 +282,7 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Check if the MCPServer instance is marked to be deleted
 	if mcpServer.GetDeletionTimestamp() != nil {
 		// The object is being deleted
-		if controllerutil.ContainsFinalizer(mcpServer, "mcpserver.toolhive.stacklok.dev/finalizer") {
+		if controllerutil.ContainsFinalizer(mcpServer, MCPServerFinalizerName) {
 			// Run finalization logic. If the finalization logic fails,
 			// don't remove the finalizer so that we can retry during the next reconciliation.
 			if err := r.finalizeMCPServer(ctx, mcpServer); err != nil {
@@ -272,7 +290,7 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			}
 
 			// Remove the finalizer. Once all finalizers have been removed, the object will be deleted.
-			controllerutil.RemoveFinalizer(mcpServer, "mcpserver.toolhive.stacklok.dev/finalizer")
+			controllerutil.RemoveFinalizer(mcpServer, MCPServerFinalizerName)
 			err := r.Update(ctx, mcpServer)
 			if err != nil {
 				return ctrl.Result{}, err
@@ -282,8 +300,8 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	// Add finalizer for this CR
-	if !controllerutil.ContainsFinalizer(mcpServer, "mcpserver.toolhive.stacklok.dev/finalizer") {
-		controllerutil.AddFinalizer(mcpServer, "mcpserver.toolhive.stacklok.dev/finalizer")
+	if !controllerutil.ContainsFinalizer(mcpServer, MCPServerFinalizerName) {
+		controllerutil.AddFinalizer(mcpServer, MCPServerFinalizerName)
 		err = r.Update(ctx, mcpServer)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -351,8 +369,9 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
-	// Ensure the deployment size is the same as the spec
-	if *deployment.Spec.Replicas != 1 {
+	// Ensure the deployment size is the same as the spec, unless the
+	// replica count is managed by a HorizontalPodAutoscaler
+	if mcpServer.Spec.Autoscaling == nil && *deployment.Spec.Replicas != 1 {
 		deployment.Spec.Replicas = int32Ptr(1)
 		err = r.Update(ctx, deployment)
 		if err != nil {
@@ -389,6 +408,24 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Ensure the PodDisruptionBudget matches the spec
+	if err := r.ensurePodDisruptionBudget(ctx, mcpServer); err != nil {
+		ctxLogger.Error(err, "Failed to ensure PodDisruptionBudget")
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the HorizontalPodAutoscaler matches the spec
+	if err := r.ensureHorizontalPodAutoscaler(ctx, mcpServer); err != nil {
+		ctxLogger.Error(err, "Failed to ensure HorizontalPodAutoscaler")
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the Ingress exposing the proxy service matches the spec
+	if err := r.ensureIngress(ctx, mcpServer); err != nil {
+		ctxLogger.Error(err, "Failed to ensure Ingress")
+		return ctrl.Result{}, err
+	}
+
 	// Update the MCPServer status with the service URL including transport-specific path
 	if mcpServer.Status.URL == "" {
 		host := fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, mcpServer.Namespace)
@@ -409,8 +446,7 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Check if the deployment spec changed
 	if r.deploymentNeedsUpdate(ctx, deployment, mcpServer, runConfigChecksum) {
 		// Update the deployment
-		newDeployment := r.deploymentForMCPServer(ctx, mcpServer, runConfigChecksum)
-		deployment.Spec = newDeployment.Spec
+		r.updateDeploymentForMCPServer(ctx, deployment, mcpServer, runConfigChecksum)
 		err = r.Update(ctx, deployment)
 		if err != nil {
 			ctxLogger.Error(err, "Failed to update Deployment",
@@ -427,6 +463,8 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		// Update the service
 		newService := r.serviceForMCPServer(ctx, mcpServer)
 		service.Spec.Ports = newService.Spec.Ports
+		service.Labels = newService.Labels
+		service.Annotations = newService.Annotations
 		err = r.Update(ctx, service)
 		if err != nil {
 			ctxLogger.Error(err, "Failed to update Service", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
@@ -904,10 +942,18 @@ func (r *MCPServerReconciler) deploymentForMCPServer(
 ) *appsv1.Deployment {
 	ls := labelsForMCPServer(m.Name)
 	replicas := int32(1)
+	if m.Spec.Autoscaling != nil {
+		replicas = autoscalingMinReplicas(m)
+	}
 
 	// Prepare container args
 	args := []string{"run"}
 
+	if m.Spec.EntrypointOverride != nil && len(m.Spec.Args) > 0 {
+		log.FromContext(ctx).Info("EntrypointOverride is set, skipping the MCP server args from the spec",
+			"mcpserver", m.Name)
+	}
+
 	// Prepare container volume mounts
 	volumeMounts := []corev1.VolumeMount{}
 	volumes := []corev1.Volume{}
@@ -930,6 +976,8 @@ func (r *MCPServerReconciler) deploymentForMCPServer(
 		finalPodTemplateSpec := builder.
 			WithServiceAccount(serviceAccount).
 			WithSecrets(m.Spec.Secrets).
+			WithEntrypointOverride(m.Spec.EntrypointOverride).
+			WithImagePullPolicy(imagePullPolicyForMCPServer(m)).
 			Build()
 		// Add pod template patch if we have one
 		if finalPodTemplateSpec != nil {
@@ -975,10 +1023,8 @@ func (r *MCPServerReconciler) deploymentForMCPServer(
 	env := []corev1.EnvVar{}
 
 	// Add OpenTelemetry environment variables
-	if m.Spec.Telemetry != nil && m.Spec.Telemetry.OpenTelemetry != nil {
-		otelEnvVars := ctrlutil.GenerateOpenTelemetryEnvVars(m.Spec.Telemetry, m.Name, m.Namespace)
-		env = append(env, otelEnvVars...)
-	}
+	telemetryConfig := resolveTelemetryConfig(ctx, r.Client, m.Namespace, m.Spec.Telemetry)
+	env = append(env, ctrlutil.GenerateOpenTelemetryEnvVars(telemetryConfig, m.Name, m.Namespace)...)
 
 	// Add token exchange environment variables
 	if m.Spec.ExternalAuthConfigRef != nil {
@@ -1006,6 +1052,9 @@ func (r *MCPServerReconciler) deploymentForMCPServer(
 		}
 	}
 
+	// Add the default secrets label selector configured on the operator
+	env = append(env, secretsLabelSelectorEnvVars(ctx)...)
+
 	// Add user-specified proxy environment variables from ResourceOverrides
 	if m.Spec.ResourceOverrides != nil && m.Spec.ResourceOverrides.ProxyDeployment != nil {
 		for _, envVar := range m.Spec.ResourceOverrides.ProxyDeployment.Env {
@@ -1082,41 +1131,40 @@ func (r *MCPServerReconciler) deploymentForMCPServer(
 		}
 	}
 
-	// Prepare deployment metadata with overrides
-	deploymentLabels := ls
-	deploymentAnnotations := make(map[string]string)
+	// Prepare deployment metadata with common metadata and overrides
+	deploymentLabels := commonLabelsForMCPServer(m)
+	deploymentAnnotations := commonAnnotationsForMCPServer(m)
 
-	deploymentTemplateLabels := ls
-	deploymentTemplateAnnotations := make(map[string]string)
+	deploymentTemplateLabels := commonLabelsForMCPServer(m)
 
-	// Add RunConfig checksum annotation to trigger pod rollout when config changes
-	deploymentTemplateAnnotations = checksum.AddRunConfigChecksumToPodTemplate(deploymentTemplateAnnotations, runConfigChecksum)
+	// The secrets checksum annotation triggers a pod rollout when referenced secrets change
+	secretsChecksum, err := r.getSecretsChecksum(ctx, m)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to compute secrets checksum", "mcpserver", m.Name)
+	}
+	deploymentTemplateAnnotations := podTemplateAnnotationsForMCPServer(m, runConfigChecksum, secretsChecksum)
 
 	if m.Spec.ResourceOverrides != nil && m.Spec.ResourceOverrides.ProxyDeployment != nil {
 		if m.Spec.ResourceOverrides.ProxyDeployment.Labels != nil {
-			deploymentLabels = ctrlutil.MergeLabels(ls, m.Spec.ResourceOverrides.ProxyDeployment.Labels)
+			deploymentLabels = ctrlutil.MergeLabels(ls,
+				withCommonMetadata(m.Spec.CommonLabels, m.Spec.ResourceOverrides.ProxyDeployment.Labels))
 		}
 		if m.Spec.ResourceOverrides.ProxyDeployment.Annotations != nil {
 			deploymentAnnotations = ctrlutil.MergeAnnotations(
 				make(map[string]string),
-				m.Spec.ResourceOverrides.ProxyDeployment.Annotations,
+				withCommonMetadata(m.Spec.CommonAnnotations, m.Spec.ResourceOverrides.ProxyDeployment.Annotations),
 			)
 		}
 
 		if m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides != nil {
 			if m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Labels != nil {
 				deploymentTemplateLabels = ctrlutil.MergeLabels(ls,
-					m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Labels)
-			}
-			if m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Annotations != nil {
-				deploymentTemplateAnnotations = ctrlutil.MergeAnnotations(deploymentAnnotations,
-					m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Annotations)
+					withCommonMetadata(m.Spec.CommonLabels,
+						m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Labels))
 			}
 		}
 	}
 
-	// Vault Agent Injection is handled via the runconfig.json in ConfigMap mode
-
 	// Detect platform and prepare ProxyRunner's pod and container security context
 	detectedPlatform, err := r.detectPlatform(ctx)
 	if err != nil {
@@ -1144,6 +1192,7 @@ func (r *MCPServerReconciler) deploymentForMCPServer(
 			Selector: &metav1.LabelSelector{
 				MatchLabels: ls, // Keep original labels for selector
 			},
+			Strategy: deploymentStrategyForMCPServer(m),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      deploymentTemplateLabels,
@@ -1213,16 +1262,18 @@ func (r *MCPServerReconciler) serviceForMCPServer(ctx context.Context, m *mcpv1a
 	// to avoid conflicts with the headless service
 	svcName := ctrlutil.CreateProxyServiceName(m.Name)
 
-	// Prepare service metadata with overrides
-	serviceLabels := ls
-	serviceAnnotations := make(map[string]string)
+	// Prepare service metadata with common metadata and overrides
+	serviceLabels := commonLabelsForMCPServer(m)
+	serviceAnnotations := commonAnnotationsForMCPServer(m)
 
 	if m.Spec.ResourceOverrides != nil && m.Spec.ResourceOverrides.ProxyService != nil {
 		if m.Spec.ResourceOverrides.ProxyService.Labels != nil {
-			serviceLabels = ctrlutil.MergeLabels(ls, m.Spec.ResourceOverrides.ProxyService.Labels)
+			serviceLabels = ctrlutil.MergeLabels(ls,
+				withCommonMetadata(m.Spec.CommonLabels, m.Spec.ResourceOverrides.ProxyService.Labels))
 		}
 		if m.Spec.ResourceOverrides.ProxyService.Annotations != nil {
-			serviceAnnotations = ctrlutil.MergeAnnotations(make(map[string]string), m.Spec.ResourceOverrides.ProxyService.Annotations)
+			serviceAnnotations = ctrlutil.MergeAnnotations(make(map[string]string),
+				withCommonMetadata(m.Spec.CommonAnnotations, m.Spec.ResourceOverrides.ProxyService.Annotations))
 		}
 	}
 
@@ -1305,7 +1356,6 @@ func (r *MCPServerReconciler) updateMCPServerStatus(ctx context.Context, m *mcpv
 
 // finalizeMCPServer performs the finalizer logic for the MCPServer
 func (r *MCPServerReconciler) finalizeMCPServer(ctx context.Context, m *mcpv1alpha1.MCPServer) error {
-	ctxLogger := log.FromContext(ctx)
 	// Update the MCPServer status
 	m.Status.Phase = mcpv1alpha1.MCPServerPhaseTerminating
 	m.Status.Message = "MCP server is being terminated"
@@ -1313,47 +1363,97 @@ func (r *MCPServerReconciler) finalizeMCPServer(ctx context.Context, m *mcpv1alp
 		return err
 	}
 
-	// Step 2: Attempt to delete associated StatefulSet by name
-	sts := &appsv1.StatefulSet{}
-	err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, sts)
-	if err == nil {
-		// StatefulSet found, delete it
-		if delErr := r.Delete(ctx, sts); delErr != nil && !errors.IsNotFound(delErr) {
-			return fmt.Errorf("failed to delete StatefulSet %s: %w", m.Name, delErr)
+	return r.cleanup(ctx, m)
+}
+
+// cleanup removes the resources derived from the MCPServer. Owner references would
+// eventually garbage collect most of them, but deleting them explicitly before the
+// finalizer is removed makes teardown deterministic and also covers the resources
+// created by the proxy runner that are not owned by the MCPServer. The optional
+// PodDisruptionBudget, HorizontalPodAutoscaler and Ingress are only deleted if they are
+// controlled by the MCPServer, as ensuring them does, so that same-named objects created
+// by users are left alone.
+func (r *MCPServerReconciler) cleanup(ctx context.Context, m *mcpv1alpha1.MCPServer) error {
+	ctxLogger := log.FromContext(ctx)
+
+	resources := []struct {
+		kind      string
+		obj       client.Object
+		name      string
+		ownedOnly bool
+	}{
+		// StatefulSet and headless Service created by the proxy runner
+		{kind: "StatefulSet", obj: &appsv1.StatefulSet{}, name: m.Name},
+		{kind: "Service", obj: &corev1.Service{}, name: fmt.Sprintf("mcp-%s-headless", m.Name)},
+		// Resources created by the operator
+		{kind: "Deployment", obj: &appsv1.Deployment{}, name: m.Name},
+		{kind: "Service", obj: &corev1.Service{}, name: ctrlutil.CreateProxyServiceName(m.Name)},
+		{kind: "PodDisruptionBudget", obj: &policyv1.PodDisruptionBudget{}, name: m.Name, ownedOnly: true},
+		{kind: "HorizontalPodAutoscaler", obj: &autoscalingv2.HorizontalPodAutoscaler{}, name: m.Name, ownedOnly: true},
+		{kind: "Ingress", obj: &networkingv1.Ingress{}, name: m.Name, ownedOnly: true},
+		{kind: "RunConfig ConfigMap", obj: &corev1.ConfigMap{}, name: fmt.Sprintf("%s-runconfig", m.Name)},
+		{kind: "authorization ConfigMap", obj: &corev1.ConfigMap{}, name: fmt.Sprintf("%s-authz-inline", m.Name)},
+	}
+
+	for _, res := range resources {
+		var owner metav1.Object
+		if res.ownedOnly {
+			owner = m
+		}
+		deleted, err := r.deleteIfExists(ctx, res.obj, res.name, m.Namespace, owner)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s %s: %w", res.kind, res.name, err)
+		}
+		if deleted {
+			ctxLogger.Info("Deleted resource", "kind", res.kind, "name", res.name, "namespace", m.Namespace)
 		}
-	} else if !errors.IsNotFound(err) {
-		// Unexpected error (not just "not found")
-		return fmt.Errorf("failed to get StatefulSet %s: %w", m.Name, err)
 	}
 
-	// Step 3: Attempt to delete associated service by name
-	svc := &corev1.Service{}
-	serviceName := fmt.Sprintf("mcp-%s-headless", m.Name)
-	err = r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: m.Namespace}, svc)
-	if err == nil {
-		if delErr := r.Delete(ctx, svc); delErr != nil && !errors.IsNotFound(delErr) {
-			return fmt.Errorf("failed to delete Service %s: %w", serviceName, delErr)
+	return nil
+}
+
+// deleteIfExists deletes the named object if it exists and, when owner is not nil, is controlled
+// by owner. It reports whether a delete was issued.
+func (r *MCPServerReconciler) deleteIfExists(
+	ctx context.Context, obj client.Object, name, namespace string, owner metav1.Object,
+) (bool, error) {
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
 		}
-	} else if !errors.IsNotFound(err) {
-		return fmt.Errorf("failed to check Service %s: %w", serviceName, err)
+		return false, err
+	}
+	if owner != nil && !metav1.IsControlledBy(obj, owner) {
+		return false, nil
 	}
 
-	// Step 4: Delete associated RunConfig ConfigMap
-	runConfigName := fmt.Sprintf("%s-runconfig", m.Name)
-	runConfigMap := &corev1.ConfigMap{}
-	err = r.Get(ctx, types.NamespacedName{Name: runConfigName, Namespace: m.Namespace}, runConfigMap)
-	if err == nil {
-		if delErr := r.Delete(ctx, runConfigMap); delErr != nil && !errors.IsNotFound(delErr) {
-			return fmt.Errorf("failed to delete RunConfig ConfigMap %s: %w", runConfigName, delErr)
+	if err := r.Delete(ctx, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
 		}
-		ctxLogger.Info("Deleted RunConfig ConfigMap", "name", runConfigName, "namespace", m.Namespace)
-	} else if !errors.IsNotFound(err) {
-		return fmt.Errorf("failed to check RunConfig ConfigMap %s: %w", runConfigName, err)
+		return false, err
 	}
+	return true, nil
+}
 
-	// The owner references will automatically delete the deployment and service
-	// when the MCPServer is deleted, so we don't need to do anything here.
-	return nil
+// updateDeploymentForMCPServer rebuilds the spec and metadata of an existing deployment for the MCPServer.
+// The pod template and the metadata are replaced rather than merged, so the annotations previously rendered
+// by the operator are dropped once they no longer apply, e.g. the Vault Agent annotations after the vault
+// secrets are removed, or a common annotation after it is removed from the spec.
+func (r *MCPServerReconciler) updateDeploymentForMCPServer(
+	ctx context.Context,
+	deployment *appsv1.Deployment,
+	mcpServer *mcpv1alpha1.MCPServer,
+	runConfigChecksum string,
+) {
+	newDeployment := r.deploymentForMCPServer(ctx, mcpServer, runConfigChecksum)
+	if mcpServer.Spec.Autoscaling != nil {
+		// Keep the replica count chosen by the HorizontalPodAutoscaler
+		newDeployment.Spec.Replicas = deployment.Spec.Replicas
+	}
+	deployment.Spec = newDeployment.Spec
+	deployment.Labels = newDeployment.Labels
+	deployment.Annotations = newDeployment.Annotations
 }
 
 // deploymentNeedsUpdate checks if the deployment needs to be updated
@@ -1395,14 +1495,20 @@ func (r *MCPServerReconciler) deploymentNeedsUpdate(
 			return true
 		}
 
+		// Check if the OpenTelemetry environment variables have changed
+		telemetryConfig := resolveTelemetryConfig(ctx, r.Client, mcpServer.Namespace, mcpServer.Spec.Telemetry)
+		if !equalOpenTelemetryEnvVars(
+			telemetryConfig, mcpServer.Name, mcpServer.Namespace, mcpServer.Spec.ResourceOverrides, container.Env,
+		) {
+			return true
+		}
+
 		// Check if the proxy environment variables have changed
 		expectedProxyEnv := []corev1.EnvVar{}
 
 		// Add OpenTelemetry environment variables first
-		if mcpServer.Spec.Telemetry != nil && mcpServer.Spec.Telemetry.OpenTelemetry != nil {
-			otelEnvVars := ctrlutil.GenerateOpenTelemetryEnvVars(mcpServer.Spec.Telemetry, mcpServer.Name, mcpServer.Namespace)
-			expectedProxyEnv = append(expectedProxyEnv, otelEnvVars...)
-		}
+		expectedProxyEnv = append(expectedProxyEnv,
+			ctrlutil.GenerateOpenTelemetryEnvVars(telemetryConfig, mcpServer.Name, mcpServer.Namespace)...)
 
 		// Add token exchange environment variables
 		if mcpServer.Spec.ExternalAuthConfigRef != nil {
@@ -1431,6 +1537,9 @@ func (r *MCPServerReconciler) deploymentNeedsUpdate(
 			}
 		}
 
+		// Add the default secrets label selector configured on the operator
+		expectedProxyEnv = append(expectedProxyEnv, secretsLabelSelectorEnvVars(ctx)...)
+
 		// Add user-specified environment variables
 		if mcpServer.Spec.ResourceOverrides != nil && mcpServer.Spec.ResourceOverrides.ProxyDeployment != nil {
 			for _, envVar := range mcpServer.Spec.ResourceOverrides.ProxyDeployment.Env {
@@ -1463,6 +1572,8 @@ func (r *MCPServerReconciler) deploymentNeedsUpdate(
 		expectedPodTemplateSpec := builder.
 			WithServiceAccount(serviceAccount).
 			WithSecrets(mcpServer.Spec.Secrets).
+			WithEntrypointOverride(mcpServer.Spec.EntrypointOverride).
+			WithImagePullPolicy(imagePullPolicyForMCPServer(mcpServer)).
 			Build()
 
 		// Find the current pod template patch in the container args
@@ -1498,6 +1609,11 @@ func (r *MCPServerReconciler) deploymentNeedsUpdate(
 		}
 	}
 
+	// Check if the deployment strategy has changed
+	if !deploymentStrategyMatches(deployment.Spec.Strategy, mcpServer) {
+		return true
+	}
+
 	// Check if the service account name has changed
 	// ServiceAccountName: treat empty (not yet set) as equal to the expected default
 	expectedServiceAccountName := ctrlutil.ProxyRunnerServiceAccountName(mcpServer.Name)
@@ -1506,21 +1622,22 @@ func (r *MCPServerReconciler) deploymentNeedsUpdate(
 		return true
 	}
 
-	// Check if the deployment metadata (labels/annotations) have changed due to resource overrides
-	expectedLabels := labelsForMCPServer(mcpServer.Name)
-	expectedAnnotations := make(map[string]string)
+	// Check if the deployment metadata (labels/annotations) have changed due to
+	// common metadata or resource overrides
+	expectedLabels := commonLabelsForMCPServer(mcpServer)
+	expectedAnnotations := commonAnnotationsForMCPServer(mcpServer)
 
 	if mcpServer.Spec.ResourceOverrides != nil && mcpServer.Spec.ResourceOverrides.ProxyDeployment != nil {
 		if mcpServer.Spec.ResourceOverrides.ProxyDeployment.Labels != nil {
 			expectedLabels = ctrlutil.MergeLabels(
-				expectedLabels,
-				mcpServer.Spec.ResourceOverrides.ProxyDeployment.Labels,
+				labelsForMCPServer(mcpServer.Name),
+				withCommonMetadata(mcpServer.Spec.CommonLabels, mcpServer.Spec.ResourceOverrides.ProxyDeployment.Labels),
 			)
 		}
 		if mcpServer.Spec.ResourceOverrides.ProxyDeployment.Annotations != nil {
 			expectedAnnotations = ctrlutil.MergeAnnotations(
 				make(map[string]string),
-				mcpServer.Spec.ResourceOverrides.ProxyDeployment.Annotations,
+				withCommonMetadata(mcpServer.Spec.CommonAnnotations, mcpServer.Spec.ResourceOverrides.ProxyDeployment.Annotations),
 			)
 		}
 	}
@@ -1533,25 +1650,31 @@ func (r *MCPServerReconciler) deploymentNeedsUpdate(
 		return true
 	}
 
-	// Check if pod template annotations have changed (including runconfig checksum)
-	expectedPodTemplateAnnotations := make(map[string]string)
-	expectedPodTemplateAnnotations = checksum.AddRunConfigChecksumToPodTemplate(expectedPodTemplateAnnotations, runConfigChecksum)
+	// Check if pod template annotations have changed (including runconfig and secrets checksums)
+	secretsChecksum, err := r.getSecretsChecksum(ctx, mcpServer)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to compute secrets checksum", "mcpserver", mcpServer.Name)
+	}
+	expectedPodTemplateAnnotations := podTemplateAnnotationsForMCPServer(mcpServer, runConfigChecksum, secretsChecksum)
+
+	if !maps.Equal(deployment.Spec.Template.Annotations, expectedPodTemplateAnnotations) {
+		return true
+	}
 
+	// Check if pod template labels have changed due to common labels
+	expectedPodTemplateLabels := commonLabelsForMCPServer(mcpServer)
 	if mcpServer.Spec.ResourceOverrides != nil &&
 		mcpServer.Spec.ResourceOverrides.ProxyDeployment != nil &&
 		mcpServer.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides != nil &&
-		mcpServer.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Annotations != nil {
-		expectedPodTemplateAnnotations = ctrlutil.MergeAnnotations(
-			expectedPodTemplateAnnotations,
-			mcpServer.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Annotations,
+		mcpServer.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Labels != nil {
+		expectedPodTemplateLabels = ctrlutil.MergeLabels(
+			labelsForMCPServer(mcpServer.Name),
+			withCommonMetadata(mcpServer.Spec.CommonLabels,
+				mcpServer.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Labels),
 		)
 	}
 
-	if !maps.Equal(deployment.Spec.Template.Annotations, expectedPodTemplateAnnotations) {
-		return true
-	}
-
-	return false
+	return !maps.Equal(deployment.Spec.Template.Labels, expectedPodTemplateLabels)
 }
 
 // serviceNeedsUpdate checks if the service needs to be updated
@@ -1561,18 +1684,20 @@ func serviceNeedsUpdate(service *corev1.Service, mcpServer *mcpv1alpha1.MCPServe
 		return true
 	}
 
-	// Check if the service metadata (labels/annotations) have changed due to resource overrides
-	expectedLabels := labelsForMCPServer(mcpServer.Name)
-	expectedAnnotations := make(map[string]string)
+	// Check if the service metadata (labels/annotations) have changed due to
+	// common metadata or resource overrides
+	expectedLabels := commonLabelsForMCPServer(mcpServer)
+	expectedAnnotations := commonAnnotationsForMCPServer(mcpServer)
 
 	if mcpServer.Spec.ResourceOverrides != nil && mcpServer.Spec.ResourceOverrides.ProxyService != nil {
 		if mcpServer.Spec.ResourceOverrides.ProxyService.Labels != nil {
-			expectedLabels = ctrlutil.MergeLabels(expectedLabels, mcpServer.Spec.ResourceOverrides.ProxyService.Labels)
+			expectedLabels = ctrlutil.MergeLabels(labelsForMCPServer(mcpServer.Name),
+				withCommonMetadata(mcpServer.Spec.CommonLabels, mcpServer.Spec.ResourceOverrides.ProxyService.Labels))
 		}
 		if mcpServer.Spec.ResourceOverrides.ProxyService.Annotations != nil {
 			expectedAnnotations = ctrlutil.MergeAnnotations(
 				make(map[string]string),
-				mcpServer.Spec.ResourceOverrides.ProxyService.Annotations,
+				withCommonMetadata(mcpServer.Spec.CommonAnnotations, mcpServer.Spec.ResourceOverrides.ProxyService.Annotations),
 			)
 		}
 	}
@@ -1629,6 +1754,44 @@ func labelsForMCPServer(name string) map[string]string {
 	}
 }
 
+// commonLabelsForMCPServer returns the labels for resources belonging to the given MCPServer,
+// including the user supplied common labels. Operator-managed labels take precedence.
+func commonLabelsForMCPServer(m *mcpv1alpha1.MCPServer) map[string]string {
+	return ctrlutil.MergeLabels(labelsForMCPServer(m.Name), m.Spec.CommonLabels)
+}
+
+// commonAnnotationsForMCPServer returns the user supplied common annotations for resources
+// belonging to the given MCPServer.
+func commonAnnotationsForMCPServer(m *mcpv1alpha1.MCPServer) map[string]string {
+	return ctrlutil.MergeAnnotations(make(map[string]string), m.Spec.CommonAnnotations)
+}
+
+// podTemplateAnnotationsForMCPServer returns the annotations of the proxy pod template: the common
+// annotations, overridden by the pod template metadata overrides, and the annotations managed by the
+// operator, i.e. the checksums rolling the pods on changes and the Vault Agent annotations.
+// Vault Agent Injection is handled via the runconfig.json in ConfigMap mode, vault-typed secrets only
+// need the annotations rendering them into env files.
+func podTemplateAnnotationsForMCPServer(m *mcpv1alpha1.MCPServer, runConfigChecksum, secretsChecksum string) map[string]string {
+	annotations := commonAnnotationsForMCPServer(m)
+	if m.Spec.ResourceOverrides != nil &&
+		m.Spec.ResourceOverrides.ProxyDeployment != nil &&
+		m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides != nil &&
+		m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Annotations != nil {
+		annotations = ctrlutil.MergeAnnotations(make(map[string]string), withCommonMetadata(m.Spec.CommonAnnotations,
+			m.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Annotations))
+	}
+
+	annotations = checksum.AddRunConfigChecksumToPodTemplate(annotations, runConfigChecksum)
+	annotations = addSecretsChecksumToPodTemplate(annotations, secretsChecksum)
+	return addVaultSecretAnnotations(annotations, m.Spec.Secrets, m.Spec.VaultAgent)
+}
+
+// withCommonMetadata layers resource specific labels or annotations over the common ones,
+// so that resource specific values win when both define the same key.
+func withCommonMetadata(common, specific map[string]string) map[string]string {
+	return ctrlutil.MergeStringMaps(specific, common)
+}
+
 // labelsForInlineAuthzConfig returns the labels for inline authorization ConfigMaps
 // belonging to the given MCPServer CR name.
 func labelsForInlineAuthzConfig(name string) map[string]string {
@@ -1706,6 +1869,37 @@ func int32Ptr(i int32) *int32 {
 	return &i
 }
 
+// mapOpenTelemetryConfigMapToMCPServers maps a ConfigMap to the MCPServers referencing it
+// for their OpenTelemetry configuration
+func (r *MCPServerReconciler) mapOpenTelemetryConfigMapToMCPServers(
+	ctx context.Context, obj client.Object,
+) []reconcile.Request {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return nil
+	}
+
+	mcpServerList := &mcpv1alpha1.MCPServerList{}
+	if err := r.List(ctx, mcpServerList, client.InNamespace(configMap.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list MCPServers for ConfigMap watch")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, server := range mcpServerList.Items {
+		if referencesOpenTelemetryConfigMap(server.Spec.Telemetry, configMap.Name) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      server.Name,
+					Namespace: server.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *MCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Create a handler that maps MCPExternalAuthConfig changes to MCPServer reconciliation requests
@@ -1745,6 +1939,11 @@ func (r *MCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&mcpv1alpha1.MCPServer{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&networkingv1.Ingress{}).
 		Watches(&mcpv1alpha1.MCPExternalAuthConfig{}, externalAuthConfigHandler).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapSecretToMCPServers)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapOpenTelemetryConfigMapToMCPServers)).
 		Complete(r)
 }
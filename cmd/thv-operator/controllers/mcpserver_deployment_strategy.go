@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+// defaultRollingUpdateValue matches the Kubernetes default for maxSurge and maxUnavailable
+const defaultRollingUpdateValue = "25%"
+
+// deploymentStrategyForMCPServer returns the deployment strategy for the proxy deployment.
+// RollingUpdate parameters that are not set fall back to the Kubernetes defaults.
+func deploymentStrategyForMCPServer(m *mcpv1alpha1.MCPServer) appsv1.DeploymentStrategy {
+	if m.Spec.Strategy != nil && m.Spec.Strategy.Type == string(appsv1.RecreateDeploymentStrategyType) {
+		return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	}
+
+	maxSurge := intstr.FromString(defaultRollingUpdateValue)
+	maxUnavailable := intstr.FromString(defaultRollingUpdateValue)
+	if m.Spec.Strategy != nil && m.Spec.Strategy.RollingUpdate != nil {
+		if m.Spec.Strategy.RollingUpdate.MaxSurge != nil {
+			maxSurge = *m.Spec.Strategy.RollingUpdate.MaxSurge
+		}
+		if m.Spec.Strategy.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = *m.Spec.Strategy.RollingUpdate.MaxUnavailable
+		}
+	}
+
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &maxSurge,
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+}
+
+// deploymentStrategyMatches checks if the deployment strategy matches the one expected for the MCPServer.
+// A deployment without a strategy type (not yet defaulted by the API server) is treated as using
+// the default strategy.
+func deploymentStrategyMatches(current appsv1.DeploymentStrategy, m *mcpv1alpha1.MCPServer) bool {
+	if current.Type == "" {
+		return m.Spec.Strategy == nil
+	}
+	return equality.Semantic.DeepEqual(current, deploymentStrategyForMCPServer(m))
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestMCPServerDeploymentStrategy(t *testing.T) {
+	t.Parallel()
+
+	maxSurge := intstr.FromInt32(1)
+	maxUnavailable := intstr.FromInt32(0)
+	defaultValue := intstr.FromString("25%")
+
+	tests := []struct {
+		name                   string
+		strategy               *mcpv1alpha1.DeploymentStrategy
+		expectedType           appsv1.DeploymentStrategyType
+		expectedMaxSurge       *intstr.IntOrString
+		expectedMaxUnavailable *intstr.IntOrString
+	}{
+		{
+			name:                   "defaults to rolling update",
+			strategy:               nil,
+			expectedType:           appsv1.RollingUpdateDeploymentStrategyType,
+			expectedMaxSurge:       &defaultValue,
+			expectedMaxUnavailable: &defaultValue,
+		},
+		{
+			name: "rolling update with custom parameters",
+			strategy: &mcpv1alpha1.DeploymentStrategy{
+				Type: "RollingUpdate",
+				RollingUpdate: &mcpv1alpha1.RollingUpdateConfig{
+					MaxSurge:       &maxSurge,
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+			expectedType:           appsv1.RollingUpdateDeploymentStrategyType,
+			expectedMaxSurge:       &maxSurge,
+			expectedMaxUnavailable: &maxUnavailable,
+		},
+		{
+			name: "rolling update with partial parameters",
+			strategy: &mcpv1alpha1.DeploymentStrategy{
+				RollingUpdate: &mcpv1alpha1.RollingUpdateConfig{
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+			expectedType:           appsv1.RollingUpdateDeploymentStrategyType,
+			expectedMaxSurge:       &defaultValue,
+			expectedMaxUnavailable: &maxUnavailable,
+		},
+		{
+			name:         "recreate for stateful servers",
+			strategy:     &mcpv1alpha1.DeploymentStrategy{Type: "Recreate"},
+			expectedType: appsv1.RecreateDeploymentStrategyType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("strategy-server", "default")
+			mcpServer.Spec.Strategy = tt.strategy
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+			require.NotNil(t, dep)
+
+			strategy := dep.Spec.Strategy
+			assert.Equal(t, tt.expectedType, strategy.Type)
+			if tt.expectedType == appsv1.RecreateDeploymentStrategyType {
+				assert.Nil(t, strategy.RollingUpdate)
+			} else {
+				require.NotNil(t, strategy.RollingUpdate)
+				assert.Equal(t, tt.expectedMaxSurge, strategy.RollingUpdate.MaxSurge)
+				assert.Equal(t, tt.expectedMaxUnavailable, strategy.RollingUpdate.MaxUnavailable)
+			}
+
+			assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"),
+				"Deployment generated with the strategy should not need an update")
+		})
+	}
+}
+
+func TestMCPServerDeploymentStrategyChangeNeedsUpdate(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("strategy-server", "default")
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+
+	mcpServer.Spec.Strategy = &mcpv1alpha1.DeploymentStrategy{Type: "Recreate"}
+	assert.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"),
+		"Switching to Recreate should require an update")
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestMCPServerEntrypointOverride(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		override        *mcpv1alpha1.EntrypointOverride
+		expectedCommand []string
+		expectedCmdArgs []string
+	}{
+		{
+			name: "override replaces command and args",
+			override: &mcpv1alpha1.EntrypointOverride{
+				Command: []string{"/bin/custom-server"},
+				Args:    []string{"--listen", "stdio"},
+			},
+			expectedCommand: []string{"/bin/custom-server"},
+			expectedCmdArgs: []string{"--listen", "stdio"},
+		},
+		{
+			name: "override with only args replaces the spec args",
+			override: &mcpv1alpha1.EntrypointOverride{
+				Args: []string{"serve"},
+			},
+			expectedCommand: nil,
+			expectedCmdArgs: []string{"serve"},
+		},
+		{
+			name:            "no override preserves the spec args",
+			override:        nil,
+			expectedCommand: nil,
+			expectedCmdArgs: []string{"--verbose", "--port=9000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("entrypoint-server", "default")
+			mcpServer.Spec.Args = []string{"--verbose", "--port=9000"}
+			mcpServer.Spec.EntrypointOverride = tt.override
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			runConfig, err := r.createRunConfigFromMCPServer(mcpServer)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCmdArgs, runConfig.CmdArgs)
+
+			dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+			require.NotNil(t, dep)
+
+			var podTemplatePatch string
+			for _, arg := range dep.Spec.Template.Spec.Containers[0].Args {
+				if strings.HasPrefix(arg, "--k8s-pod-patch=") {
+					podTemplatePatch = strings.TrimPrefix(arg, "--k8s-pod-patch=")
+					break
+				}
+			}
+			require.NotEmpty(t, podTemplatePatch, "Pod template patch should be present in args")
+
+			var podTemplateSpec corev1.PodTemplateSpec
+			require.NoError(t, json.Unmarshal([]byte(podTemplatePatch), &podTemplateSpec))
+
+			var command []string
+			for _, container := range podTemplateSpec.Spec.Containers {
+				if container.Name == mcpContainerName {
+					command = container.Command
+				}
+			}
+			assert.Equal(t, tt.expectedCommand, command)
+
+			assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"),
+				"Deployment generated with the override should not need an update")
+		})
+	}
+}
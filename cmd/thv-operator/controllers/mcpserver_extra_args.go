@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+// flagName returns the name of the flag an argument sets, if it is a flag
+func flagName(arg string) (string, bool) {
+	if !strings.HasPrefix(arg, "-") || arg == "-" || arg == "--" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(arg, "=")
+	return name, true
+}
+
+// extraArgsForMCPServer returns the extra args to append to the MCP server args.
+// Extra args setting a flag already set by the MCP server args are dropped with a warning,
+// along with their value when it is passed as a separate argument.
+func extraArgsForMCPServer(ctx context.Context, m *mcpv1alpha1.MCPServer, serverArgs []string) []string {
+	reserved := make(map[string]bool)
+	for _, arg := range serverArgs {
+		if name, ok := flagName(arg); ok {
+			reserved[name] = true
+		}
+	}
+
+	var extraArgs []string
+	for i := 0; i < len(m.Spec.ExtraArgs); i++ {
+		arg := m.Spec.ExtraArgs[i]
+		if name, ok := flagName(arg); ok && reserved[name] {
+			log.FromContext(ctx).Info("Ignoring extra arg that sets a flag already set by the MCP server args",
+				"flag", name, "mcpserver", m.Name)
+			if !strings.Contains(arg, "=") && i+1 < len(m.Spec.ExtraArgs) {
+				if _, isFlag := flagName(m.Spec.ExtraArgs[i+1]); !isFlag {
+					i++
+				}
+			}
+			continue
+		}
+		extraArgs = append(extraArgs, arg)
+	}
+	return extraArgs
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestCreateRunConfigFromMCPServer_ExtraArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		args            []string
+		override        *mcpv1alpha1.EntrypointOverride
+		extraArgs       []string
+		expectedCmdArgs []string
+	}{
+		{
+			name:            "no extra args",
+			args:            []string{"--verbose"},
+			extraArgs:       nil,
+			expectedCmdArgs: []string{"--verbose"},
+		},
+		{
+			name:            "extra args appended in order",
+			args:            []string{"--verbose"},
+			extraArgs:       []string{"--foo", "bar", "--baz=qux"},
+			expectedCmdArgs: []string{"--verbose", "--foo", "bar", "--baz=qux"},
+		},
+		{
+			name:            "extra args without spec args",
+			extraArgs:       []string{"--foo"},
+			expectedCmdArgs: []string{"--foo"},
+		},
+		{
+			name:            "extra args appended after the entrypoint override args",
+			args:            []string{"--verbose"},
+			override:        &mcpv1alpha1.EntrypointOverride{Args: []string{"serve"}},
+			extraArgs:       []string{"--foo"},
+			expectedCmdArgs: []string{"serve", "--foo"},
+		},
+		{
+			name:            "flags already set by the args are rejected",
+			args:            []string{"--port=9000", "--log-level", "info"},
+			extraArgs:       []string{"--foo", "--port=9001", "--log-level", "debug", "--bar"},
+			expectedCmdArgs: []string{"--port=9000", "--log-level", "info", "--foo", "--bar"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mcpServer := createTestMCPServer("extra-args", "default")
+			mcpServer.Spec.Args = tt.args
+			mcpServer.Spec.EntrypointOverride = tt.override
+			mcpServer.Spec.ExtraArgs = tt.extraArgs
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			runConfig, err := r.createRunConfigFromMCPServer(mcpServer)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCmdArgs, runConfig.CmdArgs)
+
+			// The spec args must not be modified by appending the extra args
+			assert.Equal(t, tt.args, mcpServer.Spec.Args)
+		})
+	}
+}
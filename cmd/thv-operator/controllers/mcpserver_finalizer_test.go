@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	ctrlutil "github.com/stacklok/toolhive/cmd/thv-operator/pkg/controllerutil"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestMCPServerReconciler_AddsFinalizerOnCreate(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("finalizer-create", "default")
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(mcpServer).
+		WithStatusSubresource(&mcpv1alpha1.MCPServer{}).
+		Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mcpServer)})
+	require.NoError(t, err)
+
+	updated := &mcpv1alpha1.MCPServer{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(mcpServer), updated))
+	assert.Contains(t, updated.Finalizers, MCPServerFinalizerName, "Finalizer should be added on create")
+}
+
+func TestMCPServerReconciler_DeletionCleansUpAndRemovesFinalizer(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	name := "finalizer-delete"
+	namespace := "default"
+	now := metav1.Now()
+	mcpServer := createTestMCPServer(name, namespace)
+	mcpServer.UID = "finalizer-delete-uid"
+	mcpServer.Finalizers = []string{MCPServerFinalizerName}
+	mcpServer.DeletionTimestamp = &now
+
+	objectMeta := func(n string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: n, Namespace: namespace}
+	}
+	ownedObjectMeta := func(n string) metav1.ObjectMeta {
+		meta := objectMeta(n)
+		meta.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(mcpServer, mcpv1alpha1.GroupVersion.WithKind("MCPServer")),
+		}
+		return meta
+	}
+	derived := []client.Object{
+		&appsv1.Deployment{ObjectMeta: objectMeta(name)},
+		&appsv1.StatefulSet{ObjectMeta: objectMeta(name)},
+		&corev1.Service{ObjectMeta: objectMeta(ctrlutil.CreateProxyServiceName(name))},
+		&corev1.Service{ObjectMeta: objectMeta(fmt.Sprintf("mcp-%s-headless", name))},
+		&policyv1.PodDisruptionBudget{ObjectMeta: ownedObjectMeta(name)},
+		&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: ownedObjectMeta(name)},
+		&networkingv1.Ingress{ObjectMeta: ownedObjectMeta(name)},
+		&corev1.ConfigMap{ObjectMeta: objectMeta(fmt.Sprintf("%s-runconfig", name))},
+		&corev1.ConfigMap{ObjectMeta: objectMeta(fmt.Sprintf("%s-authz-inline", name))},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(append([]client.Object{mcpServer}, derived...)...).
+		WithStatusSubresource(&mcpv1alpha1.MCPServer{}).
+		Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mcpServer)})
+	require.NoError(t, err)
+
+	for _, obj := range derived {
+		key := client.ObjectKeyFromObject(obj)
+		err := fakeClient.Get(ctx, key, obj)
+		assert.True(t, errors.IsNotFound(err), "%T %s should have been deleted", obj, key.Name)
+	}
+
+	// Removing the last finalizer lets the API server complete the deletion
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &mcpv1alpha1.MCPServer{})
+	assert.True(t, errors.IsNotFound(err), "MCPServer should be gone once the finalizer is removed")
+}
+
+func TestMCPServerReconciler_CleanupIgnoresMissingResources(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("finalizer-missing", "default")
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(mcpServer).
+		Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	require.NoError(t, r.cleanup(ctx, mcpServer))
+}
+
+func TestMCPServerReconciler_CleanupKeepsUnownedResources(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("finalizer-unowned", "default")
+	mcpServer.UID = "finalizer-unowned-uid"
+	objectMeta := metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace}
+	unowned := []client.Object{
+		&policyv1.PodDisruptionBudget{ObjectMeta: objectMeta},
+		&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: objectMeta},
+		&networkingv1.Ingress{ObjectMeta: objectMeta},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(append([]client.Object{mcpServer}, unowned...)...).
+		Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	require.NoError(t, r.cleanup(ctx, mcpServer))
+
+	for _, obj := range unowned {
+		key := client.ObjectKeyFromObject(obj)
+		assert.NoError(t, fakeClient.Get(ctx, key, obj), "%T %s not owned by the MCPServer should be kept", obj, key.Name)
+	}
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+const (
+	// defaultAutoscalingMinReplicas is the minimum number of replicas used when none is specified
+	defaultAutoscalingMinReplicas int32 = 1
+
+	// defaultAutoscalingTargetCPUUtilization is the target CPU utilization used when none is specified
+	defaultAutoscalingTargetCPUUtilization int32 = 80
+)
+
+// ensureHorizontalPodAutoscaler creates, updates or deletes the HorizontalPodAutoscaler for the
+// proxy deployment so that it matches the MCPServer spec.
+func (r *MCPServerReconciler) ensureHorizontalPodAutoscaler(ctx context.Context, m *mcpv1alpha1.MCPServer) error {
+	ctxLogger := log.FromContext(ctx)
+
+	current := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, current)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get HorizontalPodAutoscaler: %w", err)
+	}
+	exists := err == nil
+
+	// Autoscaling disabled, remove any HorizontalPodAutoscaler we created previously
+	if m.Spec.Autoscaling == nil {
+		if !exists || !metav1.IsControlledBy(current, m) {
+			return nil
+		}
+		ctxLogger.Info("Deleting HorizontalPodAutoscaler", "HorizontalPodAutoscaler.Name", current.Name)
+		if err := r.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete HorizontalPodAutoscaler: %w", err)
+		}
+		return nil
+	}
+
+	desired := hpaForMCPServer(m)
+	if err := controllerutil.SetControllerReference(m, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference for HorizontalPodAutoscaler: %w", err)
+	}
+
+	if !exists {
+		ctxLogger.Info("Creating a new HorizontalPodAutoscaler", "HorizontalPodAutoscaler.Name", desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create HorizontalPodAutoscaler: %w", err)
+		}
+		return nil
+	}
+
+	// A HorizontalPodAutoscaler of the same name which the MCPServer does not control is left alone
+	if !metav1.IsControlledBy(current, m) {
+		r.recordUnownedResource(ctx, m, "HorizontalPodAutoscaler", current.Name)
+		return nil
+	}
+
+	if !hpaNeedsUpdate(current, desired) {
+		return nil
+	}
+
+	ctxLogger.Info("Updating HorizontalPodAutoscaler", "HorizontalPodAutoscaler.Name", current.Name)
+	current.Labels = desired.Labels
+	current.Annotations = desired.Annotations
+	current.Spec = desired.Spec
+	if err := r.Update(ctx, current); err != nil {
+		return fmt.Errorf("failed to update HorizontalPodAutoscaler: %w", err)
+	}
+	return nil
+}
+
+// hpaForMCPServer returns the HorizontalPodAutoscaler targeting the MCPServer proxy deployment
+func hpaForMCPServer(m *mcpv1alpha1.MCPServer) *autoscalingv2.HorizontalPodAutoscaler {
+	minReplicas := autoscalingMinReplicas(m)
+	targetCPU := defaultAutoscalingTargetCPUUtilization
+	if m.Spec.Autoscaling.TargetCPUUtilizationPercentage != nil {
+		targetCPU = *m.Spec.Autoscaling.TargetCPUUtilizationPercentage
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        m.Name,
+			Namespace:   m.Namespace,
+			Labels:      commonLabelsForMCPServer(m),
+			Annotations: commonAnnotationsForMCPServer(m),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       m.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: m.Spec.Autoscaling.MaxReplicas,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: &targetCPU,
+					},
+				},
+			}},
+		},
+	}
+}
+
+// autoscalingMinReplicas returns the configured minimum number of replicas for the MCPServer
+func autoscalingMinReplicas(m *mcpv1alpha1.MCPServer) int32 {
+	if m.Spec.Autoscaling == nil || m.Spec.Autoscaling.MinReplicas == nil {
+		return defaultAutoscalingMinReplicas
+	}
+	return *m.Spec.Autoscaling.MinReplicas
+}
+
+// hpaNeedsUpdate checks if the HorizontalPodAutoscaler needs to be updated
+func hpaNeedsUpdate(current, desired *autoscalingv2.HorizontalPodAutoscaler) bool {
+	if !reflect.DeepEqual(current.Spec.ScaleTargetRef, desired.Spec.ScaleTargetRef) {
+		return true
+	}
+	if !reflect.DeepEqual(current.Spec.MinReplicas, desired.Spec.MinReplicas) {
+		return true
+	}
+	if current.Spec.MaxReplicas != desired.Spec.MaxReplicas {
+		return true
+	}
+	if !reflect.DeepEqual(current.Spec.Metrics, desired.Spec.Metrics) {
+		return true
+	}
+	if !maps.Equal(current.Annotations, desired.Annotations) {
+		return true
+	}
+	return !maps.Equal(current.Labels, desired.Labels)
+}
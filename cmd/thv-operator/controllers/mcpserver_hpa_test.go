@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestEnsureHorizontalPodAutoscaler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		initial           *mcpv1alpha1.AutoscalingConfig
+		updated           *mcpv1alpha1.AutoscalingConfig
+		expectHPA         bool
+		expectedMin       int32
+		expectedMax       int32
+		expectedTargetCPU int32
+	}{
+		{
+			name:              "creates HPA with defaults",
+			initial:           &mcpv1alpha1.AutoscalingConfig{MaxReplicas: 3},
+			updated:           &mcpv1alpha1.AutoscalingConfig{MaxReplicas: 3},
+			expectHPA:         true,
+			expectedMin:       1,
+			expectedMax:       3,
+			expectedTargetCPU: 80,
+		},
+		{
+			name:    "updates HPA when thresholds change",
+			initial: &mcpv1alpha1.AutoscalingConfig{MaxReplicas: 3},
+			updated: &mcpv1alpha1.AutoscalingConfig{
+				MinReplicas:                    int32Ptr(2),
+				MaxReplicas:                    5,
+				TargetCPUUtilizationPercentage: int32Ptr(60),
+			},
+			expectHPA:         true,
+			expectedMin:       2,
+			expectedMax:       5,
+			expectedTargetCPU: 60,
+		},
+		{
+			name:      "deletes HPA when autoscaling is disabled",
+			initial:   &mcpv1alpha1.AutoscalingConfig{MaxReplicas: 3},
+			updated:   nil,
+			expectHPA: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("hpa-server", "default")
+			mcpServer.Spec.Autoscaling = tt.initial
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			require.NoError(t, r.ensureHorizontalPodAutoscaler(ctx, mcpServer))
+
+			mcpServer.Spec.Autoscaling = tt.updated
+			require.NoError(t, r.ensureHorizontalPodAutoscaler(ctx, mcpServer))
+
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+			err := fakeClient.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, hpa)
+			if !tt.expectHPA {
+				assert.True(t, errors.IsNotFound(err), "HorizontalPodAutoscaler should not exist")
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "Deployment", hpa.Spec.ScaleTargetRef.Kind)
+			assert.Equal(t, mcpServer.Name, hpa.Spec.ScaleTargetRef.Name)
+			require.NotNil(t, hpa.Spec.MinReplicas)
+			assert.Equal(t, tt.expectedMin, *hpa.Spec.MinReplicas)
+			assert.Equal(t, tt.expectedMax, hpa.Spec.MaxReplicas)
+			require.Len(t, hpa.Spec.Metrics, 1)
+			assert.Equal(t, corev1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+			assert.Equal(t, tt.expectedTargetCPU, *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+			assert.True(t, metav1.IsControlledBy(hpa, mcpServer), "HorizontalPodAutoscaler should be owned by the MCPServer")
+		})
+	}
+}
+
+func TestEnsureHorizontalPodAutoscaler_KeepsUnownedHPA(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("hpa-unowned", "default")
+	mcpServer.UID = "hpa-unowned-uid"
+	mcpServer.Spec.Autoscaling = &mcpv1alpha1.AutoscalingConfig{MinReplicas: int32Ptr(2), MaxReplicas: 5}
+	existing := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "other"},
+			MaxReplicas:    3,
+		},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer, existing).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+	eventRecorder := record.NewFakeRecorder(10)
+	r.Recorder = eventRecorder
+
+	// The HorizontalPodAutoscaler the MCPServer does not control is neither updated nor deleted
+	require.NoError(t, r.ensureHorizontalPodAutoscaler(ctx, mcpServer))
+	require.Len(t, eventRecorder.Events, 1)
+	assert.Contains(t, <-eventRecorder.Events, "ResourceConflict")
+
+	mcpServer.Spec.Autoscaling = nil
+	require.NoError(t, r.ensureHorizontalPodAutoscaler(ctx, mcpServer))
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, hpa))
+	assert.False(t, metav1.IsControlledBy(hpa, mcpServer))
+	assert.Equal(t, "other", hpa.Spec.ScaleTargetRef.Name)
+	assert.Equal(t, int32(3), hpa.Spec.MaxReplicas)
+	assert.Nil(t, hpa.Spec.MinReplicas)
+}
+
+func TestDeploymentForMCPServer_AutoscalingReplicas(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("hpa-replicas", "default")
+	mcpServer.Spec.Autoscaling = &mcpv1alpha1.AutoscalingConfig{MinReplicas: int32Ptr(2), MaxReplicas: 4}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+	assert.Equal(t, int32(2), *dep.Spec.Replicas, "Initial replicas should match the autoscaling minimum")
+}
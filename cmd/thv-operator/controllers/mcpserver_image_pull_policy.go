@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+// imagePullPolicyForMCPServer returns the pull policy for the MCP server container.
+// An explicit ImagePullPolicy in the spec wins, otherwise the policy is derived from the image tag.
+func imagePullPolicyForMCPServer(m *mcpv1alpha1.MCPServer) corev1.PullPolicy {
+	if m.Spec.ImagePullPolicy != "" {
+		return corev1.PullPolicy(m.Spec.ImagePullPolicy)
+	}
+	return mcpv1alpha1.DefaultImagePullPolicy(m.Spec.Image)
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestMCPServerImagePullPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		image          string
+		pullPolicy     string
+		expectedPolicy corev1.PullPolicy
+	}{
+		{
+			name:           "explicit policy is applied",
+			image:          "test-image:latest",
+			pullPolicy:     string(corev1.PullNever),
+			expectedPolicy: corev1.PullNever,
+		},
+		{
+			name:           "latest tag defaults to Always",
+			image:          "test-image:latest",
+			expectedPolicy: corev1.PullAlways,
+		},
+		{
+			name:           "pinned tag defaults to IfNotPresent",
+			image:          "test-image:1.2.3",
+			expectedPolicy: corev1.PullIfNotPresent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("pull-policy-server", "default")
+			mcpServer.Spec.Image = tt.image
+			mcpServer.Spec.ImagePullPolicy = tt.pullPolicy
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+			require.NotNil(t, dep)
+
+			var podTemplatePatch string
+			for _, arg := range dep.Spec.Template.Spec.Containers[0].Args {
+				if strings.HasPrefix(arg, "--k8s-pod-patch=") {
+					podTemplatePatch = strings.TrimPrefix(arg, "--k8s-pod-patch=")
+					break
+				}
+			}
+			require.NotEmpty(t, podTemplatePatch, "Pod template patch should be present in args")
+
+			var podTemplateSpec corev1.PodTemplateSpec
+			require.NoError(t, json.Unmarshal([]byte(podTemplatePatch), &podTemplateSpec))
+
+			var policy corev1.PullPolicy
+			for _, container := range podTemplateSpec.Spec.Containers {
+				if container.Name == mcpContainerName {
+					policy = container.ImagePullPolicy
+				}
+			}
+			assert.Equal(t, tt.expectedPolicy, policy)
+
+			assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"),
+				"Deployment generated with the pull policy should not need an update")
+
+			mcpServer.Spec.ImagePullPolicy = string(corev1.PullNever)
+			if tt.expectedPolicy != corev1.PullNever {
+				assert.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"),
+					"Changing the pull policy should require an update")
+			}
+		})
+	}
+}
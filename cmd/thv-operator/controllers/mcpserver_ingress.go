@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	ctrlutil "github.com/stacklok/toolhive/cmd/thv-operator/pkg/controllerutil"
+)
+
+// ensureIngress creates, updates or deletes the Ingress exposing the proxy service
+// so that it matches the MCPServer spec.
+func (r *MCPServerReconciler) ensureIngress(ctx context.Context, m *mcpv1alpha1.MCPServer) error {
+	ctxLogger := log.FromContext(ctx)
+
+	current := &networkingv1.Ingress{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, current)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Ingress: %w", err)
+	}
+	exists := err == nil
+
+	// No Ingress requested, remove any one we created previously
+	if m.Spec.Ingress == nil {
+		if !exists || !metav1.IsControlledBy(current, m) {
+			return nil
+		}
+		ctxLogger.Info("Deleting Ingress", "Ingress.Name", current.Name)
+		if err := r.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Ingress: %w", err)
+		}
+		return nil
+	}
+
+	desired := ingressForMCPServer(m)
+	if err := controllerutil.SetControllerReference(m, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference for Ingress: %w", err)
+	}
+
+	if !exists {
+		ctxLogger.Info("Creating a new Ingress", "Ingress.Name", desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create Ingress: %w", err)
+		}
+		return nil
+	}
+
+	// An Ingress of the same name which the MCPServer does not control is left alone, rewriting its
+	// rules would route its traffic to the MCPServer
+	if !metav1.IsControlledBy(current, m) {
+		r.recordUnownedResource(ctx, m, "Ingress", current.Name)
+		return nil
+	}
+
+	if !ingressNeedsUpdate(current, desired) {
+		return nil
+	}
+
+	ctxLogger.Info("Updating Ingress", "Ingress.Name", current.Name)
+	current.Labels = desired.Labels
+	current.Annotations = desired.Annotations
+	current.Spec = desired.Spec
+	if err := r.Update(ctx, current); err != nil {
+		return fmt.Errorf("failed to update Ingress: %w", err)
+	}
+	return nil
+}
+
+// ingressForMCPServer returns the Ingress routing external traffic to the MCPServer proxy service
+func ingressForMCPServer(m *mcpv1alpha1.MCPServer) *networkingv1.Ingress {
+	cfg := m.Spec.Ingress
+	pathType := networkingv1.PathTypePrefix
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        m.Name,
+			Namespace:   m.Namespace,
+			Labels:      commonLabelsForMCPServer(m),
+			Annotations: withCommonMetadata(m.Spec.CommonAnnotations, cfg.Annotations),
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: cfg.IngressClassName,
+			Rules: []networkingv1.IngressRule{{
+				Host: cfg.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: ctrlutil.CreateProxyServiceName(m.Name),
+									Port: networkingv1.ServiceBackendPort{
+										Number: m.GetProxyPort(),
+									},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	if cfg.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{
+			Hosts:      []string{cfg.Host},
+			SecretName: cfg.TLSSecretName,
+		}}
+	}
+
+	return ingress
+}
+
+// ingressNeedsUpdate checks if the Ingress needs to be updated
+func ingressNeedsUpdate(current, desired *networkingv1.Ingress) bool {
+	if !reflect.DeepEqual(current.Spec, desired.Spec) {
+		return true
+	}
+	if !maps.Equal(current.Annotations, desired.Annotations) {
+		return true
+	}
+	return !maps.Equal(current.Labels, desired.Labels)
+}
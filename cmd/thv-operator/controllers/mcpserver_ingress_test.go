@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	ctrlutil "github.com/stacklok/toolhive/cmd/thv-operator/pkg/controllerutil"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestServiceForMCPServer_TargetsProxyPort(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("svc-port", "default")
+	mcpServer.Spec.ProxyPort = 9090
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	svc := r.serviceForMCPServer(ctx, mcpServer)
+	require.NotNil(t, svc)
+	require.Len(t, svc.Spec.Ports, 1)
+	assert.Equal(t, int32(9090), svc.Spec.Ports[0].Port)
+	assert.Equal(t, intstr.FromInt(9090), svc.Spec.Ports[0].TargetPort)
+	assert.Equal(t, labelsForMCPServer(mcpServer.Name), svc.Spec.Selector)
+}
+
+func TestEnsureIngress(t *testing.T) {
+	t.Parallel()
+
+	className := "nginx"
+
+	tests := []struct {
+		name          string
+		initial       *mcpv1alpha1.IngressConfig
+		updated       *mcpv1alpha1.IngressConfig
+		expectIngress bool
+	}{
+		{
+			name: "creates ingress with host and annotations",
+			initial: &mcpv1alpha1.IngressConfig{
+				Host:        "mcp.example.com",
+				Annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-read-timeout": "3600"},
+			},
+			updated: &mcpv1alpha1.IngressConfig{
+				Host:        "mcp.example.com",
+				Annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-read-timeout": "3600"},
+			},
+			expectIngress: true,
+		},
+		{
+			name:    "updates ingress when host, class and TLS change",
+			initial: &mcpv1alpha1.IngressConfig{Host: "old.example.com"},
+			updated: &mcpv1alpha1.IngressConfig{
+				Host:             "new.example.com",
+				IngressClassName: &className,
+				Annotations:      map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+				TLSSecretName:    "mcp-tls",
+			},
+			expectIngress: true,
+		},
+		{
+			name:          "deletes ingress when removed from spec",
+			initial:       &mcpv1alpha1.IngressConfig{Host: "mcp.example.com"},
+			updated:       nil,
+			expectIngress: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("ingress-server", "default")
+			mcpServer.Spec.Ingress = tt.initial
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			require.NoError(t, r.ensureIngress(ctx, mcpServer))
+
+			mcpServer.Spec.Ingress = tt.updated
+			require.NoError(t, r.ensureIngress(ctx, mcpServer))
+
+			ingress := &networkingv1.Ingress{}
+			err := fakeClient.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, ingress)
+			if !tt.expectIngress {
+				assert.True(t, errors.IsNotFound(err), "Ingress should not exist")
+				return
+			}
+			require.NoError(t, err)
+
+			assert.True(t, metav1.IsControlledBy(ingress, mcpServer), "Ingress should be owned by the MCPServer")
+			assert.Equal(t, tt.updated.Annotations, ingress.Annotations)
+			assert.Equal(t, tt.updated.IngressClassName, ingress.Spec.IngressClassName)
+
+			require.Len(t, ingress.Spec.Rules, 1)
+			rule := ingress.Spec.Rules[0]
+			assert.Equal(t, tt.updated.Host, rule.Host)
+			require.NotNil(t, rule.HTTP)
+			require.Len(t, rule.HTTP.Paths, 1)
+			backend := rule.HTTP.Paths[0].Backend.Service
+			require.NotNil(t, backend)
+			assert.Equal(t, ctrlutil.CreateProxyServiceName(mcpServer.Name), backend.Name)
+			assert.Equal(t, mcpServer.GetProxyPort(), backend.Port.Number)
+
+			if tt.updated.TLSSecretName == "" {
+				assert.Empty(t, ingress.Spec.TLS)
+			} else {
+				require.Len(t, ingress.Spec.TLS, 1)
+				assert.Equal(t, tt.updated.TLSSecretName, ingress.Spec.TLS[0].SecretName)
+				assert.Equal(t, []string{tt.updated.Host}, ingress.Spec.TLS[0].Hosts)
+			}
+		})
+	}
+}
+
+func TestEnsureIngress_KeepsUnownedIngress(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("ingress-unowned", "default")
+	mcpServer.UID = "ingress-unowned-uid"
+	mcpServer.Spec.Ingress = &mcpv1alpha1.IngressConfig{Host: "mcp.example.com"}
+	existing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        mcpServer.Name,
+			Namespace:   mcpServer.Namespace,
+			Annotations: map[string]string{"owner": "another-team"},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{Host: "app.example.com"}},
+		},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer, existing).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+	eventRecorder := record.NewFakeRecorder(10)
+	r.Recorder = eventRecorder
+
+	// The Ingress the MCPServer does not control is neither updated nor deleted
+	require.NoError(t, r.ensureIngress(ctx, mcpServer))
+	require.Len(t, eventRecorder.Events, 1)
+	assert.Contains(t, <-eventRecorder.Events, "ResourceConflict")
+
+	mcpServer.Spec.Ingress = nil
+	require.NoError(t, r.ensureIngress(ctx, mcpServer))
+
+	ingress := &networkingv1.Ingress{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, ingress))
+	assert.False(t, metav1.IsControlledBy(ingress, mcpServer))
+	assert.Equal(t, map[string]string{"owner": "another-team"}, ingress.Annotations)
+	require.Len(t, ingress.Spec.Rules, 1)
+	assert.Equal(t, "app.example.com", ingress.Spec.Rules[0].Host)
+	assert.Nil(t, ingress.Spec.Rules[0].HTTP)
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	ctrlutil "github.com/stacklok/toolhive/cmd/thv-operator/pkg/controllerutil"
+)
+
+// openTelemetryEnvVarPrefix is the prefix of the OpenTelemetry environment variables of the proxy container
+const openTelemetryEnvVarPrefix = "OTEL_"
+
+// equalOpenTelemetryEnvVars returns true if the OpenTelemetry environment variables of existingEnv match
+// those expected for the telemetry configuration, i.e. the generated ones followed by the user-specified
+// ones from the resource overrides. Environment variables without the OTEL_ prefix are ignored.
+func equalOpenTelemetryEnvVars(
+	telemetryConfig *mcpv1alpha1.TelemetryConfig,
+	resourceName string,
+	namespace string,
+	overrides *mcpv1alpha1.ResourceOverrides,
+	existingEnv []corev1.EnvVar,
+) bool {
+	var expected []corev1.EnvVar
+	for _, envVar := range ctrlutil.GenerateOpenTelemetryEnvVars(telemetryConfig, resourceName, namespace) {
+		if strings.HasPrefix(envVar.Name, openTelemetryEnvVarPrefix) {
+			expected = append(expected, envVar)
+		}
+	}
+	for _, envVar := range proxyDeploymentEnv(overrides) {
+		if strings.HasPrefix(envVar.Name, openTelemetryEnvVarPrefix) {
+			expected = append(expected, corev1.EnvVar{Name: envVar.Name, Value: envVar.Value})
+		}
+	}
+
+	var existing []corev1.EnvVar
+	for _, envVar := range existingEnv {
+		if strings.HasPrefix(envVar.Name, openTelemetryEnvVarPrefix) {
+			existing = append(existing, envVar)
+		}
+	}
+
+	return reflect.DeepEqual(existing, expected)
+}
+
+// resolveTelemetryConfig returns the telemetry configuration with the referenced OpenTelemetry ConfigMap
+// merged in, so that the proxy deployment is built from the same configuration as the RunConfig.
+// The configuration of the spec is returned when the ConfigMap cannot be read, the RunConfig creation
+// failing the reconciliation in that case.
+func resolveTelemetryConfig(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	telemetryConfig *mcpv1alpha1.TelemetryConfig,
+) *mcpv1alpha1.TelemetryConfig {
+	resolved, err := ctrlutil.ResolveTelemetryConfig(ctx, c, namespace, telemetryConfig)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to resolve the OpenTelemetry config", "namespace", namespace)
+		return telemetryConfig
+	}
+	return resolved
+}
+
+// referencesOpenTelemetryConfigMap returns true if the telemetry configuration references the ConfigMap
+func referencesOpenTelemetryConfigMap(telemetryConfig *mcpv1alpha1.TelemetryConfig, configMapName string) bool {
+	return telemetryConfig != nil && telemetryConfig.OpenTelemetry != nil &&
+		telemetryConfig.OpenTelemetry.ConfigMapRef != nil &&
+		telemetryConfig.OpenTelemetry.ConfigMapRef.Name == configMapName
+}
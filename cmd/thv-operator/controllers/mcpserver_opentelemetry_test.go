@@ -21,9 +21,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
 	ctrlutil "github.com/stacklok/toolhive/cmd/thv-operator/pkg/controllerutil"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
 )
 
 func TestOpenTelemetryEnvVars(t *testing.T) {
@@ -58,6 +62,18 @@ func TestOpenTelemetryEnvVars(t *testing.T) {
 				{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "service.name=test-server,service.namespace=default"},
 			},
 		},
+		{
+			name: "disabled OpenTelemetry config",
+			otelConfig: &mcpv1alpha1.OpenTelemetryConfig{
+				Enabled:     ptr.To(false),
+				Endpoint:    "otel-collector:4317",
+				ServiceName: "custom-service",
+				Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
+					Enabled: true,
+				},
+			},
+			expectedEnv: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -162,3 +178,192 @@ func TestServiceNameDefaulting(t *testing.T) {
 		})
 	}
 }
+
+func TestEqualOpenTelemetryEnvVars(t *testing.T) {
+	t.Parallel()
+
+	telemetryConfig := &mcpv1alpha1.TelemetryConfig{
+		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+			ServiceName: "custom-service",
+		},
+	}
+	overrides := &mcpv1alpha1.ResourceOverrides{
+		ProxyDeployment: &mcpv1alpha1.ProxyDeploymentOverrides{
+			Env: []mcpv1alpha1.EnvVar{
+				{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel-collector:4318"},
+				{Name: "LOG_LEVEL", Value: "debug"},
+			},
+		},
+	}
+	resourceAttributes := corev1.EnvVar{
+		Name:  "OTEL_RESOURCE_ATTRIBUTES",
+		Value: "service.name=custom-service,service.namespace=default",
+	}
+
+	tests := []struct {
+		name            string
+		telemetryConfig *mcpv1alpha1.TelemetryConfig
+		existingEnv     []corev1.EnvVar
+		expected        bool
+	}{
+		{
+			name:            "matching env vars",
+			telemetryConfig: telemetryConfig,
+			existingEnv: []corev1.EnvVar{
+				resourceAttributes,
+				{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel-collector:4318"},
+				{Name: "LOG_LEVEL", Value: "debug"},
+			},
+			expected: true,
+		},
+		{
+			name:            "unrelated env vars are ignored",
+			telemetryConfig: telemetryConfig,
+			existingEnv: []corev1.EnvVar{
+				resourceAttributes,
+				{Name: "TOOLHIVE_RUNTIME", Value: "kubernetes"},
+				{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel-collector:4318"},
+				{Name: "LOG_LEVEL", Value: "info"},
+			},
+			expected: true,
+		},
+		{
+			name:            "changed OTLP endpoint",
+			telemetryConfig: telemetryConfig,
+			existingEnv: []corev1.EnvVar{
+				resourceAttributes,
+				{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://old-collector:4318"},
+				{Name: "LOG_LEVEL", Value: "debug"},
+			},
+			expected: false,
+		},
+		{
+			name:            "missing OTLP endpoint",
+			telemetryConfig: telemetryConfig,
+			existingEnv: []corev1.EnvVar{
+				resourceAttributes,
+				{Name: "LOG_LEVEL", Value: "debug"},
+			},
+			expected: false,
+		},
+		{
+			name: "stale resource attributes with disabled OpenTelemetry",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+					Enabled:     ptr.To(false),
+					ServiceName: "custom-service",
+				},
+			},
+			existingEnv: []corev1.EnvVar{
+				resourceAttributes,
+				{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel-collector:4318"},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected,
+				equalOpenTelemetryEnvVars(tt.telemetryConfig, "test-server", "default", overrides, tt.existingEnv))
+		})
+	}
+}
+
+func TestDeploymentNeedsUpdate_OpenTelemetryEnvVars(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	mcpServer := createTestMCPServer("otel-env", "default")
+	mcpServer.Spec.Telemetry = &mcpv1alpha1.TelemetryConfig{
+		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{},
+	}
+	mcpServer.Spec.ResourceOverrides = &mcpv1alpha1.ResourceOverrides{
+		ProxyDeployment: &mcpv1alpha1.ProxyDeploymentOverrides{
+			Env: []mcpv1alpha1.EnvVar{
+				{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://old-collector:4318"},
+			},
+		},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+	assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+
+	// Only the OTLP endpoint changes
+	mcpServer.Spec.ResourceOverrides.ProxyDeployment.Env[0].Value = "http://otel-collector:4318"
+	assert.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+}
+
+func TestOpenTelemetryConfigMap_ProxyEnvVars(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	sharedConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-otel", Namespace: "default"},
+		Data: map[string]string{
+			ctrlutil.DefaultOpenTelemetryKey: `{"enabled": true, "serviceName": "shared-service"}`,
+		},
+	}
+	mcpServer := createTestMCPServer("otel-configmap", "default")
+	mcpServer.Spec.Telemetry = &mcpv1alpha1.TelemetryConfig{
+		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+			ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "shared-otel"},
+		},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer, sharedConfig).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+	assert.Contains(t, dep.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "OTEL_RESOURCE_ATTRIBUTES",
+		Value: "service.name=shared-service,service.namespace=default",
+	}, "OpenTelemetry enabled in the ConfigMap should produce the resource attributes")
+	assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+
+	// Changing the service name in the ConfigMap is detected as drift
+	sharedConfig.Data[ctrlutil.DefaultOpenTelemetryKey] = `{"enabled": true, "serviceName": "renamed-service"}`
+	require.NoError(t, fakeClient.Update(ctx, sharedConfig))
+	assert.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+}
+
+func TestMapOpenTelemetryConfigMapToMCPServers(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	referencing := createTestMCPServer("referencing", "default")
+	referencing.Spec.Telemetry = &mcpv1alpha1.TelemetryConfig{
+		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+			ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "shared-otel"},
+		},
+	}
+	inline := createTestMCPServer("inline", "default")
+	inline.Spec.Telemetry = &mcpv1alpha1.TelemetryConfig{
+		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{},
+	}
+	other := createTestMCPServer("other", "default")
+	other.Spec.Telemetry = &mcpv1alpha1.TelemetryConfig{
+		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+			ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "other-otel"},
+		},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(referencing, inline, other).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "shared-otel", Namespace: "default"}}
+	requests := r.mapOpenTelemetryConfigMapToMCPServers(ctx, configMap)
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Name: "referencing", Namespace: "default"}, requests[0].NamespacedName)
+}
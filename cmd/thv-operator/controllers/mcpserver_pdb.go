@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+// ensurePodDisruptionBudget creates, updates or deletes the PodDisruptionBudget for the
+// proxy deployment so that it matches the MCPServer spec.
+func (r *MCPServerReconciler) ensurePodDisruptionBudget(ctx context.Context, m *mcpv1alpha1.MCPServer) error {
+	ctxLogger := log.FromContext(ctx)
+
+	current := &policyv1.PodDisruptionBudget{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, current)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get PodDisruptionBudget: %w", err)
+	}
+	exists := err == nil
+
+	// No PodDisruptionBudget requested, remove any one we created previously
+	if m.Spec.PodDisruptionBudget == nil {
+		if !exists || !metav1.IsControlledBy(current, m) {
+			return nil
+		}
+		ctxLogger.Info("Deleting PodDisruptionBudget", "PodDisruptionBudget.Name", current.Name)
+		if err := r.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PodDisruptionBudget: %w", err)
+		}
+		return nil
+	}
+
+	desired := pdbForMCPServer(m)
+	if err := controllerutil.SetControllerReference(m, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference for PodDisruptionBudget: %w", err)
+	}
+
+	if !exists {
+		ctxLogger.Info("Creating a new PodDisruptionBudget", "PodDisruptionBudget.Name", desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create PodDisruptionBudget: %w", err)
+		}
+		return nil
+	}
+
+	// A PodDisruptionBudget of the same name which the MCPServer does not control is left alone
+	if !metav1.IsControlledBy(current, m) {
+		r.recordUnownedResource(ctx, m, "PodDisruptionBudget", current.Name)
+		return nil
+	}
+
+	if !pdbNeedsUpdate(current, desired) {
+		return nil
+	}
+
+	ctxLogger.Info("Updating PodDisruptionBudget", "PodDisruptionBudget.Name", current.Name)
+	current.Labels = desired.Labels
+	current.Annotations = desired.Annotations
+	current.Spec = desired.Spec
+	if err := r.Update(ctx, current); err != nil {
+		return fmt.Errorf("failed to update PodDisruptionBudget: %w", err)
+	}
+	return nil
+}
+
+// recordUnownedResource reports that a resource named after the MCPServer already exists but is not
+// controlled by it, so that it is neither taken over nor modified by the reconciler
+func (r *MCPServerReconciler) recordUnownedResource(ctx context.Context, m *mcpv1alpha1.MCPServer, kind, name string) {
+	log.FromContext(ctx).Info("Leaving a resource not controlled by the MCPServer unchanged", "kind", kind, "name", name)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(m, corev1.EventTypeWarning, "ResourceConflict",
+			"%s %s already exists and is not controlled by this MCPServer, it is left unchanged", kind, name)
+	}
+}
+
+// pdbForMCPServer returns the PodDisruptionBudget for the MCPServer proxy deployment
+func pdbForMCPServer(m *mcpv1alpha1.MCPServer) *policyv1.PodDisruptionBudget {
+	ls := labelsForMCPServer(m.Name)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        m.Name,
+			Namespace:   m.Namespace,
+			Labels:      commonLabelsForMCPServer(m),
+			Annotations: commonAnnotationsForMCPServer(m),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   m.Spec.PodDisruptionBudget.MinAvailable,
+			MaxUnavailable: m.Spec.PodDisruptionBudget.MaxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: ls,
+			},
+		},
+	}
+}
+
+// pdbNeedsUpdate checks if the PodDisruptionBudget needs to be updated
+func pdbNeedsUpdate(current, desired *policyv1.PodDisruptionBudget) bool {
+	if !reflect.DeepEqual(current.Spec.MinAvailable, desired.Spec.MinAvailable) {
+		return true
+	}
+	if !reflect.DeepEqual(current.Spec.MaxUnavailable, desired.Spec.MaxUnavailable) {
+		return true
+	}
+	if !reflect.DeepEqual(current.Spec.Selector, desired.Spec.Selector) {
+		return true
+	}
+	if !maps.Equal(current.Annotations, desired.Annotations) {
+		return true
+	}
+	return !maps.Equal(current.Labels, desired.Labels)
+}
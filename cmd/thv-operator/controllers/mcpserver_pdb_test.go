@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestEnsurePodDisruptionBudget(t *testing.T) {
+	t.Parallel()
+
+	minAvailable := intstr.FromInt32(1)
+	maxUnavailable := intstr.FromString("50%")
+
+	tests := []struct {
+		name                   string
+		initial                *mcpv1alpha1.PodDisruptionBudgetConfig
+		updated                *mcpv1alpha1.PodDisruptionBudgetConfig
+		expectPDB              bool
+		expectedMinAvailable   *intstr.IntOrString
+		expectedMaxUnavailable *intstr.IntOrString
+	}{
+		{
+			name:                 "creates PDB with minAvailable",
+			initial:              &mcpv1alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailable},
+			updated:              &mcpv1alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailable},
+			expectPDB:            true,
+			expectedMinAvailable: &minAvailable,
+		},
+		{
+			name:                   "updates PDB when values change",
+			initial:                &mcpv1alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailable},
+			updated:                &mcpv1alpha1.PodDisruptionBudgetConfig{MaxUnavailable: &maxUnavailable},
+			expectPDB:              true,
+			expectedMaxUnavailable: &maxUnavailable,
+		},
+		{
+			name:      "deletes PDB when removed from spec",
+			initial:   &mcpv1alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailable},
+			updated:   nil,
+			expectPDB: false,
+		},
+		{
+			name:      "does nothing when not configured",
+			initial:   nil,
+			updated:   nil,
+			expectPDB: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("pdb-server", "default")
+			mcpServer.Spec.PodDisruptionBudget = tt.initial
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			require.NoError(t, r.ensurePodDisruptionBudget(ctx, mcpServer))
+
+			mcpServer.Spec.PodDisruptionBudget = tt.updated
+			require.NoError(t, r.ensurePodDisruptionBudget(ctx, mcpServer))
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			err := fakeClient.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, pdb)
+			if !tt.expectPDB {
+				assert.True(t, errors.IsNotFound(err), "PodDisruptionBudget should not exist")
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedMinAvailable, pdb.Spec.MinAvailable)
+			assert.Equal(t, tt.expectedMaxUnavailable, pdb.Spec.MaxUnavailable)
+			assert.Equal(t, labelsForMCPServer(mcpServer.Name), pdb.Spec.Selector.MatchLabels)
+			assert.True(t, metav1.IsControlledBy(pdb, mcpServer), "PodDisruptionBudget should be owned by the MCPServer")
+		})
+	}
+}
+
+func TestEnsurePodDisruptionBudget_KeepsUnownedPDB(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("pdb-unowned", "default")
+	mcpServer.UID = "pdb-unowned-uid"
+	minAvailable := intstr.FromInt32(1)
+	mcpServer.Spec.PodDisruptionBudget = &mcpv1alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailable}
+	existing := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace},
+		Spec:       policyv1.PodDisruptionBudgetSpec{MinAvailable: &minAvailable},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer, existing).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+	eventRecorder := record.NewFakeRecorder(10)
+	r.Recorder = eventRecorder
+
+	// Changing the PodDisruptionBudget of the spec must not update the one the MCPServer does not control
+	changedMinAvailable := intstr.FromInt32(2)
+	mcpServer.Spec.PodDisruptionBudget = &mcpv1alpha1.PodDisruptionBudgetConfig{MinAvailable: &changedMinAvailable}
+	mcpServer.Spec.CommonLabels = map[string]string{"team": "platform"}
+	require.NoError(t, r.ensurePodDisruptionBudget(ctx, mcpServer))
+
+	unchanged := &policyv1.PodDisruptionBudget{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, unchanged))
+	assert.False(t, metav1.IsControlledBy(unchanged, mcpServer))
+	assert.Empty(t, unchanged.OwnerReferences)
+	assert.Empty(t, unchanged.Labels)
+	assert.Equal(t, &minAvailable, unchanged.Spec.MinAvailable)
+	require.Len(t, eventRecorder.Events, 1)
+	assert.Contains(t, <-eventRecorder.Events, "ResourceConflict")
+
+	// Removing the PodDisruptionBudget from the spec must not delete the one the MCPServer does not control
+	mcpServer.Spec.PodDisruptionBudget = nil
+	require.NoError(t, r.ensurePodDisruptionBudget(ctx, mcpServer))
+	require.NoError(t, r.cleanup(ctx, mcpServer))
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, pdb))
+	assert.False(t, metav1.IsControlledBy(pdb, mcpServer))
+	assert.Equal(t, &minAvailable, pdb.Spec.MinAvailable)
+}
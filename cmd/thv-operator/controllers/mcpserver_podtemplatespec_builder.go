@@ -3,6 +3,7 @@ package controllers
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -10,6 +11,33 @@ import (
 	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
 )
 
+// SecretDefaultEnvSuffix is appended to the env var of a secret with a default value to name the
+// env var holding that default
+const SecretDefaultEnvSuffix = "_DEFAULT"
+
+// DownwardAPIEnvVar maps an environment variable name to a pod field exposed through the downward API
+type DownwardAPIEnvVar struct {
+	Name      string
+	FieldPath string
+}
+
+// DefaultDownwardAPIEnv is the set of pod identity env vars injected by WithDownwardAPIEnv
+// when no explicit set is provided
+var DefaultDownwardAPIEnv = []DownwardAPIEnvVar{
+	{Name: "POD_NAME", FieldPath: "metadata.name"},
+	{Name: "POD_NAMESPACE", FieldPath: "metadata.namespace"},
+	{Name: "NODE_NAME", FieldPath: "spec.nodeName"},
+}
+
+const (
+	// caBundleVolumeName is the name of the volume holding the CA bundle
+	caBundleVolumeName = "ca-bundle"
+	// caBundleMountPath is the directory the CA bundle is mounted at in the MCP container
+	caBundleMountPath = "/etc/toolhive/ca"
+	// caBundleFileName is the file name of the CA bundle inside the mount path
+	caBundleFileName = "ca.crt"
+)
+
 // MCPServerPodTemplateSpecBuilder provides an interface for building PodTemplateSpec patches for MCP Servers
 type MCPServerPodTemplateSpecBuilder struct {
 	spec            *corev1.PodTemplateSpec
@@ -53,7 +81,10 @@ func (b *MCPServerPodTemplateSpecBuilder) WithServiceAccount(serviceAccount *str
 	return b
 }
 
-// WithSecrets adds secret environment variables to the MCP container
+// WithSecrets adds secret environment variables to the MCP container.
+// Vault-typed secrets are skipped as they do not refer to Kubernetes Secrets.
+// Secrets with a default value are optional, and their default is set in a separate
+// env var named after the secret env var with the SecretDefaultEnvSuffix suffix.
 func (b *MCPServerPodTemplateSpecBuilder) WithSecrets(secrets []mcpv1alpha1.SecretRef) *MCPServerPodTemplateSpecBuilder {
 	if len(secrets) == 0 {
 		return b
@@ -62,22 +93,39 @@ func (b *MCPServerPodTemplateSpecBuilder) WithSecrets(secrets []mcpv1alpha1.Secr
 	// Generate secret env vars
 	secretEnvVars := make([]corev1.EnvVar, 0, len(secrets))
 	for _, secret := range secrets {
+		// Vault secrets are rendered by the Vault Agent and loaded by the runner from env files
+		if secret.IsVault() {
+			continue
+		}
+
 		targetEnv := secret.Key
 		if secret.TargetEnvName != "" {
 			targetEnv = secret.TargetEnvName
 		}
 
+		secretKeyRef := &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: secret.Name,
+			},
+			Key: secret.Key,
+		}
 		secretEnvVars = append(secretEnvVars, corev1.EnvVar{
 			Name: targetEnv,
 			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: secret.Name,
-					},
-					Key: secret.Key,
-				},
+				SecretKeyRef: secretKeyRef,
 			},
 		})
+
+		// A secretKeyRef cannot fall back to a value, so a secret with a default is optional
+		// and its default is exposed through a separate env var
+		if secret.Default != nil {
+			optional := true
+			secretKeyRef.Optional = &optional
+			secretEnvVars = append(secretEnvVars, corev1.EnvVar{
+				Name:  targetEnv + SecretDefaultEnvSuffix,
+				Value: *secret.Default,
+			})
+		}
 	}
 
 	if len(secretEnvVars) == 0 {
@@ -85,30 +133,240 @@ func (b *MCPServerPodTemplateSpecBuilder) WithSecrets(secrets []mcpv1alpha1.Secr
 	}
 
 	// add secret env vars to MCP container
-	mcpIndex := -1
-	for i, container := range b.spec.Spec.Containers {
-		if container.Name == mcpContainerName {
-			mcpIndex = i
-			break
+	mcpContainer := b.mcpContainer()
+	mcpContainer.Env = append(mcpContainer.Env, secretEnvVars...)
+	return b
+}
+
+// WithDownwardAPIEnv adds env vars populated from pod fields to the MCP container.
+// If no env vars are given, DefaultDownwardAPIEnv is used. Env vars that are already
+// defined on the MCP container are left untouched.
+func (b *MCPServerPodTemplateSpecBuilder) WithDownwardAPIEnv(envVars ...DownwardAPIEnvVar) *MCPServerPodTemplateSpecBuilder {
+	if len(envVars) == 0 {
+		envVars = DefaultDownwardAPIEnv
+	}
+
+	mcpContainer := b.mcpContainer()
+	for _, envVar := range envVars {
+		if slices.ContainsFunc(mcpContainer.Env, func(e corev1.EnvVar) bool { return e.Name == envVar.Name }) {
+			continue
+		}
+
+		mcpContainer.Env = append(mcpContainer.Env, corev1.EnvVar{
+			Name: envVar.Name,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: envVar.FieldPath,
+				},
+			},
+		})
+	}
+	return b
+}
+
+// WithTerminationGracePeriod sets how long the pod is given to shut down before it is killed.
+// Negative values are ignored.
+func (b *MCPServerPodTemplateSpecBuilder) WithTerminationGracePeriod(seconds int64) *MCPServerPodTemplateSpecBuilder {
+	if seconds < 0 {
+		return b
+	}
+
+	b.spec.Spec.TerminationGracePeriodSeconds = &seconds
+	return b
+}
+
+// WithPreStopHook sets the preStop lifecycle hook of the MCP container
+func (b *MCPServerPodTemplateSpecBuilder) WithPreStopHook(handler *corev1.LifecycleHandler) *MCPServerPodTemplateSpecBuilder {
+	if handler == nil {
+		return b
+	}
+
+	mcpContainer := b.mcpContainer()
+	if mcpContainer.Lifecycle == nil {
+		mcpContainer.Lifecycle = &corev1.Lifecycle{}
+	}
+	mcpContainer.Lifecycle.PreStop = handler
+	return b
+}
+
+// WithDNSPolicy sets the DNS policy of the pod
+func (b *MCPServerPodTemplateSpecBuilder) WithDNSPolicy(policy corev1.DNSPolicy) *MCPServerPodTemplateSpecBuilder {
+	if policy != "" {
+		b.spec.Spec.DNSPolicy = policy
+	}
+	return b
+}
+
+// WithDNSConfig merges the given DNS config into the pod's DNS config.
+// Nameservers and search domains are appended when not already present, and
+// options override any user-provided option with the same name.
+func (b *MCPServerPodTemplateSpecBuilder) WithDNSConfig(dnsConfig *corev1.PodDNSConfig) *MCPServerPodTemplateSpecBuilder {
+	if dnsConfig == nil {
+		return b
+	}
+
+	if b.spec.Spec.DNSConfig == nil {
+		b.spec.Spec.DNSConfig = dnsConfig.DeepCopy()
+		return b
+	}
+
+	current := b.spec.Spec.DNSConfig
+	for _, nameserver := range dnsConfig.Nameservers {
+		if !slices.Contains(current.Nameservers, nameserver) {
+			current.Nameservers = append(current.Nameservers, nameserver)
+		}
+	}
+	for _, search := range dnsConfig.Searches {
+		if !slices.Contains(current.Searches, search) {
+			current.Searches = append(current.Searches, search)
+		}
+	}
+	for _, option := range dnsConfig.Options {
+		option = *option.DeepCopy()
+		idx := slices.IndexFunc(current.Options, func(o corev1.PodDNSConfigOption) bool { return o.Name == option.Name })
+		if idx >= 0 {
+			current.Options[idx] = option
+		} else {
+			current.Options = append(current.Options, option)
+		}
+	}
+	return b
+}
+
+// WithHostAliases adds host aliases to the pod.
+// Aliases for an IP that is already present are merged into the existing entry.
+func (b *MCPServerPodTemplateSpecBuilder) WithHostAliases(hostAliases []corev1.HostAlias) *MCPServerPodTemplateSpecBuilder {
+	for _, alias := range hostAliases {
+		idx := slices.IndexFunc(b.spec.Spec.HostAliases, func(h corev1.HostAlias) bool { return h.IP == alias.IP })
+		if idx < 0 {
+			b.spec.Spec.HostAliases = append(b.spec.Spec.HostAliases, *alias.DeepCopy())
+			continue
+		}
+
+		existing := &b.spec.Spec.HostAliases[idx]
+		for _, hostname := range alias.Hostnames {
+			if !slices.Contains(existing.Hostnames, hostname) {
+				existing.Hostnames = append(existing.Hostnames, hostname)
+			}
 		}
 	}
+	return b
+}
 
-	if mcpIndex >= 0 {
-		// Merge env vars into existing MCP container
-		b.spec.Spec.Containers[mcpIndex].Env = append(
-			b.spec.Spec.Containers[mcpIndex].Env,
-			secretEnvVars...,
-		)
+// WithPodAnnotations merges the given annotations into the pod metadata.
+// Annotations already present, such as those set through the user's PodTemplateSpec, are kept.
+func (b *MCPServerPodTemplateSpecBuilder) WithPodAnnotations(annotations map[string]string) *MCPServerPodTemplateSpecBuilder {
+	b.spec.Annotations = mergeMissing(b.spec.Annotations, annotations)
+	return b
+}
+
+// WithPodLabels merges the given labels into the pod metadata.
+// Labels already present, such as those set through the user's PodTemplateSpec, are kept.
+func (b *MCPServerPodTemplateSpecBuilder) WithPodLabels(labels map[string]string) *MCPServerPodTemplateSpecBuilder {
+	b.spec.Labels = mergeMissing(b.spec.Labels, labels)
+	return b
+}
+
+// mergeMissing adds the entries of src that are not yet present in dst
+func mergeMissing(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for k, v := range src {
+		if _, exists := dst[k]; !exists {
+			dst[k] = v
+		}
+	}
+	return dst
+}
+
+// WithCABundle mounts the given key of a ConfigMap as a CA bundle in the MCP container and
+// points SSL_CERT_FILE and NODE_EXTRA_CA_CERTS at it, so TLS clients trust private upstream CAs.
+// If key is empty, ca.crt is used. Env vars already defined on the MCP container are left untouched.
+func (b *MCPServerPodTemplateSpecBuilder) WithCABundle(configMapName, key string) *MCPServerPodTemplateSpecBuilder {
+	if configMapName == "" {
+		return b
+	}
+	if key == "" {
+		key = caBundleFileName
+	}
+
+	volume := corev1.Volume{
+		Name: caBundleVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+				Items:                []corev1.KeyToPath{{Key: key, Path: caBundleFileName}},
+			},
+		},
+	}
+	if idx := slices.IndexFunc(b.spec.Spec.Volumes, func(v corev1.Volume) bool { return v.Name == caBundleVolumeName }); idx >= 0 {
+		b.spec.Spec.Volumes[idx] = volume
 	} else {
-		// Add new MCP container with env vars
-		b.spec.Spec.Containers = append(b.spec.Spec.Containers, corev1.Container{
-			Name: mcpContainerName,
-			Env:  secretEnvVars,
+		b.spec.Spec.Volumes = append(b.spec.Spec.Volumes, volume)
+	}
+
+	mcpContainer := b.mcpContainer()
+	if !slices.ContainsFunc(mcpContainer.VolumeMounts, func(m corev1.VolumeMount) bool { return m.Name == caBundleVolumeName }) {
+		mcpContainer.VolumeMounts = append(mcpContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      caBundleVolumeName,
+			MountPath: caBundleMountPath,
+			ReadOnly:  true,
 		})
 	}
+
+	caBundlePath := caBundleMountPath + "/" + caBundleFileName
+	for _, name := range []string{"SSL_CERT_FILE", "NODE_EXTRA_CA_CERTS"} {
+		if slices.ContainsFunc(mcpContainer.Env, func(e corev1.EnvVar) bool { return e.Name == name }) {
+			continue
+		}
+		mcpContainer.Env = append(mcpContainer.Env, corev1.EnvVar{Name: name, Value: caBundlePath})
+	}
+	return b
+}
+
+// WithEntrypointOverride sets the command of the MCP container.
+// The override arguments are passed to the MCP server through the run config instead.
+func (b *MCPServerPodTemplateSpecBuilder) WithEntrypointOverride(
+	override *mcpv1alpha1.EntrypointOverride,
+) *MCPServerPodTemplateSpecBuilder {
+	if override == nil || len(override.Command) == 0 {
+		return b
+	}
+
+	b.mcpContainer().Command = override.Command
 	return b
 }
 
+// WithImagePullPolicy sets the image pull policy of the MCP container.
+// A policy already set through the user's PodTemplateSpec is left untouched.
+func (b *MCPServerPodTemplateSpecBuilder) WithImagePullPolicy(policy corev1.PullPolicy) *MCPServerPodTemplateSpecBuilder {
+	if policy == "" {
+		return b
+	}
+
+	mcpContainer := b.mcpContainer()
+	if mcpContainer.ImagePullPolicy == "" {
+		mcpContainer.ImagePullPolicy = policy
+	}
+	return b
+}
+
+// mcpContainer returns the MCP container from the spec, adding it if it does not exist yet
+func (b *MCPServerPodTemplateSpecBuilder) mcpContainer() *corev1.Container {
+	for i := range b.spec.Spec.Containers {
+		if b.spec.Spec.Containers[i].Name == mcpContainerName {
+			return &b.spec.Spec.Containers[i]
+		}
+	}
+
+	b.spec.Spec.Containers = append(b.spec.Spec.Containers, corev1.Container{Name: mcpContainerName})
+	return &b.spec.Spec.Containers[len(b.spec.Spec.Containers)-1]
+}
+
 // Build returns the final PodTemplateSpec, or nil if no customizations were made
 func (b *MCPServerPodTemplateSpecBuilder) Build() *corev1.PodTemplateSpec {
 	// Return nil if the spec is effectively empty (no meaningful customizations)
@@ -123,7 +381,9 @@ func (b *MCPServerPodTemplateSpecBuilder) isEmpty() bool {
 	// Check if spec has any meaningful customizations
 	spec := b.spec.Spec
 
-	return spec.ServiceAccountName == "" &&
+	return len(b.spec.Annotations) == 0 &&
+		len(b.spec.Labels) == 0 &&
+		spec.ServiceAccountName == "" &&
 		len(spec.Containers) == 0 &&
 		len(spec.Volumes) == 0 &&
 		len(spec.InitContainers) == 0 &&
@@ -132,5 +392,9 @@ func (b *MCPServerPodTemplateSpecBuilder) isEmpty() bool {
 		spec.Affinity == nil &&
 		spec.SecurityContext == nil &&
 		spec.PriorityClassName == "" &&
+		spec.TerminationGracePeriodSeconds == nil &&
+		spec.DNSPolicy == "" &&
+		spec.DNSConfig == nil &&
+		len(spec.HostAliases) == 0 &&
 		len(spec.ImagePullSecrets) == 0
 }
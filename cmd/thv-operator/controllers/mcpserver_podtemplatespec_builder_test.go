@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
 
@@ -248,6 +249,38 @@ func TestMCPServerPodTemplateSpecBuilder_SecretEnvVarNaming(t *testing.T) {
 	}
 }
 
+func TestMCPServerPodTemplateSpecBuilder_SecretWithDefault(t *testing.T) {
+	t.Parallel()
+	defaultValue := "fallback"
+
+	builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+	require.NoError(t, err, "Failed to create builder")
+
+	result := builder.
+		WithSecrets([]mcpv1alpha1.SecretRef{
+			{Name: "secret1", Key: "token", TargetEnvName: "API_TOKEN", Default: &defaultValue},
+			{Name: "secret2", Key: "password"},
+		}).
+		Build()
+
+	require.NotNil(t, result)
+	mcpContainer := findMCPContainer(result.Spec.Containers)
+	require.NotNil(t, mcpContainer)
+	require.Len(t, mcpContainer.Env, 3)
+
+	// The secret with a default is optional and followed by its default env var
+	tokenEnv := mcpContainer.Env[0]
+	assert.Equal(t, "API_TOKEN", tokenEnv.Name)
+	require.NotNil(t, tokenEnv.ValueFrom.SecretKeyRef.Optional)
+	assert.True(t, *tokenEnv.ValueFrom.SecretKeyRef.Optional)
+	assert.Equal(t, corev1.EnvVar{Name: "API_TOKEN" + SecretDefaultEnvSuffix, Value: defaultValue}, mcpContainer.Env[1])
+
+	// Secrets without a default stay required
+	passwordEnv := mcpContainer.Env[2]
+	assert.Equal(t, "password", passwordEnv.Name)
+	assert.Nil(t, passwordEnv.ValueFrom.SecretKeyRef.Optional)
+}
+
 func TestMCPServerPodTemplateSpecBuilder_IsEmpty(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -286,6 +319,24 @@ func TestMCPServerPodTemplateSpecBuilder_IsEmpty(t *testing.T) {
 			expectedEmpty:  false,
 			expectedResult: true,
 		},
+		{
+			name: "with_pod_annotations",
+			setupBuilder: func() *MCPServerPodTemplateSpecBuilder {
+				builder, _ := NewMCPServerPodTemplateSpecBuilder(nil)
+				return builder.WithPodAnnotations(map[string]string{"sidecar.istio.io/inject": "false"})
+			},
+			expectedEmpty:  false,
+			expectedResult: true,
+		},
+		{
+			name: "with_pod_labels",
+			setupBuilder: func() *MCPServerPodTemplateSpecBuilder {
+				builder, _ := NewMCPServerPodTemplateSpecBuilder(nil)
+				return builder.WithPodLabels(map[string]string{"team": "platform"})
+			},
+			expectedEmpty:  false,
+			expectedResult: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -402,6 +453,398 @@ func TestMCPServerPodTemplateSpecBuilder_InvalidSpecWithSecrets(t *testing.T) {
 	require.Len(t, result.Spec.Containers[0].Env, 2)
 }
 
+func TestMCPServerPodTemplateSpecBuilder_WithDownwardAPIEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		envVars  []DownwardAPIEnvVar
+		existing []corev1.EnvVar
+		expected map[string]string
+	}{
+		{
+			name: "default pod identity env vars",
+			expected: map[string]string{
+				"POD_NAME":      "metadata.name",
+				"POD_NAMESPACE": "metadata.namespace",
+				"NODE_NAME":     "spec.nodeName",
+			},
+		},
+		{
+			name: "custom env var set",
+			envVars: []DownwardAPIEnvVar{
+				{Name: "MY_POD_IP", FieldPath: "status.podIP"},
+				{Name: "MY_POD_NAME", FieldPath: "metadata.name"},
+			},
+			expected: map[string]string{
+				"MY_POD_IP":   "status.podIP",
+				"MY_POD_NAME": "metadata.name",
+			},
+		},
+		{
+			name:     "existing env var is not overridden",
+			existing: []corev1.EnvVar{{Name: "POD_NAME", Value: "fixed"}},
+			expected: map[string]string{
+				"POD_NAMESPACE": "metadata.namespace",
+				"NODE_NAME":     "spec.nodeName",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var raw *runtime.RawExtension
+			if tt.existing != nil {
+				raw = podTemplateSpecToRawExtension(t, &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: mcpContainerName, Env: tt.existing}},
+					},
+				})
+			}
+
+			builder, err := NewMCPServerPodTemplateSpecBuilder(raw)
+			require.NoError(t, err)
+
+			result := builder.WithDownwardAPIEnv(tt.envVars...).Build()
+			require.NotNil(t, result)
+
+			mcpContainer := findMCPContainer(result.Spec.Containers)
+			require.NotNil(t, mcpContainer)
+			require.Len(t, mcpContainer.Env, len(tt.existing)+len(tt.expected))
+
+			for _, env := range mcpContainer.Env {
+				fieldPath, ok := tt.expected[env.Name]
+				if !ok {
+					assert.Empty(t, env.ValueFrom, "pre-existing env var %s should be untouched", env.Name)
+					continue
+				}
+				require.NotNil(t, env.ValueFrom, "env var %s should use a value source", env.Name)
+				require.NotNil(t, env.ValueFrom.FieldRef, "env var %s should use a fieldRef", env.Name)
+				assert.Equal(t, fieldPath, env.ValueFrom.FieldRef.FieldPath)
+			}
+		})
+	}
+}
+
+func TestMCPServerPodTemplateSpecBuilder_WithTerminationGracePeriodAndPreStopHook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("grace period and preStop hook are set", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		preStop := &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: []string{"sh", "-c", "sleep 5"}},
+		}
+		result := builder.
+			WithTerminationGracePeriod(60).
+			WithPreStopHook(preStop).
+			Build()
+		require.NotNil(t, result)
+
+		require.NotNil(t, result.Spec.TerminationGracePeriodSeconds)
+		assert.Equal(t, int64(60), *result.Spec.TerminationGracePeriodSeconds)
+
+		mcpContainer := findMCPContainer(result.Spec.Containers)
+		require.NotNil(t, mcpContainer)
+		require.NotNil(t, mcpContainer.Lifecycle)
+		assert.Equal(t, preStop, mcpContainer.Lifecycle.PreStop)
+	})
+
+	t.Run("grace period alone is a customization", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		result := builder.WithTerminationGracePeriod(0).Build()
+		require.NotNil(t, result)
+		require.NotNil(t, result.Spec.TerminationGracePeriodSeconds)
+		assert.Equal(t, int64(0), *result.Spec.TerminationGracePeriodSeconds)
+		assert.Empty(t, result.Spec.Containers)
+	})
+
+	t.Run("nil preStop and negative grace period are no-ops", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		result := builder.
+			WithTerminationGracePeriod(-1).
+			WithPreStopHook(nil).
+			Build()
+		assert.Nil(t, result)
+	})
+
+	t.Run("preStop keeps the existing postStart hook", func(t *testing.T) {
+		t.Parallel()
+
+		postStart := &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: []string{"echo", "started"}},
+		}
+		builder, err := NewMCPServerPodTemplateSpecBuilder(podTemplateSpecToRawExtension(t, &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:      mcpContainerName,
+					Lifecycle: &corev1.Lifecycle{PostStart: postStart},
+				}},
+			},
+		}))
+		require.NoError(t, err)
+
+		preStop := &corev1.LifecycleHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/drain"},
+		}
+		result := builder.WithPreStopHook(preStop).Build()
+		require.NotNil(t, result)
+
+		mcpContainer := findMCPContainer(result.Spec.Containers)
+		require.NotNil(t, mcpContainer)
+		require.NotNil(t, mcpContainer.Lifecycle)
+		assert.Equal(t, postStart, mcpContainer.Lifecycle.PostStart)
+		assert.Equal(t, preStop, mcpContainer.Lifecycle.PreStop)
+	})
+}
+
+func TestMCPServerPodTemplateSpecBuilder_WithDNS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DNS policy and config are set", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		dnsConfig := &corev1.PodDNSConfig{
+			Searches: []string{"svc.internal"},
+			Options:  []corev1.PodDNSConfigOption{{Name: "ndots", Value: ptr.To("2")}},
+		}
+		result := builder.
+			WithDNSPolicy(corev1.DNSClusterFirst).
+			WithDNSConfig(dnsConfig).
+			Build()
+		require.NotNil(t, result)
+
+		assert.Equal(t, corev1.DNSClusterFirst, result.Spec.DNSPolicy)
+		assert.Equal(t, dnsConfig, result.Spec.DNSConfig)
+		assert.Empty(t, result.Spec.Containers)
+	})
+
+	t.Run("DNS config merges with user template", func(t *testing.T) {
+		t.Parallel()
+
+		userTemplate := podTemplateSpecToRawExtension(t, &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				DNSPolicy: corev1.DNSNone,
+				DNSConfig: &corev1.PodDNSConfig{
+					Nameservers: []string{"10.0.0.10"},
+					Searches:    []string{"corp.example.com"},
+					Options: []corev1.PodDNSConfigOption{
+						{Name: "ndots", Value: ptr.To("5")},
+						{Name: "edns0"},
+					},
+				},
+			},
+		})
+		builder, err := NewMCPServerPodTemplateSpecBuilder(userTemplate)
+		require.NoError(t, err)
+
+		result := builder.WithDNSConfig(&corev1.PodDNSConfig{
+			Nameservers: []string{"10.0.0.10", "10.0.0.11"},
+			Searches:    []string{"svc.internal"},
+			Options:     []corev1.PodDNSConfigOption{{Name: "ndots", Value: ptr.To("2")}},
+		}).Build()
+		require.NotNil(t, result)
+
+		assert.Equal(t, corev1.DNSNone, result.Spec.DNSPolicy, "user DNS policy should be kept")
+		require.NotNil(t, result.Spec.DNSConfig)
+		assert.Equal(t, []string{"10.0.0.10", "10.0.0.11"}, result.Spec.DNSConfig.Nameservers)
+		assert.Equal(t, []string{"corp.example.com", "svc.internal"}, result.Spec.DNSConfig.Searches)
+		assert.Equal(t, []corev1.PodDNSConfigOption{
+			{Name: "ndots", Value: ptr.To("2")},
+			{Name: "edns0"},
+		}, result.Spec.DNSConfig.Options)
+	})
+
+	t.Run("empty DNS policy and nil config are no-ops", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		assert.Nil(t, builder.WithDNSPolicy("").WithDNSConfig(nil).Build())
+	})
+}
+
+func TestMCPServerPodTemplateSpecBuilder_WithHostAliases(t *testing.T) {
+	t.Parallel()
+
+	t.Run("host aliases are added", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		hostAliases := []corev1.HostAlias{
+			{IP: "10.1.2.3", Hostnames: []string{"api.internal"}},
+			{IP: "10.1.2.4", Hostnames: []string{"db.internal"}},
+		}
+		result := builder.WithHostAliases(hostAliases).Build()
+		require.NotNil(t, result)
+		assert.Equal(t, hostAliases, result.Spec.HostAliases)
+	})
+
+	t.Run("duplicate IPs are merged", func(t *testing.T) {
+		t.Parallel()
+
+		userTemplate := podTemplateSpecToRawExtension(t, &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				HostAliases: []corev1.HostAlias{
+					{IP: "10.1.2.3", Hostnames: []string{"api.internal"}},
+				},
+			},
+		})
+		builder, err := NewMCPServerPodTemplateSpecBuilder(userTemplate)
+		require.NoError(t, err)
+
+		result := builder.WithHostAliases([]corev1.HostAlias{
+			{IP: "10.1.2.3", Hostnames: []string{"api.internal", "api-v2.internal"}},
+			{IP: "10.1.2.5", Hostnames: []string{"cache.internal"}},
+			{IP: "10.1.2.5", Hostnames: []string{"cache-replica.internal"}},
+		}).Build()
+		require.NotNil(t, result)
+
+		assert.Equal(t, []corev1.HostAlias{
+			{IP: "10.1.2.3", Hostnames: []string{"api.internal", "api-v2.internal"}},
+			{IP: "10.1.2.5", Hostnames: []string{"cache.internal", "cache-replica.internal"}},
+		}, result.Spec.HostAliases)
+	})
+
+	t.Run("no host aliases is a no-op", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		assert.Nil(t, builder.WithHostAliases(nil).Build())
+	})
+}
+
+func TestMCPServerPodTemplateSpecBuilder_WithPodMetadata(t *testing.T) {
+	t.Parallel()
+
+	userTemplate := podTemplateSpecToRawExtension(t, &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"vault.hashicorp.com/agent-inject": "true",
+				"vault.hashicorp.com/role":         "mcp-server",
+			},
+			Labels: map[string]string{
+				"team": "platform",
+			},
+		},
+	})
+	builder, err := NewMCPServerPodTemplateSpecBuilder(userTemplate)
+	require.NoError(t, err)
+
+	result := builder.
+		WithPodAnnotations(map[string]string{
+			"sidecar.istio.io/inject":  "false",
+			"vault.hashicorp.com/role": "other-role",
+		}).
+		WithPodLabels(map[string]string{
+			"app.kubernetes.io/part-of": "mcp",
+			"team":                      "other-team",
+		}).
+		Build()
+	require.NotNil(t, result)
+
+	assert.Equal(t, map[string]string{
+		"vault.hashicorp.com/agent-inject": "true",
+		"vault.hashicorp.com/role":         "mcp-server",
+		"sidecar.istio.io/inject":          "false",
+	}, result.Annotations)
+	assert.Equal(t, map[string]string{
+		"team":                      "platform",
+		"app.kubernetes.io/part-of": "mcp",
+	}, result.Labels)
+}
+
+func TestMCPServerPodTemplateSpecBuilder_WithCABundle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mounts the CA bundle and sets env vars", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		result := builder.WithCABundle("internal-ca", "root.pem").Build()
+		require.NotNil(t, result)
+
+		require.Len(t, result.Spec.Volumes, 1)
+		volume := result.Spec.Volumes[0]
+		assert.Equal(t, caBundleVolumeName, volume.Name)
+		require.NotNil(t, volume.ConfigMap)
+		assert.Equal(t, "internal-ca", volume.ConfigMap.Name)
+		assert.Equal(t, []corev1.KeyToPath{{Key: "root.pem", Path: "ca.crt"}}, volume.ConfigMap.Items)
+
+		mcpContainer := findMCPContainer(result.Spec.Containers)
+		require.NotNil(t, mcpContainer)
+		assert.Equal(t, []corev1.VolumeMount{{
+			Name:      caBundleVolumeName,
+			MountPath: "/etc/toolhive/ca",
+			ReadOnly:  true,
+		}}, mcpContainer.VolumeMounts)
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: "SSL_CERT_FILE", Value: "/etc/toolhive/ca/ca.crt"},
+			{Name: "NODE_EXTRA_CA_CERTS", Value: "/etc/toolhive/ca/ca.crt"},
+		}, mcpContainer.Env)
+	})
+
+	t.Run("defaults the key and keeps existing env vars", func(t *testing.T) {
+		t.Parallel()
+
+		userTemplate := podTemplateSpecToRawExtension(t, &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: mcpContainerName,
+					Env:  []corev1.EnvVar{{Name: "SSL_CERT_FILE", Value: "/custom/ca.pem"}},
+				}},
+			},
+		})
+		builder, err := NewMCPServerPodTemplateSpecBuilder(userTemplate)
+		require.NoError(t, err)
+
+		result := builder.WithCABundle("internal-ca", "").Build()
+		require.NotNil(t, result)
+
+		require.Len(t, result.Spec.Volumes, 1)
+		assert.Equal(t, []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}}, result.Spec.Volumes[0].ConfigMap.Items)
+
+		mcpContainer := findMCPContainer(result.Spec.Containers)
+		require.NotNil(t, mcpContainer)
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: "SSL_CERT_FILE", Value: "/custom/ca.pem"},
+			{Name: "NODE_EXTRA_CA_CERTS", Value: "/etc/toolhive/ca/ca.crt"},
+		}, mcpContainer.Env)
+	})
+
+	t.Run("empty ConfigMap name is a no-op", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewMCPServerPodTemplateSpecBuilder(nil)
+		require.NoError(t, err)
+
+		assert.Nil(t, builder.WithCABundle("", "ca.crt").Build())
+	})
+}
+
 // Helper function to find MCP container in a slice
 func findMCPContainer(containers []corev1.Container) *corev1.Container {
 	for i, container := range containers {
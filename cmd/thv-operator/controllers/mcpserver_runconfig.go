@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	configMapChecksum "github.com/stacklok/toolhive/cmd/thv-operator/pkg/runconfig/configmap/checksum"
 	"github.com/stacklok/toolhive/pkg/operator/accessors"
 	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/secrets"
 	transporttypes "github.com/stacklok/toolhive/pkg/transport/types"
 )
 
@@ -72,6 +74,21 @@ func (r *MCPServerReconciler) ensureRunConfigConfigMap(ctx context.Context, m *m
 	return nil
 }
 
+// mcpServerCmdArgs returns the arguments passed to the MCP server container.
+// An entrypoint override replaces the args from the spec instead of adding to them.
+// The extra args from the spec go after them.
+func mcpServerCmdArgs(ctx context.Context, m *mcpv1alpha1.MCPServer) []string {
+	args := m.Spec.Args
+	if m.Spec.EntrypointOverride != nil {
+		args = m.Spec.EntrypointOverride.Args
+	}
+	extraArgs := extraArgsForMCPServer(ctx, m, args)
+	if len(extraArgs) == 0 {
+		return args
+	}
+	return append(slices.Clone(args), extraArgs...)
+}
+
 // createRunConfigFromMCPServer converts MCPServer spec to RunConfig using the builder pattern
 // This creates a RunConfig for serialization to ConfigMap, not for direct execution
 //
@@ -129,7 +146,7 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 	options := []runner.RunConfigBuilderOption{
 		runner.WithName(m.Name),
 		runner.WithImage(m.Spec.Image),
-		runner.WithCmdArgs(m.Spec.Args),
+		runner.WithCmdArgs(mcpServerCmdArgs(context.Background(), m)),
 		runner.WithTransportAndPorts(m.Spec.Transport, int(m.GetProxyPort()), int(m.GetMcpPort())),
 		runner.WithProxyMode(transporttypes.ProxyMode(proxyMode)),
 		runner.WithHost(proxyHost),
@@ -137,7 +154,9 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 		runner.WithToolsFilter(toolsFilter),
 		runner.WithEnvVars(envVars),
 		runner.WithVolumes(volumes),
-		// Secrets are NOT included in runconfig for ConfigMap mode - handled via k8s pod patch
+		// Kubernetes secrets are NOT included in runconfig for ConfigMap mode - handled via k8s pod patch.
+		// Vault secrets are included so that the runner expects them from the Vault Agent env files.
+		runner.WithSecrets(vaultSecretParameters(m.Spec.Secrets)),
 		runner.WithK8sPodPatch(k8sPodPatch),
 	}
 
@@ -170,7 +189,12 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 	defer cancel()
 
 	// Add telemetry configuration if specified
-	runconfig.AddTelemetryConfigOptions(ctx, &options, m.Spec.Telemetry, m.Name)
+	telemetryConfig, err := ctrlutil.ResolveTelemetryConfig(ctx, r.Client, m.Namespace, m.Spec.Telemetry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process OpenTelemetry config: %w", err)
+	}
+	runconfig.AddTelemetryConfigOptions(ctx, &options, telemetryConfig, m.Name,
+		proxyDeploymentEnv(m.Spec.ResourceOverrides))
 
 	// Add authorization configuration if specified
 
@@ -191,10 +215,10 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 	runconfig.AddAuditConfigOptions(&options, m.Spec.Audit)
 
 	// Check for Vault Agent Injection and add env-file-dir if needed
-	vaultDetected := false
+	vaultDetected := hasVaultSecrets(m.Spec.Secrets)
 
 	// Check for Vault injection in pod template annotations
-	if m.Spec.PodTemplateSpec != nil && m.Spec.PodTemplateSpec.Raw != nil {
+	if !vaultDetected && m.Spec.PodTemplateSpec != nil && m.Spec.PodTemplateSpec.Raw != nil {
 		// Try to unmarshal the raw extension to check annotations
 		var podTemplateSpec corev1.PodTemplateSpec
 		if err := json.Unmarshal(m.Spec.PodTemplateSpec.Raw, &podTemplateSpec); err == nil {
@@ -214,7 +238,7 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 	}
 
 	if vaultDetected {
-		options = append(options, runner.WithEnvFileDir("/vault/secrets"))
+		options = append(options, runner.WithEnvFileDir(vaultSecretsDir))
 	}
 
 	// Use the RunConfigBuilder for operator context with full builder pattern
@@ -405,11 +429,7 @@ func (*MCPServerReconciler) validateSecrets(config *runner.RunConfig) error {
 			return fmt.Errorf("secret cannot be empty")
 		}
 		// Basic format validation: should contain secret name and target
-		if !strings.Contains(secret, ",target=") {
-			return fmt.Errorf("invalid secret format: %s, expected secret-name,target=env-var-name", secret)
-		}
-		parts := strings.Split(secret, ",target=")
-		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		if _, err := secrets.ParseSecretParameter(secret); err != nil {
 			return fmt.Errorf("invalid secret format: %s, expected secret-name,target=env-var-name", secret)
 		}
 	}
@@ -443,6 +463,14 @@ func convertEnvVarsFromMCPServer(envs []mcpv1alpha1.EnvVar) map[string]string {
 	return envVars
 }
 
+// proxyDeploymentEnv returns the environment variables declared for the proxy container
+func proxyDeploymentEnv(overrides *mcpv1alpha1.ResourceOverrides) []mcpv1alpha1.EnvVar {
+	if overrides == nil || overrides.ProxyDeployment == nil {
+		return nil
+	}
+	return overrides.ProxyDeployment.Env
+}
+
 // convertVolumesFromMCPServer converts MCPServer volumes to builder format
 func convertVolumesFromMCPServer(vols []mcpv1alpha1.Volume) []string {
 	if len(vols) == 0 {
@@ -466,6 +494,6 @@ func hasVaultAgentInjection(annotations map[string]string) bool {
 	}
 
 	// Check if vault.hashicorp.com/agent-inject annotation is present and set to "true"
-	value, exists := annotations["vault.hashicorp.com/agent-inject"]
+	value, exists := annotations[vaultAgentInjectAnnotation]
 	return exists && value == "true"
 }
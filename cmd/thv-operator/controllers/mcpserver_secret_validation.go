@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+// hasSecretValueConstraints returns true if the secret value has a length or pattern constraint
+func hasSecretValueConstraints(secret mcpv1alpha1.SecretRef) bool {
+	return !secret.IsVault() && (secret.MinLength > 0 || secret.Pattern != "")
+}
+
+// validateSecretValue checks a secret value against the constraints of its reference.
+// The returned errors never include the value.
+func validateSecretValue(secret mcpv1alpha1.SecretRef, value []byte) error {
+	if secret.MinLength > 0 && utf8.RuneCount(value) < int(secret.MinLength) {
+		return fmt.Errorf("secret %q key %q: value is shorter than the minimum length %d",
+			secret.Name, secret.Key, secret.MinLength)
+	}
+	if secret.Pattern != "" {
+		pattern, err := regexp.Compile(secret.Pattern)
+		if err != nil {
+			return fmt.Errorf("secret %q key %q: invalid pattern: %w", secret.Name, secret.Key, err)
+		}
+		if !pattern.Match(value) {
+			return fmt.Errorf("secret %q key %q: value does not match the pattern %q",
+				secret.Name, secret.Key, secret.Pattern)
+		}
+	}
+	return nil
+}
+
+// validateSecretValues sets the SecretsValid condition on MCPServers whose Kubernetes secrets declare a
+// minimum length or a pattern, by reading the secret values. Missing secrets and keys are not reported
+// here, they either fall back to their default or prevent the pod from starting. As for the transport
+// configuration, invalid values are reported through the condition and an event, but do not block
+// reconciliation. Secret values are never logged nor included in the condition.
+func (r *MCPServerReconciler) validateSecretValues(ctx context.Context, mcpServer *mcpv1alpha1.MCPServer) {
+	ctxLogger := log.FromContext(ctx)
+
+	var errs []error
+	constrained := false
+	secrets := make(map[string]*corev1.Secret)
+	for _, ref := range mcpServer.Spec.Secrets {
+		if !hasSecretValueConstraints(ref) {
+			continue
+		}
+		constrained = true
+
+		secret, ok := secrets[ref.Name]
+		if !ok {
+			secret = &corev1.Secret{}
+			err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: mcpServer.Namespace}, secret)
+			if err != nil && !kerrors.IsNotFound(err) {
+				ctxLogger.Error(err, "Failed to get secret for value validation", "secret", ref.Name)
+				return
+			}
+			secrets[ref.Name] = secret
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			continue
+		}
+		if err := validateSecretValue(ref, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if !constrained {
+		// No secret value is constrained, drop the condition left over from a previous configuration if any
+		if meta.RemoveStatusCondition(&mcpServer.Status.Conditions, mcpv1alpha1.ConditionSecretsValid) {
+			if err := r.Status().Update(ctx, mcpServer); err != nil {
+				ctxLogger.Error(err, "Failed to update MCPServer status after secret value validation")
+			}
+		}
+		return
+	}
+
+	if len(errs) > 0 {
+		message := strings.ReplaceAll(errors.Join(errs...).Error(), "\n", "; ")
+		if r.Recorder != nil {
+			r.Recorder.Eventf(mcpServer, corev1.EventTypeWarning, mcpv1alpha1.ConditionReasonSecretsInvalid,
+				"Invalid secret values: %s", message)
+		}
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, metav1.Condition{
+			Type:               mcpv1alpha1.ConditionSecretsValid,
+			Status:             metav1.ConditionFalse,
+			Reason:             mcpv1alpha1.ConditionReasonSecretsInvalid,
+			Message:            message,
+			ObservedGeneration: mcpServer.Generation,
+		})
+	} else {
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, metav1.Condition{
+			Type:               mcpv1alpha1.ConditionSecretsValid,
+			Status:             metav1.ConditionTrue,
+			Reason:             mcpv1alpha1.ConditionReasonSecretsValid,
+			Message:            "Secret values meet their length and pattern constraints",
+			ObservedGeneration: mcpServer.Generation,
+		})
+	}
+
+	if err := r.Status().Update(ctx, mcpServer); err != nil {
+		ctxLogger.Error(err, "Failed to update MCPServer status after secret value validation")
+	}
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+func TestValidateSecretValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		secret        mcpv1alpha1.SecretRef
+		value         string
+		expectedError string
+	}{
+		{
+			name:   "valid value",
+			secret: mcpv1alpha1.SecretRef{Name: "github", Key: "token", MinLength: 8, Pattern: `^ghp_[A-Za-z0-9]+$`},
+			value:  "ghp_abcdef123456",
+		},
+		{
+			name:          "too short value",
+			secret:        mcpv1alpha1.SecretRef{Name: "github", Key: "token", MinLength: 40},
+			value:         "ghp_short",
+			expectedError: `secret "github" key "token": value is shorter than the minimum length 40`,
+		},
+		{
+			name:          "pattern mismatch",
+			secret:        mcpv1alpha1.SecretRef{Name: "github", Key: "token", Pattern: `^ghp_[A-Za-z0-9]+$`},
+			value:         "github_pat_abcdef",
+			expectedError: `secret "github" key "token": value does not match the pattern "^ghp_[A-Za-z0-9]+$"`,
+		},
+		{
+			name:          "invalid pattern",
+			secret:        mcpv1alpha1.SecretRef{Name: "github", Key: "token", Pattern: `^ghp_[`},
+			value:         "ghp_abcdef",
+			expectedError: "invalid pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateSecretValue(tt.secret, []byte(tt.value))
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			assert.NotContains(t, err.Error(), tt.value, "the secret value must never be reported")
+		})
+	}
+}
+
+func TestMCPServerReconciler_ValidateSecretValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		secrets      []mcpv1alpha1.SecretRef
+		expectStatus metav1.ConditionStatus
+		expectReason string
+	}{
+		{
+			name: "valid values",
+			secrets: []mcpv1alpha1.SecretRef{
+				{Name: "github", Key: "token", MinLength: 8, Pattern: `^ghp_`},
+				{Name: "github", Key: "missing", MinLength: 8},
+				{Name: "missing-secret", Key: "token", MinLength: 8},
+			},
+			expectStatus: metav1.ConditionTrue,
+			expectReason: mcpv1alpha1.ConditionReasonSecretsValid,
+		},
+		{
+			name:         "too short value",
+			secrets:      []mcpv1alpha1.SecretRef{{Name: "github", Key: "token", MinLength: 40}},
+			expectStatus: metav1.ConditionFalse,
+			expectReason: mcpv1alpha1.ConditionReasonSecretsInvalid,
+		},
+		{
+			name:         "pattern mismatch",
+			secrets:      []mcpv1alpha1.SecretRef{{Name: "github", Key: "token", Pattern: `^github_pat_`}},
+			expectStatus: metav1.ConditionFalse,
+			expectReason: mcpv1alpha1.ConditionReasonSecretsInvalid,
+		},
+		{
+			name:    "no constraints",
+			secrets: []mcpv1alpha1.SecretRef{{Name: "github", Key: "token"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := log.IntoContext(t.Context(), log.Log)
+
+			s := runtime.NewScheme()
+			require.NoError(t, scheme.AddToScheme(s))
+			require.NoError(t, mcpv1alpha1.AddToScheme(s))
+
+			mcpServer := &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-secrets",
+					Namespace: "default",
+				},
+				Spec: mcpv1alpha1.MCPServerSpec{
+					Image:   "test-image:latest",
+					Secrets: tt.secrets,
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "github",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{"token": []byte("ghp_abcdef123456")},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(s).
+				WithObjects(mcpServer, secret).
+				WithStatusSubresource(mcpServer).
+				Build()
+
+			eventRecorder := record.NewFakeRecorder(10)
+			r := &MCPServerReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: eventRecorder,
+			}
+
+			r.validateSecretValues(ctx, mcpServer)
+
+			var updated mcpv1alpha1.MCPServer
+			require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(mcpServer), &updated))
+
+			condition := meta.FindStatusCondition(updated.Status.Conditions, mcpv1alpha1.ConditionSecretsValid)
+			if tt.expectReason == "" {
+				assert.Nil(t, condition, "SecretsValid condition should not be set without value constraints")
+				assert.Empty(t, eventRecorder.Events)
+				return
+			}
+			require.NotNil(t, condition, "SecretsValid condition should be set")
+			assert.Equal(t, tt.expectStatus, condition.Status)
+			assert.Equal(t, tt.expectReason, condition.Reason)
+			assert.NotContains(t, condition.Message, "ghp_abcdef123456", "the secret value must never be reported")
+
+			if tt.expectStatus == metav1.ConditionFalse {
+				require.Len(t, eventRecorder.Events, 1)
+				event := <-eventRecorder.Events
+				assert.Contains(t, event, mcpv1alpha1.ConditionReasonSecretsInvalid)
+				assert.NotContains(t, event, "ghp_abcdef123456", "the secret value must never be reported")
+			} else {
+				assert.Empty(t, eventRecorder.Events)
+			}
+		})
+	}
+}
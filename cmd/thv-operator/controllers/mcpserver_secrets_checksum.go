@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+// SecretsChecksumAnnotationKey is the pod template annotation holding the checksum of the
+// secrets referenced by an MCPServer. A change in the checksum rolls the deployment.
+const SecretsChecksumAnnotationKey = "toolhive.stacklok.dev/secrets-checksum"
+
+// getSecretsChecksum computes a checksum over the values of the secret keys referenced by
+// the MCPServer. It returns an empty string when the MCPServer has not opted in to
+// restarting on secret changes or does not reference any secrets.
+func (r *MCPServerReconciler) getSecretsChecksum(ctx context.Context, m *mcpv1alpha1.MCPServer) (string, error) {
+	if !m.Spec.RestartOnSecretChange || len(m.Spec.Secrets) == 0 {
+		return "", nil
+	}
+
+	// Vault secrets are not stored in Kubernetes Secrets, so they cannot be watched
+	refs := make([]mcpv1alpha1.SecretRef, 0, len(m.Spec.Secrets))
+	for _, ref := range m.Spec.Secrets {
+		if !ref.IsVault() {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		return "", nil
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].Key < refs[j].Key
+	})
+
+	h := sha256.New()
+	secrets := make(map[string]*corev1.Secret)
+	for _, ref := range refs {
+		secret, ok := secrets[ref.Name]
+		if !ok {
+			secret = &corev1.Secret{}
+			err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: m.Namespace}, secret)
+			if err != nil && !(errors.IsNotFound(err) && ref.Default != nil) {
+				return "", fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+			}
+			secrets[ref.Name] = secret
+		}
+
+		value, ok := secret.Data[ref.Key]
+		if !ok && ref.Default == nil {
+			return "", fmt.Errorf("key %s not found in secret %s", ref.Key, ref.Name)
+		}
+
+		// Length-prefix each field so that different name/key/value splits cannot collide
+		for _, field := range [][]byte{[]byte(ref.Name), []byte(ref.Key)} {
+			_, _ = fmt.Fprintf(h, "%d:", len(field))
+			h.Write(field)
+		}
+		if !ok {
+			// A missing optional key is distinct from an empty value
+			_, _ = fmt.Fprint(h, "-1:")
+			continue
+		}
+		_, _ = fmt.Fprintf(h, "%d:", len(value))
+		h.Write(value)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// addSecretsChecksumToPodTemplate adds the secrets checksum annotation to the given annotations.
+// If the checksum is empty, no annotation is added.
+func addSecretsChecksumToPodTemplate(annotations map[string]string, secretsChecksum string) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if secretsChecksum != "" {
+		annotations[SecretsChecksumAnnotationKey] = secretsChecksum
+	}
+	return annotations
+}
+
+// mapSecretToMCPServers returns reconcile requests for the MCPServers that reference the
+// given secret and have opted in to restarting on secret changes.
+func (r *MCPServerReconciler) mapSecretToMCPServers(ctx context.Context, obj client.Object) []reconcile.Request {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil
+	}
+
+	mcpServerList := &mcpv1alpha1.MCPServerList{}
+	if err := r.List(ctx, mcpServerList, client.InNamespace(secret.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list MCPServers for Secret watch")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, server := range mcpServerList.Items {
+		if !server.Spec.RestartOnSecretChange {
+			continue
+		}
+		for _, ref := range server.Spec.Secrets {
+			if !ref.IsVault() && ref.Name == secret.Name {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      server.Name,
+						Namespace: server.Namespace,
+					},
+				})
+				break
+			}
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/cmd/thv-operator/pkg/runconfig/configmap/checksum"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func TestSecretsChecksumAnnotation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		optIn           bool
+		newData         map[string][]byte
+		expectAnnotated bool
+		expectChanged   bool
+	}{
+		{
+			name:            "annotation changes when secret data changes",
+			optIn:           true,
+			newData:         map[string][]byte{"token": []byte("rotated")},
+			expectAnnotated: true,
+			expectChanged:   true,
+		},
+		{
+			name:            "annotation is stable when secret data is unchanged",
+			optIn:           true,
+			newData:         map[string][]byte{"token": []byte("initial")},
+			expectAnnotated: true,
+			expectChanged:   false,
+		},
+		{
+			name:            "annotation is stable when unreferenced keys change",
+			optIn:           true,
+			newData:         map[string][]byte{"token": []byte("initial"), "other": []byte("value")},
+			expectAnnotated: true,
+			expectChanged:   false,
+		},
+		{
+			name:            "no annotation when not opted in",
+			optIn:           false,
+			newData:         map[string][]byte{"token": []byte("rotated")},
+			expectAnnotated: false,
+			expectChanged:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("secrets-checksum", "default")
+			mcpServer.Spec.RestartOnSecretChange = tt.optIn
+			mcpServer.Spec.Secrets = []mcpv1alpha1.SecretRef{{Name: "api-token", Key: "token"}}
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "default"},
+				Data:       map[string][]byte{"token": []byte("initial")},
+			}
+
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer, secret).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+			require.NotNil(t, dep)
+			before, annotated := dep.Spec.Template.Annotations[SecretsChecksumAnnotationKey]
+			assert.Equal(t, tt.expectAnnotated, annotated)
+			assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+
+			secret.Data = tt.newData
+			require.NoError(t, fakeClient.Update(ctx, secret))
+
+			assert.Equal(t, tt.expectChanged, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+			updated := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+			require.NotNil(t, updated)
+			after := updated.Spec.Template.Annotations[SecretsChecksumAnnotationKey]
+			if tt.expectChanged {
+				assert.NotEqual(t, before, after)
+			} else {
+				assert.Equal(t, before, after)
+			}
+		})
+	}
+}
+
+func TestSecretsChecksumAnnotation_WithPodTemplateOverrides(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServer("secrets-checksum", "default")
+	mcpServer.Spec.RestartOnSecretChange = true
+	mcpServer.Spec.Secrets = []mcpv1alpha1.SecretRef{{Name: "api-token", Key: "token"}}
+	mcpServer.Spec.CommonAnnotations = map[string]string{"owner": "platform@example.com", "team": "platform"}
+	mcpServer.Spec.ResourceOverrides = &mcpv1alpha1.ResourceOverrides{
+		ProxyDeployment: &mcpv1alpha1.ProxyDeploymentOverrides{
+			PodTemplateMetadataOverrides: &mcpv1alpha1.ResourceMetadataOverrides{
+				Annotations: map[string]string{"team": "pod-team", "prometheus.io/scrape": "true"},
+			},
+		},
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("initial")},
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer, secret).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+	annotations := dep.Spec.Template.Annotations
+	assert.Equal(t, "test-checksum", annotations[checksum.RunConfigChecksumAnnotation])
+	assert.NotEmpty(t, annotations[SecretsChecksumAnnotationKey])
+	assert.Equal(t, "platform@example.com", annotations["owner"])
+	assert.Equal(t, "pod-team", annotations["team"])
+	assert.Equal(t, "true", annotations["prometheus.io/scrape"])
+	assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+
+	secret.Data = map[string][]byte{"token": []byte("rotated")}
+	require.NoError(t, fakeClient.Update(ctx, secret))
+
+	assert.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+	updated := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, updated)
+	assert.NotEqual(t, annotations[SecretsChecksumAnnotationKey], updated.Spec.Template.Annotations[SecretsChecksumAnnotationKey])
+	assert.False(t, r.deploymentNeedsUpdate(ctx, updated, mcpServer, "test-checksum"))
+}
+
+func TestSecretsChecksumWithDefault(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	defaultValue := "fallback"
+
+	mcpServer := createTestMCPServer("secrets-checksum-default", "default")
+	mcpServer.Spec.RestartOnSecretChange = true
+	mcpServer.Spec.Secrets = []mcpv1alpha1.SecretRef{{Name: "api-token", Key: "token", Default: &defaultValue}}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	// A missing secret with a default does not fail the checksum
+	missing, err := r.getSecretsChecksum(ctx, mcpServer)
+	require.NoError(t, err)
+	assert.NotEmpty(t, missing)
+
+	// Creating the secret changes the checksum so the pods pick it up
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("value")},
+	}
+	require.NoError(t, fakeClient.Create(ctx, secret))
+
+	present, err := r.getSecretsChecksum(ctx, mcpServer)
+	require.NoError(t, err)
+	assert.NotEqual(t, missing, present)
+
+	// Without a default the missing secret is an error
+	mcpServer.Spec.Secrets = []mcpv1alpha1.SecretRef{{Name: "other-token", Key: "token"}}
+	_, err = r.getSecretsChecksum(ctx, mcpServer)
+	assert.Error(t, err)
+}
+
+func TestMapSecretToMCPServers(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	optedIn := createTestMCPServer("opted-in", "default")
+	optedIn.Spec.RestartOnSecretChange = true
+	optedIn.Spec.Secrets = []mcpv1alpha1.SecretRef{{Name: "api-token", Key: "token"}}
+
+	optedOut := createTestMCPServer("opted-out", "default")
+	optedOut.Spec.Secrets = []mcpv1alpha1.SecretRef{{Name: "api-token", Key: "token"}}
+
+	unrelated := createTestMCPServer("unrelated", "default")
+	unrelated.Spec.RestartOnSecretChange = true
+	unrelated.Spec.Secrets = []mcpv1alpha1.SecretRef{{Name: "other-secret", Key: "token"}}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(optedIn, optedOut, unrelated).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "default"}}
+	requests := r.mapSecretToMCPServers(ctx, secret)
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Name: "opted-in", Namespace: "default"}, requests[0].NamespacedName)
+}
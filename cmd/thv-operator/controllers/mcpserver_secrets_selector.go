@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"context"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SecretsLabelSelectorEnvVar is the environment variable used to configure, on the operator, the default
+// label selector limiting which Kubernetes secrets an MCP server's secrets provider may read
+// (e.g. "toolhive.stacklok.dev/mcp-visible=true"). The same variable is set on the proxy runner
+// so that its secrets provider configuration carries the selector.
+const SecretsLabelSelectorEnvVar = "TOOLHIVE_SECRETS_LABEL_SELECTOR"
+
+// secretsLabelSelectorEnvVars returns the proxy runner environment variables carrying the default
+// secrets label selector configured on the operator. An invalid selector is logged and ignored.
+func secretsLabelSelectorEnvVars(ctx context.Context) []corev1.EnvVar {
+	selector := os.Getenv(SecretsLabelSelectorEnvVar)
+	if selector == "" {
+		return nil
+	}
+
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		ctxLogger := log.FromContext(ctx)
+		ctxLogger.Error(err, "Ignoring invalid secrets label selector", "selector", selector)
+		return nil
+	}
+
+	return []corev1.EnvVar{{
+		Name:  SecretsLabelSelectorEnvVar,
+		Value: parsed.String(),
+	}}
+}
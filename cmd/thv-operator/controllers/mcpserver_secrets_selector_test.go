@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+//nolint:paralleltest // Cannot run in parallel due to environment variable manipulation
+func TestDeploymentForMCPServer_SecretsLabelSelector(t *testing.T) {
+	tests := []struct {
+		name          string
+		selector      string
+		expectedValue string
+		expectPresent bool
+	}{
+		{
+			name:          "selector configured on the operator",
+			selector:      "toolhive.stacklok.dev/mcp-visible=true,team in (platform)",
+			expectedValue: "team in (platform),toolhive.stacklok.dev/mcp-visible=true",
+			expectPresent: true,
+		},
+		{
+			name:          "no selector configured",
+			selector:      "",
+			expectPresent: false,
+		},
+		{
+			name:          "invalid selector is ignored",
+			selector:      "team in (",
+			expectPresent: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(SecretsLabelSelectorEnvVar, tt.selector)
+			ctx := t.Context()
+
+			mcpServer := createTestMCPServer("secrets-selector", "default")
+			testScheme := createTestScheme()
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+			r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+			dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+			require.NotNil(t, dep)
+			require.NotEmpty(t, dep.Spec.Template.Spec.Containers)
+
+			var found *corev1.EnvVar
+			for i, envVar := range dep.Spec.Template.Spec.Containers[0].Env {
+				if envVar.Name == SecretsLabelSelectorEnvVar {
+					found = &dep.Spec.Template.Spec.Containers[0].Env[i]
+				}
+			}
+			if tt.expectPresent {
+				require.NotNil(t, found, "proxy runner should carry the secrets label selector")
+				assert.Equal(t, tt.expectedValue, found.Value)
+			} else {
+				assert.Nil(t, found)
+			}
+
+			assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+		})
+	}
+
+	t.Run("changing the selector requires a deployment update", func(t *testing.T) {
+		t.Setenv(SecretsLabelSelectorEnvVar, "tier=backend")
+		ctx := t.Context()
+
+		mcpServer := createTestMCPServer("secrets-selector", "default")
+		testScheme := createTestScheme()
+		fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+		r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+		dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+		require.NotNil(t, dep)
+
+		t.Setenv(SecretsLabelSelectorEnvVar, "tier=frontend")
+		assert.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+	})
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	transporttypes "github.com/stacklok/toolhive/pkg/transport/types"
+)
+
+// validateTransportPorts cross-checks the transport of an MCPServer against its port configuration.
+// The stdio transport talks to the MCP server over stdin/stdout so it must not declare an MCP port.
+// The HTTP-based transports use the default port of GetMcpPort when no MCP port is declared.
+func validateTransportPorts(m *mcpv1alpha1.MCPServer) error {
+	mcpPortSet := m.Spec.McpPort > 0 || m.Spec.TargetPort > 0
+
+	if (m.Spec.Transport == "" || m.Spec.Transport == transporttypes.TransportTypeStdio.String()) && mcpPortSet {
+		return fmt.Errorf("transport stdio does not use a network port, but mcpPort/targetPort is set to %d",
+			m.GetMcpPort())
+	}
+
+	return nil
+}
+
+// validateTransportConfig sets the TransportConfigValid condition on the MCPServer.
+// An invalid configuration is reported through the condition and an event, but does not
+// block reconciliation so that existing workloads keep running.
+func (r *MCPServerReconciler) validateTransportConfig(ctx context.Context, mcpServer *mcpv1alpha1.MCPServer) {
+	ctxLogger := log.FromContext(ctx)
+
+	if err := validateTransportPorts(mcpServer); err != nil {
+		if r.Recorder != nil {
+			r.Recorder.Eventf(mcpServer, corev1.EventTypeWarning, mcpv1alpha1.ConditionReasonTransportConfigInvalid,
+				"Invalid transport configuration: %v", err)
+		}
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, metav1.Condition{
+			Type:               mcpv1alpha1.ConditionTransportConfigValid,
+			Status:             metav1.ConditionFalse,
+			Reason:             mcpv1alpha1.ConditionReasonTransportConfigInvalid,
+			Message:            err.Error(),
+			ObservedGeneration: mcpServer.Generation,
+		})
+	} else {
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, metav1.Condition{
+			Type:               mcpv1alpha1.ConditionTransportConfigValid,
+			Status:             metav1.ConditionTrue,
+			Reason:             mcpv1alpha1.ConditionReasonTransportConfigValid,
+			Message:            "Transport and port configuration are consistent",
+			ObservedGeneration: mcpServer.Generation,
+		})
+	}
+
+	if err := r.Status().Update(ctx, mcpServer); err != nil {
+		ctxLogger.Error(err, "Failed to update MCPServer status after transport validation")
+	}
+}
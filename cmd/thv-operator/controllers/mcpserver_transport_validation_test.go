@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+func TestMCPServerReconciler_ValidateTransportConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		spec         mcpv1alpha1.MCPServerSpec
+		expectStatus metav1.ConditionStatus
+		expectReason string
+		expectEvent  bool
+	}{
+		{
+			name:         "stdio with mcpPort",
+			spec:         mcpv1alpha1.MCPServerSpec{Transport: "stdio", McpPort: 8081},
+			expectStatus: metav1.ConditionFalse,
+			expectReason: mcpv1alpha1.ConditionReasonTransportConfigInvalid,
+			expectEvent:  true,
+		},
+		{
+			name:         "stdio with deprecated targetPort",
+			spec:         mcpv1alpha1.MCPServerSpec{Transport: "stdio", TargetPort: 8081},
+			expectStatus: metav1.ConditionFalse,
+			expectReason: mcpv1alpha1.ConditionReasonTransportConfigInvalid,
+			expectEvent:  true,
+		},
+		{
+			name:         "sse without mcpPort uses the default port",
+			spec:         mcpv1alpha1.MCPServerSpec{Transport: "sse"},
+			expectStatus: metav1.ConditionTrue,
+			expectReason: mcpv1alpha1.ConditionReasonTransportConfigValid,
+		},
+		{
+			name:         "streamable-http without mcpPort uses the default port",
+			spec:         mcpv1alpha1.MCPServerSpec{Transport: "streamable-http"},
+			expectStatus: metav1.ConditionTrue,
+			expectReason: mcpv1alpha1.ConditionReasonTransportConfigValid,
+		},
+		{
+			name:         "stdio without mcpPort",
+			spec:         mcpv1alpha1.MCPServerSpec{Transport: "stdio"},
+			expectStatus: metav1.ConditionTrue,
+			expectReason: mcpv1alpha1.ConditionReasonTransportConfigValid,
+		},
+		{
+			name:         "streamable-http with mcpPort",
+			spec:         mcpv1alpha1.MCPServerSpec{Transport: "streamable-http", McpPort: 8080},
+			expectStatus: metav1.ConditionTrue,
+			expectReason: mcpv1alpha1.ConditionReasonTransportConfigValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := log.IntoContext(t.Context(), log.Log)
+
+			s := runtime.NewScheme()
+			require.NoError(t, scheme.AddToScheme(s))
+			require.NoError(t, mcpv1alpha1.AddToScheme(s))
+
+			mcpServer := &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-transport",
+					Namespace: "default",
+				},
+				Spec: tt.spec,
+			}
+			mcpServer.Spec.Image = "test-image:latest"
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(s).
+				WithObjects(mcpServer).
+				WithStatusSubresource(mcpServer).
+				Build()
+
+			eventRecorder := record.NewFakeRecorder(10)
+			r := &MCPServerReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: eventRecorder,
+			}
+
+			r.validateTransportConfig(ctx, mcpServer)
+
+			var updated mcpv1alpha1.MCPServer
+			require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(mcpServer), &updated))
+
+			condition := meta.FindStatusCondition(updated.Status.Conditions, mcpv1alpha1.ConditionTransportConfigValid)
+			require.NotNil(t, condition, "TransportConfigValid condition should be set")
+			assert.Equal(t, tt.expectStatus, condition.Status)
+			assert.Equal(t, tt.expectReason, condition.Reason)
+
+			if tt.expectEvent {
+				require.Len(t, eventRecorder.Events, 1)
+				event := <-eventRecorder.Events
+				assert.Contains(t, event, "Warning")
+				assert.Contains(t, event, mcpv1alpha1.ConditionReasonTransportConfigInvalid)
+			} else {
+				assert.Empty(t, eventRecorder.Events)
+			}
+		})
+	}
+}
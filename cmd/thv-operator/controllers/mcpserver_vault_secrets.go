@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+const (
+	// vaultAgentInjectSecretAnnotationPrefix is the prefix of the annotation asking the Vault Agent
+	// to render a secret into /vault/secrets/<file>
+	vaultAgentInjectSecretAnnotationPrefix = "vault.hashicorp.com/agent-inject-secret-"
+	// vaultAgentInjectTemplateAnnotationPrefix is the prefix of the annotation holding the template
+	// used by the Vault Agent to render /vault/secrets/<file>
+	vaultAgentInjectTemplateAnnotationPrefix = "vault.hashicorp.com/agent-inject-template-"
+	// vaultAgentInjectCommandAnnotationPrefix is the prefix of the annotation holding the command
+	// run by the Vault Agent after rendering /vault/secrets/<file>
+	vaultAgentInjectCommandAnnotationPrefix = "vault.hashicorp.com/agent-inject-command-"
+	// vaultSecretFilesAnnotation lists the files of the secret-specific Vault Agent annotations rendered by the
+	// operator, so that they can be told apart from those set by the user through the pod template overrides
+	vaultSecretFilesAnnotation = "toolhive.stacklok.dev/vault-secret-files"
+	// vaultSecretsDir is the directory the Vault Agent renders secrets into
+	vaultSecretsDir = "/vault/secrets"
+
+	// Annotations setting the resources of the Vault Agent containers
+	vaultAgentRequestsCPUAnnotation = "vault.hashicorp.com/agent-requests-cpu"
+	vaultAgentRequestsMemAnnotation = "vault.hashicorp.com/agent-requests-mem"
+	vaultAgentLimitsCPUAnnotation   = "vault.hashicorp.com/agent-limits-cpu"
+	vaultAgentLimitsMemAnnotation   = "vault.hashicorp.com/agent-limits-mem"
+
+	// Annotations configuring how the Vault Agent verifies the TLS certificate of the Vault server
+	vaultTLSSecretAnnotation     = "vault.hashicorp.com/tls-secret"
+	vaultCACertAnnotation        = "vault.hashicorp.com/ca-cert"
+	vaultTLSSkipVerifyAnnotation = "vault.hashicorp.com/tls-skip-verify"
+	vaultTLSServerNameAnnotation = "vault.hashicorp.com/tls-server-name"
+	// vaultTLSDir is the directory the Vault Agent injector mounts the tls-secret into
+	vaultTLSDir = "/vault/tls"
+)
+
+// secretTargetEnvName returns the environment variable a secret is exposed as
+func secretTargetEnvName(secret mcpv1alpha1.SecretRef) string {
+	if secret.TargetEnvName != "" {
+		return secret.TargetEnvName
+	}
+	return secret.Key
+}
+
+// hasVaultSecrets returns true if any of the secrets is rendered by the Vault Agent
+func hasVaultSecrets(secretRefs []mcpv1alpha1.SecretRef) bool {
+	for _, secret := range secretRefs {
+		if secret.IsVault() {
+			return true
+		}
+	}
+	return false
+}
+
+// vaultSecretParameters returns the `--secret` parameters for the vault-typed secrets of an MCPServer.
+// The runner does not resolve these through a secrets provider, it expects their values in the env
+// files rendered by the Vault Agent.
+func vaultSecretParameters(secretRefs []mcpv1alpha1.SecretRef) []string {
+	var params []string
+	for _, secret := range secretRefs {
+		if !secret.IsVault() {
+			continue
+		}
+		params = append(params, secrets.SecretParameter{
+			Name:   secret.Name,
+			Target: secretTargetEnvName(secret),
+			Type:   secrets.SecretParameterTypeVault,
+		}.ToCLIString())
+	}
+	return params
+}
+
+// vaultSecretsRole returns the Vault role declared by the vault secrets, empty if none declares one.
+// The Vault Agent authenticates with a single role, so the secrets declaring different roles are an error.
+func vaultSecretsRole(secretRefs []mcpv1alpha1.SecretRef) (string, error) {
+	var role, roleSecret string
+	for _, secret := range secretRefs {
+		if !secret.IsVault() || secret.Role == "" {
+			continue
+		}
+		if role == "" {
+			role, roleSecret = secret.Role, secret.Name
+			continue
+		}
+		if secret.Role != role {
+			return "", fmt.Errorf("vault secrets %q and %q declare the different roles %q and %q, "+
+				"but the Vault Agent authenticates with a single role", roleSecret, secret.Name, role, secret.Role)
+		}
+	}
+	return role, nil
+}
+
+// addVaultSecretAnnotations adds the Vault Agent annotations rendering each vault-typed secret as a
+// KEY=VALUE env file in the vault secrets directory, and running its command after rendering it if any,
+// along with the annotations of the Vault Agent configuration.
+// Vault Agent Injection itself (agent-inject and role) must be enabled through the proxy deployment
+// pod template metadata overrides.
+// The secret-specific annotations rendered by the operator are managed as a whole: those rendered by a
+// previous call, e.g. for other secrets, are replaced by the ones of the current vault secrets, while
+// those set by the user are kept.
+func addVaultSecretAnnotations(
+	annotations map[string]string,
+	secretRefs []mcpv1alpha1.SecretRef,
+	vaultAgent *mcpv1alpha1.VaultAgentConfig,
+) map[string]string {
+	removeVaultSecretAnnotations(annotations)
+	if !hasVaultSecrets(secretRefs) {
+		return annotations
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	var files []string
+	for _, secret := range secretRefs {
+		if !secret.IsVault() {
+			continue
+		}
+		target := secretTargetEnvName(secret)
+		file := strings.ReplaceAll(strings.ToLower(target), "_", "-")
+		files = append(files, file)
+		annotations[vaultAgentInjectSecretAnnotationPrefix+file] = secret.Name
+		annotations[vaultAgentInjectTemplateAnnotationPrefix+file] = fmt.Sprintf(
+			"{{- with secret %q -}}\n%s={{ index .Data.data %q }}\n{{- end -}}", secret.Name, target, secret.Key)
+		if secret.Command != "" {
+			annotations[vaultAgentInjectCommandAnnotationPrefix+file] = secret.Command
+		}
+	}
+	annotations[vaultSecretFilesAnnotation] = strings.Join(files, ",")
+
+	// The role annotation set through the pod template overrides takes precedence,
+	// conflicting roles are reported by validateVaultConfig
+	if role, err := vaultSecretsRole(secretRefs); err == nil && role != "" && annotations[vaultRoleAnnotation] == "" {
+		annotations[vaultRoleAnnotation] = role
+	}
+
+	for annotation, value := range vaultAgentAnnotations(vaultAgent) {
+		if value != "" {
+			annotations[annotation] = value
+		}
+	}
+	return annotations
+}
+
+// removeVaultSecretAnnotations removes the secret-specific Vault Agent annotations rendered by addVaultSecretAnnotations
+func removeVaultSecretAnnotations(annotations map[string]string) {
+	files, ok := annotations[vaultSecretFilesAnnotation]
+	if !ok {
+		return
+	}
+	for _, file := range strings.Split(files, ",") {
+		delete(annotations, vaultAgentInjectSecretAnnotationPrefix+file)
+		delete(annotations, vaultAgentInjectTemplateAnnotationPrefix+file)
+		delete(annotations, vaultAgentInjectCommandAnnotationPrefix+file)
+	}
+	delete(annotations, vaultSecretFilesAnnotation)
+}
+
+// vaultAgentAnnotations returns the annotations configuring the Vault Agent, empty values are not to be set
+func vaultAgentAnnotations(vaultAgent *mcpv1alpha1.VaultAgentConfig) map[string]string {
+	if vaultAgent == nil {
+		return nil
+	}
+
+	annotations := map[string]string{
+		vaultAgentRequestsCPUAnnotation: vaultAgent.Resources.Requests.CPU,
+		vaultAgentRequestsMemAnnotation: vaultAgent.Resources.Requests.Memory,
+		vaultAgentLimitsCPUAnnotation:   vaultAgent.Resources.Limits.CPU,
+		vaultAgentLimitsMemAnnotation:   vaultAgent.Resources.Limits.Memory,
+		vaultTLSServerNameAnnotation:    vaultAgent.TLSServerName,
+	}
+	if vaultAgent.TLSCACert != nil {
+		annotations[vaultTLSSecretAnnotation] = vaultAgent.TLSCACert.Name
+		annotations[vaultCACertAnnotation] = path.Join(vaultTLSDir, vaultAgent.TLSCACert.Key)
+	}
+	if vaultAgent.TLSSkipVerify {
+		annotations[vaultTLSSkipVerifyAnnotation] = "true"
+	}
+	return annotations
+}
@@ -0,0 +1,406 @@
+package controllers
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+)
+
+func createTestMCPServerWithVaultSecret(name, namespace string) *mcpv1alpha1.MCPServer {
+	mcpServer := createTestMCPServer(name, namespace)
+	mcpServer.Spec.Secrets = []mcpv1alpha1.SecretRef{
+		{Name: "github", Key: "token", TargetEnvName: "GITHUB_TOKEN"},
+		{
+			Name:          "workload-secrets/data/github-mcp/config",
+			Key:           "token",
+			TargetEnvName: "GITHUB_PERSONAL_ACCESS_TOKEN",
+			Type:          mcpv1alpha1.SecretRefTypeVault,
+		},
+	}
+	mcpServer.Spec.ResourceOverrides = &mcpv1alpha1.ResourceOverrides{
+		ProxyDeployment: &mcpv1alpha1.ProxyDeploymentOverrides{
+			PodTemplateMetadataOverrides: &mcpv1alpha1.ResourceMetadataOverrides{
+				Annotations: map[string]string{
+					"vault.hashicorp.com/agent-inject": "true",
+					"vault.hashicorp.com/role":         "toolhive-mcp-workloads",
+				},
+			},
+		},
+	}
+	return mcpServer
+}
+
+func TestVaultSecretsDeployment(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServerWithVaultSecret("vault-secret-server", "default")
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+
+	var podTemplatePatch string
+	for _, arg := range dep.Spec.Template.Spec.Containers[0].Args {
+		if strings.HasPrefix(arg, "--k8s-pod-patch=") {
+			podTemplatePatch = strings.TrimPrefix(arg, "--k8s-pod-patch=")
+			break
+		}
+	}
+	require.NotEmpty(t, podTemplatePatch, "Pod template patch should be present in args")
+
+	var podTemplateSpec corev1.PodTemplateSpec
+	require.NoError(t, json.Unmarshal([]byte(podTemplatePatch), &podTemplateSpec))
+
+	secretKeyRefs := map[string]*corev1.SecretKeySelector{}
+	for _, container := range podTemplateSpec.Spec.Containers {
+		if container.Name != mcpContainerName {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				secretKeyRefs[env.Name] = env.ValueFrom.SecretKeyRef
+			}
+		}
+	}
+	require.Contains(t, secretKeyRefs, "GITHUB_TOKEN", "Kubernetes secrets should use a secretKeyRef")
+	assert.NotContains(t, secretKeyRefs, "GITHUB_PERSONAL_ACCESS_TOKEN",
+		"Vault secrets should not produce a Kubernetes secretKeyRef")
+
+	annotations := dep.Spec.Template.Annotations
+	assert.Equal(t, "workload-secrets/data/github-mcp/config",
+		annotations["vault.hashicorp.com/agent-inject-secret-github-personal-access-token"])
+	assert.Equal(t,
+		"{{- with secret \"workload-secrets/data/github-mcp/config\" -}}\n"+
+			"GITHUB_PERSONAL_ACCESS_TOKEN={{ index .Data.data \"token\" }}\n{{- end -}}",
+		annotations["vault.hashicorp.com/agent-inject-template-github-personal-access-token"])
+	assert.Equal(t, "true", annotations["vault.hashicorp.com/agent-inject"])
+}
+
+func TestVaultSecretsRemoved(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServerWithVaultSecret("vault-secret-server", "default")
+	mcpServer.Spec.Secrets[1].Command = "kill -HUP 1"
+	mcpServer.Spec.VaultAgent = &mcpv1alpha1.VaultAgentConfig{
+		Resources:     mcpv1alpha1.ResourceRequirements{Limits: mcpv1alpha1.ResourceList{Memory: "128Mi"}},
+		TLSServerName: "vault.internal.example.com",
+	}
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+	require.Contains(t, dep.Spec.Template.Annotations, "vault.hashicorp.com/agent-inject-secret-github-personal-access-token")
+
+	// Remove the vault secret along with the Vault Agent Injection overrides
+	mcpServer.Spec.Secrets = mcpServer.Spec.Secrets[:1]
+	mcpServer.Spec.ResourceOverrides = nil
+	require.True(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"),
+		"removing the vault secrets should be detected as drift")
+
+	r.updateDeploymentForMCPServer(ctx, dep, mcpServer, "test-checksum")
+	for key := range dep.Spec.Template.Annotations {
+		assert.False(t, strings.HasPrefix(key, "vault.hashicorp.com/"), "stale Vault annotation %s", key)
+	}
+	assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+}
+
+func TestVaultSecretsKeepUserAnnotations(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	// The user renders a config file through the Vault Agent alongside the vault secrets of the operator
+	mcpServer := createTestMCPServerWithVaultSecret("vault-secret-server", "default")
+	userAnnotations := mcpServer.Spec.ResourceOverrides.ProxyDeployment.PodTemplateMetadataOverrides.Annotations
+	userAnnotations["vault.hashicorp.com/agent-inject-secret-config"] = "workload-secrets/data/github-mcp/config"
+	userAnnotations["vault.hashicorp.com/agent-inject-template-config"] =
+		"{{- with secret \"workload-secrets/data/github-mcp/config\" -}}{{ .Data.data.url }}{{- end -}}"
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	dep := r.deploymentForMCPServer(ctx, mcpServer, "test-checksum")
+	require.NotNil(t, dep)
+
+	annotations := dep.Spec.Template.Annotations
+	assert.Equal(t, userAnnotations["vault.hashicorp.com/agent-inject-secret-config"],
+		annotations["vault.hashicorp.com/agent-inject-secret-config"])
+	assert.Equal(t, userAnnotations["vault.hashicorp.com/agent-inject-template-config"],
+		annotations["vault.hashicorp.com/agent-inject-template-config"])
+	assert.Contains(t, annotations, "vault.hashicorp.com/agent-inject-secret-github-personal-access-token")
+	assert.False(t, r.deploymentNeedsUpdate(ctx, dep, mcpServer, "test-checksum"))
+
+	// Re-rendering the annotations replaces those of the operator only
+	rerendered := addVaultSecretAnnotations(maps.Clone(annotations), []mcpv1alpha1.SecretRef{{
+		Name:          "workload-secrets/data/slack-mcp/config",
+		Key:           "token",
+		TargetEnvName: "SLACK_TOKEN",
+		Type:          mcpv1alpha1.SecretRefTypeVault,
+	}}, nil)
+	assert.Contains(t, rerendered, "vault.hashicorp.com/agent-inject-secret-config")
+	assert.Contains(t, rerendered, "vault.hashicorp.com/agent-inject-template-config")
+	assert.Contains(t, rerendered, "vault.hashicorp.com/agent-inject-secret-slack-token")
+	assert.NotContains(t, rerendered, "vault.hashicorp.com/agent-inject-secret-github-personal-access-token")
+}
+
+func TestVaultSecretsRunConfig(t *testing.T) {
+	t.Parallel()
+
+	mcpServer := createTestMCPServerWithVaultSecret("vault-secret-server", "default")
+	r := newTestMCPServerReconciler(nil, nil, kubernetes.PlatformKubernetes)
+
+	runConfig, err := r.createRunConfigFromMCPServer(mcpServer)
+	require.NoError(t, err)
+
+	// Only the vault secret is passed to the runner, Kubernetes secrets are handled via the pod patch
+	assert.Equal(t, []string{
+		"workload-secrets/data/github-mcp/config,target=GITHUB_PERSONAL_ACCESS_TOKEN,type=vault",
+	}, runConfig.Secrets)
+	assert.Equal(t, vaultSecretsDir, runConfig.EnvFileDir)
+}
+
+func TestVaultSecretsChecksum(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mcpServer := createTestMCPServerWithVaultSecret("vault-secret-server", "default")
+	mcpServer.Spec.RestartOnSecretChange = true
+	mcpServer.Spec.Secrets = mcpServer.Spec.Secrets[1:]
+
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(mcpServer).Build()
+	r := newTestMCPServerReconciler(fakeClient, testScheme, kubernetes.PlatformKubernetes)
+
+	// Vault secrets are not looked up as Kubernetes Secrets
+	checksum, err := r.getSecretsChecksum(ctx, mcpServer)
+	require.NoError(t, err)
+	assert.Empty(t, checksum)
+}
+
+func TestAddVaultSecretAnnotations_Command(t *testing.T) {
+	t.Parallel()
+
+	secretRefs := []mcpv1alpha1.SecretRef{
+		{
+			Name:          "workload-secrets/data/github-mcp/config",
+			Key:           "token",
+			TargetEnvName: "GITHUB_PERSONAL_ACCESS_TOKEN",
+			Type:          mcpv1alpha1.SecretRefTypeVault,
+			Command:       "kill -HUP $(pidof thv)",
+		},
+		{
+			Name:          "workload-secrets/data/slack-mcp/config",
+			Key:           "token",
+			TargetEnvName: "SLACK_TOKEN",
+			Type:          mcpv1alpha1.SecretRefTypeVault,
+		},
+	}
+
+	annotations := addVaultSecretAnnotations(nil, secretRefs, nil)
+
+	assert.Equal(t, "kill -HUP $(pidof thv)",
+		annotations["vault.hashicorp.com/agent-inject-command-github-personal-access-token"])
+	assert.Contains(t, annotations, "vault.hashicorp.com/agent-inject-secret-slack-token")
+	assert.NotContains(t, annotations, "vault.hashicorp.com/agent-inject-command-slack-token",
+		"no command annotation should be emitted for secrets without a command")
+}
+
+func TestAddVaultSecretAnnotations_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	githubSecret := mcpv1alpha1.SecretRef{
+		Name:          "workload-secrets/data/github-mcp/config",
+		Key:           "token",
+		TargetEnvName: "GITHUB_PERSONAL_ACCESS_TOKEN",
+		Type:          mcpv1alpha1.SecretRefTypeVault,
+		Command:       "kill -HUP 1",
+	}
+	slackSecret := mcpv1alpha1.SecretRef{
+		Name:          "workload-secrets/data/slack-mcp/config",
+		Key:           "token",
+		TargetEnvName: "SLACK_TOKEN",
+		Type:          mcpv1alpha1.SecretRefTypeVault,
+	}
+	base := map[string]string{
+		"vault.hashicorp.com/agent-inject": "true",
+		"vault.hashicorp.com/role":         "toolhive-mcp-workloads",
+	}
+
+	first := addVaultSecretAnnotations(maps.Clone(base), []mcpv1alpha1.SecretRef{githubSecret}, nil)
+	assert.Equal(t, first, addVaultSecretAnnotations(maps.Clone(first), []mcpv1alpha1.SecretRef{githubSecret}, nil),
+		"rendering the same secrets again should not change the annotations")
+
+	second := addVaultSecretAnnotations(first, []mcpv1alpha1.SecretRef{slackSecret}, nil)
+	assert.Equal(t, addVaultSecretAnnotations(maps.Clone(base), []mcpv1alpha1.SecretRef{slackSecret}, nil), second,
+		"only the annotations of the latest secrets should remain")
+	assert.NotContains(t, second, "vault.hashicorp.com/agent-inject-secret-github-personal-access-token")
+	assert.NotContains(t, second, "vault.hashicorp.com/agent-inject-template-github-personal-access-token")
+	assert.NotContains(t, second, "vault.hashicorp.com/agent-inject-command-github-personal-access-token")
+	assert.Contains(t, second, "vault.hashicorp.com/agent-inject-secret-slack-token")
+	assert.Equal(t, "toolhive-mcp-workloads", second["vault.hashicorp.com/role"])
+}
+
+func TestAddVaultSecretAnnotations_Role(t *testing.T) {
+	t.Parallel()
+
+	vaultSecret := func(name, role string) mcpv1alpha1.SecretRef {
+		return mcpv1alpha1.SecretRef{
+			Name: "workload-secrets/data/" + name + "/config",
+			Key:  "token",
+			Type: mcpv1alpha1.SecretRefTypeVault,
+			Role: role,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		secretRefs   []mcpv1alpha1.SecretRef
+		expectedRole string
+	}{
+		{
+			name:         "role declared by the vault secrets",
+			secretRefs:   []mcpv1alpha1.SecretRef{vaultSecret("github-mcp", "github-mcp"), vaultSecret("slack-mcp", "")},
+			expectedRole: "github-mcp",
+		},
+		{
+			name:         "role annotation takes precedence",
+			annotations:  map[string]string{vaultRoleAnnotation: "toolhive-mcp-workloads"},
+			secretRefs:   []mcpv1alpha1.SecretRef{vaultSecret("github-mcp", "github-mcp")},
+			expectedRole: "toolhive-mcp-workloads",
+		},
+		{
+			name:       "conflicting roles",
+			secretRefs: []mcpv1alpha1.SecretRef{vaultSecret("github-mcp", "github-mcp"), vaultSecret("slack-mcp", "slack-mcp")},
+		},
+		{
+			name:       "no role",
+			secretRefs: []mcpv1alpha1.SecretRef{vaultSecret("github-mcp", "")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			annotations := addVaultSecretAnnotations(tt.annotations, tt.secretRefs, nil)
+			if tt.expectedRole == "" {
+				assert.NotContains(t, annotations, vaultRoleAnnotation)
+				return
+			}
+			assert.Equal(t, tt.expectedRole, annotations[vaultRoleAnnotation])
+		})
+	}
+}
+
+func TestAddVaultSecretAnnotations_AgentResources(t *testing.T) {
+	t.Parallel()
+
+	vaultAgent := &mcpv1alpha1.VaultAgentConfig{
+		Resources: mcpv1alpha1.ResourceRequirements{
+			Requests: mcpv1alpha1.ResourceList{CPU: "50m", Memory: "64Mi"},
+			Limits:   mcpv1alpha1.ResourceList{CPU: "250m", Memory: "128Mi"},
+		},
+	}
+	vaultSecrets := createTestMCPServerWithVaultSecret("vault-secret-server", "default").Spec.Secrets
+
+	annotations := addVaultSecretAnnotations(nil, vaultSecrets, vaultAgent)
+	assert.Equal(t, "50m", annotations["vault.hashicorp.com/agent-requests-cpu"])
+	assert.Equal(t, "64Mi", annotations["vault.hashicorp.com/agent-requests-mem"])
+	assert.Equal(t, "250m", annotations["vault.hashicorp.com/agent-limits-cpu"])
+	assert.Equal(t, "128Mi", annotations["vault.hashicorp.com/agent-limits-mem"])
+
+	// Unset resources keep the Vault Agent injector defaults
+	annotations = addVaultSecretAnnotations(nil, vaultSecrets, &mcpv1alpha1.VaultAgentConfig{
+		Resources: mcpv1alpha1.ResourceRequirements{Limits: mcpv1alpha1.ResourceList{Memory: "128Mi"}},
+	})
+	assert.Equal(t, "128Mi", annotations["vault.hashicorp.com/agent-limits-mem"])
+	assert.NotContains(t, annotations, "vault.hashicorp.com/agent-requests-cpu")
+	assert.NotContains(t, annotations, "vault.hashicorp.com/agent-limits-cpu")
+
+	// Nothing is emitted without vault secrets
+	annotations = addVaultSecretAnnotations(nil, []mcpv1alpha1.SecretRef{{Name: "github", Key: "token"}}, vaultAgent)
+	assert.Empty(t, annotations)
+}
+
+func TestAddVaultSecretAnnotations_TLS(t *testing.T) {
+	t.Parallel()
+
+	vaultSecrets := createTestMCPServerWithVaultSecret("vault-secret-server", "default").Spec.Secrets
+
+	tests := []struct {
+		name       string
+		vaultAgent *mcpv1alpha1.VaultAgentConfig
+		expected   map[string]string
+		absent     []string
+	}{
+		{
+			name: "CA certificate and server name",
+			vaultAgent: &mcpv1alpha1.VaultAgentConfig{
+				TLSCACert:     &mcpv1alpha1.SecretKeyRef{Name: "vault-ca", Key: "ca.crt"},
+				TLSServerName: "vault.internal.example.com",
+			},
+			expected: map[string]string{
+				"vault.hashicorp.com/tls-secret":      "vault-ca",
+				"vault.hashicorp.com/ca-cert":         "/vault/tls/ca.crt",
+				"vault.hashicorp.com/tls-server-name": "vault.internal.example.com",
+			},
+			absent: []string{"vault.hashicorp.com/tls-skip-verify"},
+		},
+		{
+			name:       "skip verify",
+			vaultAgent: &mcpv1alpha1.VaultAgentConfig{TLSSkipVerify: true},
+			expected:   map[string]string{"vault.hashicorp.com/tls-skip-verify": "true"},
+			absent: []string{
+				"vault.hashicorp.com/tls-secret",
+				"vault.hashicorp.com/ca-cert",
+				"vault.hashicorp.com/tls-server-name",
+			},
+		},
+		{
+			name:       "no TLS settings",
+			vaultAgent: &mcpv1alpha1.VaultAgentConfig{},
+			absent: []string{
+				"vault.hashicorp.com/tls-secret",
+				"vault.hashicorp.com/ca-cert",
+				"vault.hashicorp.com/tls-skip-verify",
+				"vault.hashicorp.com/tls-server-name",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			annotations := addVaultSecretAnnotations(nil, vaultSecrets, tt.vaultAgent)
+			for annotation, value := range tt.expected {
+				assert.Equal(t, value, annotations[annotation], annotation)
+			}
+			for _, annotation := range tt.absent {
+				assert.NotContains(t, annotations, annotation)
+			}
+		})
+	}
+
+	// Nothing is emitted without vault secrets
+	annotations := addVaultSecretAnnotations(nil, nil, &mcpv1alpha1.VaultAgentConfig{TLSSkipVerify: true})
+	assert.Empty(t, annotations)
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/operator/accessors"
+)
+
+const (
+	// vaultAgentInjectAnnotation enables Vault Agent Injection on a pod
+	vaultAgentInjectAnnotation = "vault.hashicorp.com/agent-inject"
+	// vaultRoleAnnotation is the Vault role the injected agent authenticates as
+	vaultRoleAnnotation = "vault.hashicorp.com/role"
+	// vaultAuthPathAnnotation is the path of the Kubernetes auth method the injected agent logs in with
+	vaultAuthPathAnnotation = "vault.hashicorp.com/auth-path"
+)
+
+// validateVaultConfig checks the Vault Agent annotations of the proxy deployment pod template along
+// with the vault-typed secrets rendered through them. Malformed values are otherwise only reported
+// by the Vault Agent injector when the pod is created, or by the agent once it is running.
+func validateVaultConfig(annotations map[string]string, secretRefs []mcpv1alpha1.SecretRef) []error {
+	var errs []error
+
+	injectionEnabled := hasVaultAgentInjection(annotations)
+	if hasVaultSecrets(secretRefs) && !injectionEnabled {
+		errs = append(errs, fmt.Errorf("vault secrets require the %s annotation to be set to \"true\" "+
+			"on the proxy deployment pod template", vaultAgentInjectAnnotation))
+	}
+	secretsRole, err := vaultSecretsRole(secretRefs)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if injectionEnabled {
+		annotationRole := strings.TrimSpace(annotations[vaultRoleAnnotation])
+		switch {
+		case annotationRole == "" && secretsRole == "" && err == nil:
+			errs = append(errs, fmt.Errorf("the %s annotation is required when Vault Agent Injection is enabled, "+
+				"unless the vault secrets declare a role", vaultRoleAnnotation))
+		case annotationRole != "" && secretsRole != "" && annotationRole != secretsRole:
+			errs = append(errs, fmt.Errorf("the role %q of the vault secrets conflicts with the %s annotation %q, "+
+				"but the Vault Agent authenticates with a single role", secretsRole, vaultRoleAnnotation, annotationRole))
+		}
+		if authPath, ok := annotations[vaultAuthPathAnnotation]; ok {
+			if err := validateVaultAuthPath(authPath); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s annotation: %w", vaultAuthPathAnnotation, err))
+			}
+		}
+	}
+
+	for _, secret := range secretRefs {
+		if !secret.IsVault() {
+			continue
+		}
+		if err := validateVaultPath(secret.Name); err != nil {
+			errs = append(errs, fmt.Errorf("vault secret %q: invalid path: %w", secret.Name, err))
+		}
+		if secret.Key == "" {
+			errs = append(errs, fmt.Errorf("vault secret %q: key is required", secret.Name))
+		}
+	}
+
+	return errs
+}
+
+// validateVaultPath checks that a Vault path is relative and made of non-empty segments
+func validateVaultPath(path string) error {
+	if path == "" {
+		return errors.New("path is required")
+	}
+	if strings.ContainsAny(path, " \t\n") {
+		return errors.New("path must not contain whitespace")
+	}
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		return errors.New("path must not start or end with a slash")
+	}
+	if strings.Contains(path, "//") {
+		return errors.New("path must not contain empty segments")
+	}
+	return nil
+}
+
+// validateVaultAuthPath checks that an auth path names an auth method mount, e.g. auth/kubernetes
+func validateVaultAuthPath(authPath string) error {
+	if err := validateVaultPath(authPath); err != nil {
+		return err
+	}
+	if mount, ok := strings.CutPrefix(authPath, "auth/"); !ok || mount == "" {
+		return fmt.Errorf("auth path %q must be of the form auth/<mount>", authPath)
+	}
+	return nil
+}
+
+// validateVaultAgentConfig sets the VaultConfigValid condition on MCPServers using Vault Agent Injection.
+// As for the transport configuration, an invalid configuration is reported through the condition and
+// an event, but does not block reconciliation.
+func (r *MCPServerReconciler) validateVaultAgentConfig(ctx context.Context, mcpServer *mcpv1alpha1.MCPServer) {
+	ctxLogger := log.FromContext(ctx)
+
+	_, annotations := accessors.NewMCPServerFieldAccessor().GetProxyDeploymentTemplateLabelsAndAnnotations(mcpServer)
+	if !hasVaultSecrets(mcpServer.Spec.Secrets) && !hasVaultAgentInjection(annotations) {
+		// Vault is not used, drop the condition left over from a previous configuration if any
+		if meta.RemoveStatusCondition(&mcpServer.Status.Conditions, mcpv1alpha1.ConditionVaultConfigValid) {
+			if err := r.Status().Update(ctx, mcpServer); err != nil {
+				ctxLogger.Error(err, "Failed to update MCPServer status after Vault configuration validation")
+			}
+		}
+		return
+	}
+
+	if errs := validateVaultConfig(annotations, mcpServer.Spec.Secrets); len(errs) > 0 {
+		message := errors.Join(errs...).Error()
+		message = strings.ReplaceAll(message, "\n", "; ")
+		if r.Recorder != nil {
+			r.Recorder.Eventf(mcpServer, corev1.EventTypeWarning, mcpv1alpha1.ConditionReasonVaultConfigInvalid,
+				"Invalid Vault configuration: %s", message)
+		}
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, metav1.Condition{
+			Type:               mcpv1alpha1.ConditionVaultConfigValid,
+			Status:             metav1.ConditionFalse,
+			Reason:             mcpv1alpha1.ConditionReasonVaultConfigInvalid,
+			Message:            message,
+			ObservedGeneration: mcpServer.Generation,
+		})
+	} else {
+		meta.SetStatusCondition(&mcpServer.Status.Conditions, metav1.Condition{
+			Type:               mcpv1alpha1.ConditionVaultConfigValid,
+			Status:             metav1.ConditionTrue,
+			Reason:             mcpv1alpha1.ConditionReasonVaultConfigValid,
+			Message:            "Vault Agent annotations and vault secrets are valid",
+			ObservedGeneration: mcpServer.Generation,
+		})
+	}
+
+	if err := r.Status().Update(ctx, mcpServer); err != nil {
+		ctxLogger.Error(err, "Failed to update MCPServer status after Vault configuration validation")
+	}
+}
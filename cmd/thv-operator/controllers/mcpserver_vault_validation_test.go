@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+func TestValidateVaultConfig(t *testing.T) {
+	t.Parallel()
+
+	validAnnotations := map[string]string{
+		vaultAgentInjectAnnotation: "true",
+		vaultRoleAnnotation:        "toolhive-mcp-workloads",
+	}
+	vaultSecret := mcpv1alpha1.SecretRef{
+		Name: "workload-secrets/data/github-mcp/config",
+		Key:  "token",
+		Type: mcpv1alpha1.SecretRefTypeVault,
+	}
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		secrets       []mcpv1alpha1.SecretRef
+		expectedError []string
+	}{
+		{
+			name:        "valid config",
+			annotations: validAnnotations,
+			secrets:     []mcpv1alpha1.SecretRef{vaultSecret, {Name: "github-token", Key: "token"}},
+		},
+		{
+			name: "valid auth path",
+			annotations: map[string]string{
+				vaultAgentInjectAnnotation: "true",
+				vaultRoleAnnotation:        "toolhive-mcp-workloads",
+				vaultAuthPathAnnotation:    "auth/kubernetes-prod",
+			},
+			secrets: []mcpv1alpha1.SecretRef{vaultSecret},
+		},
+		{
+			name:          "missing role",
+			annotations:   map[string]string{vaultAgentInjectAnnotation: "true"},
+			secrets:       []mcpv1alpha1.SecretRef{vaultSecret},
+			expectedError: []string{vaultRoleAnnotation + " annotation is required"},
+		},
+		{
+			name:        "role declared by the vault secrets",
+			annotations: map[string]string{vaultAgentInjectAnnotation: "true"},
+			secrets: []mcpv1alpha1.SecretRef{
+				{Name: "workload-secrets/data/github-mcp/config", Key: "token", Type: mcpv1alpha1.SecretRefTypeVault,
+					Role: "github-mcp"},
+				{Name: "workload-secrets/data/slack-mcp/config", Key: "token", Type: mcpv1alpha1.SecretRefTypeVault,
+					Role: "github-mcp"},
+				vaultSecret,
+			},
+		},
+		{
+			name:        "vault secrets with conflicting roles",
+			annotations: map[string]string{vaultAgentInjectAnnotation: "true"},
+			secrets: []mcpv1alpha1.SecretRef{
+				{Name: "workload-secrets/data/github-mcp/config", Key: "token", Type: mcpv1alpha1.SecretRefTypeVault,
+					Role: "github-mcp"},
+				{Name: "workload-secrets/data/slack-mcp/config", Key: "token", Type: mcpv1alpha1.SecretRefTypeVault,
+					Role: "slack-mcp"},
+			},
+			expectedError: []string{`declare the different roles "github-mcp" and "slack-mcp"`},
+		},
+		{
+			name:        "vault secret role conflicting with the role annotation",
+			annotations: validAnnotations,
+			secrets: []mcpv1alpha1.SecretRef{
+				{Name: "workload-secrets/data/github-mcp/config", Key: "token", Type: mcpv1alpha1.SecretRefTypeVault,
+					Role: "github-mcp"},
+			},
+			expectedError: []string{`conflicts with the ` + vaultRoleAnnotation + ` annotation "toolhive-mcp-workloads"`},
+		},
+		{
+			name:        "vault secret with empty path",
+			annotations: validAnnotations,
+			secrets: []mcpv1alpha1.SecretRef{
+				{Key: "token", Type: mcpv1alpha1.SecretRefTypeVault},
+			},
+			expectedError: []string{"path is required"},
+		},
+		{
+			name:        "vault secret with malformed path and no key",
+			annotations: validAnnotations,
+			secrets: []mcpv1alpha1.SecretRef{
+				{Name: "/workload-secrets//github", Type: mcpv1alpha1.SecretRefTypeVault},
+			},
+			expectedError: []string{"must not start or end with a slash", "key is required"},
+		},
+		{
+			name: "malformed auth path",
+			annotations: map[string]string{
+				vaultAgentInjectAnnotation: "true",
+				vaultRoleAnnotation:        "toolhive-mcp-workloads",
+				vaultAuthPathAnnotation:    "kubernetes",
+			},
+			secrets:       []mcpv1alpha1.SecretRef{vaultSecret},
+			expectedError: []string{"must be of the form auth/<mount>"},
+		},
+		{
+			name:          "vault secrets without Vault Agent Injection",
+			secrets:       []mcpv1alpha1.SecretRef{vaultSecret},
+			expectedError: []string{"vault secrets require the " + vaultAgentInjectAnnotation + " annotation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			errs := validateVaultConfig(tt.annotations, tt.secrets)
+			require.Len(t, errs, len(tt.expectedError))
+			for i, expected := range tt.expectedError {
+				assert.ErrorContains(t, errs[i], expected)
+			}
+		})
+	}
+}
+
+func TestMCPServerReconciler_ValidateVaultAgentConfig(t *testing.T) {
+	t.Parallel()
+
+	vaultSecrets := []mcpv1alpha1.SecretRef{{
+		Name: "workload-secrets/data/github-mcp/config",
+		Key:  "token",
+		Type: mcpv1alpha1.SecretRefTypeVault,
+	}}
+	proxyAnnotations := func(annotations map[string]string) *mcpv1alpha1.ResourceOverrides {
+		return &mcpv1alpha1.ResourceOverrides{
+			ProxyDeployment: &mcpv1alpha1.ProxyDeploymentOverrides{
+				PodTemplateMetadataOverrides: &mcpv1alpha1.ResourceMetadataOverrides{
+					Annotations: annotations,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		spec         mcpv1alpha1.MCPServerSpec
+		expectStatus metav1.ConditionStatus
+		expectReason string
+		expectEvent  bool
+	}{
+		{
+			name: "valid config",
+			spec: mcpv1alpha1.MCPServerSpec{
+				Secrets: vaultSecrets,
+				ResourceOverrides: proxyAnnotations(map[string]string{
+					vaultAgentInjectAnnotation: "true",
+					vaultRoleAnnotation:        "toolhive-mcp-workloads",
+				}),
+			},
+			expectStatus: metav1.ConditionTrue,
+			expectReason: mcpv1alpha1.ConditionReasonVaultConfigValid,
+		},
+		{
+			name: "missing role",
+			spec: mcpv1alpha1.MCPServerSpec{
+				Secrets:           vaultSecrets,
+				ResourceOverrides: proxyAnnotations(map[string]string{vaultAgentInjectAnnotation: "true"}),
+			},
+			expectStatus: metav1.ConditionFalse,
+			expectReason: mcpv1alpha1.ConditionReasonVaultConfigInvalid,
+			expectEvent:  true,
+		},
+		{
+			name: "vault not used",
+			spec: mcpv1alpha1.MCPServerSpec{
+				Secrets: []mcpv1alpha1.SecretRef{{Name: "github-token", Key: "token"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := log.IntoContext(t.Context(), log.Log)
+
+			s := runtime.NewScheme()
+			require.NoError(t, scheme.AddToScheme(s))
+			require.NoError(t, mcpv1alpha1.AddToScheme(s))
+
+			mcpServer := &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-vault",
+					Namespace: "default",
+				},
+				Spec: tt.spec,
+			}
+			mcpServer.Spec.Image = "test-image:latest"
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(s).
+				WithObjects(mcpServer).
+				WithStatusSubresource(mcpServer).
+				Build()
+
+			eventRecorder := record.NewFakeRecorder(10)
+			r := &MCPServerReconciler{
+				Client:   fakeClient,
+				Scheme:   s,
+				Recorder: eventRecorder,
+			}
+
+			r.validateVaultAgentConfig(ctx, mcpServer)
+
+			var updated mcpv1alpha1.MCPServer
+			require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(mcpServer), &updated))
+
+			condition := meta.FindStatusCondition(updated.Status.Conditions, mcpv1alpha1.ConditionVaultConfigValid)
+			if tt.expectReason == "" {
+				assert.Nil(t, condition, "VaultConfigValid condition should not be set when Vault is not used")
+				assert.Empty(t, eventRecorder.Events)
+				return
+			}
+			require.NotNil(t, condition, "VaultConfigValid condition should be set")
+			assert.Equal(t, tt.expectStatus, condition.Status)
+			assert.Equal(t, tt.expectReason, condition.Reason)
+
+			if tt.expectEvent {
+				require.Len(t, eventRecorder.Events, 1)
+				event := <-eventRecorder.Events
+				assert.Contains(t, event, "Warning")
+				assert.Contains(t, event, mcpv1alpha1.ConditionReasonVaultConfigInvalid)
+			} else {
+				assert.Empty(t, eventRecorder.Events)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@
 //   - resources.go: Resource limit and request calculation utilities
 //   - authz.go: Authorization (Cedar policy) configuration helpers
 //   - oidc.go: OIDC (OpenID Connect) configuration helpers
+//   - opentelemetry.go: OpenTelemetry env var and flag generation and ConfigMap resolution
 //   - tokenexchange.go: Token exchange configuration for external auth
 //   - config.go: General configuration merging and validation utilities
 //
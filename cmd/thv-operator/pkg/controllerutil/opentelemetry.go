@@ -0,0 +1,170 @@
+package controllerutil
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultOpenTelemetryKey is the default key for OpenTelemetry configuration in ConfigMaps
+	DefaultOpenTelemetryKey = "opentelemetry.json"
+)
+
+// GenerateOpenTelemetryEnvVars generates OpenTelemetry environment variables.
+// No environment variables are generated when OpenTelemetry is disabled.
+func GenerateOpenTelemetryEnvVars(
+	telemetryConfig *mcpv1alpha1.TelemetryConfig,
+	resourceName string,
+	namespace string,
+) []corev1.EnvVar {
+	if telemetryConfig == nil {
+		return nil
+	}
+	return BuildOTELEnvVars(telemetryConfig.OpenTelemetry, resourceName, namespace)
+}
+
+// BuildOTELEnvVars builds the OpenTelemetry environment variables of the proxy container for the
+// OpenTelemetry configuration of the named resource. The service name defaults to the resource name.
+// No environment variables are built when the configuration is nil or disabled.
+func BuildOTELEnvVars(cfg *mcpv1alpha1.OpenTelemetryConfig, resourceName, namespace string) []corev1.EnvVar {
+	if cfg == nil || !cfg.IsEnabled() {
+		return nil
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = resourceName
+	}
+
+	return []corev1.EnvVar{{
+		Name:  "OTEL_RESOURCE_ATTRIBUTES",
+		Value: fmt.Sprintf("service.name=%s,service.namespace=%s", serviceName, namespace),
+	}}
+}
+
+// BuildOTELArgs builds the `thv run` flags equivalent to the OpenTelemetry configuration, for running
+// the proxy from the command line rather than from a RunConfig. As in the RunConfig, tracing and
+// metrics are disabled unless enabled in the configuration. The configuration is expected to be
+// resolved, a referenced ConfigMap is ignored. No flags are built when the configuration is nil or disabled.
+func BuildOTELArgs(cfg *mcpv1alpha1.OpenTelemetryConfig) []string {
+	if cfg == nil || !cfg.IsEnabled() {
+		return nil
+	}
+
+	var args []string
+	if cfg.Endpoint != "" {
+		args = append(args, "--otel-endpoint="+cfg.Endpoint)
+	}
+	if cfg.ServiceName != "" {
+		args = append(args, "--otel-service-name="+cfg.ServiceName)
+	}
+	for _, header := range cfg.Headers {
+		args = append(args, "--otel-headers="+header)
+	}
+	if cfg.Insecure {
+		args = append(args, "--otel-insecure")
+	}
+
+	tracingEnabled := cfg.Tracing != nil && cfg.Tracing.Enabled
+	args = append(args, fmt.Sprintf("--otel-tracing-enabled=%t", tracingEnabled))
+	if cfg.Tracing != nil && cfg.Tracing.SamplingRate != "" {
+		args = append(args, "--otel-sampling-rate="+cfg.Tracing.SamplingRate)
+	}
+	metricsEnabled := cfg.Metrics != nil && cfg.Metrics.Enabled
+	args = append(args, fmt.Sprintf("--otel-metrics-enabled=%t", metricsEnabled))
+
+	for _, envVar := range cfg.EnvironmentVariables {
+		args = append(args, "--otel-env-vars="+envVar)
+	}
+	return args
+}
+
+// ResolveTelemetryConfig returns the telemetry configuration with the OpenTelemetry configuration of the
+// referenced ConfigMap merged in, the fields set inline overriding those of the ConfigMap.
+// The telemetry configuration is returned as is when it does not reference a ConfigMap.
+func ResolveTelemetryConfig(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	telemetryConfig *mcpv1alpha1.TelemetryConfig,
+) (*mcpv1alpha1.TelemetryConfig, error) {
+	if telemetryConfig == nil || telemetryConfig.OpenTelemetry == nil || telemetryConfig.OpenTelemetry.ConfigMapRef == nil {
+		return telemetryConfig, nil
+	}
+
+	ref := telemetryConfig.OpenTelemetry.ConfigMapRef
+	if ref.Name == "" {
+		return nil, fmt.Errorf("OpenTelemetry configMapRef is missing name")
+	}
+	key := ref.Key
+	if key == "" {
+		key = DefaultOpenTelemetryKey
+	}
+
+	// Ensure we have a Kubernetes client to fetch the ConfigMap
+	if c == nil {
+		return nil, fmt.Errorf("kubernetes client is not configured for ConfigMap OpenTelemetry resolution")
+	}
+
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &cm); err != nil {
+		return nil, fmt.Errorf("failed to get OpenTelemetry ConfigMap %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	raw, ok := cm.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("OpenTelemetry ConfigMap %s/%s is missing key %q", namespace, ref.Name, key)
+	}
+
+	// YAML unmarshalling also handles JSON
+	var shared mcpv1alpha1.OpenTelemetryConfig
+	if err := yaml.Unmarshal([]byte(raw), &shared); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenTelemetry config from ConfigMap %s/%s key %q: %w",
+			namespace, ref.Name, key, err)
+	}
+
+	resolved := telemetryConfig.DeepCopy()
+	resolved.OpenTelemetry = mergeOpenTelemetryConfig(&shared, telemetryConfig.OpenTelemetry)
+	return resolved, nil
+}
+
+// mergeOpenTelemetryConfig returns the shared configuration overridden by the fields set inline.
+// The non-pointer boolean fields cannot be unset inline, so they are enabled when enabled in either configuration.
+func mergeOpenTelemetryConfig(shared, inline *mcpv1alpha1.OpenTelemetryConfig) *mcpv1alpha1.OpenTelemetryConfig {
+	merged := shared.DeepCopy()
+	merged.ConfigMapRef = nil
+
+	if inline.Enabled != nil {
+		merged.Enabled = ptr.To(*inline.Enabled)
+	}
+	if inline.Endpoint != "" {
+		merged.Endpoint = inline.Endpoint
+	}
+	if inline.ServiceName != "" {
+		merged.ServiceName = inline.ServiceName
+	}
+	if len(inline.Headers) > 0 {
+		merged.Headers = append([]string(nil), inline.Headers...)
+	}
+	if inline.Insecure {
+		merged.Insecure = true
+	}
+	if len(inline.EnvironmentVariables) > 0 {
+		merged.EnvironmentVariables = append([]string(nil), inline.EnvironmentVariables...)
+	}
+	if inline.Metrics != nil {
+		merged.Metrics = inline.Metrics.DeepCopy()
+	}
+	if inline.Tracing != nil {
+		merged.Tracing = inline.Tracing.DeepCopy()
+	}
+	return merged
+}
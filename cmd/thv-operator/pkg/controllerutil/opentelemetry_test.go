@@ -0,0 +1,362 @@
+package controllerutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+func TestGenerateOpenTelemetryEnvVars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		telemetryConfig *mcpv1alpha1.TelemetryConfig
+		expectedEnv     []corev1.EnvVar
+	}{
+		{
+			name:            "nil telemetry config",
+			telemetryConfig: nil,
+			expectedEnv:     nil,
+		},
+		{
+			name: "prometheus only",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				Prometheus: &mcpv1alpha1.PrometheusConfig{Enabled: true},
+			},
+			expectedEnv: nil,
+		},
+		{
+			name: "disabled OpenTelemetry",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{Enabled: ptr.To(false), ServiceName: "custom-service"},
+			},
+			expectedEnv: nil,
+		},
+		{
+			name: "custom service name",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{ServiceName: "custom-service"},
+			},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "service.name=custom-service,service.namespace=test-ns"},
+			},
+		},
+		{
+			name: "service name defaults to the resource name",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{},
+			},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "service.name=test-resource,service.namespace=test-ns"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expectedEnv, GenerateOpenTelemetryEnvVars(tt.telemetryConfig, "test-resource", "test-ns"))
+		})
+	}
+}
+
+func TestBuildOTELEnvVars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		cfg         *mcpv1alpha1.OpenTelemetryConfig
+		expectedEnv []corev1.EnvVar
+	}{
+		{
+			name:        "nil config",
+			cfg:         nil,
+			expectedEnv: nil,
+		},
+		{
+			name:        "disabled config",
+			cfg:         &mcpv1alpha1.OpenTelemetryConfig{Enabled: ptr.To(false), ServiceName: "custom-service"},
+			expectedEnv: nil,
+		},
+		{
+			name: "explicitly enabled config",
+			cfg:  &mcpv1alpha1.OpenTelemetryConfig{Enabled: ptr.To(true), ServiceName: "custom-service"},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "service.name=custom-service,service.namespace=test-ns"},
+			},
+		},
+		{
+			name: "service name defaults to the resource name",
+			cfg:  &mcpv1alpha1.OpenTelemetryConfig{Endpoint: "otel-collector:4317"},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "service.name=test-resource,service.namespace=test-ns"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expectedEnv, BuildOTELEnvVars(tt.cfg, "test-resource", "test-ns"))
+		})
+	}
+}
+
+func TestBuildOTELArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		cfg          *mcpv1alpha1.OpenTelemetryConfig
+		expectedArgs []string
+	}{
+		{
+			name:         "nil config",
+			cfg:          nil,
+			expectedArgs: nil,
+		},
+		{
+			name: "disabled config",
+			cfg: &mcpv1alpha1.OpenTelemetryConfig{
+				Enabled:  ptr.To(false),
+				Endpoint: "otel-collector:4317",
+				Tracing:  &mcpv1alpha1.OpenTelemetryTracingConfig{Enabled: true},
+			},
+			expectedArgs: nil,
+		},
+		{
+			name: "empty config disables tracing and metrics",
+			cfg:  &mcpv1alpha1.OpenTelemetryConfig{},
+			expectedArgs: []string{
+				"--otel-tracing-enabled=false",
+				"--otel-metrics-enabled=false",
+			},
+		},
+		{
+			name: "full config",
+			cfg: &mcpv1alpha1.OpenTelemetryConfig{
+				Endpoint:             "https://otel-collector:4318",
+				ServiceName:          "custom-service",
+				Headers:              []string{"Authorization=Bearer token", "X-API-Key=abc"},
+				Insecure:             true,
+				EnvironmentVariables: []string{"NODE_NAME", "POD_NAME"},
+				Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
+					Enabled:      true,
+					SamplingRate: "0.25",
+				},
+				Metrics: &mcpv1alpha1.OpenTelemetryMetricsConfig{Enabled: true},
+			},
+			expectedArgs: []string{
+				"--otel-endpoint=https://otel-collector:4318",
+				"--otel-service-name=custom-service",
+				"--otel-headers=Authorization=Bearer token",
+				"--otel-headers=X-API-Key=abc",
+				"--otel-insecure",
+				"--otel-tracing-enabled=true",
+				"--otel-sampling-rate=0.25",
+				"--otel-metrics-enabled=true",
+				"--otel-env-vars=NODE_NAME",
+				"--otel-env-vars=POD_NAME",
+			},
+		},
+		{
+			name: "sampling rate without tracing",
+			cfg: &mcpv1alpha1.OpenTelemetryConfig{
+				Endpoint: "otel-collector:4317",
+				Tracing:  &mcpv1alpha1.OpenTelemetryTracingConfig{SamplingRate: "0.5"},
+			},
+			expectedArgs: []string{
+				"--otel-endpoint=otel-collector:4317",
+				"--otel-tracing-enabled=false",
+				"--otel-sampling-rate=0.5",
+				"--otel-metrics-enabled=false",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expectedArgs, BuildOTELArgs(tt.cfg))
+		})
+	}
+}
+
+func TestResolveTelemetryConfig(t *testing.T) {
+	t.Parallel()
+
+	sharedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "shared-otel",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			DefaultOpenTelemetryKey: `{
+				"enabled": true,
+				"endpoint": "otel-collector:4317",
+				"serviceName": "shared-service",
+				"headers": ["X-API-Key=shared"],
+				"tracing": {"enabled": true, "samplingRate": "0.1"}
+			}`,
+			"otel.yaml": "endpoint: yaml-collector:4317\ninsecure: true\n",
+		},
+	}
+
+	tests := []struct {
+		name            string
+		telemetryConfig *mcpv1alpha1.TelemetryConfig
+		expectError     bool
+		errContains     string
+		validate        func(*testing.T, *mcpv1alpha1.TelemetryConfig)
+	}{
+		{
+			name:            "nil telemetry config is returned as is",
+			telemetryConfig: nil,
+			validate: func(t *testing.T, resolved *mcpv1alpha1.TelemetryConfig) {
+				t.Helper()
+				assert.Nil(t, resolved)
+			},
+		},
+		{
+			name: "shared configuration without inline fields",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+					ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "shared-otel"},
+				},
+			},
+			validate: func(t *testing.T, resolved *mcpv1alpha1.TelemetryConfig) {
+				t.Helper()
+				require.NotNil(t, resolved.OpenTelemetry)
+				assert.Equal(t, &mcpv1alpha1.OpenTelemetryConfig{
+					Enabled:     ptr.To(true),
+					Endpoint:    "otel-collector:4317",
+					ServiceName: "shared-service",
+					Headers:     []string{"X-API-Key=shared"},
+					Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
+						Enabled:      true,
+						SamplingRate: "0.1",
+					},
+				}, resolved.OpenTelemetry)
+			},
+		},
+		{
+			name: "inline fields override the shared configuration",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+					ServiceName: "inline-service",
+					Metrics: &mcpv1alpha1.OpenTelemetryMetricsConfig{
+						Enabled: true,
+					},
+					Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
+						Enabled:      true,
+						SamplingRate: "0.5",
+					},
+					ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "shared-otel"},
+				},
+				Prometheus: &mcpv1alpha1.PrometheusConfig{
+					Enabled: true,
+				},
+			},
+			validate: func(t *testing.T, resolved *mcpv1alpha1.TelemetryConfig) {
+				t.Helper()
+				require.NotNil(t, resolved.OpenTelemetry)
+				assert.True(t, resolved.OpenTelemetry.IsEnabled())
+				assert.Equal(t, "otel-collector:4317", resolved.OpenTelemetry.Endpoint)
+				assert.Equal(t, "inline-service", resolved.OpenTelemetry.ServiceName)
+				assert.Equal(t, []string{"X-API-Key=shared"}, resolved.OpenTelemetry.Headers)
+				assert.Equal(t, &mcpv1alpha1.OpenTelemetryMetricsConfig{Enabled: true}, resolved.OpenTelemetry.Metrics)
+				assert.Equal(t, "0.5", resolved.OpenTelemetry.Tracing.SamplingRate)
+				assert.Nil(t, resolved.OpenTelemetry.ConfigMapRef)
+				assert.True(t, resolved.Prometheus.Enabled)
+			},
+		},
+		{
+			name: "inline disabled overrides the shared configuration",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+					Enabled:      ptr.To(false),
+					ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "shared-otel"},
+				},
+			},
+			validate: func(t *testing.T, resolved *mcpv1alpha1.TelemetryConfig) {
+				t.Helper()
+				require.NotNil(t, resolved.OpenTelemetry)
+				assert.False(t, resolved.OpenTelemetry.IsEnabled())
+				assert.Equal(t, "otel-collector:4317", resolved.OpenTelemetry.Endpoint)
+			},
+		},
+		{
+			name: "YAML configuration under a custom key",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+					ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "shared-otel", Key: "otel.yaml"},
+				},
+			},
+			validate: func(t *testing.T, resolved *mcpv1alpha1.TelemetryConfig) {
+				t.Helper()
+				require.NotNil(t, resolved.OpenTelemetry)
+				assert.True(t, resolved.OpenTelemetry.IsEnabled())
+				assert.True(t, resolved.OpenTelemetry.Insecure)
+				assert.Equal(t, "yaml-collector:4317", resolved.OpenTelemetry.Endpoint)
+			},
+		},
+		{
+			name: "missing ConfigMap returns error",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+					ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "missing-otel"},
+				},
+			},
+			expectError: true,
+			errContains: "failed to get OpenTelemetry ConfigMap default/missing-otel",
+		},
+		{
+			name: "missing key returns error",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+					ConfigMapRef: &mcpv1alpha1.ConfigMapOpenTelemetryRef{Name: "shared-otel", Key: "wrong-key"},
+				},
+			},
+			expectError: true,
+			errContains: "is missing key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			require.NoError(t, mcpv1alpha1.AddToScheme(scheme))
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sharedConfigMap).Build()
+
+			resolved, err := ResolveTelemetryConfig(context.TODO(), fakeClient, "default", tt.telemetryConfig)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			tt.validate(t, resolved)
+
+			// The resolution must not modify the spec
+			if tt.telemetryConfig != nil && tt.telemetryConfig.OpenTelemetry != nil {
+				assert.NotNil(t, tt.telemetryConfig.OpenTelemetry.ConfigMapRef)
+			}
+		})
+	}
+}
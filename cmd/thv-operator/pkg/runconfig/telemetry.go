@@ -3,6 +3,7 @@ package runconfig
 
 import (
 	"context"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -12,12 +13,15 @@ import (
 	"github.com/stacklok/toolhive/pkg/runner"
 )
 
-// AddTelemetryConfigOptions adds telemetry configuration options to the builder options
+// AddTelemetryConfigOptions adds telemetry configuration options to the builder options.
+// The environment variable prefix patterns are expanded against proxyEnv, the environment variables
+// declared for the proxy container.
 func AddTelemetryConfigOptions(
 	ctx context.Context,
 	options *[]runner.RunConfigBuilderOption,
 	telemetryConfig *mcpv1alpha1.TelemetryConfig,
 	mcpServerName string,
+	proxyEnv []mcpv1alpha1.EnvVar,
 ) {
 	if telemetryConfig == nil {
 		return
@@ -35,13 +39,14 @@ func AddTelemetryConfigOptions(
 	var otelEnvironmentVariables []string
 
 	// Process OpenTelemetry configuration
-	if telemetryConfig.OpenTelemetry != nil && telemetryConfig.OpenTelemetry.Enabled {
+	if telemetryConfig.OpenTelemetry != nil && telemetryConfig.OpenTelemetry.IsEnabled() {
 		otel := telemetryConfig.OpenTelemetry
 
 		// Strip http:// or https:// prefix if present, as OTLP client expects host:port format
 		otelEndpoint = strings.TrimPrefix(strings.TrimPrefix(otel.Endpoint, "https://"), "http://")
 		otelInsecure = otel.Insecure
 		otelHeaders = otel.Headers
+		otelEnvironmentVariables = expandEnvironmentVariables(otel.EnvironmentVariables, proxyEnv)
 
 		// Use MCPServer name as service name if not specified
 		if otel.ServiceName != "" {
@@ -95,3 +100,35 @@ func AddTelemetryConfigOptions(
 		otelEnvironmentVariables,
 	))
 }
+
+// expandEnvironmentVariables expands the prefix patterns of names, ending with *, into the names of the
+// matching declared environment variables, sorted. Other names are kept as they are, and duplicates are removed.
+func expandEnvironmentVariables(names []string, declared []mcpv1alpha1.EnvVar) []string {
+	var expanded []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			expanded = append(expanded, name)
+		}
+	}
+
+	for _, name := range names {
+		prefix, isPattern := strings.CutSuffix(name, "*")
+		if !isPattern {
+			add(name)
+			continue
+		}
+		var matches []string
+		for _, env := range declared {
+			if strings.HasPrefix(env.Name, prefix) {
+				matches = append(matches, env.Name)
+			}
+		}
+		slices.Sort(matches)
+		for _, match := range matches {
+			add(match)
+		}
+	}
+	return expanded
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
 	"github.com/stacklok/toolhive/pkg/runner"
@@ -50,7 +51,7 @@ func TestAddTelemetryConfigOptions(t *testing.T) {
 					ProxyPort: 8080,
 					Telemetry: &mcpv1alpha1.TelemetryConfig{
 						OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
-							Enabled:     true,
+							Enabled:     ptr.To(true),
 							Endpoint:    "http://otel-collector:4317",
 							ServiceName: "custom-service-name",
 							Insecure:    true,
@@ -102,7 +103,7 @@ func TestAddTelemetryConfigOptions(t *testing.T) {
 					ProxyPort: 8080,
 					Telemetry: &mcpv1alpha1.TelemetryConfig{
 						OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
-							Enabled:  true,
+							Enabled:  ptr.To(true),
 							Endpoint: "https://secure-otel:4318",
 							// ServiceName not specified - should default to MCPServer name
 						},
@@ -123,6 +124,68 @@ func TestAddTelemetryConfigOptions(t *testing.T) {
 				assert.Equal(t, 0.05, config.TelemetryConfig.SamplingRate) // Default sampling rate
 			},
 		},
+		{
+			name: "with OpenTelemetry enabled by default",
+			mcpServer: &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-otel-server",
+					Namespace: "test-ns",
+				},
+				Spec: mcpv1alpha1.MCPServerSpec{
+					Image:     testImage,
+					Transport: stdioTransport,
+					ProxyPort: 8080,
+					Telemetry: &mcpv1alpha1.TelemetryConfig{
+						OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+							Endpoint: "otel-collector:4317",
+							Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
+								Enabled: true,
+							},
+						},
+					},
+				},
+			},
+			//nolint:thelper // We want to see the error at the specific line
+			expected: func(t *testing.T, config *runner.RunConfig) {
+				// An OpenTelemetry block without enabled set is treated as enabled
+				assert.NotNil(t, config.TelemetryConfig)
+				assert.Equal(t, "otel-collector:4317", config.TelemetryConfig.Endpoint)
+				assert.Equal(t, "default-otel-server", config.TelemetryConfig.ServiceName)
+				assert.True(t, config.TelemetryConfig.TracingEnabled)
+			},
+		},
+		{
+			name: "with disabled OpenTelemetry configuration",
+			mcpServer: &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "disabled-otel-server",
+					Namespace: "test-ns",
+				},
+				Spec: mcpv1alpha1.MCPServerSpec{
+					Image:     testImage,
+					Transport: stdioTransport,
+					ProxyPort: 8080,
+					Telemetry: &mcpv1alpha1.TelemetryConfig{
+						OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+							Enabled:     ptr.To(false),
+							Endpoint:    "otel-collector:4317",
+							ServiceName: "custom-service-name",
+							Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
+								Enabled: true,
+							},
+							Metrics: &mcpv1alpha1.OpenTelemetryMetricsConfig{
+								Enabled: true,
+							},
+						},
+					},
+				},
+			},
+			//nolint:thelper // We want to see the error at the specific line
+			expected: func(t *testing.T, config *runner.RunConfig) {
+				// A disabled OpenTelemetry block is treated as no OpenTelemetry configuration
+				assert.Nil(t, config.TelemetryConfig)
+			},
+		},
 		{
 			name: "with prometheus only telemetry",
 			mcpServer: &mcpv1alpha1.MCPServer{
@@ -168,7 +231,7 @@ func TestAddTelemetryConfigOptions(t *testing.T) {
 					ProxyPort: 8080,
 					Telemetry: &mcpv1alpha1.TelemetryConfig{
 						OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
-							Enabled:  true,
+							Enabled:  ptr.To(true),
 							Endpoint: "otel-collector:4317",
 							Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
 								Enabled:      true,
@@ -202,7 +265,7 @@ func TestAddTelemetryConfigOptions(t *testing.T) {
 				runner.WithImage(tt.mcpServer.Spec.Image),
 			}
 			ctx := context.Background()
-			AddTelemetryConfigOptions(ctx, &options, tt.mcpServer.Spec.Telemetry, tt.mcpServer.Name)
+			AddTelemetryConfigOptions(ctx, &options, tt.mcpServer.Spec.Telemetry, tt.mcpServer.Name, nil)
 
 			rc, err := runner.NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, options...)
 			assert.NoError(t, err)
@@ -219,7 +282,7 @@ func TestAddTelemetryConfigOptions_NilOptions(t *testing.T) {
 	ctx := context.Background()
 	telemetryConfig := &mcpv1alpha1.TelemetryConfig{
 		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
-			Enabled:     true,
+			Enabled:     ptr.To(true),
 			Endpoint:    "otel-collector:4317",
 			ServiceName: "test-service",
 			Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
@@ -237,6 +300,84 @@ func TestAddTelemetryConfigOptions_NilOptions(t *testing.T) {
 
 	// Test with nil options pointer - should not panic
 	assert.NotPanics(t, func() {
-		AddTelemetryConfigOptions(ctx, nil, telemetryConfig, "test-server")
+		AddTelemetryConfigOptions(ctx, nil, telemetryConfig, "test-server", nil)
 	}, "AddTelemetryConfigOptions should not panic with nil options")
 }
+
+// TestAddTelemetryConfigOptions_EnvironmentVariables tests the expansion of environment variable prefix patterns
+func TestAddTelemetryConfigOptions_EnvironmentVariables(t *testing.T) {
+	t.Parallel()
+
+	telemetryConfig := &mcpv1alpha1.TelemetryConfig{
+		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+			Enabled:              ptr.To(true),
+			Endpoint:             "otel-collector:4317",
+			EnvironmentVariables: []string{"NODE_ENV", "APP_*", "APP_VERSION", "MISSING_*"},
+			Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
+				Enabled: true,
+			},
+		},
+	}
+	proxyEnv := []mcpv1alpha1.EnvVar{
+		{Name: "APP_VERSION", Value: "1.2.3"},
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "APP_REGION", Value: "eu-west-1"},
+	}
+
+	options := []runner.RunConfigBuilderOption{
+		runner.WithName("telemetry-server"),
+		runner.WithImage(testImage),
+	}
+	AddTelemetryConfigOptions(context.Background(), &options, telemetryConfig, "telemetry-server", proxyEnv)
+
+	rc, err := runner.NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, options...)
+	assert.NoError(t, err)
+	assert.NotNil(t, rc.TelemetryConfig)
+	assert.Equal(t, []string{"NODE_ENV", "APP_REGION", "APP_VERSION"}, rc.TelemetryConfig.EnvironmentVariables)
+}
+
+// TestAddTelemetryConfigOptions_MatchesCLIFlags tests that the operator produces the same telemetry
+// configuration as the CLI runner given the equivalent --otel-* flags, as both proxies read it from the RunConfig
+func TestAddTelemetryConfigOptions_MatchesCLIFlags(t *testing.T) {
+	t.Parallel()
+
+	telemetryConfig := &mcpv1alpha1.TelemetryConfig{
+		OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+			Enabled:              ptr.To(true),
+			Endpoint:             "https://otel-collector:4318",
+			ServiceName:          "github",
+			Headers:              []string{"X-API-Key=abc"},
+			Insecure:             true,
+			EnvironmentVariables: []string{"NODE_ENV"},
+			Tracing: &mcpv1alpha1.OpenTelemetryTracingConfig{
+				Enabled:      true,
+				SamplingRate: "0.25",
+			},
+			Metrics: &mcpv1alpha1.OpenTelemetryMetricsConfig{
+				Enabled: true,
+			},
+		},
+		Prometheus: &mcpv1alpha1.PrometheusConfig{
+			Enabled: true,
+		},
+	}
+
+	operatorOptions := []runner.RunConfigBuilderOption{runner.WithName("github"), runner.WithImage(testImage)}
+	AddTelemetryConfigOptions(context.Background(), &operatorOptions, telemetryConfig, "github", nil)
+	operatorConfig, err := runner.NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, operatorOptions...)
+	assert.NoError(t, err)
+
+	// thv run --otel-endpoint otel-collector:4318 --otel-service-name github --otel-headers X-API-Key=abc
+	// --otel-insecure --otel-env-vars NODE_ENV --otel-tracing-enabled --otel-sampling-rate 0.25
+	// --otel-metrics-enabled --otel-enable-prometheus-metrics-path
+	cliOptions := []runner.RunConfigBuilderOption{
+		runner.WithName("github"),
+		runner.WithImage(testImage),
+		runner.WithTelemetryConfig("otel-collector:4318", true, true, true, "github", 0.25,
+			[]string{"X-API-Key=abc"}, true, []string{"NODE_ENV"}),
+	}
+	cliConfig, err := runner.NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, cliOptions...)
+	assert.NoError(t, err)
+
+	assert.Equal(t, cliConfig.TelemetryConfig, operatorConfig.TelemetryConfig)
+}
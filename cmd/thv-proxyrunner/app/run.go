@@ -185,11 +185,11 @@ func runWithFileBasedConfig(
 
 	// Process environment files from EnvFileDir if specified (e.g., for Vault secrets)
 	if config.EnvFileDir != "" {
-		updatedConfig, err := config.WithEnvFilesFromDirectory(config.EnvFileDir)
+		loaded, overridden, err := config.WithEnvFilesFromDirectoryDetailed(ctx, config.EnvFileDir)
 		if err != nil {
 			return fmt.Errorf("failed to process environment files from directory %s: %v", config.EnvFileDir, err)
 		}
-		config = updatedConfig
+		logger.Debugf("Loaded environment variables %v from %s, overriding %v", loaded, config.EnvFileDir, overridden)
 	}
 
 	// Apply image metadata overrides if needed (similar to what the builder does)
@@ -76,6 +76,9 @@ type RemoteAuthFlags struct {
 	RemoteAuthAuthorizeURL     string
 	RemoteAuthTokenURL         string
 	RemoteAuthResource         string
+	RemoteAuthFlowTimeout      time.Duration
+	RemoteAuthScopePolicy      string
+	RemoteAuthCacheTokens      bool
 
 	// Token Exchange Configuration
 	TokenExchangeURL              string
@@ -165,6 +168,13 @@ func AddRemoteAuthFlags(cmd *cobra.Command, config *RemoteAuthFlags) {
 		"OAuth token endpoint URL (alternative to --remote-auth-issuer for non-OIDC OAuth)")
 	cmd.Flags().StringVar(&config.RemoteAuthResource, "remote-auth-resource", "",
 		"OAuth 2.0 resource indicator (RFC 8707)")
+	cmd.Flags().DurationVar(&config.RemoteAuthFlowTimeout, "remote-auth-flow-timeout", 0,
+		"Timeout for the whole OAuth flow, including discovery and client registration (0 means no limit)")
+	cmd.Flags().StringVar(&config.RemoteAuthScopePolicy, "remote-auth-scope-policy", "",
+		"How the requested scopes are combined with the scopes advertised by the server "+
+			"(union, intersection or user-only; by default the advertised scopes are only used when none are requested)")
+	cmd.Flags().BoolVar(&config.RemoteAuthCacheTokens, "remote-auth-cache-tokens", false,
+		"Store the OAuth tokens in the secrets provider so that later runs reuse them")
 
 	// Token Exchange flags
 	cmd.Flags().StringVar(&config.TokenExchangeURL, "token-exchange-url", "",
@@ -44,8 +44,9 @@ func IsOIDCEnabled(cmd *cobra.Command) bool {
 
 // SetSecretsProvider sets the secrets provider type in the configuration.
 // It validates the input, tests the provider functionality, and updates the configuration.
-// Choices are `encrypted`, `1password`, and `none`.
-func SetSecretsProvider(provider secrets.ProviderType) error {
+// Choices are `encrypted`, `1password`, and `none`. The autoFallback provider is the one the
+// `auto` provider uses outside Kubernetes, and must be empty for the other providers.
+func SetSecretsProvider(provider, autoFallback secrets.ProviderType) error {
 	// Validate input
 	if provider == "" {
 		fmt.Println("validation error: provider cannot be empty")
@@ -58,16 +59,30 @@ func SetSecretsProvider(provider secrets.ProviderType) error {
 	case secrets.OnePasswordType:
 	case secrets.NoneType:
 	case secrets.EnvironmentType:
+	case secrets.FileType:
+	case secrets.AutoType:
 		// Valid provider type
 	default:
-		return fmt.Errorf("invalid secrets provider type: %s (valid types: %s, %s, %s, %s)",
+		return fmt.Errorf("invalid secrets provider type: %s (valid types: %s, %s, %s, %s, %s, %s)",
 			provider, string(secrets.EncryptedType), string(secrets.OnePasswordType),
-			string(secrets.NoneType), string(secrets.EnvironmentType))
+			string(secrets.NoneType), string(secrets.EnvironmentType), string(secrets.FileType),
+			string(secrets.AutoType))
+	}
+
+	// The auto provider is validated as the provider it resolves to in the current environment
+	resolved := provider
+	if provider == secrets.AutoType {
+		if autoFallback == secrets.AutoType {
+			return fmt.Errorf("invalid auto fallback provider type: %s", autoFallback)
+		}
+		resolved = secrets.ResolveAutoProviderType(autoFallback)
+	} else if autoFallback != "" {
+		return fmt.Errorf("the auto fallback provider can only be set for the %s provider", secrets.AutoType)
 	}
 
 	// Validate that the provider can be created and works correctly
 	ctx := context.Background()
-	result := secrets.ValidateProvider(ctx, provider)
+	result := secrets.ValidateProvider(ctx, resolved)
 	if !result.Success {
 		return fmt.Errorf("provider validation failed: %w", result.Error)
 	}
@@ -75,6 +90,7 @@ func SetSecretsProvider(provider secrets.ProviderType) error {
 	// Update the secrets provider type and mark setup as completed
 	err := config.UpdateConfig(func(c *config.Config) {
 		c.Secrets.ProviderType = string(provider)
+		c.Secrets.AutoFallbackProviderType = string(autoFallback)
 		c.Secrets.SetupCompleted = true
 	})
 	if err != nil {
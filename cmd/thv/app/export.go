@@ -11,7 +11,10 @@ import (
 	"github.com/stacklok/toolhive/pkg/runner"
 )
 
-var exportFormat string
+var (
+	exportFormat    string
+	exportEffective bool
+)
 
 func newExportCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -24,23 +27,36 @@ the same workload with identical settings.
 
 You can export in different formats:
 - json: Export as RunConfig JSON (default, can be used with 'thv run --from-config')
+- yaml: Export as RunConfig YAML (can be used with 'thv run --from-config' with a .yaml or .yml file)
 - k8s: Export as Kubernetes MCPServer resource YAML
 
+With --effective, the effective run configuration recorded when the workload was last started
+is exported instead, once its secrets and env files are resolved. The values of the secrets are
+masked, so it is meant for debugging rather than for 'thv run --from-config'.
+
 Examples:
 
 	# Export a workload configuration to a JSON file
 	thv export my-server ./my-server-config.json
 
+	# Export a workload configuration to a YAML file
+	thv export my-server ./my-server-config.yaml --format yaml
+
 	# Export as Kubernetes MCPServer resource
 	thv export my-server ./my-server.yaml --format k8s
 
 	# Export to a specific directory
-	thv export github-mcp /tmp/configs/github-config.json`,
+	thv export github-mcp /tmp/configs/github-config.json
+
+	# Export the effective configuration, with the secret values masked
+	thv export my-server ./my-server-effective.json --effective`,
 		Args: cobra.ExactArgs(2),
 		RunE: exportCmdFunc,
 	}
 
-	cmd.Flags().StringVar(&exportFormat, "format", "json", "Export format: json or k8s")
+	cmd.Flags().StringVar(&exportFormat, "format", "json", "Export format: json, yaml or k8s")
+	cmd.Flags().BoolVar(&exportEffective, "effective", false,
+		"Export the effective run configuration of the workload, with the secret values masked (json or yaml only)")
 
 	return cmd
 }
@@ -51,12 +67,20 @@ func exportCmdFunc(cmd *cobra.Command, args []string) error {
 	outputPath := args[1]
 
 	// Validate format
-	if exportFormat != "json" && exportFormat != "k8s" {
-		return fmt.Errorf("invalid format '%s': must be 'json' or 'k8s'", exportFormat)
+	if exportFormat != "json" && exportFormat != "yaml" && exportFormat != "k8s" {
+		return fmt.Errorf("invalid format '%s': must be 'json', 'yaml' or 'k8s'", exportFormat)
+	}
+
+	if exportEffective && exportFormat == "k8s" {
+		return fmt.Errorf("the effective run configuration cannot be exported as a Kubernetes resource")
 	}
 
-	// Load the saved run configuration
-	runConfig, err := runner.LoadState(ctx, workloadName)
+	// Load the saved run configuration, or the effective one recorded by the runner
+	loadState := runner.LoadState
+	if exportEffective {
+		loadState = runner.LoadEffectiveState
+	}
+	runConfig, err := loadState(ctx, workloadName)
 	if err != nil {
 		return fmt.Errorf("failed to load run configuration for workload '%s': %w", workloadName, err)
 	}
@@ -82,6 +106,15 @@ func exportCmdFunc(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to write configuration to file: %w", err)
 		}
 		fmt.Printf("Successfully exported run configuration for '%s' to '%s'\n", workloadName, outputPath)
+	case "yaml":
+		data, err := runner.MarshalRunConfig(runConfig, runner.RunConfigFormatYAML)
+		if err != nil {
+			return fmt.Errorf("failed to write configuration to file: %w", err)
+		}
+		if _, err := outputFile.Write(data); err != nil {
+			return fmt.Errorf("failed to write configuration to file: %w", err)
+		}
+		fmt.Printf("Successfully exported run configuration for '%s' to '%s'\n", workloadName, outputPath)
 	case "k8s":
 		// Check for secrets and warn the user
 		if len(runConfig.Secrets) > 0 {
@@ -18,6 +18,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/registry"
 	types "github.com/stacklok/toolhive/pkg/registry/registry"
 	"github.com/stacklok/toolhive/pkg/runner/retriever"
+	"github.com/stacklok/toolhive/pkg/secrets"
 	"github.com/stacklok/toolhive/pkg/transport"
 	"github.com/stacklok/toolhive/pkg/validation"
 	"github.com/stacklok/toolhive/pkg/workloads"
@@ -639,17 +640,16 @@ func validateEnvVarFormat(envVar string, registryGroup *types.Group) error {
 }
 
 // filterSecretsForServer filters secrets that are targeted for a specific server
-func filterSecretsForServer(secrets []string, serverName string) []string {
+func filterSecretsForServer(groupSecrets []string, serverName string) []string {
 	var serverSecrets []string
-	for _, secret := range secrets {
+	for _, secret := range groupSecrets {
 		// Expected format: NAME,target=SERVER_NAME.TARGET
-		parts := strings.Split(secret, ",target=")
-		if len(parts) != 2 {
+		param, err := secrets.ParseSecretParameter(secret)
+		if err != nil {
 			continue // Skip invalid formats (should have been caught in validation)
 		}
 
-		targetPart := parts[1]
-		targetParts := strings.Split(targetPart, ".")
+		targetParts := strings.Split(param.Target, ".")
 		if len(targetParts) < 2 {
 			continue // Skip invalid formats (should have been caught in validation)
 		}
@@ -659,10 +659,9 @@ func filterSecretsForServer(secrets []string, serverName string) []string {
 			// Convert from group format to normal run format
 			// From: GITHUB_TOKEN,target=github.GITHUB_PERSONAL_ACCESS_TOKEN
 			// To: GITHUB_TOKEN,target=GITHUB_PERSONAL_ACCESS_TOKEN
-			secretName := parts[0]
 			targetEnvVar := strings.Join(targetParts[1:], ".") // Join in case env var has dots
-			normalRunFormat := fmt.Sprintf("%s,target=%s", secretName, targetEnvVar)
-			serverSecrets = append(serverSecrets, normalRunFormat)
+			param.Target = targetEnvVar
+			serverSecrets = append(serverSecrets, param.ToCLIString())
 		}
 	}
 	return serverSecrets
@@ -14,6 +14,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/auth/discovery"
 	"github.com/stacklok/toolhive/pkg/auth/oauth"
+	"github.com/stacklok/toolhive/pkg/auth/remote"
 	"github.com/stacklok/toolhive/pkg/auth/tokenexchange"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/networking"
@@ -321,6 +322,7 @@ func handleOutgoingAuthentication(ctx context.Context) (*discovery.OAuthFlowResu
 			Timeout:      remoteAuthFlags.RemoteAuthTimeout,
 			SkipBrowser:  remoteAuthFlags.RemoteAuthSkipBrowser,
 		}
+		applyOAuthFlowOptions(flowConfig, nil)
 
 		result, err := discovery.PerformOAuthFlow(ctx, remoteAuthFlags.RemoteAuthIssuer, flowConfig)
 		if err != nil {
@@ -351,6 +353,7 @@ func handleOutgoingAuthentication(ctx context.Context) (*discovery.OAuthFlowResu
 			Timeout:      remoteAuthFlags.RemoteAuthTimeout,
 			SkipBrowser:  remoteAuthFlags.RemoteAuthSkipBrowser,
 		}
+		applyOAuthFlowOptions(flowConfig, authInfo.ScopesSupported)
 
 		result, err := discovery.PerformOAuthFlow(ctx, authInfo.Realm, flowConfig)
 		if err != nil {
@@ -363,6 +366,16 @@ func handleOutgoingAuthentication(ctx context.Context) (*discovery.OAuthFlowResu
 	return nil, nil // No authentication required
 }
 
+// applyOAuthFlowOptions sets the flow timeout, scope policy and token cache flags on the OAuth flow config
+func applyOAuthFlowOptions(flowConfig *discovery.OAuthFlowConfig, scopesSupported []string) {
+	options := &remote.Config{
+		FlowTimeout: remoteAuthFlags.RemoteAuthFlowTimeout,
+		ScopePolicy: remoteAuthFlags.RemoteAuthScopePolicy,
+		CacheTokens: remoteAuthFlags.RemoteAuthCacheTokens,
+	}
+	options.ApplyFlowOptions(flowConfig, scopesSupported)
+}
+
 // resolveClientSecret resolves the OAuth client secret from multiple sources
 // Priority: 1. Flag value, 2. File, 3. Environment variable
 func resolveClientSecret() (string, error) {
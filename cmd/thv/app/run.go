@@ -418,15 +418,14 @@ func ValidateAndNormaliseHostFlag(host string) (string, error) {
 
 // runFromConfigFile loads a run configuration from a file and executes it
 func runFromConfigFile(ctx context.Context) error {
-	// Open and read the configuration file
-	configFile, err := os.Open(runFlags.FromConfig)
+	// Read the configuration file
+	configData, err := os.ReadFile(runFlags.FromConfig)
 	if err != nil {
 		return fmt.Errorf("failed to open configuration file '%s': %w", runFlags.FromConfig, err)
 	}
-	defer configFile.Close()
 
-	// Deserialize the configuration
-	runConfig, err := runner.ReadJSON(configFile)
+	// Deserialize and validate the configuration, in YAML for .yaml and .yml files and JSON otherwise
+	runConfig, err := runner.UnmarshalRunConfig(configData, runner.RunConfigFormatFromPath(runFlags.FromConfig))
 	if err != nil {
 		return fmt.Errorf("failed to parse configuration file: %w", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -42,6 +43,9 @@ type RunFlags struct {
 	TargetPort int
 	TargetHost string
 
+	// ShutdownGracePeriod is how long the proxy drains active client connections on shutdown
+	ShutdownGracePeriod time.Duration
+
 	// Server configuration
 	Name              string
 	Group             string
@@ -124,6 +128,8 @@ type RunFlags struct {
 func AddRunFlags(cmd *cobra.Command, config *RunFlags) {
 	cmd.Flags().StringVar(&config.Transport, "transport", "", "Transport mode (sse, streamable-http or stdio)")
 	cmd.Flags().StringVar(&config.ProxyMode, "proxy-mode", "streamable-http", "Proxy mode for stdio (streamable-http or sse)")
+	cmd.Flags().DurationVar(&config.ShutdownGracePeriod, "shutdown-grace-period", transport.DefaultShutdownGracePeriod,
+		"Time to wait for active client connections to drain on shutdown before forcing them closed")
 	cmd.Flags().StringVar(&config.Name, "name", "", "Name of the MCP server (auto-generated from image if not provided)")
 	cmd.Flags().StringVar(&config.Group, "group", "default",
 		"Name of the group this workload belongs to (defaults to 'default' if not specified)")
@@ -452,6 +458,7 @@ func buildRunnerConfig(
 		runner.WithNetworkMode(runFlags.Network),
 		runner.WithK8sPodPatch(runFlags.K8sPodPatch),
 		runner.WithProxyMode(types.ProxyMode(runFlags.ProxyMode)),
+		runner.WithShutdownGracePeriod(runFlags.ShutdownGracePeriod),
 		runner.WithTransportAndPorts(transportType, runFlags.ProxyPort, runFlags.TargetPort),
 		runner.WithAuditEnabled(runFlags.EnableAudit, runFlags.AuditConfig),
 		runner.WithLabels(runFlags.Labels),
@@ -665,6 +672,9 @@ func getRemoteAuthFromRemoteServerMetadata(
 		Timeout:      f.RemoteAuthTimeout,
 		Headers:      remoteServerMetadata.Headers,
 		EnvVars:      remoteServerMetadata.EnvVars,
+		FlowTimeout:  f.RemoteAuthFlowTimeout,
+		ScopePolicy:  f.RemoteAuthScopePolicy,
+		CacheTokens:  f.RemoteAuthCacheTokens,
 	}
 
 	// Scopes: CLI overrides if provided
@@ -746,6 +756,9 @@ func getRemoteAuthFromRunFlags(runFlags *RunFlags) (*remote.Config, error) {
 		TokenURL:     runFlags.RemoteAuthFlags.RemoteAuthTokenURL,
 		Resource:     resource,
 		OAuthParams:  runFlags.OAuthParams,
+		FlowTimeout:  runFlags.RemoteAuthFlags.RemoteAuthFlowTimeout,
+		ScopePolicy:  runFlags.RemoteAuthFlags.RemoteAuthScopePolicy,
+		CacheTokens:  runFlags.RemoteAuthFlags.RemoteAuthCacheTokens,
 	}, nil
 }
 
@@ -40,7 +40,8 @@ Run "thv secret setup" first to configure a secrets provider before using any se
 }
 
 func newSecretProviderCommand() *cobra.Command {
-	return &cobra.Command{
+	var autoFallback string
+	cmd := &cobra.Command{
 		Use:   "provider <name>",
 		Short: "Set the secrets provider directly",
 		Long: `Configure the secrets provider directly.
@@ -53,13 +54,18 @@ making it suitable for scripted deployments and automation.
 Valid secrets providers:
   - encrypted: Full read-write secrets provider using AES-256-GCM encryption
   - 1password: Read-only secrets provider (requires OP_SERVICE_ACCOUNT_TOKEN)
-  - none: Disables secrets functionality`,
+  - none: Disables secrets functionality
+  - file: Read-only secrets provider reading files below TOOLHIVE_SECRETS_FILE_ROOT (default /mnt/secrets-store)
+  - auto: Uses environment variables in Kubernetes and the --auto-fallback provider (default none) elsewhere`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			provider := args[0]
-			return SetSecretsProvider(secrets.ProviderType(provider))
+			return SetSecretsProvider(secrets.ProviderType(provider), secrets.ProviderType(autoFallback))
 		},
 	}
+	cmd.Flags().StringVar(&autoFallback, "auto-fallback", "",
+		"Provider the auto provider uses outside Kubernetes (default none)")
+	return cmd
 }
 
 func newSecretSetupCommand() *cobra.Command {
@@ -438,7 +444,7 @@ This provider is read-only and suitable for CI/CD and containerized environments
 
 	// SetSecretsProvider will handle validation and configuration
 	fmt.Println("Validating provider setup...")
-	if err := SetSecretsProvider(providerType); err != nil {
+	if err := SetSecretsProvider(providerType, ""); err != nil {
 		return fmt.Errorf("failed to configure secrets provider: %w", err)
 	}
 
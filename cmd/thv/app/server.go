@@ -19,6 +19,7 @@ var (
 	host            string
 	port            int
 	enableDocs      bool
+	basePath        string
 	socketPath      string
 	enableMCPServer bool
 	mcpServerPort   string
@@ -99,7 +100,7 @@ var serveCmd = &cobra.Command{
 			}()
 		}
 
-		return s.Serve(ctx, address, isUnixSocket, debugMode, enableDocs, oidcConfig)
+		return s.Serve(ctx, address, isUnixSocket, debugMode, enableDocs, basePath, oidcConfig)
 	},
 }
 
@@ -107,7 +108,9 @@ func init() {
 	serveCmd.Flags().StringVar(&host, "host", "127.0.0.1", "Host address to bind the server to")
 	serveCmd.Flags().IntVar(&port, "port", 8080, "Port to bind the server to")
 	serveCmd.Flags().BoolVar(&enableDocs, "openapi", false,
-		"Enable OpenAPI documentation endpoints (/api/openapi.json and /api/doc)")
+		"Enable OpenAPI documentation endpoints (/api/openapi.json, /api/doc, /api/swagger.json and /api/swagger)")
+	serveCmd.Flags().StringVar(&basePath, "base-path", "",
+		"Path prefix to serve all the API routes under, e.g. when behind a gateway (e.g., /toolhive)")
 	serveCmd.Flags().StringVar(&socketPath, "socket", "", "UNIX socket path to bind the "+
 		"server to (overrides host and port if provided)")
 
@@ -11,5 +11,20 @@ func DocsRouter() http.Handler {
 	r := chi.NewRouter()
 	r.Get("/openapi.json", ServeOpenAPI)
 	r.Get("/doc", ServeScalar)
+	registerSwaggerRoutes(r)
 	return r
 }
+
+// SwaggerRouter creates a new router serving the OpenAPI specification at /swagger.json
+// and a Swagger UI browsing it at /swagger.
+func SwaggerRouter() http.Handler {
+	r := chi.NewRouter()
+	registerSwaggerRoutes(r)
+	return r
+}
+
+// registerSwaggerRoutes registers the Swagger routes on the router
+func registerSwaggerRoutes(r chi.Router) {
+	r.Get("/swagger.json", ServeOpenAPI)
+	r.Get("/swagger", ServeSwaggerUI)
+}
@@ -12,7 +12,7 @@ const scalarHTML = `<!doctype html>
     <meta name="viewport" content="width=device-width, initial-scale=1" />
   </head>
   <body>
-    <script id="api-reference" data-url="/api/openapi.json"></script>
+    <script id="api-reference" data-url="openapi.json"></script>
     <script>
       const servers = [
         {
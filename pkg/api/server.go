@@ -19,6 +19,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -50,6 +51,7 @@ type ServerBuilder struct {
 	isUnixSocket     bool
 	debugMode        bool
 	enableDocs       bool
+	basePath         string
 	oidcConfig       *auth.TokenValidatorConfig
 	middlewares      []func(http.Handler) http.Handler
 	customRoutes     map[string]http.Handler
@@ -91,6 +93,26 @@ func (b *ServerBuilder) WithDocs(enableDocs bool) *ServerBuilder {
 	return b
 }
 
+// WithBasePath serves all the routes below basePath (e.g. /toolhive), for when the API is
+// mounted behind a gateway. An empty base path or "/" serves the routes at the root.
+func (b *ServerBuilder) WithBasePath(basePath string) *ServerBuilder {
+	b.basePath = normalizeBasePath(basePath)
+	return b
+}
+
+// normalizeBasePath returns the base path with a leading slash and without a trailing one,
+// or an empty string for the root
+func normalizeBasePath(basePath string) string {
+	if basePath == "" {
+		return ""
+	}
+	cleaned := path.Clean("/" + basePath)
+	if cleaned == "/" {
+		return ""
+	}
+	return cleaned
+}
+
 // WithOIDCConfig sets the OIDC configuration
 func (b *ServerBuilder) WithOIDCConfig(oidcConfig *auth.TokenValidatorConfig) *ServerBuilder {
 	b.oidcConfig = oidcConfig
@@ -142,7 +164,8 @@ func (b *ServerBuilder) Build(ctx context.Context) (*chi.Mux, error) {
 		middleware.RequestID,
 		// TODO: Figure out logging middleware. We may want to use a different logger.
 		middleware.Timeout(middlewareTimeout),
-		headersMiddleware,
+		headersMiddleware(b.basePath),
+		v1.CompressionMiddleware(v1.DefaultCompressionMinSize),
 	)
 
 	// Add update check middleware
@@ -173,7 +196,17 @@ func (b *ServerBuilder) Build(ctx context.Context) (*chi.Mux, error) {
 		r.Mount(prefix, handler)
 	}
 
-	return r, nil
+	return withBasePath(b.basePath, r), nil
+}
+
+// withBasePath mounts the router below basePath, so that none of its routes is served outside of it
+func withBasePath(basePath string, r *chi.Mux) *chi.Mux {
+	if basePath == "" {
+		return r
+	}
+	root := chi.NewRouter()
+	root.Mount(basePath, r)
+	return root
 }
 
 // createDefaultManagers creates default managers if they weren't provided
@@ -215,6 +248,7 @@ func (b *ServerBuilder) createDefaultManagers(ctx context.Context) error {
 func (b *ServerBuilder) setupDefaultRoutes(r *chi.Mux) {
 	routers := map[string]http.Handler{
 		"/health":             v1.HealthcheckRouter(b.containerRuntime),
+		"/ready":              v1.ReadinessRouter(b.containerRuntime),
 		"/api/v1beta/version": v1.VersionRouter(),
 		"/api/v1beta/workloads": v1.WorkloadRouter(
 			b.workloadManager,
@@ -276,13 +310,16 @@ func cleanupUnixSocket(address string) {
 	}
 }
 
-func headersMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.HasPrefix(r.URL.Path, "/api/") {
-			w.Header().Set("Content-Type", "application/json")
-		}
-		next.ServeHTTP(w, r)
-	})
+func headersMiddleware(basePath string) func(http.Handler) http.Handler {
+	apiPrefix := basePath + "/api/"
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, apiPrefix) {
+				w.Header().Set("Content-Type", "application/json")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 // updateCheckMiddleware triggers update checks for API usage
@@ -432,6 +469,7 @@ func createListener(address string, isUnixSocket bool) (net.Listener, string, er
 // Serve starts the server on the given address and serves the API.
 // It is assumed that the caller sets up appropriate signal handling.
 // If isUnixSocket is true, address is treated as a UNIX socket path.
+// If basePath is not empty, all the routes are served below it.
 // If oidcConfig is provided, OIDC authentication will be enabled for all API endpoints.
 func Serve(
 	ctx context.Context,
@@ -439,6 +477,7 @@ func Serve(
 	isUnixSocket bool,
 	debugMode bool,
 	enableDocs bool,
+	basePath string,
 	oidcConfig *auth.TokenValidatorConfig,
 	middlewares ...func(http.Handler) http.Handler,
 ) error {
@@ -447,6 +486,7 @@ func Serve(
 		WithUnixSocket(isUnixSocket).
 		WithDebugMode(debugMode).
 		WithDocs(enableDocs).
+		WithBasePath(basePath).
 		WithOIDCConfig(oidcConfig).
 		WithMiddleware(middlewares...)
 
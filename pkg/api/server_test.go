@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	v1 "github.com/stacklok/toolhive/pkg/api/v1"
+	"github.com/stacklok/toolhive/pkg/container/runtime/mocks"
+)
+
+func TestNormalizeBasePath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":            "",
+		"/":           "",
+		"/toolhive":   "/toolhive",
+		"toolhive":    "/toolhive",
+		"/toolhive/":  "/toolhive",
+		"//tools//v1": "/tools/v1",
+	}
+	for basePath, expected := range tests {
+		assert.Equal(t, expected, normalizeBasePath(basePath), "base path %q", basePath)
+	}
+}
+
+func TestWithBasePath(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockRuntime := mocks.NewMockRuntime(ctrl)
+	mockRuntime.EXPECT().IsRunning(gomock.Any()).Return(nil).AnyTimes()
+
+	basePath := NewServerBuilder().WithBasePath("/toolhive/").basePath
+	r := chi.NewRouter()
+	r.Use(headersMiddleware(basePath))
+	r.Mount("/health", v1.HealthcheckRouter(mockRuntime))
+	r.Mount("/api/v1beta/version", v1.VersionRouter())
+	handler := withBasePath(basePath, r)
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/toolhive/health", expectedStatus: http.StatusNoContent},
+		{path: "/toolhive/api/v1beta/version", expectedStatus: http.StatusOK},
+		{path: "/health", expectedStatus: http.StatusNotFound},
+		{path: "/api/v1beta/version", expectedStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestWithBasePath_Root(t *testing.T) {
+	t.Parallel()
+
+	r := chi.NewRouter()
+	r.Mount("/api/v1beta/version", v1.VersionRouter())
+	handler := withBasePath(NewServerBuilder().WithBasePath("/").basePath, r)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1beta/version", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestSwaggerRouter(t *testing.T) {
+	t.Parallel()
+
+	handler := SwaggerRouter()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title string `json:"title"`
+		} `json:"info"`
+		Paths map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, "ToolHive API", spec.Info.Title)
+	assert.NotEmpty(t, spec.OpenAPI)
+	assert.Contains(t, spec.Paths, "/api/v1beta/version")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `url: "swagger.json"`)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+)
+
+// swaggerUIHTML loads the specification relative to the page, so that it works wherever the router is mounted
+const swaggerUIHTML = `<!doctype html>
+<html>
+  <head>
+    <title>ToolHive API Reference</title>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css" />
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+      window.onload = () => {
+        window.ui = SwaggerUIBundle({
+          url: "swagger.json",
+          dom_id: "#swagger-ui",
+        });
+      };
+    </script>
+  </body>
+</html>`
+
+// ServeSwaggerUI serves the Swagger UI page
+func ServeSwaggerUI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	if _, err := w.Write([]byte(swaggerUIHTML)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,188 @@
+package v1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultCompressionMinSize is the default size in bytes under which responses are not compressed,
+// since the gzip overhead outweighs the savings for tiny bodies
+const DefaultCompressionMinSize = 1024
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// CompressionMiddleware returns a middleware gzip-compressing the responses of the clients accepting it.
+// Responses are buffered until they reach minSize bytes, smaller responses are sent uncompressed with
+// their Content-Length. A minSize of zero or less uses DefaultCompressionMinSize.
+// Event streams, and responses flushed before reaching minSize, are streamed uncompressed.
+func CompressionMiddleware(minSize int) func(http.Handler) http.Handler {
+	if minSize <= 0 {
+		minSize = DefaultCompressionMinSize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{ResponseWriter: w, minSize: minSize, status: http.StatusOK}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip, ignoring encodings with q=0
+func acceptsGzip(acceptEncoding string) bool {
+	for _, encoding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), "gzip") && strings.TrimSpace(name) != "*" {
+			continue
+		}
+		q, found := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q=")
+		if !found {
+			return true
+		}
+		if value, err := strconv.ParseFloat(q, 64); err == nil && value > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// compressResponseWriter buffers the response until it is large enough to be worth compressing,
+// and then either compresses it or sends it as is
+type compressResponseWriter struct {
+	http.ResponseWriter
+	minSize     int
+	status      int
+	wroteHeader bool
+	buf         bytes.Buffer
+	// decided is set once the response is either compressed through gz or sent as is
+	decided bool
+	gz      *gzip.Writer
+}
+
+// WriteHeader records the status code, which is sent once the response is decided
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	if isEventStream(w.Header()) {
+		_ = w.decide(false, false)
+	}
+}
+
+// Write buffers the body until it reaches the minimum size
+func (w *compressResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	if !w.decided && isEventStream(w.Header()) {
+		if err := w.decide(false, false); err != nil {
+			return 0, err
+		}
+	}
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf.Write(p)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(true, false); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends the buffered response uncompressed if it is still too small to be compressed,
+// without a Content-Length since more of the body may follow
+func (w *compressResponseWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false, false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped response writer for http.ResponseController
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide sends the headers and the buffered body, compressing them if compress is true and the
+// response is not already encoded or bodiless. The Content-Length is only set when complete is true,
+// i.e. when the buffer holds the whole body.
+func (w *compressResponseWriter) decide(compress, complete bool) error {
+	w.decided = true
+	header := w.Header()
+
+	if compress && header.Get("Content-Encoding") == "" && bodyAllowed(w.status) {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", "gzip")
+		w.ResponseWriter.WriteHeader(w.status)
+
+		w.gz = gzipWriterPool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+		_, err := w.gz.Write(w.buf.Bytes())
+		return err
+	}
+
+	if complete && header.Get("Content-Length") == "" && bodyAllowed(w.status) {
+		header.Set("Content-Length", strconv.Itoa(w.buf.Len()))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	return err
+}
+
+// close sends the response if it is still buffered, or terminates the gzip stream
+func (w *compressResponseWriter) close() {
+	if !w.decided {
+		// The response is smaller than the minimum size, or empty
+		if !w.wroteHeader {
+			return
+		}
+		_ = w.decide(false, true)
+		return
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+		gzipWriterPool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// isEventStream reports whether the response is a server-sent event stream, which must reach the client
+// as it is written
+func isEventStream(header http.Header) bool {
+	mediaType, _, _ := strings.Cut(header.Get("Content-Type"), ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), "text/event-stream")
+}
+
+// bodyAllowed reports whether a response with the status code can have a body
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bodyHandler responds with the status and body, writing the body in two parts
+func bodyHandler(status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		half := len(body) / 2
+		_, _ = w.Write([]byte(body[:half]))
+		_, _ = w.Write([]byte(body[half:]))
+	})
+}
+
+// doCompressedRequest sends a request with the Accept-Encoding header through the handler
+func doCompressedRequest(handler http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1beta/workloads", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	largeBody := `{"workloads":[` + strings.Repeat(`{"name":"fetch","status":"running"},`, 100) + `{}]}`
+	smallBody := `{"workloads":[]}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		status         int
+		body           string
+		expectGzip     bool
+	}{
+		{
+			name:           "large response is compressed",
+			acceptEncoding: "gzip, deflate, br",
+			status:         http.StatusOK,
+			body:           largeBody,
+			expectGzip:     true,
+		},
+		{
+			name:           "status code is kept",
+			acceptEncoding: "gzip",
+			status:         http.StatusCreated,
+			body:           largeBody,
+			expectGzip:     true,
+		},
+		{
+			name:           "small response is not compressed",
+			acceptEncoding: "gzip",
+			status:         http.StatusOK,
+			body:           smallBody,
+		},
+		{
+			name:   "gzip not accepted",
+			status: http.StatusOK,
+			body:   largeBody,
+		},
+		{
+			name:           "gzip refused",
+			acceptEncoding: "gzip;q=0, deflate",
+			status:         http.StatusOK,
+			body:           largeBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := doCompressedRequest(CompressionMiddleware(0)(bodyHandler(tt.status, tt.body)), tt.acceptEncoding)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+
+			if !tt.expectGzip {
+				assert.Empty(t, rec.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.body, rec.Body.String())
+				if rec.Body.Len() < DefaultCompressionMinSize && tt.acceptEncoding != "" {
+					assert.Equal(t, strconv.Itoa(len(tt.body)), rec.Header().Get("Content-Length"))
+				}
+				return
+			}
+
+			assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+			assert.Empty(t, rec.Header().Get("Content-Length"))
+			assert.Less(t, rec.Body.Len(), len(tt.body))
+
+			reader, err := gzip.NewReader(rec.Body)
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(decompressed))
+		})
+	}
+}
+
+func TestCompressionMiddleware_AlreadyEncoded(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("a", 2*DefaultCompressionMinSize)
+	handler := CompressionMiddleware(0)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = w.Write([]byte(body))
+	}))
+
+	rec := doCompressedRequest(handler, "gzip, br")
+	assert.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, rec.Body.String())
+}
+
+func TestCompressionMiddleware_NoContent(t *testing.T) {
+	t.Parallel()
+
+	handler := CompressionMiddleware(0)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := doCompressedRequest(handler, "gzip")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+	assert.Zero(t, rec.Body.Len())
+}
+
+func TestCompressionMiddleware_Streaming(t *testing.T) {
+	t.Parallel()
+
+	event := "data: " + strings.Repeat("x", DefaultCompressionMinSize) + "\n\n"
+
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{
+			name:        "flushed response",
+			contentType: "application/json",
+		},
+		{
+			name:        "event stream",
+			contentType: "text/event-stream; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			writeErrs := make(chan error, 3)
+			handler := CompressionMiddleware(0)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(http.StatusOK)
+				flusher, ok := w.(http.Flusher)
+				require.True(t, ok)
+				flusher.Flush()
+				for range 3 {
+					_, err := w.Write([]byte(event))
+					writeErrs <- err
+					flusher.Flush()
+				}
+			}))
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept-Encoding", "gzip")
+			resp, err := server.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Empty(t, resp.Header.Get("Content-Encoding"))
+			assert.Empty(t, resp.Header.Get("Content-Length"))
+			assert.Equal(t, strings.Repeat(event, 3), string(body))
+			for range 3 {
+				assert.NoError(t, <-writeErrs)
+			}
+		})
+	}
+}
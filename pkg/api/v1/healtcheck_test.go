@@ -1,15 +1,21 @@
 package v1
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/stacklok/toolhive/pkg/config"
 	"github.com/stacklok/toolhive/pkg/container/runtime/mocks"
+	"github.com/stacklok/toolhive/pkg/secrets"
 )
 
 func TestGetHealthcheck(t *testing.T) {
@@ -80,3 +86,114 @@ func TestGetHealthcheck(t *testing.T) {
 		assert.Equal(t, expectedError.Error()+"\n", resp.Body.String())
 	})
 }
+
+func TestReadinessRouter_SecretsProvider(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		secretsErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "returns 204 when the secrets provider is healthy",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "returns 503 when the secrets provider is not healthy",
+			secretsErr:     errors.New("1Password service unreachable"),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockRuntime := mocks.NewMockRuntime(ctrl)
+			mockRuntime.EXPECT().IsRunning(gomock.Any()).Return(nil)
+
+			checked := false
+			router := readinessRouterWithRoutes(&healthcheckRoutes{
+				containerRuntime: mockRuntime,
+				secretsHealthCheck: func(context.Context) error {
+					checked = true
+					return tt.secretsErr
+				},
+			})
+
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.True(t, checked)
+			assert.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.secretsErr != nil {
+				assert.Contains(t, resp.Body.String(), tt.secretsErr.Error())
+			}
+		})
+	}
+}
+
+func TestSecretsProviderHealthCheck_SkipsLocalProviders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		secrets config.Secrets
+	}{
+		{
+			name:    "setup not completed",
+			secrets: config.Secrets{ProviderType: string(secrets.OnePasswordType)},
+		},
+		{
+			name:    "encrypted provider",
+			secrets: config.Secrets{ProviderType: string(secrets.EncryptedType), SetupCompleted: true},
+		},
+		{
+			name:    "none provider",
+			secrets: config.Secrets{ProviderType: string(secrets.NoneType), SetupCompleted: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configProvider := config.NewPathProvider(filepath.Join(t.TempDir(), "config.yaml"))
+			require.NoError(t, configProvider.UpdateConfig(func(c *config.Config) {
+				c.Secrets = tt.secrets
+			}))
+
+			assert.NoError(t, newSecretsProviderHealthCheck(configProvider)(t.Context()))
+		})
+	}
+}
+
+func TestCachedHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	calls := 0
+	checkErr := errors.New("1Password service unreachable")
+	cached := &cachedHealthCheck{
+		check: func(context.Context) error {
+			calls++
+			return checkErr
+		},
+		ttl: time.Minute,
+		now: func() time.Time { return now },
+	}
+
+	// The result is reused within the TTL
+	assert.ErrorIs(t, cached.Check(t.Context()), checkErr)
+	now = now.Add(30 * time.Second)
+	assert.ErrorIs(t, cached.Check(t.Context()), checkErr)
+	assert.Equal(t, 1, calls)
+
+	// The check runs again once the result expired
+	checkErr = nil
+	now = now.Add(time.Minute)
+	assert.NoError(t, cached.Check(t.Context()))
+	assert.Equal(t, 2, calls)
+}
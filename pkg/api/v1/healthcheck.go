@@ -1,13 +1,23 @@
 package v1
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/stacklok/toolhive/pkg/config"
 	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/secrets"
 )
 
+// secretsHealthCheckTTL is how long the result of the secrets provider health check is reused,
+// so that frequent readiness probes do not each reach the remote service backing the provider
+const secretsHealthCheckTTL = 30 * time.Second
+
 // HealthcheckRouter sets up healthcheck route.
 func HealthcheckRouter(containerRuntime rt.Runtime) http.Handler {
 	routes := &healthcheckRoutes{containerRuntime: containerRuntime}
@@ -16,8 +26,102 @@ func HealthcheckRouter(containerRuntime rt.Runtime) http.Handler {
 	return r
 }
 
+// ReadinessRouter sets up the readiness route, which also checks the configured secrets provider.
+func ReadinessRouter(containerRuntime rt.Runtime) http.Handler {
+	routes := &healthcheckRoutes{
+		containerRuntime:   containerRuntime,
+		secretsHealthCheck: newCachedHealthCheck(newSecretsProviderHealthCheck(config.NewDefaultProvider()), secretsHealthCheckTTL),
+	}
+	return readinessRouterWithRoutes(routes)
+}
+
+func readinessRouterWithRoutes(routes *healthcheckRoutes) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", routes.getReadiness)
+	return r
+}
+
 type healthcheckRoutes struct {
 	containerRuntime rt.Runtime
+	// secretsHealthCheck checks the configured secrets provider for readiness, it is skipped if nil
+	secretsHealthCheck func(ctx context.Context) error
+}
+
+// cachedHealthCheck reuses the result of a health check until it is older than its TTL.
+// Concurrent callers wait for the running check instead of starting their own.
+type cachedHealthCheck struct {
+	check func(ctx context.Context) error
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+func newCachedHealthCheck(check func(ctx context.Context) error, ttl time.Duration) func(ctx context.Context) error {
+	c := &cachedHealthCheck{check: check, ttl: ttl, now: time.Now}
+	return c.Check
+}
+
+// Check returns the cached result of the health check, running it again if the result expired
+func (c *cachedHealthCheck) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkedAt.IsZero() && c.now().Sub(c.checkedAt) < c.ttl {
+		return c.err
+	}
+	c.err = c.check(ctx)
+	c.checkedAt = c.now()
+	return c.err
+}
+
+// secretsProviderHealthCheck checks the configured secrets provider. Only the providers backed by a
+// remote service are created and checked, so that the check never prompts for the password of the
+// encrypted provider. The provider is created once and kept until the configured type changes.
+type secretsProviderHealthCheck struct {
+	configProvider config.Provider
+
+	mu           sync.Mutex
+	providerType secrets.ProviderType
+	provider     secrets.Provider
+}
+
+func newSecretsProviderHealthCheck(configProvider config.Provider) func(ctx context.Context) error {
+	c := &secretsProviderHealthCheck{configProvider: configProvider}
+	return c.Check
+}
+
+// Check reports whether the configured secrets provider is healthy
+func (c *secretsProviderHealthCheck) Check(ctx context.Context) error {
+	cfg := c.configProvider.GetConfig()
+	if !cfg.Secrets.SetupCompleted {
+		return nil
+	}
+
+	providerType, err := cfg.Secrets.GetProviderType()
+	if err != nil {
+		return err
+	}
+	if providerType != secrets.OnePasswordType {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.provider == nil || c.providerType != providerType {
+		provider, err := secrets.CreateSecretProvider(providerType)
+		if err != nil {
+			return fmt.Errorf("failed to create secrets provider: %w", err)
+		}
+		c.provider, c.providerType = provider, providerType
+	}
+
+	if checker, ok := c.provider.(secrets.HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
 }
 
 //	 getHealthcheck
@@ -35,3 +139,26 @@ func (h *healthcheckRoutes) getHealthcheck(w http.ResponseWriter, r *http.Reques
 	// If the container runtime is running, we consider the API healthy.
 	w.WriteHeader(http.StatusNoContent)
 }
+
+//	 getReadiness
+//		@Summary		Readiness check
+//		@Description	Check if the API is ready to run workloads, i.e. the container runtime is running
+//		@Description	and the configured secrets provider is healthy
+//		@Tags			system
+//		@Success		204	{string}	string	"No Content"
+//		@Failure		503	{string}	string	"Service Unavailable"
+//		@Router			/ready [get]
+func (h *healthcheckRoutes) getReadiness(w http.ResponseWriter, r *http.Request) {
+	if err := h.containerRuntime.IsRunning(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if h.secretsHealthCheck != nil {
+		if err := h.secretsHealthCheck(r.Context()); err != nil {
+			// Workloads using secrets cannot be started while the secrets provider is unavailable.
+			http.Error(w, fmt.Sprintf("secrets provider is not healthy: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultRateLimitRequestsPerSecond is the default sustained request rate allowed for each client
+	DefaultRateLimitRequestsPerSecond = 10
+	// DefaultRateLimitBurst is the default number of requests each client may make at once
+	DefaultRateLimitBurst = 20
+
+	// rateLimiterIdleTimeout is how long the limiter of a client is kept after its last request
+	rateLimiterIdleTimeout = 10 * time.Minute
+)
+
+// RateLimitConfig configures the per-client rate limiting of the API.
+// Zero values are replaced by the defaults.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained request rate allowed for each client IP
+	RequestsPerSecond float64
+	// Burst is the number of requests each client IP may make at once
+	Burst int
+}
+
+// RateLimitMiddleware returns a middleware limiting the requests of each client IP with a token bucket.
+// Requests over the limit are rejected with 429 Too Many Requests and a Retry-After header telling
+// the client when to try again. It can be mounted on any router.
+func RateLimitMiddleware(config RateLimitConfig) func(http.Handler) http.Handler {
+	return newIPRateLimiter(config, time.Now).middleware
+}
+
+// clientLimiter is the token bucket of a client along with the time of its last request
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// ipRateLimiter keeps a token bucket for each client IP
+type ipRateLimiter struct {
+	limit     rate.Limit
+	burst     int
+	now       func() time.Time
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+func newIPRateLimiter(config RateLimitConfig, now func() time.Time) *ipRateLimiter {
+	if config.RequestsPerSecond <= 0 {
+		config.RequestsPerSecond = DefaultRateLimitRequestsPerSecond
+	}
+	if config.Burst <= 0 {
+		config.Burst = DefaultRateLimitBurst
+	}
+	return &ipRateLimiter{
+		limit:     rate.Limit(config.RequestsPerSecond),
+		burst:     config.Burst,
+		now:       now,
+		clients:   make(map[string]*clientLimiter),
+		lastSweep: now(),
+	}
+}
+
+func (l *ipRateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if delay := l.reserve(clientIP(r)); delay > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reserve takes a token from the bucket of the client. If none is available, it returns
+// how long the client has to wait for the next one.
+func (l *ipRateLimiter) reserve(client string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	c, ok := l.clients[client]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[client] = c
+	}
+	c.lastSeen = now
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// The request is rejected, so it must not consume the token it would have waited for
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// sweep forgets the clients which have been idle for longer than rateLimiterIdleTimeout,
+// so that the limiters of past clients do not accumulate
+func (l *ipRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterIdleTimeout {
+		return
+	}
+	for client, c := range l.clients {
+		if now.Sub(c.lastSeen) > rateLimiterIdleTimeout {
+			delete(l.clients, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientIP returns the IP address the request comes from, or the whole remote address
+// when it has no port, as is the case for Unix sockets
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for rate limiter tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// doRateLimitedRequest sends a request from remoteAddr through the handler and returns the response
+func doRateLimitedRequest(handler http.Handler, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1beta/version", nil)
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestRateLimitMiddleware_LimitsBursts(t *testing.T) {
+	t.Parallel()
+
+	handler := RateLimitMiddleware(RateLimitConfig{RequestsPerSecond: 1, Burst: 5})(okHandler)
+
+	var limited int
+	for i := range 20 {
+		rec := doRateLimitedRequest(handler, "192.0.2.1:1234")
+		if i < 5 {
+			assert.Equal(t, http.StatusOK, rec.Code, "requests within the burst should pass")
+			continue
+		}
+		if rec.Code == http.StatusTooManyRequests {
+			limited++
+			assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+		}
+	}
+	assert.Greater(t, limited, 10, "requests past the burst should be limited")
+
+	// Other clients have their own bucket
+	assert.Equal(t, http.StatusOK, doRateLimitedRequest(handler, "192.0.2.2:1234").Code)
+}
+
+func TestRateLimitMiddleware_SlowClientIsNeverLimited(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Now()}
+	handler := newIPRateLimiter(RateLimitConfig{RequestsPerSecond: 2, Burst: 1}, clock.Now).middleware(okHandler)
+
+	for range 100 {
+		require.Equal(t, http.StatusOK, doRateLimitedRequest(handler, "192.0.2.1:1234").Code)
+		clock.Advance(600 * time.Millisecond)
+	}
+}
+
+func TestRateLimitMiddleware_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Now()}
+	handler := newIPRateLimiter(RateLimitConfig{RequestsPerSecond: 0.25, Burst: 1}, clock.Now).middleware(okHandler)
+
+	require.Equal(t, http.StatusOK, doRateLimitedRequest(handler, "192.0.2.1:1234").Code)
+	rec := doRateLimitedRequest(handler, "192.0.2.1:5678")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code, "the client is identified by its IP, not its port")
+	assert.Equal(t, "4", rec.Header().Get("Retry-After"))
+
+	// Rejected requests do not delay the next token
+	clock.Advance(4 * time.Second)
+	assert.Equal(t, http.StatusOK, doRateLimitedRequest(handler, "192.0.2.1:1234").Code)
+}
+
+func TestRateLimitMiddleware_ForgetsIdleClients(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Now()}
+	limiter := newIPRateLimiter(RateLimitConfig{}, clock.Now)
+	handler := limiter.middleware(okHandler)
+
+	doRateLimitedRequest(handler, "192.0.2.1:1234")
+	doRateLimitedRequest(handler, "@")
+	assert.Len(t, limiter.clients, 2)
+
+	clock.Advance(rateLimiterIdleTimeout + time.Second)
+	doRateLimitedRequest(handler, "192.0.2.2:1234")
+	assert.Len(t, limiter.clients, 1)
+	assert.Contains(t, limiter.clients, "192.0.2.2")
+}
@@ -2,8 +2,11 @@
 package v1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
@@ -26,14 +29,44 @@ type versionResponse struct {
 //		@Description	Returns the current version of the server
 //		@Tags			version
 //		@Produce		json
+//		@Param			If-None-Match	header	string	false	"ETag of a previously returned version"
 //		@Success		200	{object}	versionResponse
+//		@Success		304	"Version unchanged"
 //		@Router			/api/v1beta/version [get]
-func getVersion(w http.ResponseWriter, _ *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+func getVersion(w http.ResponseWriter, r *http.Request) {
 	versionInfo := versions.GetVersionInfo()
+
+	// The version is constant for a build, so clients polling it can skip unchanged responses
+	etag := versionETag(versionInfo)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	err := json.NewEncoder(w).Encode(versionResponse{Version: versionInfo.Version})
 	if err != nil {
 		http.Error(w, "Failed to marshal version info", http.StatusInternalServerError)
 		return
 	}
 }
+
+// versionETag returns the strong ETag of the version info
+func versionETag(versionInfo versions.VersionInfo) string {
+	data, _ := json.Marshal(versionInfo)
+	hash := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(hash[:8]) + `"`
+}
+
+// etagMatches reports whether the If-None-Match header matches the ETag, using the weak
+// comparison required for If-None-Match by RFC 9110
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
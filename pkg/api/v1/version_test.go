@@ -12,7 +12,7 @@ import (
 func TestGetVersion(t *testing.T) {
 	t.Parallel()
 	resp := httptest.NewRecorder()
-	getVersion(resp, nil)
+	getVersion(resp, httptest.NewRequest(http.MethodGet, "/", nil))
 	require.Equal(t, http.StatusOK, resp.Code)
 	var version versionResponse
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&version))
@@ -22,7 +22,37 @@ func TestGetVersion(t *testing.T) {
 func TestGetVersionContentType(t *testing.T) {
 	t.Parallel()
 	resp := httptest.NewRecorder()
-	getVersion(resp, nil)
+	getVersion(resp, httptest.NewRequest(http.MethodGet, "/", nil))
 	require.Equal(t, http.StatusOK, resp.Code)
 	require.Equal(t, "application/json", resp.Header().Get("Content-Type"))
 }
+
+func TestGetVersionETag(t *testing.T) {
+	t.Parallel()
+	resp := httptest.NewRecorder()
+	getVersion(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	etag := resp.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// The ETag is stable across requests
+	resp = httptest.NewRecorder()
+	getVersion(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, etag, resp.Header().Get("ETag"))
+
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		resp = httptest.NewRecorder()
+		getVersion(resp, req)
+		require.Equal(t, http.StatusNotModified, resp.Code, "If-None-Match: %s", ifNoneMatch)
+		require.Equal(t, etag, resp.Header().Get("ETag"))
+		require.Zero(t, resp.Body.Len())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", `"other"`)
+	resp = httptest.NewRecorder()
+	getVersion(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+}
@@ -632,19 +632,25 @@ func (s *WorkloadRoutes) getWorkloadStatus(w http.ResponseWriter, r *http.Reques
 // exportWorkload
 //
 //	@Summary		Export workload configuration
-//	@Description	Export a workload's run configuration as JSON
+//	@Description	Export a workload's run configuration as JSON, or the effective run configuration
+//	@Description	recorded when the workload was last started, with the secret values masked
 //	@Tags			workloads
 //	@Produce		json
-//	@Param			name	path		string	true	"Workload name"
-//	@Success		200		{object}	runner.RunConfig
-//	@Failure		404		{string}	string	"Not Found"
+//	@Param			name		path		string	true	"Workload name"
+//	@Param			effective	query		bool	false	"Export the effective run configuration"
+//	@Success		200			{object}	runner.RunConfig
+//	@Failure		404			{string}	string	"Not Found"
 //	@Router			/api/v1beta/workloads/{name}/export [get]
 func (*WorkloadRoutes) exportWorkload(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	name := chi.URLParam(r, "name")
 
-	// Load the saved run configuration
-	runConfig, err := runner.LoadState(ctx, name)
+	// Load the saved run configuration, or the effective one recorded by the runner
+	loadState := runner.LoadState
+	if r.URL.Query().Get("effective") == "true" {
+		loadState = runner.LoadEffectiveState
+	}
+	runConfig, err := loadState(ctx, name)
 	if err != nil {
 		if thverrors.IsRunConfigNotFound(err) {
 			http.Error(w, "Workload configuration not found", http.StatusNotFound)
@@ -8,11 +8,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/adrg/xdg"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/sync/errgroup"
 
+	authoauth "github.com/stacklok/toolhive/pkg/auth/oauth"
 	"github.com/stacklok/toolhive/pkg/config"
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	runtimemocks "github.com/stacklok/toolhive/pkg/container/runtime/mocks"
@@ -450,3 +453,42 @@ func makeMockRetriever(
 		return returnedImage, returnedServerMetadata, returnedError
 	}
 }
+
+func TestExportWorkload_Effective(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	logger.Initialize()
+	t.Cleanup(xdg.Reload)
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("TOOLHIVE_RUNTIME", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	xdg.Reload()
+
+	runConfig := &runner.RunConfig{
+		Name:     "github",
+		BaseName: "github",
+		Image:    "ghcr.io/github/github-mcp-server",
+		EnvVars:  map[string]string{"GITHUB_TOKEN": "ghp_secret", "LOG_LEVEL": "debug"},
+		Secrets:  []string{"github,target=GITHUB_TOKEN"},
+	}
+	require.NoError(t, runConfig.SaveEffectiveState(t.Context()))
+
+	router := chi.NewRouter()
+	router.Get("/{name}/export", (&WorkloadRoutes{}).exportWorkload)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/github/export?effective=true", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "ghp_secret")
+
+	exported, err := runner.ReadJSON(w.Body)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"GITHUB_TOKEN": authoauth.RedactedValue,
+		"LOG_LEVEL":    "debug",
+	}, exported.EnvVars)
+	assert.Equal(t, "ghcr.io/github/github-mcp-server", exported.Image)
+
+	// No effective configuration is recorded until the runner starts the workload
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/gitlab/export?effective=true", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
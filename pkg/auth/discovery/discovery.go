@@ -10,12 +10,17 @@ package discovery
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"slices"
 	"strings"
 	"time"
 
@@ -35,6 +40,7 @@ const (
 	MaxRetryAttempts         = 3
 	RetryBaseDelay           = 2 * time.Second
 	MaxResponseBodyDrain     = 1 * 1024 * 1024 // 1 MB - limit response body draining to prevent resource exhaustion
+	MaxMetadataResponseSize  = 1 * 1024 * 1024 // 1 MB - limit metadata responses to prevent resource exhaustion
 )
 
 // AuthInfo contains authentication information extracted from WWW-Authenticate header
@@ -44,6 +50,179 @@ type AuthInfo struct {
 	ResourceMetadata string
 	Error            string
 	ErrorDescription string
+
+	// Fields below are populated from RFC 9728 protected resource metadata via SetResourceMetadata,
+	// which DetectAuthenticationFromServer calls when the server advertises its metadata.
+	// AuthorizationServers lists every advertised server, AuthorizationServer is the effective one.
+	AuthorizationServers []string
+	AuthorizationServer  string
+	ScopesSupported      []string
+	JWKSURI              string
+}
+
+// authInfoJSON is the stable JSON representation of AuthInfo
+type authInfoJSON struct {
+	Type                 string   `json:"type"`
+	Realm                string   `json:"realm"`
+	ResourceMetadata     string   `json:"resourceMetadata"`
+	AuthorizationServer  string   `json:"authorizationServer"`
+	AuthorizationServers []string `json:"authorizationServers"`
+	Scopes               []string `json:"scopes"`
+	JWKSURI              string   `json:"jwksURI"`
+	Error                string   `json:"error,omitempty"`
+	ErrorDescription     string   `json:"errorDescription,omitempty"`
+}
+
+// MarshalJSON serializes the AuthInfo with stable field names so that detection results
+// can be consumed by other tools. Empty lists are serialized as [] rather than null.
+func (a AuthInfo) MarshalJSON() ([]byte, error) {
+	out := authInfoJSON{
+		Type:                 a.Type,
+		Realm:                a.Realm,
+		ResourceMetadata:     a.ResourceMetadata,
+		AuthorizationServer:  a.AuthorizationServer,
+		AuthorizationServers: a.AuthorizationServers,
+		Scopes:               a.ScopesSupported,
+		JWKSURI:              a.JWKSURI,
+		Error:                a.Error,
+		ErrorDescription:     a.ErrorDescription,
+	}
+	if out.AuthorizationServers == nil {
+		out.AuthorizationServers = []string{}
+	}
+	if out.Scopes == nil {
+		out.Scopes = []string{}
+	}
+	return json.Marshal(out)
+}
+
+// AuthServerSelectionPolicy decides which of the authorization servers advertised in the
+// RFC 9728 protected resource metadata is used.
+type AuthServerSelectionPolicy struct {
+	// TrustedIssuers restricts the selection to the listed issuers. The first advertised server
+	// matching a trusted issuer is chosen. If empty, the first advertised server is chosen.
+	TrustedIssuers []string
+}
+
+// Select returns the effective authorization server according to the policy.
+// A nil policy selects the first advertised server.
+func (p *AuthServerSelectionPolicy) Select(servers []string) (string, error) {
+	if len(servers) == 0 {
+		return "", fmt.Errorf("no authorization servers advertised")
+	}
+	if p == nil || len(p.TrustedIssuers) == 0 {
+		return servers[0], nil
+	}
+
+	for _, server := range servers {
+		for _, trusted := range p.TrustedIssuers {
+			if strings.TrimSuffix(server, "/") == strings.TrimSuffix(trusted, "/") {
+				return server, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("none of the advertised authorization servers %v is trusted", servers)
+}
+
+// SetResourceMetadata records the authorization servers, supported scopes and JWKS URI
+// advertised in the RFC 9728 protected resource metadata of the server, choosing the
+// effective authorization server according to the given policy.
+func (a *AuthInfo) SetResourceMetadata(metadata *auth.RFC9728AuthInfo, policy *AuthServerSelectionPolicy) error {
+	if metadata == nil {
+		return nil
+	}
+
+	a.AuthorizationServers = metadata.AuthorizationServers
+	a.ScopesSupported = metadata.ScopesSupported
+	a.JWKSURI = metadata.JWKSURI
+
+	if len(metadata.AuthorizationServers) == 0 {
+		return nil
+	}
+	server, err := policy.Select(metadata.AuthorizationServers)
+	if err != nil {
+		return err
+	}
+	a.AuthorizationServer = server
+	return nil
+}
+
+// ScopePolicy decides how the user-specified scopes are combined with the scopes
+// discovered in the RFC 9728 protected resource metadata.
+type ScopePolicy string
+
+const (
+	// ScopePolicyUnion requests the user-specified scopes followed by the discovered ones.
+	// It is the default policy.
+	ScopePolicyUnion ScopePolicy = "union"
+	// ScopePolicyIntersection requests the user-specified scopes which are also discovered.
+	ScopePolicyIntersection ScopePolicy = "intersection"
+	// ScopePolicyUserOnly requests the user-specified scopes, ignoring the discovered ones.
+	ScopePolicyUserOnly ScopePolicy = "user-only"
+)
+
+// ResolveScopes returns the scopes to request according to the policy. When only one of the
+// scope sets is known, it is used as is, except by the user-only policy which never requests
+// discovered scopes. The user-specified scopes keep their order and come first.
+func ResolveScopes(userScopes, discoveredScopes []string, policy ScopePolicy) ([]string, error) {
+	switch policy {
+	case "", ScopePolicyUnion:
+		merged := make([]string, 0, len(userScopes)+len(discoveredScopes))
+		for _, scope := range append(slices.Clone(userScopes), discoveredScopes...) {
+			if !slices.Contains(merged, scope) {
+				merged = append(merged, scope)
+			}
+		}
+		return merged, nil
+	case ScopePolicyIntersection:
+		if len(userScopes) == 0 {
+			return slices.Clone(discoveredScopes), nil
+		}
+		if len(discoveredScopes) == 0 {
+			return slices.Clone(userScopes), nil
+		}
+		var common []string
+		for _, scope := range userScopes {
+			if slices.Contains(discoveredScopes, scope) && !slices.Contains(common, scope) {
+				common = append(common, scope)
+			}
+		}
+		if len(common) == 0 {
+			return nil, fmt.Errorf("none of the scopes %v is supported by the server, which supports %v",
+				userScopes, discoveredScopes)
+		}
+		return common, nil
+	case ScopePolicyUserOnly:
+		return slices.Clone(userScopes), nil
+	default:
+		return nil, fmt.Errorf("unknown scope policy %q (valid policies: %s, %s, %s)",
+			policy, ScopePolicyUnion, ScopePolicyIntersection, ScopePolicyUserOnly)
+	}
+}
+
+// ToOAuthConfig builds an OAuthFlowConfig from the discovered authentication information.
+// The issuer is the discovered authorization server, falling back to the issuer derived from the realm,
+// and is used by PerformOAuthFlow when no issuer is passed to it. The scopes are the given scopes
+// followed by the discovered supported scopes, without duplicates, so that the explicit scopes come
+// first. The discovered JWKS URI is carried to validate the tokens of the flow.
+func (a *AuthInfo) ToOAuthConfig(clientID, clientSecret string, scopes []string) *OAuthFlowConfig {
+	issuer := a.AuthorizationServer
+	if issuer == "" && a.Realm != "" {
+		issuer = DeriveIssuerFromRealm(a.Realm)
+	}
+
+	// The union policy never fails
+	merged, _ := ResolveScopes(scopes, a.ScopesSupported, ScopePolicyUnion)
+
+	return &OAuthFlowConfig{
+		ClientID:        clientID,
+		ClientSecret:    clientSecret,
+		Issuer:          issuer,
+		JWKSURI:         a.JWKSURI,
+		Scopes:          merged,
+		ScopesSupported: slices.Clone(a.ScopesSupported),
+	}
 }
 
 // AuthServerInfo contains information about a validated authorization server
@@ -60,6 +239,18 @@ type Config struct {
 	TLSHandshakeTimeout   time.Duration
 	ResponseHeaderTimeout time.Duration
 	EnablePOSTDetection   bool // Whether to try POST requests for detection
+
+	// CACertFile is the path to a PEM encoded CA bundle used to verify the server's certificate
+	// in addition to the system roots. Useful for servers behind a private CA.
+	CACertFile string
+
+	// InsecureSkipTLSVerify disables verification of the server's certificate.
+	// WARNING: This is insecure and should only be used for local development.
+	InsecureSkipTLSVerify bool
+
+	// AuthServerSelectionPolicy chooses the effective authorization server among those advertised
+	// in the protected resource metadata. If nil, the first advertised server is chosen.
+	AuthServerSelectionPolicy *AuthServerSelectionPolicy
 }
 
 // DefaultDiscoveryConfig returns a default discovery configuration
@@ -72,7 +263,9 @@ func DefaultDiscoveryConfig() *Config {
 	}
 }
 
-// DetectAuthenticationFromServer attempts to detect authentication requirements from the target server
+// DetectAuthenticationFromServer attempts to detect authentication requirements from the target server.
+// When the server advertises its RFC 9728 protected resource metadata, the metadata is fetched and
+// attached to the returned AuthInfo, see AuthInfo.SetResourceMetadata.
 func DetectAuthenticationFromServer(ctx context.Context, targetURI string, config *Config) (*AuthInfo, error) {
 	if config == nil {
 		config = DefaultDiscoveryConfig()
@@ -83,14 +276,44 @@ func DetectAuthenticationFromServer(ctx context.Context, targetURI string, confi
 	defer cancel()
 
 	// Make a test request to the target server to see if it returns WWW-Authenticate
-	client := &http.Client{
-		Timeout: config.Timeout,
-		Transport: &http.Transport{
-			TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
-			ResponseHeaderTimeout: config.ResponseHeaderTimeout,
-		},
+	client, err := newDetectionClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	authInfo, err := detectAuthentication(detectCtx, client, targetURI, config)
+	if err != nil || authInfo == nil {
+		return authInfo, err
+	}
+
+	if err := attachResourceMetadata(detectCtx, client, authInfo, config.AuthServerSelectionPolicy); err != nil {
+		return nil, err
+	}
+	return authInfo, nil
+}
+
+// attachResourceMetadata fetches the protected resource metadata advertised by the server, if any,
+// and records it on authInfo. Failing to fetch the metadata is not an error, as the authentication
+// requirements were detected without it, but none of the advertised servers being trusted is.
+func attachResourceMetadata(
+	ctx context.Context, client *http.Client, authInfo *AuthInfo, policy *AuthServerSelectionPolicy,
+) error {
+	if authInfo.ResourceMetadata == "" {
+		return nil
+	}
+
+	metadata, err := fetchResourceMetadata(ctx, client, authInfo.ResourceMetadata)
+	if err != nil {
+		logger.Debugf("Failed to fetch resource metadata from %s: %v", authInfo.ResourceMetadata, err)
+		return nil
 	}
+	return authInfo.SetResourceMetadata(metadata, policy)
+}
 
+// detectAuthentication probes the target server for its authentication requirements
+func detectAuthentication(
+	detectCtx context.Context, client *http.Client, targetURI string, config *Config,
+) (*AuthInfo, error) {
 	// First try a GET request
 	authInfo, err := detectAuthWithRequest(detectCtx, client, targetURI, http.MethodGet, nil)
 	if err != nil {
@@ -131,6 +354,50 @@ func DetectAuthenticationFromServer(ctx context.Context, targetURI string, confi
 	return nil, nil // No authentication required
 }
 
+// newDetectionClient builds the HTTP client used to probe the target server
+func newDetectionClient(config *Config) (*http.Client, error) {
+	transport := &http.Transport{
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+	}
+
+	if config.CACertFile != "" {
+		caCert, err := os.ReadFile(config.CACertFile) // #nosec G304 - path is provided by the caller
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate bundle: %w", err)
+		}
+
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil {
+			caCertPool = x509.NewCertPool()
+		}
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate bundle")
+		}
+
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    caCertPool,
+		}
+	}
+
+	if config.InsecureSkipTLSVerify {
+		logger.Warnf("TLS certificate verification is disabled for authentication discovery; " +
+			"this is insecure and must only be used for local development")
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{
+				MinVersion: tls.VersionTLS12,
+			}
+		}
+		transport.TLSClientConfig.InsecureSkipVerify = true // #nosec G402 - explicitly requested for development
+	}
+
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+	}, nil
+}
+
 // detectAuthWithRequest makes a specific HTTP request and checks for authentication requirements
 func detectAuthWithRequest(
 	ctx context.Context,
@@ -193,6 +460,30 @@ func buildWellKnownURI(parsedURL *url.URL, endpointSpecific bool) string {
 	return baseURL.String()
 }
 
+// buildPathScopedWellKnownURIs constructs well-known URIs for OAuth Protected Resource metadata
+// located under the resource's path (e.g. /app/.well-known/oauth-protected-resource), as used by
+// servers that host the metadata alongside a path-prefixed resource. URIs are returned from the
+// most specific path to the least specific one, excluding the origin root.
+func buildPathScopedWellKnownURIs(parsedURL *url.URL) []string {
+	cleanPath := strings.Trim(path.Clean("/"+parsedURL.Path), "/")
+	if cleanPath == "" {
+		return nil
+	}
+
+	segments := strings.Split(cleanPath, "/")
+	uris := make([]string, 0, len(segments))
+	for i := len(segments); i > 0; i-- {
+		scopedURL := url.URL{
+			Scheme: parsedURL.Scheme,
+			Host:   parsedURL.Host,
+			Path:   "/" + strings.Join(segments[:i], "/") + auth.WellKnownOAuthResourcePath,
+		}
+		uris = append(uris, scopedURL.String())
+	}
+
+	return uris
+}
+
 // checkWellKnownURIExists returns true if a well-known URI is accessible and returns application/json
 // Per RFC 9728, protected resource metadata MUST be queried using HTTP GET and MUST return application/json
 func checkWellKnownURIExists(ctx context.Context, client *http.Client, uri string) bool {
@@ -241,12 +532,12 @@ func tryWellKnownDiscovery(ctx context.Context, client *http.Client, targetURI s
 	}
 
 	// Build well-known URIs to try (in priority order per MCP spec)
-	wellKnownURIs := []string{
-		// 1. Endpoint-specific: /.well-known/oauth-protected-resource/<path>
-		buildWellKnownURI(parsedURL, true),
-		// 2. Root-level: /.well-known/oauth-protected-resource
-		buildWellKnownURI(parsedURL, false),
-	}
+	// 1. Endpoint-specific: /.well-known/oauth-protected-resource/<path>
+	wellKnownURIs := []string{buildWellKnownURI(parsedURL, true)}
+	// 2. Path-scoped: /<path>/.well-known/oauth-protected-resource, most specific path first
+	wellKnownURIs = append(wellKnownURIs, buildPathScopedWellKnownURIs(parsedURL)...)
+	// 3. Root-level: /.well-known/oauth-protected-resource
+	wellKnownURIs = append(wellKnownURIs, buildWellKnownURI(parsedURL, false))
 
 	// Try each well-known URI in order
 	for _, wellKnownURI := range wellKnownURIs {
@@ -481,15 +772,23 @@ func DeriveIssuerFromRealm(realm string) string {
 type OAuthFlowConfig struct {
 	ClientID             string
 	ClientSecret         string
+	Issuer               string // Discovered issuer, used when none is passed to PerformOAuthFlow (optional)
+	JWKSURI              string // Discovered JWKS URI to validate the tokens of the flow against (optional)
 	AuthorizeURL         string // Manual OAuth endpoint (optional)
 	TokenURL             string // Manual OAuth endpoint (optional)
 	RegistrationEndpoint string // Manual registration endpoint (optional)
 	Scopes               []string
+	ScopesSupported      []string    // Scopes discovered in the RFC 9728 metadata (optional)
+	ScopePolicy          ScopePolicy // How Scopes and ScopesSupported are combined (optional, defaults to union)
 	CallbackPort         int
-	Timeout              time.Duration
+	CallbackHost         string        // Address the local callback server binds to (optional, defaults to all interfaces)
+	RedirectURI          string        // Redirect URI registered for the client (optional, derived from CallbackPort)
+	Timeout              time.Duration // Timeout for the user to complete the browser step
+	FlowTimeout          time.Duration // Timeout for the whole flow, including discovery and registration (optional)
 	SkipBrowser          bool
-	Resource             string // RFC 8707 resource indicator (optional)
-	OAuthParams          map[string]string
+	Resource             string            // RFC 8707 resource indicator (optional)
+	OAuthParams          map[string]string // Additional authorization request parameters, e.g. audience (optional)
+	TokenCache           oauth.TokenCache  // Cache of the tokens reused by later flows (optional)
 }
 
 // OAuthFlowResult contains the result of an OAuth flow
@@ -502,13 +801,79 @@ func shouldDynamicallyRegisterClient(config *OAuthFlowConfig) bool {
 	return config.ClientID == "" && config.ClientSecret == ""
 }
 
-// PerformOAuthFlow performs an OAuth authentication flow with the given configuration
-func PerformOAuthFlow(ctx context.Context, issuer string, config *OAuthFlowConfig) (*OAuthFlowResult, error) {
-	logger.Infof("Starting OAuth authentication flow for issuer: %s", issuer)
+// redirectURI returns the redirect URI of the flow, which defaults to the local callback server
+func (c *OAuthFlowConfig) redirectURI() string {
+	if c.RedirectURI != "" {
+		return c.RedirectURI
+	}
+	return oauth.CallbackRedirectURL(c.CallbackPort)
+}
+
+// ErrOAuthFlowTimeout is returned when an OAuth flow does not complete within its FlowTimeout
+var ErrOAuthFlowTimeout = errors.New("OAuth flow timed out")
 
+// PerformOAuthFlow performs an OAuth authentication flow with the given configuration.
+// If FlowTimeout is set, the whole flow is bounded by it and ErrOAuthFlowTimeout is returned
+// when it expires, e.g. because the user never completed the browser step.
+func PerformOAuthFlow(ctx context.Context, issuer string, config *OAuthFlowConfig) (*OAuthFlowResult, error) {
 	if config == nil {
 		return nil, fmt.Errorf("OAuth flow config cannot be nil")
 	}
+	if issuer == "" {
+		issuer = config.Issuer
+	}
+	logger.Infof("Starting OAuth authentication flow for issuer: %s", issuer)
+
+	if config.FlowTimeout <= 0 {
+		return performOAuthFlow(ctx, issuer, config)
+	}
+
+	flowCtx, cancel := context.WithTimeoutCause(ctx, config.FlowTimeout, ErrOAuthFlowTimeout)
+	defer cancel()
+
+	result, err := performOAuthFlow(flowCtx, issuer, config)
+	if err != nil && errors.Is(context.Cause(flowCtx), ErrOAuthFlowTimeout) {
+		return nil, fmt.Errorf("%w after %v", ErrOAuthFlowTimeout, config.FlowTimeout)
+	}
+	return result, err
+}
+
+// performOAuthFlow performs the steps of an OAuth authentication flow
+func performOAuthFlow(ctx context.Context, issuer string, config *OAuthFlowConfig) (*OAuthFlowResult, error) {
+	if config.RedirectURI != "" {
+		if err := oauth.ValidateRedirectURL(config.RedirectURI); err != nil {
+			return nil, err
+		}
+	}
+	if err := oauth.ValidateOAuthParams(config.OAuthParams); err != nil {
+		return nil, err
+	}
+
+	scopes, err := ResolveScopes(config.Scopes, config.ScopesSupported, config.ScopePolicy)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(scopes, config.Scopes) {
+		logger.Debugw("Resolved OAuth scopes",
+			"policy", config.ScopePolicy,
+			"scopes", config.Scopes,
+			"scopes_supported", config.ScopesSupported,
+			"resolved", scopes)
+	}
+	// The flow works on a copy of the config, so that the resolved scopes neither replace the
+	// caller's scopes nor share their backing array
+	flowConfig := *config
+	flowConfig.Scopes = scopes
+	config = &flowConfig
+
+	// The cache key is computed before dynamic registration replaces the client credentials,
+	// so that a client registered by a previous flow can be reused
+	cacheKey := oauth.TokenCacheKey(issuer, config.ClientID, config.Scopes)
+	if config.TokenCache != nil {
+		if result := cachedOAuthFlowResult(ctx, config, cacheKey); result != nil {
+			return result, nil
+		}
+	}
 
 	// Resolve port availability BEFORE dynamic registration
 	// This ensures we register the OAuth client with the same port we'll actually use
@@ -548,9 +913,84 @@ func PerformOAuthFlow(ctx context.Context, issuer string, config *OAuthFlowConfi
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OAuth config: %w", err)
 	}
+	logger.Debugw("OAuth endpoints resolved",
+		"issuer", issuer,
+		"authorization_endpoint", oauthConfig.AuthURL,
+		"token_endpoint", oauthConfig.TokenURL,
+		"client_id", oauthConfig.ClientID,
+		"client_secret", oauth.Redact(oauthConfig.ClientSecret))
+	// The flow derives the default redirect URL from the port it actually listens on
+	oauthConfig.CallbackHost = config.CallbackHost
+	oauthConfig.RedirectURL = config.RedirectURI
+	// OIDC discovery does not take additional parameters, so they are set for both kinds of endpoints
+	oauthConfig.OAuthParams = config.OAuthParams
 
 	// Create and execute OAuth flow
-	return newOAuthFlow(ctx, oauthConfig, config)
+	result, err := newOAuthFlow(ctx, oauthConfig, config)
+	if err != nil || config.TokenCache == nil {
+		return result, err
+	}
+
+	result.TokenSource = oauth.NewCachingTokenSource(result.TokenSource, config.TokenCache, cacheKey, oauth.CachedToken{
+		ClientID:     oauthConfig.ClientID,
+		ClientSecret: oauthConfig.ClientSecret,
+		TokenURL:     oauthConfig.TokenURL,
+	})
+	// Store the token obtained by the flow right away
+	if _, err := result.TokenSource.Token(); err != nil {
+		logger.Warnf("Failed to cache OAuth token: %v", err)
+	}
+	return result, nil
+}
+
+// cachedOAuthFlowResult returns a result reusing the cached token, which is refreshed with ctx if it
+// has expired or is about to. It returns nil if there is no usable cached token.
+func cachedOAuthFlowResult(ctx context.Context, config *OAuthFlowConfig, cacheKey string) *OAuthFlowResult {
+	cached, err := config.TokenCache.Get(ctx, cacheKey)
+	if err != nil {
+		logger.Warnf("Failed to read OAuth token cache: %v", err)
+		return nil
+	}
+	if cached == nil || cached.Token == nil {
+		return nil
+	}
+
+	oauthConfig := &oauth.Config{
+		ClientID:     cached.ClientID,
+		ClientSecret: cached.ClientSecret,
+		TokenURL:     cached.TokenURL,
+		Scopes:       config.Scopes,
+		Resource:     config.Resource,
+	}
+	oauth2Config := &oauth2.Config{
+		ClientID:     cached.ClientID,
+		ClientSecret: cached.ClientSecret,
+		Scopes:       config.Scopes,
+		Endpoint:     oauth2.Endpoint{TokenURL: cached.TokenURL},
+	}
+
+	// Refresh the token now with the caller's context, so that the browser is only opened when
+	// it cannot be refreshed
+	token, err := oauth2Config.TokenSource(ctx, cached.Token).Token()
+	if err != nil {
+		logger.Infof("Cached OAuth token cannot be used, starting a new OAuth flow: %v", err)
+		return nil
+	}
+
+	// Like the token source of an OAuth flow, the returned one outlives the flow, so later refreshes
+	// are not bound to the caller's context
+	base := oauth2.ReuseTokenSource(token, oauth2Config.TokenSource(context.Background(), token))
+	source := oauth.NewCachingTokenSource(base, config.TokenCache, cacheKey, *cached)
+	// Store the refreshed token right away
+	if _, err := source.Token(); err != nil {
+		logger.Warnf("Failed to cache OAuth token: %v", err)
+	}
+
+	logger.Info("Reusing cached OAuth token")
+	return &OAuthFlowResult{
+		TokenSource: source,
+		Config:      oauthConfig,
+	}
 }
 
 // handleDynamicRegistration handles the dynamic client registration process
@@ -568,6 +1008,10 @@ func handleDynamicRegistration(ctx context.Context, issuer string, config *OAuth
 	// Update config with registered client credentials
 	config.ClientID = registrationResponse.ClientID
 	config.ClientSecret = registrationResponse.ClientSecret
+	logger.Debugw("OAuth client dynamically registered",
+		"registration_endpoint", discoveredDoc.RegistrationEndpoint,
+		"client_id", registrationResponse.ClientID,
+		"client_secret", oauth.Redact(registrationResponse.ClientSecret))
 
 	if discoveredDoc.RegistrationEndpoint != "" {
 		config.AuthorizeURL = discoveredDoc.AuthorizationEndpoint
@@ -591,6 +1035,7 @@ func getDiscoveryDocument(ctx context.Context, issuer string, config *OAuthFlowC
 	}
 
 	// Fall back to discovering endpoints
+	logger.Debugw("Discovering OAuth endpoints for dynamic registration", "issuer", issuer)
 	return oauth.DiscoverOIDCEndpoints(ctx, issuer)
 }
 
@@ -686,6 +1131,8 @@ func registerDynamicClient(
 
 	// Use default client name if not provided
 	registrationRequest := oauth.NewDynamicClientRegistrationRequest(config.Scopes, config.CallbackPort)
+	redirectURI := config.redirectURI()
+	registrationRequest.RedirectURIs = []string{redirectURI}
 
 	// Perform dynamic client registration
 	registrationResponse, err := oauth.RegisterClientDynamically(ctx, discoveredDoc.RegistrationEndpoint, registrationRequest)
@@ -693,11 +1140,40 @@ func registerDynamicClient(
 		return nil, fmt.Errorf("dynamic client registration failed: %w", err)
 	}
 
+	// The provider may register other redirect URIs than requested, in which case the callback would be rejected
+	if len(registrationResponse.RedirectURIs) > 0 && !slices.Contains(registrationResponse.RedirectURIs, redirectURI) {
+		return nil, fmt.Errorf("registered client redirect URIs %v do not include %s",
+			registrationResponse.RedirectURIs, redirectURI)
+	}
+
 	return registrationResponse, nil
 }
 
 // FetchResourceMetadata as specified in RFC 9728
 func FetchResourceMetadata(ctx context.Context, metadataURL string) (*auth.RFC9728AuthInfo, error) {
+	return FetchResourceMetadataWithConfig(ctx, metadataURL, nil)
+}
+
+// FetchResourceMetadataWithConfig fetches the RFC 9728 protected resource metadata with an HTTP client
+// built from the discovery config, so that the CACertFile and InsecureSkipTLSVerify options used to
+// detect the authentication requirements also apply to the metadata. A nil config uses the defaults.
+func FetchResourceMetadataWithConfig(
+	ctx context.Context, metadataURL string, config *Config,
+) (*auth.RFC9728AuthInfo, error) {
+	if config == nil {
+		config = DefaultDiscoveryConfig()
+		config.Timeout = DefaultHTTPTimeout
+	}
+
+	client, err := newDetectionClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return fetchResourceMetadata(ctx, client, metadataURL)
+}
+
+// fetchResourceMetadata fetches the RFC 9728 protected resource metadata with the given HTTP client
+func fetchResourceMetadata(ctx context.Context, client *http.Client, metadataURL string) (*auth.RFC9728AuthInfo, error) {
 	if metadataURL == "" {
 		return nil, fmt.Errorf("metadata URL is empty")
 	}
@@ -713,15 +1189,6 @@ func FetchResourceMetadata(ctx context.Context, metadataURL string) (*auth.RFC97
 		return nil, fmt.Errorf("metadata URL must use HTTPS: %s", metadataURL)
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: DefaultHTTPTimeout,
-		Transport: &http.Transport{
-			TLSHandshakeTimeout:   5 * time.Second,
-			ResponseHeaderTimeout: 5 * time.Second,
-		},
-	}
-
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -745,10 +1212,15 @@ func FetchResourceMetadata(ctx context.Context, metadataURL string) (*auth.RFC97
 		return nil, fmt.Errorf("unexpected content type: %s", contentType)
 	}
 
+	// Read the metadata, refusing to consume more than MaxMetadataResponseSize bytes
+	body, err := readLimitedBody(resp, MaxMetadataResponseSize)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse the metadata
-	const maxResponseSize = 1024 * 1024 // 1MB limit
 	var metadata auth.RFC9728AuthInfo
-	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&metadata); err != nil {
+	if err := json.Unmarshal(body, &metadata); err != nil {
 		return nil, fmt.Errorf("failed to parse metadata: %w", err)
 	}
 
@@ -761,6 +1233,24 @@ func FetchResourceMetadata(ctx context.Context, metadataURL string) (*auth.RFC97
 	return &metadata, nil
 }
 
+// readLimitedBody reads the response body, returning an error if it is larger than limit bytes
+func readLimitedBody(resp *http.Response, limit int64) ([]byte, error) {
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("metadata response too large: %d bytes exceeds limit of %d bytes", resp.ContentLength, limit)
+	}
+
+	// Read one byte past the limit to detect bodies without a (correct) Content-Length
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("metadata response too large: exceeds limit of %d bytes", limit)
+	}
+
+	return body, nil
+}
+
 // ValidateAndDiscoverAuthServer attempts to validate if a URL is an authorization server
 // and discover its actual issuer by fetching its metadata.
 // This handles the case where the URL used to fetch metadata differs from the actual issuer
@@ -3,20 +3,31 @@ package discovery
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"golang.org/x/oauth2"
 
 	"github.com/stacklok/toolhive/pkg/auth/oauth"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/networking"
+	"github.com/stacklok/toolhive/pkg/secrets"
 )
 
 const wellKnownOAuthPath = "/.well-known/oauth-protected-resource"
@@ -725,6 +736,106 @@ func TestPerformOAuthFlow_PortFallbackBehavior(t *testing.T) {
 	})
 }
 
+func TestPerformOAuthFlow_KeepsCallerScopes(t *testing.T) {
+	t.Parallel()
+
+	// Make the flow fail on the callback port, after the scopes are resolved
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// Spare capacity would let an in-place append write into the caller's backing array
+	scopes := make([]string, 1, 4)
+	scopes[0] = "openid"
+	config := &OAuthFlowConfig{
+		ClientID:        "test-client",
+		ClientSecret:    "test-secret",
+		CallbackPort:    listener.Addr().(*net.TCPAddr).Port,
+		Scopes:          scopes,
+		ScopesSupported: []string{"read", "write"},
+		ScopePolicy:     ScopePolicyUnion,
+	}
+
+	_, err = PerformOAuthFlow(t.Context(), "https://example.com", config)
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"openid"}, config.Scopes)
+	assert.Equal(t, []string{"openid", "", "", ""}, scopes[:cap(scopes)])
+}
+
+func TestPerformOAuthFlow_FlowTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("callback never arrives", func(t *testing.T) {
+		t.Parallel()
+
+		// Reserve a free callback port for the pre-registered client
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		callbackPort := listener.Addr().(*net.TCPAddr).Port
+		require.NoError(t, listener.Close())
+
+		config := &OAuthFlowConfig{
+			ClientID:     "test-client",
+			ClientSecret: "test-secret",
+			AuthorizeURL: "https://example.com/auth",
+			TokenURL:     "https://example.com/token",
+			Scopes:       []string{"openid"},
+			CallbackPort: callbackPort,
+			SkipBrowser:  true,
+			FlowTimeout:  200 * time.Millisecond,
+		}
+
+		start := time.Now()
+		_, err = PerformOAuthFlow(t.Context(), "https://example.com", config)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrOAuthFlowTimeout)
+		assert.Less(t, time.Since(start), DefaultOAuthTimeout, "the flow timeout should apply before the browser timeout")
+	})
+
+	t.Run("registration never completes", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		config := &OAuthFlowConfig{
+			AuthorizeURL:         server.URL + "/auth",
+			TokenURL:             server.URL + "/token",
+			RegistrationEndpoint: server.URL + "/register",
+			Scopes:               []string{"openid"},
+			SkipBrowser:          true,
+			FlowTimeout:          200 * time.Millisecond,
+		}
+
+		_, err := PerformOAuthFlow(t.Context(), server.URL, config)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrOAuthFlowTimeout)
+	})
+
+	t.Run("parent cancellation is not a flow timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		config := &OAuthFlowConfig{
+			AuthorizeURL:         "https://example.com/auth",
+			TokenURL:             "https://example.com/token",
+			RegistrationEndpoint: "https://example.com/register",
+			Scopes:               []string{"openid"},
+			SkipBrowser:          true,
+			FlowTimeout:          time.Minute,
+		}
+
+		_, err := PerformOAuthFlow(ctx, "https://example.com", config)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrOAuthFlowTimeout)
+	})
+}
+
 // TestPerformOAuthFlow_PortCheckingOnly tests just the port checking logic
 // without going through the full OAuth flow
 func TestPerformOAuthFlow_PortCheckingOnly(t *testing.T) {
@@ -830,6 +941,151 @@ func TestBuildWellKnownURI(t *testing.T) {
 	}
 }
 
+func TestBuildPathScopedWellKnownURIs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		inputURL string
+		expected []string
+	}{
+		{
+			name:     "no path",
+			inputURL: "https://mcp.example.com",
+			expected: nil,
+		},
+		{
+			name:     "root path",
+			inputURL: "https://mcp.example.com/",
+			expected: nil,
+		},
+		{
+			name:     "single segment",
+			inputURL: "https://mcp.example.com/app",
+			expected: []string{"https://mcp.example.com/app/.well-known/oauth-protected-resource"},
+		},
+		{
+			name:     "nested path most specific first",
+			inputURL: "https://mcp.example.com/app/mcp/",
+			expected: []string{
+				"https://mcp.example.com/app/mcp/.well-known/oauth-protected-resource",
+				"https://mcp.example.com/app/.well-known/oauth-protected-resource",
+			},
+		},
+		{
+			name:     "query is dropped",
+			inputURL: "https://mcp.example.com/app?session=1",
+			expected: []string{"https://mcp.example.com/app/.well-known/oauth-protected-resource"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			parsedURL, err := url.Parse(tt.inputURL)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buildPathScopedWellKnownURIs(parsedURL))
+		})
+	}
+}
+
+func TestDetectAuthenticationFromServer_PathScopedWellKnown(t *testing.T) {
+	t.Parallel()
+
+	// Server that only serves the metadata under the resource's path prefix
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app"+wellKnownOAuthPath {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"resource":"https://example.com/app"}`))
+			return
+		}
+		if strings.Contains(r.URL.Path, ".well-known") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result, err := DetectAuthenticationFromServer(context.Background(), server.URL+"/app/mcp", nil)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "OAuth", result.Type)
+	assert.Equal(t, server.URL+"/app"+wellKnownOAuthPath, result.ResourceMetadata)
+}
+
+func TestDetectAuthenticationFromServer_ResourceMetadata(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc(wellKnownOAuthPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"resource": "https://mcp.example.com",
+			"authorization_servers": ["https://auth.example.com", "https://trusted.example.com"],
+			"scopes_supported": ["read", "write"],
+			"jwks_uri": "https://auth.example.com/jwks"
+		}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer resource_metadata="%s%s"`, server.URL, wellKnownOAuthPath))
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	tests := []struct {
+		name           string
+		policy         *AuthServerSelectionPolicy
+		expectedServer string
+		wantErr        bool
+	}{
+		{
+			name:           "first advertised server without a policy",
+			expectedServer: "https://auth.example.com",
+		},
+		{
+			name:           "trusted server with a policy",
+			policy:         &AuthServerSelectionPolicy{TrustedIssuers: []string{"https://trusted.example.com"}},
+			expectedServer: "https://trusted.example.com",
+		},
+		{
+			name:    "no trusted server",
+			policy:  &AuthServerSelectionPolicy{TrustedIssuers: []string{"https://other.example.com"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := DefaultDiscoveryConfig()
+			config.AuthServerSelectionPolicy = tt.policy
+
+			result, err := DetectAuthenticationFromServer(context.Background(), server.URL, config)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			assert.Equal(t, []string{"https://auth.example.com", "https://trusted.example.com"}, result.AuthorizationServers)
+			assert.Equal(t, tt.expectedServer, result.AuthorizationServer)
+			assert.Equal(t, []string{"read", "write"}, result.ScopesSupported)
+			assert.Equal(t, "https://auth.example.com/jwks", result.JWKSURI)
+
+			flowConfig := result.ToOAuthConfig("client-id", "", []string{"openid"})
+			assert.Equal(t, tt.expectedServer, flowConfig.Issuer)
+			assert.Equal(t, []string{"openid", "read", "write"}, flowConfig.Scopes)
+			assert.Equal(t, []string{"read", "write"}, flowConfig.ScopesSupported)
+			assert.Equal(t, "https://auth.example.com/jwks", flowConfig.JWKSURI)
+		})
+	}
+}
+
 func TestCheckWellKnownURIExists(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1162,6 +1418,188 @@ func TestDetectAuthenticationFromServer_ErrorPaths(t *testing.T) {
 	})
 }
 
+// newBearerTLSServer starts a TLS server with a self-signed certificate that requires Bearer auth
+func newBearerTLSServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="https://example.com"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDetectAuthenticationFromServer_CustomCA(t *testing.T) {
+	t.Parallel()
+
+	server := newBearerTLSServer(t)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0600))
+
+	invalidCAFile := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidCAFile, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name        string
+		caCertFile  string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:        "fails without the CA",
+			wantErr:     true,
+			errContains: "failed to make GET request",
+		},
+		{
+			name:       "succeeds with the CA",
+			caCertFile: caFile,
+		},
+		{
+			name:        "missing CA file",
+			caCertFile:  filepath.Join(t.TempDir(), "missing.pem"),
+			wantErr:     true,
+			errContains: "failed to read CA certificate bundle",
+		},
+		{
+			name:        "invalid CA file",
+			caCertFile:  invalidCAFile,
+			wantErr:     true,
+			errContains: "failed to parse CA certificate bundle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := DefaultDiscoveryConfig()
+			config.CACertFile = tt.caCertFile
+
+			result, err := DetectAuthenticationFromServer(context.Background(), server.URL, config)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			assert.Equal(t, "OAuth", result.Type)
+			assert.Equal(t, "https://example.com", result.Realm)
+		})
+	}
+}
+
+//nolint:paralleltest // This test replaces the global logger to capture warnings
+func TestDetectAuthenticationFromServer_InsecureSkipTLSVerify(t *testing.T) {
+	server := newBearerTLSServer(t)
+
+	tests := []struct {
+		name                  string
+		insecureSkipTLSVerify bool
+		wantErr               bool
+		wantWarning           bool
+	}{
+		{
+			name:    "fails against self-signed server by default",
+			wantErr: true,
+		},
+		{
+			name:                  "succeeds and warns when verification is skipped",
+			insecureSkipTLSVerify: true,
+			wantWarning:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, observedLogs := observer.New(zap.WarnLevel)
+			originalLogger := zap.L()
+			zap.ReplaceGlobals(zap.New(core))
+			t.Cleanup(func() {
+				zap.ReplaceGlobals(originalLogger)
+			})
+
+			config := DefaultDiscoveryConfig()
+			config.InsecureSkipTLSVerify = tt.insecureSkipTLSVerify
+
+			result, err := DetectAuthenticationFromServer(context.Background(), server.URL, config)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, "OAuth", result.Type)
+			}
+
+			warnings := observedLogs.FilterMessageSnippet("TLS certificate verification is disabled").All()
+			if tt.wantWarning {
+				assert.NotEmpty(t, warnings)
+			} else {
+				assert.Empty(t, warnings)
+			}
+		})
+	}
+}
+
+func TestFetchResourceMetadataWithConfig_TLS(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"resource": "https://mcp.example.com", "authorization_servers": ["https://auth.example.com"]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0600))
+
+	tests := []struct {
+		name                  string
+		caCertFile            string
+		insecureSkipTLSVerify bool
+		wantErr               bool
+	}{
+		{
+			name:    "fails without the CA",
+			wantErr: true,
+		},
+		{
+			name:       "succeeds with the CA",
+			caCertFile: caFile,
+		},
+		{
+			name:                  "succeeds when verification is skipped",
+			insecureSkipTLSVerify: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := DefaultDiscoveryConfig()
+			config.CACertFile = tt.caCertFile
+			config.InsecureSkipTLSVerify = tt.insecureSkipTLSVerify
+
+			metadata, err := FetchResourceMetadataWithConfig(context.Background(), server.URL, config)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "failed to fetch metadata")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "https://mcp.example.com", metadata.Resource)
+			assert.Equal(t, []string{"https://auth.example.com"}, metadata.AuthorizationServers)
+		})
+	}
+}
+
 // TestCheckWellKnownURIExists_ErrorPaths tests error handling in checkWellKnownURIExists
 func TestCheckWellKnownURIExists_ErrorPaths(t *testing.T) {
 	t.Parallel()
@@ -1278,6 +1716,213 @@ func TestTryWellKnownDiscovery_ErrorPaths(t *testing.T) {
 // TestRegisterDynamicClient_MissingRegistrationEndpoint tests that registerDynamicClient
 // returns a clear error message when the OIDC discovery document doesn't include
 // a registration_endpoint (provider doesn't support DCR).
+func TestRegisterDynamicClient_RedirectURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		config             *OAuthFlowConfig
+		registeredURIs     []string
+		expectedRequestURI string
+		expectedError      string
+	}{
+		{
+			name:               "redirect URI derived from the callback port",
+			config:             &OAuthFlowConfig{CallbackPort: 8765},
+			expectedRequestURI: "http://localhost:8765/callback",
+		},
+		{
+			name:               "configured redirect URI",
+			config:             &OAuthFlowConfig{CallbackPort: 8765, RedirectURI: "http://127.0.0.1:9000/callback"},
+			expectedRequestURI: "http://127.0.0.1:9000/callback",
+		},
+		{
+			name:               "registered redirect URIs include the redirect URI",
+			config:             &OAuthFlowConfig{CallbackPort: 8765},
+			registeredURIs:     []string{"http://localhost:8765/callback"},
+			expectedRequestURI: "http://localhost:8765/callback",
+		},
+		{
+			name:               "registered redirect URIs do not include the redirect URI",
+			config:             &OAuthFlowConfig{CallbackPort: 8765},
+			registeredURIs:     []string{"http://localhost:8666/callback"},
+			expectedRequestURI: "http://localhost:8765/callback",
+			expectedError:      "do not include http://localhost:8765/callback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requestedURIs []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request oauth.DynamicClientRegistrationRequest
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				requestedURIs = request.RedirectURIs
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				_ = json.NewEncoder(w).Encode(oauth.DynamicClientRegistrationResponse{
+					ClientID:     "dynamic-client-id",
+					RedirectURIs: tt.registeredURIs,
+				})
+			}))
+			defer server.Close()
+
+			discoveredDoc := &oauth.OIDCDiscoveryDocument{
+				Issuer:                server.URL,
+				AuthorizationEndpoint: server.URL + "/authorize",
+				TokenEndpoint:         server.URL + "/token",
+				RegistrationEndpoint:  server.URL + "/register",
+			}
+
+			result, err := registerDynamicClient(t.Context(), tt.config, discoveredDoc)
+			assert.Equal(t, []string{tt.expectedRequestURI}, requestedURIs)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "dynamic-client-id", result.ClientID)
+		})
+	}
+}
+
+func TestPerformOAuthFlow_InvalidRedirectURI(t *testing.T) {
+	t.Parallel()
+
+	config := &OAuthFlowConfig{
+		ClientID:     "test-client",
+		AuthorizeURL: "https://example.com/auth",
+		TokenURL:     "https://example.com/token",
+		RedirectURI:  "http://localhost:9000/oauth/callback",
+	}
+
+	_, err := PerformOAuthFlow(t.Context(), "https://example.com", config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redirect URL path must be /callback")
+}
+
+func TestPerformOAuthFlow_ReservedOAuthParams(t *testing.T) {
+	t.Parallel()
+
+	config := &OAuthFlowConfig{
+		ClientID:     "test-client",
+		AuthorizeURL: "https://example.com/auth",
+		TokenURL:     "https://example.com/token",
+		OAuthParams:  map[string]string{"redirect_uri": "https://attacker.example.com/callback"},
+	}
+
+	_, err := PerformOAuthFlow(t.Context(), "https://example.com", config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OAuth parameter redirect_uri is reserved")
+}
+
+// newTestTokenCache returns a token cache storing the tokens in an encrypted secrets file
+func newTestTokenCache(t *testing.T) oauth.TokenCache {
+	t.Helper()
+	key := sha256.Sum256([]byte("test-password"))
+	provider, err := secrets.NewEncryptedManager(filepath.Join(t.TempDir(), "secrets_encrypted"), key[:])
+	require.NoError(t, err)
+	cache, err := oauth.NewSecretsTokenCache(provider)
+	require.NoError(t, err)
+	return cache
+}
+
+func TestPerformOAuthFlow_TokenCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid cached token is reused without authentication", func(t *testing.T) {
+		t.Parallel()
+
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		config := &OAuthFlowConfig{
+			ClientID:     "test-client",
+			AuthorizeURL: server.URL + "/auth",
+			TokenURL:     server.URL + "/token",
+			Scopes:       []string{"openid", "profile"},
+			SkipBrowser:  true,
+			TokenCache:   newTestTokenCache(t),
+		}
+		// The cache key does not depend on the order of the scopes
+		cacheKey := oauth.TokenCacheKey(server.URL, "test-client", []string{"profile", "openid"})
+		require.NoError(t, config.TokenCache.Set(t.Context(), cacheKey, &oauth.CachedToken{
+			ClientID: "test-client",
+			TokenURL: server.URL + "/token",
+			Token:    &oauth2.Token{AccessToken: "cached-token", Expiry: time.Now().Add(time.Hour)},
+		}))
+
+		result, err := PerformOAuthFlow(t.Context(), server.URL, config)
+		require.NoError(t, err)
+		token, err := result.TokenSource.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "cached-token", token.AccessToken)
+		assert.Zero(t, requests.Load(), "no authentication request should be made")
+	})
+
+	t.Run("expired cached token is refreshed", func(t *testing.T) {
+		t.Parallel()
+
+		var refreshes atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/token" || r.FormValue("grant_type") != "refresh_token" ||
+				r.FormValue("refresh_token") != "refresh-token" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			refreshes.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"refreshed-token","token_type":"Bearer","expires_in":3600}`))
+		}))
+		defer server.Close()
+
+		tokenCache := newTestTokenCache(t)
+		config := &OAuthFlowConfig{
+			ClientID:     "test-client",
+			AuthorizeURL: server.URL + "/auth",
+			TokenURL:     server.URL + "/token",
+			Scopes:       []string{"openid"},
+			SkipBrowser:  true,
+			TokenCache:   tokenCache,
+		}
+		cacheKey := oauth.TokenCacheKey(server.URL, "test-client", []string{"openid"})
+		require.NoError(t, tokenCache.Set(t.Context(), cacheKey, &oauth.CachedToken{
+			ClientID: "test-client",
+			TokenURL: server.URL + "/token",
+			Token: &oauth2.Token{
+				AccessToken:  "expired-token",
+				RefreshToken: "refresh-token",
+				Expiry:       time.Now().Add(-time.Minute),
+			},
+		}))
+
+		result, err := PerformOAuthFlow(t.Context(), server.URL, config)
+		require.NoError(t, err)
+		token, err := result.TokenSource.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "refreshed-token", token.AccessToken)
+		assert.Equal(t, int32(1), refreshes.Load())
+
+		// The refreshed token is stored for later flows, keeping the refresh token
+		cached, err := tokenCache.Get(t.Context(), cacheKey)
+		require.NoError(t, err)
+		require.NotNil(t, cached)
+		assert.Equal(t, "refreshed-token", cached.Token.AccessToken)
+		assert.Equal(t, "refresh-token", cached.Token.RefreshToken)
+	})
+}
+
 func TestRegisterDynamicClient_MissingRegistrationEndpoint(t *testing.T) {
 	t.Parallel()
 
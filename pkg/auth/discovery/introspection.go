@@ -0,0 +1,190 @@
+package discovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/auth/oauth"
+)
+
+const (
+	// DefaultIntrospectionCacheTTL is how long the result of introspecting an active token is reused
+	DefaultIntrospectionCacheTTL = 30 * time.Second
+	// maxIntrospectionResponseSize limits introspection responses to prevent resource exhaustion
+	maxIntrospectionResponseSize = 64 * 1024
+)
+
+// IntrospectionConfig configures the RFC 7662 introspection of opaque tokens
+type IntrospectionConfig struct {
+	// Endpoint is the token introspection endpoint of the authorization server
+	Endpoint string
+	// ClientID and ClientSecret authenticate the caller to the introspection endpoint (optional)
+	ClientID     string
+	ClientSecret string
+	// CacheTTL is how long the result of introspecting an active token is reused
+	// (optional, defaults to DefaultIntrospectionCacheTTL, negative disables caching)
+	CacheTTL time.Duration
+}
+
+// introspectionCacheEntry is the claims of an active token along with the time they stop being reused
+type introspectionCacheEntry struct {
+	claims    map[string]any
+	expiresAt time.Time
+}
+
+// introspectionCache holds the results of IntrospectToken for active tokens, keyed by
+// the hash of the endpoint, the client and the token
+var introspectionCache = struct {
+	sync.Mutex
+	entries map[string]*introspectionCacheEntry
+}{entries: make(map[string]*introspectionCacheEntry)}
+
+// IntrospectToken asks the RFC 7662 introspection endpoint whether an opaque token is active and
+// returns its claims. Inactive tokens are not an error, they are reported with active set to false.
+// Active results are cached for the CacheTTL of the config, and never past the expiry of the token.
+func IntrospectToken(ctx context.Context, token string, cfg *IntrospectionConfig) (bool, map[string]any, error) {
+	if cfg == nil || cfg.Endpoint == "" {
+		return false, nil, errors.New("introspection endpoint is not configured")
+	}
+	if token == "" {
+		return false, nil, errors.New("token is empty")
+	}
+
+	key := introspectionCacheKey(token, cfg)
+	if claims := cachedIntrospection(key); claims != nil {
+		return true, claims, nil
+	}
+
+	claims, err := introspect(ctx, token, cfg)
+	if err != nil {
+		return false, nil, err
+	}
+	if active, _ := claims["active"].(bool); !active {
+		return false, nil, nil
+	}
+
+	ttl := cfg.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultIntrospectionCacheTTL
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		if exp, ok := claims["exp"].(float64); ok && time.Unix(int64(exp), 0).Before(expiresAt) {
+			expiresAt = time.Unix(int64(exp), 0)
+		}
+		storeIntrospection(key, claims, expiresAt)
+	}
+
+	return true, maps.Clone(claims), nil
+}
+
+// introspect POSTs the token to the introspection endpoint and returns the parsed response
+func introspect(ctx context.Context, token string, cfg *IntrospectionConfig) (map[string]any, error) {
+	parsedURL, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid introspection endpoint: %w", err)
+	}
+
+	// Tokens must be sent over HTTPS (except for localhost in development)
+	if parsedURL.Scheme != "https" && parsedURL.Hostname() != "localhost" && parsedURL.Hostname() != "127.0.0.1" {
+		return nil, fmt.Errorf("introspection endpoint must use HTTPS: %s", cfg.Endpoint)
+	}
+
+	formData := url.Values{}
+	formData.Set("token", token)
+	formData.Set("token_type_hint", "access_token")
+	// Public clients identify themselves in the request body, confidential clients authenticate below
+	if cfg.ClientID != "" && cfg.ClientSecret == "" {
+		formData.Set("client_id", cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Endpoint, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", oauth.UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if cfg.ClientID != "" && cfg.ClientSecret != "" {
+		req.SetBasicAuth(cfg.ClientID, cfg.ClientSecret)
+	}
+
+	client := &http.Client{
+		Timeout: DefaultHTTPTimeout,
+		Transport: &http.Transport{
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 5 * time.Second,
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("introspection request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, errors.New("introspection unauthorized")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection failed with status %d", resp.StatusCode)
+	}
+
+	body, err := readLimitedBody(resp, maxIntrospectionResponseSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+	return claims, nil
+}
+
+// introspectionCacheKey returns the cache key of the introspection of token with cfg, which does not
+// keep the token itself in memory
+func introspectionCacheKey(token string, cfg *IntrospectionConfig) string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{cfg.Endpoint, cfg.ClientID, token}, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// cachedIntrospection returns a copy of the cached claims stored under key, or nil if there are none
+func cachedIntrospection(key string) map[string]any {
+	introspectionCache.Lock()
+	defer introspectionCache.Unlock()
+
+	entry := introspectionCache.entries[key]
+	if entry == nil {
+		return nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(introspectionCache.entries, key)
+		return nil
+	}
+	return maps.Clone(entry.claims)
+}
+
+// storeIntrospection caches the claims under key until expiresAt, dropping the expired entries
+func storeIntrospection(key string, claims map[string]any, expiresAt time.Time) {
+	introspectionCache.Lock()
+	defer introspectionCache.Unlock()
+
+	now := time.Now()
+	for k, entry := range introspectionCache.entries {
+		if now.After(entry.expiresAt) {
+			delete(introspectionCache.entries, k)
+		}
+	}
+	introspectionCache.entries[key] = &introspectionCacheEntry{claims: claims, expiresAt: expiresAt}
+}
@@ -0,0 +1,113 @@
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestIntrospectionServer serves an RFC 7662 introspection endpoint for which only "active-token"
+// is active, and counts the requests
+func newTestIntrospectionServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if clientID, clientSecret, ok := r.BasicAuth(); !ok || clientID != "client" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		response := map[string]any{"active": false}
+		if r.PostForm.Get("token") == "active-token" {
+			response = map[string]any{
+				"active": true,
+				"sub":    "user",
+				"aud":    "https://mcp.example.com",
+				"scope":  "read write",
+				"exp":    time.Now().Add(time.Hour).Unix(),
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestIntrospectToken(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newTestIntrospectionServer(t)
+	cfg := &IntrospectionConfig{Endpoint: server.URL, ClientID: "client", ClientSecret: "secret"}
+
+	active, claims, err := IntrospectToken(t.Context(), "active-token", cfg)
+	require.NoError(t, err)
+	assert.True(t, active)
+	assert.Equal(t, "user", claims["sub"])
+	assert.Equal(t, "read write", claims["scope"])
+
+	active, claims, err = IntrospectToken(t.Context(), "inactive-token", cfg)
+	require.NoError(t, err)
+	assert.False(t, active)
+	assert.Nil(t, claims)
+
+	// Active results are cached, inactive ones are not
+	_, _, err = IntrospectToken(t.Context(), "active-token", cfg)
+	require.NoError(t, err)
+	_, _, err = IntrospectToken(t.Context(), "inactive-token", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestIntrospectToken_CacheDisabled(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newTestIntrospectionServer(t)
+	cfg := &IntrospectionConfig{Endpoint: server.URL, ClientID: "client", ClientSecret: "secret", CacheTTL: -1}
+
+	for range 2 {
+		active, _, err := IntrospectToken(t.Context(), "active-token", cfg)
+		require.NoError(t, err)
+		assert.True(t, active)
+	}
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestIntrospectToken_Errors(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newTestIntrospectionServer(t)
+
+	_, _, err := IntrospectToken(t.Context(), "active-token", nil)
+	assert.ErrorContains(t, err, "not configured")
+
+	_, _, err = IntrospectToken(t.Context(), "", &IntrospectionConfig{Endpoint: server.URL})
+	assert.ErrorContains(t, err, "token is empty")
+
+	_, _, err = IntrospectToken(t.Context(), "active-token", &IntrospectionConfig{
+		Endpoint: server.URL, ClientID: "client", ClientSecret: "wrong",
+	})
+	assert.ErrorContains(t, err, "unauthorized")
+
+	_, _, err = IntrospectToken(t.Context(), "active-token", &IntrospectionConfig{
+		Endpoint: "http://auth.example.com/introspect",
+	})
+	assert.ErrorContains(t, err, "must use HTTPS")
+}
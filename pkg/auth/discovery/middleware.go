@@ -0,0 +1,133 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+)
+
+// RequireAuth returns a middleware enforcing the discovered authentication on the wrapped handler.
+// The bearer token of each request is validated against the discovered JWKS, or introspected when
+// it is not a JWT and WithIntrospection is given. Requests with a valid token reach the handler with
+// their identity stored in the context, see auth.IdentityFromContext. The others are rejected with
+// 401 Unauthorized and an RFC 6750 WWW-Authenticate challenge pointing to the resource metadata.
+func RequireAuth(authInfo *AuthInfo, opts ...TokenValidationOption) func(http.Handler) http.Handler {
+	if authInfo == nil {
+		authInfo = &AuthInfo{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := auth.ExtractBearerToken(r)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", buildAuthChallenge(authInfo, false, ""))
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := validateBearerToken(r.Context(), token, authInfo, opts)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", buildAuthChallenge(authInfo, true, err.Error()))
+				http.Error(w, fmt.Sprintf("Invalid token: %v", err), http.StatusUnauthorized)
+				return
+			}
+
+			sub, _ := claims["sub"].(string)
+			if sub == "" {
+				w.Header().Set("WWW-Authenticate", buildAuthChallenge(authInfo, true, "missing sub claim"))
+				http.Error(w, "Invalid authentication claims", http.StatusUnauthorized)
+				return
+			}
+			identity := &auth.Identity{
+				Subject:   sub,
+				Claims:    claims,
+				Token:     token,
+				TokenType: "Bearer",
+			}
+			identity.Name, _ = claims["name"].(string)
+			identity.Email, _ = claims["email"].(string)
+
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// validateBearerToken validates a JWT against the discovered JWKS, falling back to introspection
+// for opaque tokens, and returns its claims
+func validateBearerToken(
+	ctx context.Context, token string, authInfo *AuthInfo, opts []TokenValidationOption,
+) (map[string]any, error) {
+	options := newTokenValidationOptions(opts)
+	if authInfo.JWKSURI != "" {
+		claims, err := ValidateToken(ctx, token, authInfo, opts...)
+		if err == nil || options.introspection == nil || !errors.Is(err, jwt.ErrTokenMalformed) {
+			return claims, err
+		}
+	}
+	if options.introspection == nil {
+		return nil, errors.New("no JWKS URI or introspection endpoint to validate the token against")
+	}
+
+	active, claims, err := IntrospectToken(ctx, token, options.introspection)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, errors.New("token is not active")
+	}
+	if options.audience != "" && !hasAudience(claims, options.audience) {
+		return nil, errors.New("token is not issued for the expected audience")
+	}
+	return claims, nil
+}
+
+// hasAudience reports whether the aud claim, a string or a list of strings, contains audience
+func hasAudience(claims map[string]any, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []any:
+		return slices.Contains(aud, any(audience))
+	default:
+		return false
+	}
+}
+
+// buildAuthChallenge builds the RFC 6750 WWW-Authenticate challenge for the discovered authentication.
+// It includes the realm, the RFC 9728 resource metadata and the supported scopes when known, and the
+// invalid_token error when includeError is true.
+func buildAuthChallenge(authInfo *AuthInfo, includeError bool, errDescription string) string {
+	var parts []string
+
+	realm := authInfo.Realm
+	if realm == "" {
+		realm = authInfo.AuthorizationServer
+	}
+	if realm != "" {
+		parts = append(parts, fmt.Sprintf(`realm="%s"`, auth.EscapeQuotes(realm)))
+	}
+	if authInfo.ResourceMetadata != "" {
+		parts = append(parts, fmt.Sprintf(`resource_metadata="%s"`, auth.EscapeQuotes(authInfo.ResourceMetadata)))
+	}
+	if len(authInfo.ScopesSupported) > 0 {
+		parts = append(parts, fmt.Sprintf(`scope="%s"`, auth.EscapeQuotes(strings.Join(authInfo.ScopesSupported, " "))))
+	}
+	if includeError {
+		parts = append(parts, `error="invalid_token"`)
+		if errDescription != "" {
+			parts = append(parts, fmt.Sprintf(`error_description="%s"`, auth.EscapeQuotes(errDescription)))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(parts, ", ")
+}
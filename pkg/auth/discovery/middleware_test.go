@@ -0,0 +1,136 @@
+package discovery
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+)
+
+func TestRequireAuth(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksServer, _ := newTestJWKSServer(t, privateKey)
+	introspectionServer, _ := newTestIntrospectionServer(t)
+
+	authInfo := &AuthInfo{
+		Type:                "OAuth",
+		ResourceMetadata:    "https://mcp.example.com/.well-known/oauth-protected-resource",
+		AuthorizationServer: "https://auth.example.com",
+		ScopesSupported:     []string{"read", "write"},
+		JWKSURI:             jwksServer.URL,
+	}
+	handler := RequireAuth(authInfo,
+		WithAudience("https://mcp.example.com"),
+		WithIntrospection(&IntrospectionConfig{
+			Endpoint: introspectionServer.URL, ClientID: "client", ClientSecret: "secret",
+		}),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := auth.IdentityFromContext(r.Context())
+		require.True(t, ok, "identity should be stored in the context")
+		_, _ = w.Write([]byte(identity.Subject))
+	}))
+
+	validToken := signTestToken(t, privateKey, testTokenKeyID, jwt.MapClaims{
+		"iss": "https://auth.example.com",
+		"aud": "https://mcp.example.com",
+		"sub": "user",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	expiredToken := signTestToken(t, privateKey, testTokenKeyID, jwt.MapClaims{
+		"iss": "https://auth.example.com",
+		"aud": "https://mcp.example.com",
+		"sub": "user",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	})
+
+	const challenge = `Bearer realm="https://auth.example.com", ` +
+		`resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource", scope="read write"`
+
+	tests := []struct {
+		name              string
+		authorization     string
+		expectedStatus    int
+		expectedBody      string
+		expectedChallenge string
+	}{
+		{
+			name:           "valid JWT",
+			authorization:  "Bearer " + validToken,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user",
+		},
+		{
+			name:           "active opaque token",
+			authorization:  "Bearer active-token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user",
+		},
+		{
+			name:              "missing token",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: challenge,
+		},
+		{
+			name:              "invalid JWT",
+			authorization:     "Bearer " + expiredToken,
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: challenge + `, error="invalid_token", error_description=`,
+		},
+		{
+			name:              "inactive opaque token",
+			authorization:     "Bearer inactive-token",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: challenge + `, error="invalid_token", error_description="token is not active"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+			if tt.expectedChallenge != "" {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), tt.expectedChallenge)
+			} else {
+				assert.Empty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequireAuth_WithoutValidationMethod(t *testing.T) {
+	t.Parallel()
+
+	handler := RequireAuth(nil)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("handler should not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `Bearer error="invalid_token"`)
+}
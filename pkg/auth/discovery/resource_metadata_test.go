@@ -1,11 +1,14 @@
 package discovery
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -173,6 +176,372 @@ func TestFetchResourceMetadata_InvalidURL(t *testing.T) {
 	}
 }
 
+func TestFetchResourceMetadata_OversizedResponse(t *testing.T) {
+	t.Parallel()
+
+	const totalSize = 64 * MaxMetadataResponseSize
+
+	tests := []struct {
+		name                 string
+		declareContentLength bool
+	}{
+		{
+			name:                 "streamed response without content length",
+			declareContentLength: false,
+		},
+		{
+			name:                 "response with declared content length",
+			declareContentLength: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var written atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if tt.declareContentLength {
+					w.Header().Set("Content-Length", strconv.Itoa(totalSize))
+				}
+				w.WriteHeader(http.StatusOK)
+
+				// A valid document padded with whitespace, so only the size bound can reject it
+				prefix := []byte(`{"resource":"https://resource.example.com"}`)
+				n, err := w.Write(prefix)
+				written.Add(int64(n))
+				if err != nil {
+					return
+				}
+				chunk := bytes.Repeat([]byte(" "), 32*1024)
+				for written.Load() < totalSize {
+					n, err := w.Write(chunk)
+					written.Add(int64(n))
+					if err != nil {
+						return
+					}
+				}
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			metadata, err := FetchResourceMetadata(ctx, server.URL)
+			require.Error(t, err)
+			assert.Nil(t, metadata)
+			assert.Contains(t, err.Error(), "metadata response too large")
+
+			// Closing the server waits for the handler, which stops once the client hangs up
+			server.Close()
+			assert.Less(t, written.Load(), int64(totalSize), "oversized response should not be fully consumed")
+		})
+	}
+}
+
+func TestAuthInfo_ToOAuthConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		authInfo       *AuthInfo
+		metadata       *auth.RFC9728AuthInfo
+		scopes         []string
+		expectedIssuer string
+		expectedJWKS   string
+		expectedScopes []string
+	}{
+		{
+			name:     "uses discovered authorization server and scopes",
+			authInfo: &AuthInfo{Type: "OAuth", ResourceMetadata: "https://mcp.example.com/.well-known/oauth-protected-resource"},
+			metadata: &auth.RFC9728AuthInfo{
+				Resource:             "https://mcp.example.com",
+				AuthorizationServers: []string{"https://auth.example.com", "https://backup.example.com"},
+				ScopesSupported:      []string{"read", "write"},
+				JWKSURI:              "https://auth.example.com/jwks",
+			},
+			scopes:         []string{"openid", "read"},
+			expectedIssuer: "https://auth.example.com",
+			expectedJWKS:   "https://auth.example.com/jwks",
+			expectedScopes: []string{"openid", "read", "write"},
+		},
+		{
+			name:     "explicit scopes come first and are not duplicated",
+			authInfo: &AuthInfo{Type: "OAuth", ResourceMetadata: "https://mcp.example.com/.well-known/oauth-protected-resource"},
+			metadata: &auth.RFC9728AuthInfo{
+				Resource:             "https://mcp.example.com",
+				AuthorizationServers: []string{"https://auth.example.com"},
+				ScopesSupported:      []string{"read", "write", "read"},
+			},
+			scopes:         []string{"write", "admin", "write"},
+			expectedIssuer: "https://auth.example.com",
+			expectedScopes: []string{"write", "admin", "read"},
+		},
+		{
+			name:           "falls back to realm without metadata",
+			authInfo:       &AuthInfo{Type: "OAuth", Realm: "https://auth.example.com"},
+			scopes:         []string{"openid"},
+			expectedIssuer: "https://auth.example.com",
+			expectedScopes: []string{"openid"},
+		},
+		{
+			name:           "no scopes at all",
+			authInfo:       &AuthInfo{Type: "OAuth"},
+			expectedScopes: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NoError(t, tt.authInfo.SetResourceMetadata(tt.metadata, nil))
+			config := tt.authInfo.ToOAuthConfig("client-id", "client-secret", tt.scopes)
+
+			require.NotNil(t, config)
+			assert.Equal(t, "client-id", config.ClientID)
+			assert.Equal(t, "client-secret", config.ClientSecret)
+			assert.Equal(t, tt.expectedIssuer, config.Issuer)
+			assert.Equal(t, tt.expectedJWKS, config.JWKSURI)
+			assert.Equal(t, tt.expectedScopes, config.Scopes)
+
+			// Resolving the scopes again when the flow is performed keeps the merged scopes
+			scopes, err := ResolveScopes(config.Scopes, config.ScopesSupported, config.ScopePolicy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedScopes, scopes)
+		})
+	}
+}
+
+func TestResolveScopes(t *testing.T) {
+	t.Parallel()
+
+	overlappingUser := []string{"openid", "read", "admin"}
+	overlappingDiscovered := []string{"read", "write", "openid"}
+	disjointUser := []string{"openid", "profile"}
+	disjointDiscovered := []string{"read", "write"}
+
+	tests := []struct {
+		name          string
+		user          []string
+		discovered    []string
+		policy        ScopePolicy
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:       "union of overlapping scopes",
+			user:       overlappingUser,
+			discovered: overlappingDiscovered,
+			policy:     ScopePolicyUnion,
+			expected:   []string{"openid", "read", "admin", "write"},
+		},
+		{
+			name:       "union of disjoint scopes",
+			user:       disjointUser,
+			discovered: disjointDiscovered,
+			policy:     ScopePolicyUnion,
+			expected:   []string{"openid", "profile", "read", "write"},
+		},
+		{
+			name:       "union is the default policy",
+			user:       disjointUser,
+			discovered: disjointDiscovered,
+			expected:   []string{"openid", "profile", "read", "write"},
+		},
+		{
+			name:       "intersection of overlapping scopes",
+			user:       overlappingUser,
+			discovered: overlappingDiscovered,
+			policy:     ScopePolicyIntersection,
+			expected:   []string{"openid", "read"},
+		},
+		{
+			name:          "intersection of disjoint scopes",
+			user:          disjointUser,
+			discovered:    disjointDiscovered,
+			policy:        ScopePolicyIntersection,
+			expectedError: "none of the scopes [openid profile] is supported",
+		},
+		{
+			name:     "intersection without discovered scopes",
+			user:     disjointUser,
+			policy:   ScopePolicyIntersection,
+			expected: disjointUser,
+		},
+		{
+			name:       "intersection without user scopes",
+			discovered: disjointDiscovered,
+			policy:     ScopePolicyIntersection,
+			expected:   disjointDiscovered,
+		},
+		{
+			name:       "user-only with overlapping scopes",
+			user:       overlappingUser,
+			discovered: overlappingDiscovered,
+			policy:     ScopePolicyUserOnly,
+			expected:   overlappingUser,
+		},
+		{
+			name:       "user-only with disjoint scopes",
+			user:       disjointUser,
+			discovered: disjointDiscovered,
+			policy:     ScopePolicyUserOnly,
+			expected:   disjointUser,
+		},
+		{
+			name:          "unknown policy",
+			user:          disjointUser,
+			policy:        "all",
+			expectedError: "unknown scope policy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scopes, err := ResolveScopes(tt.user, tt.discovered, tt.policy)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, scopes)
+		})
+	}
+}
+
+func TestAuthInfo_SetResourceMetadata_SelectionPolicy(t *testing.T) {
+	t.Parallel()
+
+	servers := []string{
+		"https://untrusted.example.com",
+		"https://auth.example.com",
+		"https://backup.example.com",
+	}
+
+	tests := []struct {
+		name           string
+		policy         *AuthServerSelectionPolicy
+		expectedServer string
+		wantErr        bool
+	}{
+		{
+			name:           "nil policy selects the first server",
+			policy:         nil,
+			expectedServer: "https://untrusted.example.com",
+		},
+		{
+			name:           "empty allow-list selects the first server",
+			policy:         &AuthServerSelectionPolicy{},
+			expectedServer: "https://untrusted.example.com",
+		},
+		{
+			name:           "allow-list selects the first trusted server in advertised order",
+			policy:         &AuthServerSelectionPolicy{TrustedIssuers: []string{"https://backup.example.com", "https://auth.example.com/"}},
+			expectedServer: "https://auth.example.com",
+		},
+		{
+			name:    "allow-list without any match is an error",
+			policy:  &AuthServerSelectionPolicy{TrustedIssuers: []string{"https://other.example.com"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			authInfo := &AuthInfo{Type: "OAuth"}
+			err := authInfo.SetResourceMetadata(&auth.RFC9728AuthInfo{
+				Resource:             "https://mcp.example.com",
+				AuthorizationServers: servers,
+			}, tt.policy)
+
+			assert.Equal(t, servers, authInfo.AuthorizationServers, "all advertised servers should be exposed")
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Empty(t, authInfo.AuthorizationServer)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedServer, authInfo.AuthorizationServer)
+		})
+	}
+}
+
+func TestAuthInfo_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	rfc9728Info := &AuthInfo{
+		Type:             "OAuth",
+		ResourceMetadata: "https://mcp.example.com/.well-known/oauth-protected-resource",
+	}
+	require.NoError(t, rfc9728Info.SetResourceMetadata(&auth.RFC9728AuthInfo{
+		Resource:             "https://mcp.example.com",
+		AuthorizationServers: []string{"https://auth.example.com"},
+		ScopesSupported:      []string{"read", "write"},
+		JWKSURI:              "https://auth.example.com/jwks",
+	}, nil))
+
+	wwwAuthInfo, err := ParseWWWAuthenticate(
+		`Bearer realm="https://auth.example.com", error="invalid_token", error_description="The token expired"`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		authInfo *AuthInfo
+		expected string
+	}{
+		{
+			name:     "WWW-Authenticate result",
+			authInfo: wwwAuthInfo,
+			expected: `{
+				"type": "OAuth",
+				"realm": "https://auth.example.com",
+				"resourceMetadata": "",
+				"authorizationServer": "",
+				"authorizationServers": [],
+				"scopes": [],
+				"jwksURI": "",
+				"error": "invalid_token",
+				"errorDescription": "The token expired"
+			}`,
+		},
+		{
+			name:     "RFC 9728 result",
+			authInfo: rfc9728Info,
+			expected: `{
+				"type": "OAuth",
+				"realm": "",
+				"resourceMetadata": "https://mcp.example.com/.well-known/oauth-protected-resource",
+				"authorizationServer": "https://auth.example.com",
+				"authorizationServers": ["https://auth.example.com"],
+				"scopes": ["read", "write"],
+				"jwksURI": "https://auth.example.com/jwks"
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Both pointer and value forms must use the custom serialization
+			fromPointer, err := json.Marshal(tt.authInfo)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(fromPointer))
+
+			fromValue, err := json.Marshal(*tt.authInfo)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(fromValue))
+		})
+	}
+}
+
 func TestValidateAndDiscoverAuthServer(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,229 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+)
+
+const (
+	// JWKSCacheTTL is how long a fetched JWKS is reused before it is fetched again
+	JWKSCacheTTL = 10 * time.Minute
+	// jwksMinRefreshInterval limits how often a JWKS is fetched again because a token was signed
+	// by an unknown key, which happens when the authorization server rotates its keys
+	jwksMinRefreshInterval = 30 * time.Second
+	// DefaultClockSkew is the clock difference tolerated by ValidateToken between the authorization
+	// server and the host when checking the exp, nbf and iat claims
+	DefaultClockSkew = 60 * time.Second
+)
+
+// validSigningMethods are the asymmetric algorithms accepted for tokens verified against a JWKS
+var validSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// TokenValidationOption configures ValidateToken
+type TokenValidationOption func(*tokenValidationOptions)
+
+type tokenValidationOptions struct {
+	audience      string
+	clockSkew     time.Duration
+	introspection *IntrospectionConfig
+}
+
+// newTokenValidationOptions returns the options with the defaults overridden by opts
+func newTokenValidationOptions(opts []TokenValidationOption) tokenValidationOptions {
+	options := tokenValidationOptions{clockSkew: DefaultClockSkew}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithAudience makes ValidateToken require the token to be issued for the audience
+func WithAudience(audience string) TokenValidationOption {
+	return func(o *tokenValidationOptions) {
+		o.audience = audience
+	}
+}
+
+// WithClockSkew sets the clock difference tolerated when checking the exp, nbf and iat claims,
+// instead of DefaultClockSkew
+func WithClockSkew(skew time.Duration) TokenValidationOption {
+	return func(o *tokenValidationOptions) {
+		o.clockSkew = skew
+	}
+}
+
+// WithIntrospection makes RequireAuth introspect the tokens which are not JWTs with cfg.
+// It has no effect on ValidateToken.
+func WithIntrospection(cfg *IntrospectionConfig) TokenValidationOption {
+	return func(o *tokenValidationOptions) {
+		o.introspection = cfg
+	}
+}
+
+// jwksCacheEntry is a JWKS along with the time it was fetched
+type jwksCacheEntry struct {
+	keys      jwk.Set
+	fetchedAt time.Time
+}
+
+// jwksCache holds the key sets fetched by ValidateToken, keyed by JWKS URI
+var jwksCache = struct {
+	sync.Mutex
+	entries map[string]*jwksCacheEntry
+}{entries: make(map[string]*jwksCacheEntry)}
+
+// ValidateToken validates a JWT against the JWKS discovered in the RFC 9728 protected resource
+// metadata and returns its claims. The signature, the expiration time and the issuer, which is the
+// effective authorization server, are always verified, the audience only when WithAudience is given.
+// The time based claims are checked with a tolerance of DefaultClockSkew, unless WithClockSkew is given.
+// Key sets are cached for JWKSCacheTTL and fetched again when a token is signed by an unknown key.
+func ValidateToken(
+	ctx context.Context, token string, authInfo *AuthInfo, opts ...TokenValidationOption,
+) (map[string]any, error) {
+	if authInfo == nil || authInfo.JWKSURI == "" {
+		return nil, errors.New("no JWKS URI discovered to validate the token against")
+	}
+
+	options := newTokenValidationOptions(opts)
+
+	issuer := authInfo.AuthorizationServer
+	if issuer == "" && authInfo.Realm != "" {
+		issuer = DeriveIssuerFromRealm(authInfo.Realm)
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(validSigningMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(options.clockSkew),
+	}
+	if issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(issuer))
+	}
+	if options.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(options.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		return lookupJWKSKey(ctx, authInfo.JWKSURI, t)
+	}, parserOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	return claims, nil
+}
+
+// lookupJWKSKey returns the public key of the JWKS at jwksURI which signed the token
+func lookupJWKSKey(ctx context.Context, jwksURI string, token *jwt.Token) (any, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("token header missing kid")
+	}
+
+	entry := cachedJWKS(jwksURI)
+	if entry == nil || time.Since(entry.fetchedAt) > JWKSCacheTTL {
+		var err error
+		if entry, err = refreshJWKS(ctx, jwksURI); err != nil {
+			return nil, err
+		}
+	}
+
+	key, found := entry.keys.LookupKeyID(kid)
+	if !found && time.Since(entry.fetchedAt) > jwksMinRefreshInterval {
+		var err error
+		if entry, err = refreshJWKS(ctx, jwksURI); err != nil {
+			return nil, err
+		}
+		key, found = entry.keys.LookupKeyID(kid)
+	}
+	if !found {
+		return nil, fmt.Errorf("key ID %s not found in JWKS", kid)
+	}
+
+	var rawKey any
+	if err := jwk.Export(key, &rawKey); err != nil {
+		return nil, fmt.Errorf("failed to export raw key: %w", err)
+	}
+	return rawKey, nil
+}
+
+// cachedJWKS returns the cached JWKS of jwksURI, or nil if it has not been fetched yet
+func cachedJWKS(jwksURI string) *jwksCacheEntry {
+	jwksCache.Lock()
+	defer jwksCache.Unlock()
+	return jwksCache.entries[jwksURI]
+}
+
+// refreshJWKS fetches the JWKS at jwksURI and stores it in the cache.
+// The cache is not locked during the fetch, so that a slow authorization server does not block
+// the validation of tokens against the other key sets.
+func refreshJWKS(ctx context.Context, jwksURI string) (*jwksCacheEntry, error) {
+	keys, err := fetchJWKS(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &jwksCacheEntry{keys: keys, fetchedAt: time.Now()}
+	jwksCache.Lock()
+	defer jwksCache.Unlock()
+	jwksCache.entries[jwksURI] = entry
+	return entry, nil
+}
+
+// fetchJWKS fetches the JSON Web Key Set at jwksURI
+func fetchJWKS(ctx context.Context, jwksURI string) (jwk.Set, error) {
+	parsedURL, err := url.Parse(jwksURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWKS URI: %w", err)
+	}
+
+	// Keys must be fetched over HTTPS (except for localhost in development)
+	if parsedURL.Scheme != "https" && parsedURL.Hostname() != "localhost" && parsedURL.Hostname() != "127.0.0.1" {
+		return nil, fmt.Errorf("JWKS URI must use HTTPS: %s", jwksURI)
+	}
+
+	client := &http.Client{
+		Timeout: DefaultHTTPTimeout,
+		Transport: &http.Transport{
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 5 * time.Second,
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS request failed with status %d", resp.StatusCode)
+	}
+
+	body, err := readLimitedBody(resp, MaxMetadataResponseSize)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := jwk.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,352 @@
+package discovery
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTokenKeyID = "test-key"
+
+// newTestJWKSServer serves a JWKS holding the public key of privateKey and counts the requests
+func newTestJWKSServer(t *testing.T, privateKey *rsa.PrivateKey) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	key, err := jwk.Import(&privateKey.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, key.Set(jwk.KeyIDKey, testTokenKeyID))
+	require.NoError(t, key.Set(jwk.AlgorithmKey, "RS256"))
+	keySet := jwk.NewSet()
+	require.NoError(t, keySet.AddKey(key))
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(keySet)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// signTestToken signs a token with the claims using privateKey and the key ID kid
+func signTestToken(t *testing.T, privateKey *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(privateKey)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestValidateToken(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server, _ := newTestJWKSServer(t, privateKey)
+	authInfo := &AuthInfo{
+		AuthorizationServer: "https://auth.example.com",
+		JWKSURI:             server.URL,
+	}
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": "https://auth.example.com",
+			"aud": "https://mcp.example.com",
+			"sub": "user",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		signingKey  *rsa.PrivateKey
+		kid         string
+		claims      func() jwt.MapClaims
+		expectedErr error
+	}{
+		{
+			name:       "valid token",
+			signingKey: privateKey,
+			kid:        testTokenKeyID,
+			claims:     validClaims,
+		},
+		{
+			name:       "expired token",
+			signingKey: privateKey,
+			kid:        testTokenKeyID,
+			claims: func() jwt.MapClaims {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+				return claims
+			},
+			expectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:       "token without expiration time",
+			signingKey: privateKey,
+			kid:        testTokenKeyID,
+			claims: func() jwt.MapClaims {
+				claims := validClaims()
+				delete(claims, "exp")
+				return claims
+			},
+			expectedErr: jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:       "wrong audience",
+			signingKey: privateKey,
+			kid:        testTokenKeyID,
+			claims: func() jwt.MapClaims {
+				claims := validClaims()
+				claims["aud"] = "https://other.example.com"
+				return claims
+			},
+			expectedErr: jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:       "wrong issuer",
+			signingKey: privateKey,
+			kid:        testTokenKeyID,
+			claims: func() jwt.MapClaims {
+				claims := validClaims()
+				claims["iss"] = "https://other.example.com"
+				return claims
+			},
+			expectedErr: jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:        "signed with the wrong key",
+			signingKey:  otherKey,
+			kid:         testTokenKeyID,
+			claims:      validClaims,
+			expectedErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:        "signed with an unknown key",
+			signingKey:  otherKey,
+			kid:         "unknown-key",
+			claims:      validClaims,
+			expectedErr: jwt.ErrTokenUnverifiable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := signTestToken(t, tt.signingKey, tt.kid, tt.claims())
+			claims, err := ValidateToken(t.Context(), token, authInfo, WithAudience("https://mcp.example.com"))
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.expectedErr), "expected %v, got %v", tt.expectedErr, err)
+				assert.Nil(t, claims)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user", claims["sub"])
+			assert.Equal(t, "https://auth.example.com", claims["iss"])
+		})
+	}
+}
+
+func TestValidateToken_AudienceIsOptional(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	server, _ := newTestJWKSServer(t, privateKey)
+
+	token := signTestToken(t, privateKey, testTokenKeyID, jwt.MapClaims{
+		"iss": "https://auth.example.com",
+		"aud": "https://any.example.com",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	_, err = ValidateToken(t.Context(), token, &AuthInfo{
+		AuthorizationServer: "https://auth.example.com",
+		JWKSURI:             server.URL,
+	})
+	assert.NoError(t, err)
+}
+
+func TestValidateToken_ClockSkew(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	server, _ := newTestJWKSServer(t, privateKey)
+	authInfo := &AuthInfo{JWKSURI: server.URL}
+
+	tests := []struct {
+		name        string
+		claims      jwt.MapClaims
+		opts        []TokenValidationOption
+		expectedErr error
+	}{
+		{
+			name:   "expired within the default skew",
+			claims: jwt.MapClaims{"exp": time.Now().Add(-30 * time.Second).Unix()},
+		},
+		{
+			name:        "expired beyond the default skew",
+			claims:      jwt.MapClaims{"exp": time.Now().Add(-120 * time.Second).Unix()},
+			expectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:   "expired within a custom skew",
+			claims: jwt.MapClaims{"exp": time.Now().Add(-120 * time.Second).Unix()},
+			opts:   []TokenValidationOption{WithClockSkew(5 * time.Minute)},
+		},
+		{
+			name:        "expired without skew",
+			claims:      jwt.MapClaims{"exp": time.Now().Add(-30 * time.Second).Unix()},
+			opts:        []TokenValidationOption{WithClockSkew(0)},
+			expectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			name: "not yet valid within the default skew",
+			claims: jwt.MapClaims{
+				"nbf": time.Now().Add(30 * time.Second).Unix(),
+				"iat": time.Now().Add(30 * time.Second).Unix(),
+				"exp": time.Now().Add(time.Hour).Unix(),
+			},
+		},
+		{
+			name: "not yet valid beyond the default skew",
+			claims: jwt.MapClaims{
+				"nbf": time.Now().Add(120 * time.Second).Unix(),
+				"exp": time.Now().Add(time.Hour).Unix(),
+			},
+			expectedErr: jwt.ErrTokenNotValidYet,
+		},
+		{
+			name: "issued in the future beyond the default skew",
+			claims: jwt.MapClaims{
+				"iat": time.Now().Add(120 * time.Second).Unix(),
+				"exp": time.Now().Add(time.Hour).Unix(),
+			},
+			expectedErr: jwt.ErrTokenUsedBeforeIssued,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := signTestToken(t, privateKey, testTokenKeyID, tt.claims)
+			_, err := ValidateToken(t.Context(), token, authInfo, tt.opts...)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateToken_CachesJWKS(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	server, requests := newTestJWKSServer(t, privateKey)
+	authInfo := &AuthInfo{JWKSURI: server.URL}
+
+	for range 3 {
+		token := signTestToken(t, privateKey, testTokenKeyID, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()})
+		_, err := ValidateToken(t.Context(), token, authInfo)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), requests.Load(), "the JWKS should only be fetched once")
+}
+
+func TestNewTokenValidationOptions(t *testing.T) {
+	t.Parallel()
+
+	options := newTokenValidationOptions(nil)
+	assert.Equal(t, tokenValidationOptions{clockSkew: DefaultClockSkew}, options)
+
+	options = newTokenValidationOptions([]TokenValidationOption{
+		WithAudience("https://mcp.example.com"),
+		WithClockSkew(time.Second),
+	})
+	assert.Equal(t, tokenValidationOptions{audience: "https://mcp.example.com", clockSkew: time.Second}, options)
+}
+
+func TestValidateToken_SlowJWKSDoesNotBlockOtherKeySets(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	server, _ := newTestJWKSServer(t, privateKey)
+
+	release := make(chan struct{})
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(slowServer.Close)
+	t.Cleanup(func() { close(release) })
+
+	token := signTestToken(t, privateKey, testTokenKeyID, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()})
+
+	slowDone := make(chan struct{})
+	go func() {
+		defer close(slowDone)
+		_, _ = ValidateToken(t.Context(), token, &AuthInfo{JWKSURI: slowServer.URL})
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := ValidateToken(t.Context(), token, &AuthInfo{JWKSURI: server.URL})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-slowDone:
+		t.Fatal("the slow JWKS fetch returned before it was released")
+	case <-time.After(5 * time.Second):
+		t.Fatal("validation was blocked by the fetch of another JWKS")
+	}
+}
+
+func TestValidateToken_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ValidateToken(t.Context(), "token", nil)
+	assert.ErrorContains(t, err, "no JWKS URI")
+
+	_, err = ValidateToken(t.Context(), "token", &AuthInfo{AuthorizationServer: "https://auth.example.com"})
+	assert.ErrorContains(t, err, "no JWKS URI")
+
+	_, err = ValidateToken(t.Context(), "not-a-jwt", &AuthInfo{JWKSURI: "https://auth.example.com/jwks"})
+	assert.ErrorIs(t, err, jwt.ErrTokenMalformed)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	token := signTestToken(t, privateKey, testTokenKeyID, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()})
+	_, err = ValidateToken(t.Context(), token, &AuthInfo{JWKSURI: "http://auth.example.com/jwks"})
+	assert.ErrorContains(t, err, "must use HTTPS")
+}
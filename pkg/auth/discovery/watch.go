@@ -0,0 +1,88 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// watchJitterFactor is the maximum fraction of the interval added as random jitter between
+// re-detections, so that many proxies watching the same server do not probe it in lockstep.
+const watchJitterFactor = 0.1
+
+// WatchAuthentication periodically re-detects the authentication requirements of the target
+// server and emits the new AuthInfo on the returned channel whenever it changes. A nil value
+// is emitted when the server stops requiring authentication.
+//
+// The initial detection is performed synchronously and establishes the baseline; it is not
+// emitted on the channel. Failed re-detections are logged and do not produce an event.
+// The channel is closed when the context is cancelled.
+func WatchAuthentication(
+	ctx context.Context,
+	targetURI string,
+	config *Config,
+	interval time.Duration,
+) (<-chan *AuthInfo, error) {
+	if interval <= 0 {
+		return nil, errors.New("watch interval must be positive")
+	}
+
+	current, err := DetectAuthenticationFromServer(ctx, targetURI, config)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan *AuthInfo, 1)
+	go func() {
+		defer close(changes)
+
+		timer := time.NewTimer(jitteredInterval(interval))
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			detected, err := DetectAuthenticationFromServer(ctx, targetURI, config)
+			if err != nil {
+				logger.Debugf("Failed to re-detect authentication for %s: %v", targetURI, err)
+			} else if !authInfoEqual(current, detected) {
+				logger.Infof("Authentication requirements changed for %s", targetURI)
+				current = detected
+				select {
+				case changes <- detected:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			timer.Reset(jitteredInterval(interval))
+		}
+	}()
+
+	return changes, nil
+}
+
+// jitteredInterval returns the interval extended by a random amount of up to watchJitterFactor
+func jitteredInterval(interval time.Duration) time.Duration {
+	maxJitter := int64(float64(interval) * watchJitterFactor)
+	if maxJitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int64N(maxJitter))
+}
+
+// authInfoEqual reports whether two AuthInfo values describe the same requirements
+func authInfoEqual(a, b *AuthInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return reflect.DeepEqual(*a, *b)
+}
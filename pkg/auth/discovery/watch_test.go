@@ -0,0 +1,104 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchAuthentication_EmitsOnlyOnChange(t *testing.T) {
+	t.Parallel()
+
+	var requireAuth atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requireAuth.Load() {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="https://example.com"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.Contains(r.URL.Path, ".well-known") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 20 * time.Millisecond
+	changes, err := WatchAuthentication(ctx, server.URL, nil, interval)
+	require.NoError(t, err)
+
+	// Several re-detections against an unchanged server must not emit anything
+	select {
+	case info := <-changes:
+		t.Fatalf("unexpected change event before auth requirements changed: %+v", info)
+	case <-time.After(5 * interval):
+	}
+
+	requireAuth.Store(true)
+
+	select {
+	case info := <-changes:
+		require.NotNil(t, info)
+		assert.Equal(t, "OAuth", info.Type)
+		assert.Equal(t, "https://example.com", info.Realm)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for change event")
+	}
+
+	// The server keeps requiring the same auth, so no further events are expected
+	select {
+	case info := <-changes:
+		t.Fatalf("unexpected additional change event: %+v", info)
+	case <-time.After(5 * interval):
+	}
+
+	// The channel is closed once the context is cancelled
+	cancel()
+	select {
+	case _, ok := <-changes:
+		assert.False(t, ok, "channel should be closed after cancellation")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+}
+
+func TestWatchAuthentication_InvalidInterval(t *testing.T) {
+	t.Parallel()
+
+	_, err := WatchAuthentication(context.Background(), "http://localhost", nil, 0)
+	require.Error(t, err)
+}
+
+func TestAuthInfoEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		a        *AuthInfo
+		b        *AuthInfo
+		expected bool
+	}{
+		{name: "both nil", expected: true},
+		{name: "nil and non-nil", b: &AuthInfo{Type: "OAuth"}, expected: false},
+		{name: "same values", a: &AuthInfo{Type: "OAuth", Realm: "r"}, b: &AuthInfo{Type: "OAuth", Realm: "r"}, expected: true},
+		{name: "different realm", a: &AuthInfo{Type: "OAuth", Realm: "a"}, b: &AuthInfo{Type: "OAuth", Realm: "b"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, authInfoEqual(tt.a, tt.b))
+		})
+	}
+}
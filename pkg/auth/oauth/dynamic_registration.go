@@ -43,7 +43,7 @@ type DynamicClientRegistrationRequest struct {
 // NewDynamicClientRegistrationRequest creates a new dynamic client registration request
 func NewDynamicClientRegistrationRequest(scopes []string, callbackPort int) *DynamicClientRegistrationRequest {
 
-	redirectURIs := []string{fmt.Sprintf("http://localhost:%d/callback", callbackPort)}
+	redirectURIs := []string{CallbackRedirectURL(callbackPort)}
 
 	// Create dynamic registration request
 	registrationRequest := &DynamicClientRegistrationRequest{
@@ -9,9 +9,13 @@ import (
 	"errors"
 	"fmt"
 	"html"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -49,16 +53,82 @@ type Config struct {
 	// CallbackPort is the port for the OAuth callback server (optional, 0 means auto-select)
 	CallbackPort int
 
+	// CallbackHost is the address the OAuth callback server binds to (optional, empty binds to all interfaces)
+	CallbackHost string
+
 	// IntrospectionEndpoint is the optional introspection endpoint for validating tokens
 	IntrospectionEndpoint string
 
 	// Resource is the OAuth 2.0 resource indicator (RFC 8707).
 	Resource string
 
-	// OAuthParams are additional parameters to pass to the authorization URL
+	// OAuthParams are additional parameters to pass to the authorization URL, e.g. audience or prompt.
+	// Parameters set by the flow itself, such as client_id or redirect_uri, cannot be overridden.
 	OAuthParams map[string]string
 }
 
+// callbackPath is the path the OAuth callback server handles the redirect on
+const callbackPath = "/callback"
+
+// CallbackRedirectURL returns the default redirect URL for a callback server on the given port
+func CallbackRedirectURL(port int) string {
+	return fmt.Sprintf("http://localhost:%d%s", port, callbackPath)
+}
+
+// ValidateRedirectURL checks that a redirect URL can be served by the OAuth callback server.
+// Its host and port may differ from the callback server's, e.g. when the port is forwarded into a container.
+func ValidateRedirectURL(redirectURL string) error {
+	parsed, err := url.Parse(redirectURL)
+	if err != nil {
+		return fmt.Errorf("invalid redirect URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("redirect URL must use http or https: %s", redirectURL)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("redirect URL must have a host: %s", redirectURL)
+	}
+	if parsed.Path != callbackPath {
+		return fmt.Errorf("redirect URL path must be %s: %s", callbackPath, redirectURL)
+	}
+	return nil
+}
+
+// RedactedValue replaces sensitive values, such as authorization codes, tokens and client secrets, in logs
+const RedactedValue = "[REDACTED]"
+
+// Redact returns RedactedValue for a non-empty sensitive value, so that logs show whether it is set
+// without leaking it
+func Redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return RedactedValue
+}
+
+// reservedAuthParams are the authorization request parameters set by the flow itself
+var reservedAuthParams = []string{
+	"client_id",
+	"redirect_uri",
+	"response_type",
+	"scope",
+	"state",
+	"code_challenge",
+	"code_challenge_method",
+	"resource",
+}
+
+// ValidateOAuthParams checks that additional authorization request parameters do not override
+// the parameters set by the flow itself
+func ValidateOAuthParams(params map[string]string) error {
+	for key := range params {
+		if slices.Contains(reservedAuthParams, key) {
+			return fmt.Errorf("OAuth parameter %s is reserved and cannot be overridden", key)
+		}
+	}
+	return nil
+}
+
 // Flow handles the OAuth authentication flow
 type Flow struct {
 	config       *Config
@@ -102,6 +172,10 @@ func NewFlow(config *Config) (*Flow, error) {
 		return nil, errors.New("token URL is required")
 	}
 
+	if err := ValidateOAuthParams(config.OAuthParams); err != nil {
+		return nil, err
+	}
+
 	// Use specified callback port or find an available port for the local server
 	port, err := networking.FindOrUsePort(config.CallbackPort)
 	if err != nil {
@@ -111,7 +185,9 @@ func NewFlow(config *Config) (*Flow, error) {
 	// Set default redirect URL if not provided
 	redirectURL := config.RedirectURL
 	if redirectURL == "" {
-		redirectURL = fmt.Sprintf("http://localhost:%d/callback", port)
+		redirectURL = CallbackRedirectURL(port)
+	} else if err := ValidateRedirectURL(redirectURL); err != nil {
+		return nil, err
 	}
 
 	// Create OAuth2 config
@@ -131,6 +207,12 @@ func NewFlow(config *Config) (*Flow, error) {
 		oauth2Config: oauth2Config,
 		port:         port,
 	}
+	logger.Debugw("OAuth flow created",
+		"client_id", config.ClientID,
+		"client_secret", Redact(config.ClientSecret),
+		"redirect_url", redirectURL,
+		"callback_port", port,
+		"pkce", config.UsePKCE)
 
 	// Generate PKCE parameters if enabled
 	if config.UsePKCE {
@@ -181,11 +263,11 @@ func (f *Flow) Start(ctx context.Context, skipBrowser bool) (*TokenResult, error
 
 	// Set up HTTP server for handling the callback
 	mux := http.NewServeMux()
-	mux.HandleFunc("/callback", f.handleCallback(tokenChan, errorChan))
+	mux.HandleFunc(callbackPath, f.handleCallback(tokenChan, errorChan))
 	mux.HandleFunc("/", f.handleRoot())
 
 	f.server = &http.Server{
-		Addr:              fmt.Sprintf(":%d", f.port),
+		Addr:              net.JoinHostPort(f.config.CallbackHost, strconv.Itoa(f.port)),
 		Handler:           mux,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
@@ -209,6 +291,11 @@ func (f *Flow) Start(ctx context.Context, skipBrowser bool) (*TokenResult, error
 
 	// Build authorization URL
 	authURL := f.buildAuthURL()
+	logger.Debugw("OAuth authorization URL built",
+		"authorization_endpoint", f.config.AuthURL,
+		"scopes", f.config.Scopes,
+		"resource", f.config.Resource,
+		"pkce", f.config.UsePKCE)
 
 	// Open browser or display URL
 	if !skipBrowser {
@@ -273,6 +360,10 @@ func (f *Flow) handleCallback(tokenChan chan<- *oauth2.Token, errorChan chan<- e
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Parse query parameters
 		query := r.URL.Query()
+		logger.Debugw("OAuth callback received",
+			"code", Redact(query.Get("code")),
+			"state_valid", query.Get("state") == f.state,
+			"error", query.Get("error"))
 
 		// Check for error
 		if errParam := query.Get("error"); errParam != "" {
@@ -314,6 +405,7 @@ func (f *Flow) handleCallback(tokenChan chan<- *oauth2.Token, errorChan chan<- e
 			opts = append(opts, oauth2.SetAuthURLParam("resource", f.config.Resource))
 		}
 
+		logger.Debugw("Exchanging OAuth authorization code for token", "token_endpoint", f.oauth2Config.Endpoint.TokenURL)
 		token, err := f.oauth2Config.Exchange(ctx, code, opts...)
 		if err != nil {
 			err = fmt.Errorf("failed to exchange code for token: %w", err)
@@ -321,6 +413,11 @@ func (f *Flow) handleCallback(tokenChan chan<- *oauth2.Token, errorChan chan<- e
 			errorChan <- err
 			return
 		}
+		logger.Debugw("OAuth token exchanged",
+			"token_type", token.Type(),
+			"expiry", token.Expiry,
+			"access_token", Redact(token.AccessToken),
+			"refresh_token", Redact(token.RefreshToken))
 
 		// Write success page
 		f.writeSuccessPage(w)
@@ -6,16 +6,21 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 	"golang.org/x/oauth2"
 
 	"github.com/stacklok/toolhive/pkg/logger"
@@ -143,6 +148,100 @@ func TestNewFlow(t *testing.T) {
 	}
 }
 
+func TestNewFlow_CallbackConfiguration(t *testing.T) {
+	t.Parallel()
+
+	// Reserve a free port to configure as the callback port
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	callbackPort := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	tests := []struct {
+		name          string
+		redirectURL   string
+		expectedURL   string
+		expectedError string
+	}{
+		{
+			name:        "redirect URL derived from the callback port",
+			expectedURL: fmt.Sprintf("http://localhost:%d/callback", callbackPort),
+		},
+		{
+			name:        "configured redirect URL",
+			redirectURL: "http://127.0.0.1:9000/callback",
+			expectedURL: "http://127.0.0.1:9000/callback",
+		},
+		{
+			name:          "redirect URL with another path",
+			redirectURL:   "http://localhost:9000/oauth/callback",
+			expectedError: "redirect URL path must be /callback",
+		},
+		{
+			name:          "redirect URL with another scheme",
+			redirectURL:   "myapp://localhost/callback",
+			expectedError: "redirect URL must use http or https",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			flow, err := NewFlow(&Config{
+				ClientID:     "test-client",
+				AuthURL:      "https://example.com/auth",
+				TokenURL:     "https://example.com/token",
+				CallbackPort: callbackPort,
+				RedirectURL:  tt.redirectURL,
+			})
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, callbackPort, flow.port, "configured callback port should be used")
+			assert.Equal(t, tt.expectedURL, flow.oauth2Config.RedirectURL)
+			assert.Contains(t, flow.buildAuthURL(), "redirect_uri="+url.QueryEscape(tt.expectedURL))
+		})
+	}
+}
+
+func TestStart_CallbackHost(t *testing.T) {
+	t.Parallel()
+
+	flow, err := NewFlow(&Config{
+		ClientID:     "test-client",
+		AuthURL:      "https://example.com/auth",
+		TokenURL:     "https://example.com/token",
+		CallbackHost: "127.0.0.1",
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = flow.Start(ctx, true)
+	}()
+
+	// The callback server listens on the configured host
+	callbackAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(flow.port))
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", callbackAddr)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 2*time.Second, 20*time.Millisecond)
+
+	cancel()
+	<-done
+	assert.Equal(t, callbackAddr, flow.server.Addr)
+}
+
 func TestGeneratePKCEParams(t *testing.T) {
 	t.Parallel()
 	flow := &Flow{}
@@ -257,6 +356,34 @@ func TestBuildAuthURL(t *testing.T) {
 				assert.Equal(t, "S256", query.Get("code_challenge_method"))
 			},
 		},
+		{
+			name: "auth URL with additional parameters",
+			config: &Config{
+				ClientID: "test-client",
+				AuthURL:  "https://example.com/auth",
+				TokenURL: "https://example.com/token",
+				Scopes:   []string{"openid"},
+				OAuthParams: map[string]string{
+					"audience":     "https://api.example.com",
+					"prompt":       "consent",
+					"login_hint":   "user@example.com",
+					"organization": "org_123",
+				},
+			},
+			validate: func(t *testing.T, authURL string, flow *Flow) {
+				t.Helper()
+				parsedURL, err := url.Parse(authURL)
+				require.NoError(t, err)
+
+				query := parsedURL.Query()
+				assert.Equal(t, "https://api.example.com", query.Get("audience"))
+				assert.Equal(t, "consent", query.Get("prompt"))
+				assert.Equal(t, "user@example.com", query.Get("login_hint"))
+				assert.Equal(t, "org_123", query.Get("organization"))
+				assert.Equal(t, "test-client", query.Get("client_id"))
+				assert.Equal(t, flow.state, query.Get("state"))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -273,6 +400,25 @@ func TestBuildAuthURL(t *testing.T) {
 	}
 }
 
+func TestNewFlow_ReservedOAuthParams(t *testing.T) {
+	t.Parallel()
+
+	for _, param := range reservedAuthParams {
+		t.Run(param, func(t *testing.T) {
+			t.Parallel()
+			flow, err := NewFlow(&Config{
+				ClientID:    "test-client",
+				AuthURL:     "https://example.com/auth",
+				TokenURL:    "https://example.com/token",
+				OAuthParams: map[string]string{"audience": "api", param: "override"},
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "OAuth parameter "+param+" is reserved")
+			assert.Nil(t, flow)
+		})
+	}
+}
+
 func TestHandleCallback_SecurityValidation(t *testing.T) {
 	t.Parallel()
 	config := &Config{
@@ -931,3 +1077,78 @@ func TestExtractJWTClaims_ErrorCases(t *testing.T) {
 		})
 	}
 }
+
+//nolint:paralleltest // replaces the global logger
+func TestFlow_DebugLogsRedactSecrets(t *testing.T) {
+	core, observedLogs := observer.New(zap.DebugLevel)
+	originalLogger := zap.L()
+	zap.ReplaceGlobals(zap.New(core))
+	t.Cleanup(func() {
+		zap.ReplaceGlobals(originalLogger)
+	})
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "secret-access-token",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"refresh_token": "secret-refresh-token",
+		})
+	}))
+	defer tokenServer.Close()
+
+	flow, err := NewFlow(&Config{
+		ClientID:     "test-client",
+		ClientSecret: "secret-client-secret",
+		AuthURL:      "https://example.com/auth",
+		TokenURL:     tokenServer.URL,
+		UsePKCE:      true,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := flow.Start(ctx, true)
+		done <- err
+	}()
+
+	// Complete the flow through the callback server
+	callbackURL := fmt.Sprintf("http://localhost:%d/callback?code=secret-auth-code&state=%s", flow.port, flow.state)
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(callbackURL)
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return true
+	}, 2*time.Second, 20*time.Millisecond)
+	require.NoError(t, <-done)
+
+	var logs []string
+	for _, entry := range observedLogs.All() {
+		logs = append(logs, fmt.Sprintf("%s %v", entry.Message, entry.ContextMap()))
+	}
+	allLogs := strings.Join(logs, "\n")
+
+	for _, phase := range []string{
+		"OAuth flow created",
+		"OAuth authorization URL built",
+		"OAuth callback received",
+		"OAuth token exchanged",
+	} {
+		assert.Contains(t, allLogs, phase)
+	}
+	assert.Contains(t, allLogs, RedactedValue)
+	for _, secret := range []string{
+		"secret-client-secret",
+		"secret-auth-code",
+		"secret-access-token",
+		"secret-refresh-token",
+		flow.codeVerifier,
+	} {
+		assert.NotContains(t, allLogs, secret)
+	}
+}
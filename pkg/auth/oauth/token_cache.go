@@ -0,0 +1,150 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+
+	"github.com/stacklok/toolhive/pkg/config"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+// tokenCacheSecretPrefix prefixes the names of the secrets the cached tokens are stored in
+const tokenCacheSecretPrefix = "OAUTH_TOKEN_CACHE_"
+
+// CachedToken is a token stored in a TokenCache, along with the client it was issued to
+// and the token endpoint, which are needed to refresh it
+type CachedToken struct {
+	ClientID     string        `json:"client_id"`
+	ClientSecret string        `json:"client_secret,omitempty"`
+	TokenURL     string        `json:"token_url"`
+	Token        *oauth2.Token `json:"token"`
+}
+
+// TokenCache stores the tokens obtained by OAuth flows so that later flows can reuse them
+type TokenCache interface {
+	// Get returns the token stored under key, or nil if there is none
+	Get(ctx context.Context, key string) (*CachedToken, error)
+	// Set stores the token under key
+	Set(ctx context.Context, key string, token *CachedToken) error
+}
+
+// TokenCacheKey returns the cache key of the tokens issued by issuer to clientID for the given scopes.
+// The order of the scopes does not matter.
+func TokenCacheKey(issuer, clientID string, scopes []string) string {
+	sorted := slices.Clone(scopes)
+	slices.Sort(sorted)
+	hash := sha256.Sum256([]byte(strings.Join([]string{issuer, clientID, strings.Join(sorted, " ")}, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// SecretsTokenCache is a TokenCache storing each token as a secret of a secrets provider,
+// e.g. the encrypted provider, so that the tokens and client secrets are never written in plaintext.
+type SecretsTokenCache struct {
+	provider secrets.Provider
+}
+
+// NewSecretsTokenCache creates a token cache storing the tokens in the given secrets provider,
+// which must support writing secrets
+func NewSecretsTokenCache(provider secrets.Provider) (*SecretsTokenCache, error) {
+	if !provider.Capabilities().CanWrite {
+		return nil, errors.New("the secrets provider does not support storing OAuth tokens")
+	}
+	return &SecretsTokenCache{provider: provider}, nil
+}
+
+// NewDefaultSecretsTokenCache creates a token cache storing the tokens in the configured secrets provider.
+// The cache lives as long as the proxy, so the provider is reloadable with secrets.ReloadProviders,
+// e.g. to pick up a rotated token of the provider.
+func NewDefaultSecretsTokenCache() (*SecretsTokenCache, error) {
+	cfg := config.NewDefaultProvider().GetConfig()
+	if !cfg.Secrets.SetupCompleted {
+		return nil, secrets.ErrSecretsNotSetup
+	}
+
+	providerType, err := cfg.Secrets.GetProviderType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secrets provider type: %w", err)
+	}
+
+	provider, err := secrets.NewReloadableProvider(providerType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets manager: %w", err)
+	}
+	return NewSecretsTokenCache(provider)
+}
+
+// Get returns the token stored under key, or nil if there is none
+func (c *SecretsTokenCache) Get(ctx context.Context, key string) (*CachedToken, error) {
+	value, err := c.provider.GetSecret(ctx, tokenCacheSecretPrefix+key)
+	if secrets.IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached token: %w", err)
+	}
+
+	var token CachedToken
+	if err := json.Unmarshal([]byte(value), &token); err != nil {
+		return nil, fmt.Errorf("failed to parse cached token: %w", err)
+	}
+	return &token, nil
+}
+
+// Set stores the token under key
+func (c *SecretsTokenCache) Set(ctx context.Context, key string, token *CachedToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached token: %w", err)
+	}
+	if err := c.provider.SetSecret(ctx, tokenCacheSecretPrefix+key, string(data)); err != nil {
+		return fmt.Errorf("failed to store cached token: %w", err)
+	}
+	return nil
+}
+
+// cachingTokenSource stores the tokens of a token source in a TokenCache each time they are refreshed
+type cachingTokenSource struct {
+	base   oauth2.TokenSource
+	cache  TokenCache
+	key    string
+	cached CachedToken
+	mu     sync.Mutex
+}
+
+// NewCachingTokenSource returns a token source storing each new token of base in cache under key,
+// along with the client and token endpoint of cached
+func NewCachingTokenSource(base oauth2.TokenSource, cache TokenCache, key string, cached CachedToken) oauth2.TokenSource {
+	return &cachingTokenSource{base: base, cache: cache, key: key, cached: cached}
+}
+
+// Token returns the token of the base token source, storing it if it changed
+func (s *cachingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached.Token == nil || s.cached.Token.AccessToken != token.AccessToken {
+		s.cached.Token = token
+		cached := s.cached
+		// oauth2.TokenSource has no context, and the token must be stored even if the flow that
+		// created the token source is over
+		if err := s.cache.Set(context.Background(), s.key, &cached); err != nil {
+			// The token is still usable, it will just not be reused by later flows
+			logger.Warnf("Failed to cache OAuth token: %v", err)
+		}
+	}
+	return token, nil
+}
@@ -0,0 +1,122 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+func TestTokenCacheKey(t *testing.T) {
+	t.Parallel()
+
+	key := TokenCacheKey("https://issuer.example.com", "client", []string{"openid", "profile"})
+	assert.Equal(t, key, TokenCacheKey("https://issuer.example.com", "client", []string{"profile", "openid"}))
+	assert.NotEqual(t, key, TokenCacheKey("https://other.example.com", "client", []string{"openid", "profile"}))
+	assert.NotEqual(t, key, TokenCacheKey("https://issuer.example.com", "other", []string{"openid", "profile"}))
+	assert.NotEqual(t, key, TokenCacheKey("https://issuer.example.com", "client", []string{"openid"}))
+}
+
+func TestSecretsTokenCache(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	path := filepath.Join(t.TempDir(), "secrets_encrypted")
+	key := sha256.Sum256([]byte("test-password"))
+	provider, err := secrets.NewEncryptedManager(path, key[:])
+	require.NoError(t, err)
+	cache, err := NewSecretsTokenCache(provider)
+	require.NoError(t, err)
+
+	cached, err := cache.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, cache.Set(ctx, "key", &CachedToken{
+		ClientID:     "client",
+		ClientSecret: "client-secret",
+		TokenURL:     "https://issuer.example.com/token",
+		Token:        &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: expiry},
+	}))
+
+	// A new cache reads the tokens persisted by the previous one
+	provider, err = secrets.NewEncryptedManager(path, key[:])
+	require.NoError(t, err)
+	cache, err = NewSecretsTokenCache(provider)
+	require.NoError(t, err)
+	cached, err = cache.Get(ctx, "key")
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "client", cached.ClientID)
+	assert.Equal(t, "client-secret", cached.ClientSecret)
+	assert.Equal(t, "https://issuer.example.com/token", cached.TokenURL)
+	assert.Equal(t, "access", cached.Token.AccessToken)
+	assert.Equal(t, "refresh", cached.Token.RefreshToken)
+	assert.True(t, expiry.Equal(cached.Token.Expiry))
+
+	// The tokens and the client secret are not stored in plaintext
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, value := range []string{"access", "refresh", "client-secret"} {
+		assert.NotContains(t, string(data), value)
+	}
+}
+
+func TestNewSecretsTokenCache_ReadOnlyProvider(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSecretsTokenCache(secrets.NewEnvironmentProvider())
+	require.Error(t, err)
+}
+
+// staticTokenCache is an in-memory TokenCache
+type staticTokenCache struct {
+	tokens map[string]*CachedToken
+	err    error
+}
+
+func (c *staticTokenCache) Get(_ context.Context, key string) (*CachedToken, error) {
+	return c.tokens[key], nil
+}
+
+func (c *staticTokenCache) Set(_ context.Context, key string, token *CachedToken) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.tokens[key] = token
+	return nil
+}
+
+func TestCachingTokenSource(t *testing.T) {
+	t.Parallel()
+
+	cache := &staticTokenCache{tokens: map[string]*CachedToken{}}
+	source := NewCachingTokenSource(
+		oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"}),
+		cache, "key", CachedToken{ClientID: "client", TokenURL: "https://issuer.example.com/token"},
+	)
+
+	token, err := source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+	require.Contains(t, cache.tokens, "key")
+	assert.Equal(t, "client", cache.tokens["key"].ClientID)
+	assert.Equal(t, "access", cache.tokens["key"].Token.AccessToken)
+
+	// Failing to store a token does not fail the token source
+	cache = &staticTokenCache{tokens: map[string]*CachedToken{}, err: errors.New("read-only")}
+	source = NewCachingTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"}), cache, "key", CachedToken{})
+	token, err = source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/stacklok/toolhive/pkg/auth/discovery"
+	"github.com/stacklok/toolhive/pkg/auth/oauth"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/registry/registry"
 	"github.com/stacklok/toolhive/pkg/validation"
@@ -40,6 +42,36 @@ type Config struct {
 
 	// OAuth parameters for server-specific customization
 	OAuthParams map[string]string `json:"oauth_params,omitempty" yaml:"oauth_params,omitempty"`
+
+	// FlowTimeout bounds the whole OAuth flow, including discovery and client registration.
+	// Zero means no limit.
+	FlowTimeout time.Duration `json:"flow_timeout,omitempty" yaml:"flow_timeout,omitempty" swaggertype:"string" example:"5m"`
+
+	// ScopePolicy is how Scopes are combined with the scopes advertised by the server:
+	// union, intersection or user-only. If empty, the advertised scopes are only used when Scopes is empty.
+	ScopePolicy string `json:"scope_policy,omitempty" yaml:"scope_policy,omitempty"`
+
+	// CacheTokens stores the OAuth tokens in the secrets provider so that later flows reuse them.
+	CacheTokens bool `json:"cache_tokens,omitempty" yaml:"cache_tokens,omitempty"`
+}
+
+// ApplyFlowOptions sets the flow timeout, scope policy and token cache of the config on the OAuth flow
+// config. The scopes advertised by the server are only combined with the requested ones when a scope
+// policy is set. A token cache which cannot be created is logged and not used.
+func (r *Config) ApplyFlowOptions(flowConfig *discovery.OAuthFlowConfig, scopesSupported []string) {
+	flowConfig.FlowTimeout = r.FlowTimeout
+	if r.ScopePolicy != "" {
+		flowConfig.ScopePolicy = discovery.ScopePolicy(r.ScopePolicy)
+		flowConfig.ScopesSupported = scopesSupported
+	}
+	if r.CacheTokens {
+		tokenCache, err := oauth.NewDefaultSecretsTokenCache()
+		if err != nil {
+			logger.Warnf("OAuth tokens will not be cached: %v", err)
+			return
+		}
+		flowConfig.TokenCache = tokenCache
+	}
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for backward compatibility
@@ -60,6 +60,7 @@ func (h *Handler) Authenticate(ctx context.Context, remoteURL string) (oauth2.To
 				Resource:     h.config.Resource,
 				OAuthParams:  h.config.OAuthParams,
 			}
+			h.config.ApplyFlowOptions(flowConfig, authInfo.ScopesSupported)
 
 			// If we have discovered endpoints from the authorization server metadata,
 			// use them instead of trying to discover them again
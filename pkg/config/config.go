@@ -41,6 +41,9 @@ type Config struct {
 type Secrets struct {
 	ProviderType   string `yaml:"provider_type"`
 	SetupCompleted bool   `yaml:"setup_completed"`
+	// AutoFallbackProviderType is the provider the auto provider type resolves to outside Kubernetes
+	// when provider_type is auto. Defaults to none.
+	AutoFallbackProviderType string `yaml:"auto_fallback_provider_type,omitempty"`
 }
 
 // validateProviderType validates and returns the secrets provider type.
@@ -52,15 +55,25 @@ func validateProviderType(provider string) (secrets.ProviderType, error) {
 		return secrets.OnePasswordType, nil
 	case string(secrets.NoneType):
 		return secrets.NoneType, nil
+	case string(secrets.FileType):
+		return secrets.FileType, nil
+	case string(secrets.AutoType):
+		return secrets.AutoType, nil
 	default:
-		return "", fmt.Errorf("invalid secrets provider type: %s (valid types: %s, %s, %s)",
-			provider, string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType))
+		return "", fmt.Errorf("invalid secrets provider type: %s (valid types: %s, %s, %s, %s, %s)",
+			provider, string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType),
+			string(secrets.FileType), string(secrets.AutoType))
 	}
 }
 
 // GetProviderType returns the secrets provider type from the environment variable or application config.
-// It first checks the TOOLHIVE_SECRETS_PROVIDER environment variable, and falls back to the config file.
-// Returns ErrSecretsNotSetup if secrets have not been configured yet.
+// It first checks the TOOLHIVE_SECRETS_PROVIDER environment variable, and falls back to the provider_type
+// stored in the config file (e.g. ~/.config/toolhive/config.yaml), so that the provider does not need to be
+// exported in every shell. Returns ErrSecretsNotSetup if neither is set and setup has not been completed.
+//
+// The auto provider type is resolved with secrets.ResolveAutoProviderType. Outside Kubernetes, it falls back
+// to the provider_type of the config file when auto is set by the environment variable, and to the
+// auto_fallback_provider_type of the config file when the provider_type itself is auto.
 func (s *Secrets) GetProviderType() (secrets.ProviderType, error) {
 	return s.GetProviderTypeWithEnv(&env.OSReader{})
 }
@@ -68,18 +81,60 @@ func (s *Secrets) GetProviderType() (secrets.ProviderType, error) {
 // GetProviderTypeWithEnv returns the secrets provider type using the provided environment reader.
 // This method allows for dependency injection of environment variable access for testing.
 func (s *Secrets) GetProviderTypeWithEnv(envReader env.Reader) (secrets.ProviderType, error) {
+	// An explicit environment variable takes precedence over the config file, even before setup
+	if envVar := envReader.Getenv(secrets.ProviderEnvVar); envVar != "" {
+		providerType, err := validateProviderType(envVar)
+		if err != nil {
+			return "", err
+		}
+		if providerType == secrets.AutoType {
+			return secrets.ResolveAutoProviderTypeWithEnv(envReader, s.autoFallbackProviderType()), nil
+		}
+		return providerType, nil
+	}
+
 	// Check if secrets setup has been completed
 	if !s.SetupCompleted {
 		return "", secrets.ErrSecretsNotSetup
 	}
 
-	// First check the environment variable
-	if envVar := envReader.Getenv(secrets.ProviderEnvVar); envVar != "" {
-		return validateProviderType(envVar)
+	// Fall back to config file
+	providerType, err := validateProviderType(s.ProviderType)
+	if err != nil {
+		return "", err
+	}
+	if providerType == secrets.AutoType {
+		return secrets.ResolveAutoProviderTypeWithEnv(envReader, s.autoFallbackProviderType()), nil
 	}
+	return providerType, nil
+}
 
-	// Fall back to config file
-	return validateProviderType(s.ProviderType)
+// autoFallbackProviderType returns the provider type the auto provider type falls back to outside
+// Kubernetes: the configured provider type, or the configured auto fallback if the configured provider
+// type is auto itself. An empty type, standing for none, is returned if neither is valid.
+func (s *Secrets) autoFallbackProviderType() secrets.ProviderType {
+	configured := s.configuredProviderType()
+	if configured != secrets.AutoType {
+		return configured
+	}
+	fallback, err := validateProviderType(s.AutoFallbackProviderType)
+	if err != nil || fallback == secrets.AutoType {
+		return ""
+	}
+	return fallback
+}
+
+// configuredProviderType returns the provider type from the config file, or an empty
+// type if setup has not been completed or the configured type is invalid.
+func (s *Secrets) configuredProviderType() secrets.ProviderType {
+	if !s.SetupCompleted {
+		return ""
+	}
+	providerType, err := validateProviderType(s.ProviderType)
+	if err != nil {
+		return ""
+	}
+	return providerType
 }
 
 // Clients contains settings for client configuration.
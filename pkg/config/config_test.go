@@ -328,6 +328,103 @@ func TestSecrets_GetProviderType_EnvironmentVariable(t *testing.T) {
 		assert.Equal(t, secrets.NoneType, got, "Config should support none provider")
 	})
 
+	t.Run("Auto provider via environment variable in Kubernetes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEnv := mocks.NewMockReader(ctrl)
+		s := &Secrets{
+			ProviderType:   string(secrets.OnePasswordType),
+			SetupCompleted: true,
+		}
+
+		mockEnv.EXPECT().Getenv(secrets.ProviderEnvVar).Return(string(secrets.AutoType))
+		mockEnv.EXPECT().Getenv("TOOLHIVE_RUNTIME").Return("")
+		mockEnv.EXPECT().Getenv("KUBERNETES_SERVICE_HOST").Return("10.96.0.1")
+		got, err := s.GetProviderTypeWithEnv(mockEnv)
+		require.NoError(t, err)
+		assert.Equal(t, secrets.EnvironmentType, got, "Auto should resolve to the environment provider in Kubernetes")
+	})
+
+	t.Run("Auto provider via environment variable locally", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEnv := mocks.NewMockReader(ctrl)
+		s := &Secrets{
+			ProviderType:   string(secrets.OnePasswordType),
+			SetupCompleted: true,
+		}
+
+		mockEnv.EXPECT().Getenv(secrets.ProviderEnvVar).Return(string(secrets.AutoType))
+		mockEnv.EXPECT().Getenv("TOOLHIVE_RUNTIME").Return("")
+		mockEnv.EXPECT().Getenv("KUBERNETES_SERVICE_HOST").Return("")
+		got, err := s.GetProviderTypeWithEnv(mockEnv)
+		require.NoError(t, err)
+		assert.Equal(t, secrets.OnePasswordType, got, "Auto should resolve to the configured provider locally")
+	})
+
+	t.Run("Auto provider via config without Kubernetes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEnv := mocks.NewMockReader(ctrl)
+		s := &Secrets{
+			ProviderType:   string(secrets.AutoType),
+			SetupCompleted: true,
+		}
+
+		mockEnv.EXPECT().Getenv(secrets.ProviderEnvVar).Return("")
+		mockEnv.EXPECT().Getenv("TOOLHIVE_RUNTIME").Return("")
+		mockEnv.EXPECT().Getenv("KUBERNETES_SERVICE_HOST").Return("")
+		got, err := s.GetProviderTypeWithEnv(mockEnv)
+		require.NoError(t, err)
+		assert.Equal(t, secrets.NoneType, got, "Auto should resolve to none locally without a configured provider")
+	})
+
+	t.Run("Auto provider via config with a fallback", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEnv := mocks.NewMockReader(ctrl)
+		s := &Secrets{
+			ProviderType:             string(secrets.AutoType),
+			SetupCompleted:           true,
+			AutoFallbackProviderType: string(secrets.EncryptedType),
+		}
+
+		mockEnv.EXPECT().Getenv(secrets.ProviderEnvVar).Return("")
+		mockEnv.EXPECT().Getenv("TOOLHIVE_RUNTIME").Return("")
+		mockEnv.EXPECT().Getenv("KUBERNETES_SERVICE_HOST").Return("")
+		got, err := s.GetProviderTypeWithEnv(mockEnv)
+		require.NoError(t, err)
+		assert.Equal(t, secrets.EncryptedType, got, "Auto should resolve to the configured fallback locally")
+	})
+
+	t.Run("Auto provider via environment variable and config", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEnv := mocks.NewMockReader(ctrl)
+		s := &Secrets{
+			ProviderType:             string(secrets.AutoType),
+			SetupCompleted:           true,
+			AutoFallbackProviderType: string(secrets.OnePasswordType),
+		}
+
+		mockEnv.EXPECT().Getenv(secrets.ProviderEnvVar).Return(string(secrets.AutoType))
+		mockEnv.EXPECT().Getenv("TOOLHIVE_RUNTIME").Return("")
+		mockEnv.EXPECT().Getenv("KUBERNETES_SERVICE_HOST").Return("")
+		got, err := s.GetProviderTypeWithEnv(mockEnv)
+		require.NoError(t, err)
+		assert.Equal(t, secrets.OnePasswordType, got, "Auto should resolve to the configured fallback locally")
+	})
+
 	t.Run("Invalid environment variable returns error", func(t *testing.T) {
 		t.Parallel()
 		ctrl := gomock.NewController(t)
@@ -356,9 +453,72 @@ func TestSecrets_GetProviderType_EnvironmentVariable(t *testing.T) {
 			SetupCompleted: false,
 		}
 
-		// No expectation needed since the function returns early when SetupCompleted is false
+		mockEnv.EXPECT().Getenv(secrets.ProviderEnvVar).Return("")
 		_, err := s.GetProviderTypeWithEnv(mockEnv)
 		assert.Error(t, err, "Should return error when setup not completed")
 		assert.ErrorIs(t, err, secrets.ErrSecretsNotSetup, "Should return ErrSecretsNotSetup when setup not completed")
 	})
 }
+
+func TestSecrets_GetProviderType_ConfigFile(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	tests := []struct {
+		name       string
+		fileConfig *Config
+		envValue   string
+		expected   secrets.ProviderType
+		expectErr  error
+	}{
+		{
+			name:     "environment variable only",
+			envValue: string(secrets.OnePasswordType),
+			expected: secrets.OnePasswordType,
+		},
+		{
+			name: "config file only",
+			fileConfig: &Config{Secrets: Secrets{
+				ProviderType:   string(secrets.EncryptedType),
+				SetupCompleted: true,
+			}},
+			expected: secrets.EncryptedType,
+		},
+		{
+			name: "environment variable wins over config file",
+			fileConfig: &Config{Secrets: Secrets{
+				ProviderType:   string(secrets.EncryptedType),
+				SetupCompleted: true,
+			}},
+			envValue: string(secrets.NoneType),
+			expected: secrets.NoneType,
+		},
+		{
+			name:      "neither configured",
+			expectErr: secrets.ErrSecretsNotSetup,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_, configPath := SetupTestConfig(t, tt.fileConfig)
+			cfg, err := LoadOrCreateConfigFromPath(configPath)
+			require.NoError(t, err)
+
+			mockEnv := mocks.NewMockReader(ctrl)
+			mockEnv.EXPECT().Getenv(secrets.ProviderEnvVar).Return(tt.envValue)
+
+			got, err := cfg.Secrets.GetProviderTypeWithEnv(mockEnv)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -258,9 +258,9 @@ func (c *Client) DeployWorkload(ctx context.Context,
 	attachStdio := options == nil || options.AttachStdio
 
 	// Convert environment variables to Kubernetes format
-	var envVarList []*corev1apply.EnvVarApplyConfiguration
-	for k, v := range envVars {
-		envVarList = append(envVarList, corev1apply.EnvVar().WithName(k).WithValue(v))
+	envVarList, err := envVarApplyConfigurations(envVars)
+	if err != nil {
+		return 0, err
 	}
 
 	// Create a pod template spec
@@ -268,7 +268,6 @@ func (c *Client) DeployWorkload(ctx context.Context,
 
 	// Apply the patch if provided
 	if options != nil && options.K8sPodTemplatePatch != "" {
-		var err error
 		podTemplateSpec, err = applyPodTemplatePatch(podTemplateSpec, options.K8sPodTemplatePatch)
 		if err != nil {
 			return 0, fmt.Errorf("failed to apply pod template patch: %w", err)
@@ -911,6 +910,9 @@ func applyPodTemplatePatch(
 	if patchedSpec.ObjectMetaApplyConfiguration != nil && len(patchedSpec.Labels) > 0 {
 		baseTemplate = baseTemplate.WithLabels(patchedSpec.Labels)
 	}
+	if patchedSpec.ObjectMetaApplyConfiguration != nil && len(patchedSpec.Annotations) > 0 {
+		baseTemplate = baseTemplate.WithAnnotations(patchedSpec.Annotations)
+	}
 
 	if patchedSpec.Spec != nil {
 		// Ensure baseTemplate.Spec is not nil
@@ -1072,6 +1074,10 @@ func configureContainer(
 	}
 	logger.Debugf("AttachStdio: %v", attachStdio)
 	for _, envVar := range envVars {
+		if envVar.Value == nil {
+			logger.Debugf("EnvVar: %s (from a Kubernetes Secret)", *envVar.Name)
+			continue
+		}
 		logger.Debugf("EnvVar: %s=%s", *envVar.Name, *envVar.Value)
 	}
 
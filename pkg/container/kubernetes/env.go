@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+// secretReferenceEnvVarPrefix is the prefix of the env vars holding the secrets referenced by other env vars
+const secretReferenceEnvVarPrefix = "TOOLHIVE_SECRET_"
+
+// envVarApplyConfigurations converts environment variables to Kubernetes env vars, sorted by name.
+// The `${secret:<secret>/<key>}` references of their values are read from the key of the Kubernetes Secret:
+// each referenced secret is exposed through a secretKeyRef env var, which the referencing env var
+// uses through the Kubernetes `$(VAR)` dependent env var syntax.
+func envVarApplyConfigurations(envVars map[string]string) ([]*corev1apply.EnvVarApplyConfiguration, error) {
+	names := make([]string, 0, len(envVars))
+	for name := range envVars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// The secretKeyRef env vars must be defined before the env vars referencing them
+	var secretEnvVars, envVarList []*corev1apply.EnvVarApplyConfiguration
+	secretRefs := make(map[string]string)
+	for _, name := range names {
+		value, err := secrets.ExpandSecretReferences(envVars[name], func(ref string) (string, error) {
+			secretName, key, ok := strings.Cut(ref, "/")
+			if !ok || secretName == "" || key == "" || strings.Contains(key, "/") {
+				return "", fmt.Errorf("secret reference %s must be of the form <secret>/<key>", ref)
+			}
+			secretEnvVar := secretReferenceEnvVarName(ref)
+			if other, ok := secretRefs[secretEnvVar]; !ok {
+				secretRefs[secretEnvVar] = ref
+				secretEnvVars = append(secretEnvVars, corev1apply.EnvVar().
+					WithName(secretEnvVar).
+					WithValueFrom(corev1apply.EnvVarSource().
+						WithSecretKeyRef(corev1apply.SecretKeySelector().WithName(secretName).WithKey(key))))
+			} else if other != ref {
+				return "", fmt.Errorf("secret references %s and %s both map to the env var %s", other, ref, secretEnvVar)
+			}
+			return "$(" + secretEnvVar + ")", nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment variable %s: %w", name, err)
+		}
+		envVarList = append(envVarList, corev1apply.EnvVar().WithName(name).WithValue(value))
+	}
+	return append(secretEnvVars, envVarList...), nil
+}
+
+// secretReferenceEnvVarName returns the name of the env var holding the secret referenced as ref
+func secretReferenceEnvVarName(ref string) string {
+	return secretReferenceEnvVarPrefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, ref)
+}
@@ -0,0 +1,57 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+func TestEnvVarApplyConfigurations(t *testing.T) {
+	t.Parallel()
+
+	envVars, err := envVarApplyConfigurations(map[string]string{
+		"PLAIN":        "value",
+		"AUTH_HEADER":  "Bearer ${secret:token/value}",
+		"BASIC_AUTH":   "${secret:creds/user}:${secret:creds/password}",
+		"SECOND_TOKEN": "${secret:token/value}",
+	})
+	require.NoError(t, err)
+
+	secretEnvVar := func(name, secret, key string) *corev1apply.EnvVarApplyConfiguration {
+		return corev1apply.EnvVar().WithName(name).WithValueFrom(corev1apply.EnvVarSource().
+			WithSecretKeyRef(corev1apply.SecretKeySelector().WithName(secret).WithKey(key)))
+	}
+	// The secretKeyRef env vars come first, so that Kubernetes can expand the references to them
+	assert.Equal(t, []*corev1apply.EnvVarApplyConfiguration{
+		secretEnvVar("TOOLHIVE_SECRET_TOKEN_VALUE", "token", "value"),
+		secretEnvVar("TOOLHIVE_SECRET_CREDS_USER", "creds", "user"),
+		secretEnvVar("TOOLHIVE_SECRET_CREDS_PASSWORD", "creds", "password"),
+		corev1apply.EnvVar().WithName("AUTH_HEADER").WithValue("Bearer $(TOOLHIVE_SECRET_TOKEN_VALUE)"),
+		corev1apply.EnvVar().WithName("BASIC_AUTH").
+			WithValue("$(TOOLHIVE_SECRET_CREDS_USER):$(TOOLHIVE_SECRET_CREDS_PASSWORD)"),
+		corev1apply.EnvVar().WithName("PLAIN").WithValue("value"),
+		corev1apply.EnvVar().WithName("SECOND_TOKEN").WithValue("$(TOOLHIVE_SECRET_TOKEN_VALUE)"),
+	}, envVars)
+}
+
+func TestEnvVarApplyConfigurations_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"missing key":         "${secret:token}",
+		"nested key":          "${secret:token/nested/value}",
+		"conflicting names":   "${secret:api-token/value} ${secret:api_token/value}",
+		"empty reference":     "${secret:}",
+		"missing secret name": "${secret:/value}",
+	}
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := envVarApplyConfigurations(map[string]string{"AUTH_HEADER": value})
+			assert.ErrorContains(t, err, "invalid environment variable AUTH_HEADER")
+		})
+	}
+}
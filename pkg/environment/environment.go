@@ -12,7 +12,8 @@ import (
 
 // ParseSecretParameters parses the secret parameters from the command line,
 // fetches them from the secrets manager, and returns a map of secrets and
-// their environment variable names.
+// their environment variable names. Vault-typed secrets are skipped, as their
+// values are rendered by the Vault Agent rather than held by the secrets manager.
 func ParseSecretParameters(ctx context.Context, parameters []string, secretsManager secrets.Provider) (map[string]string, error) {
 	secretVariables := make(map[string]string, len(parameters))
 	for _, param := range parameters {
@@ -20,8 +21,11 @@ func ParseSecretParameters(ctx context.Context, parameters []string, secretsMana
 		if err != nil {
 			return nil, err
 		}
+		if parameter.IsVault() {
+			continue
+		}
 
-		secret, err := secretsManager.GetSecret(ctx, parameter.Name)
+		secret, err := parameter.Resolve(ctx, secretsManager)
 		if err != nil {
 			return nil, err
 		}
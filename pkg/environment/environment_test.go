@@ -79,6 +79,19 @@ func TestParseSecretParameters(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:       "Vault secrets are skipped",
+			parameters: []string{"secret1,target=ENV_VAR1", "workload/github,target=GITHUB_TOKEN,type=vault"},
+			provider: &mockSecretsProvider{
+				secrets: map[string]string{
+					"secret1": "value1",
+				},
+			},
+			want: map[string]string{
+				"ENV_VAR1": "value1",
+			},
+			wantErr: false,
+		},
 		{
 			name:       "Invalid parameter format",
 			parameters: []string{"invalid-format"},
@@ -162,7 +162,6 @@ func runConfigToMCPServer(config *runner.RunConfig) (*v1alpha1.MCPServer, error)
 
 		if config.TelemetryConfig.Endpoint != "" {
 			mcpServer.Spec.Telemetry.OpenTelemetry = &v1alpha1.OpenTelemetryConfig{
-				Enabled:  true,
 				Endpoint: config.TelemetryConfig.Endpoint,
 				Insecure: config.TelemetryConfig.Insecure,
 			}
@@ -218,7 +218,7 @@ func TestWriteK8sManifest(t *testing.T) {
 				t.Helper()
 				require.NotNil(t, mcpServer.Spec.Telemetry)
 				require.NotNil(t, mcpServer.Spec.Telemetry.OpenTelemetry)
-				assert.True(t, mcpServer.Spec.Telemetry.OpenTelemetry.Enabled)
+				assert.True(t, mcpServer.Spec.Telemetry.OpenTelemetry.IsEnabled())
 				assert.Equal(t, "http://otel-collector:4318", mcpServer.Spec.Telemetry.OpenTelemetry.Endpoint)
 				assert.Equal(t, "my-service", mcpServer.Spec.Telemetry.OpenTelemetry.ServiceName)
 				assert.True(t, mcpServer.Spec.Telemetry.OpenTelemetry.Insecure)
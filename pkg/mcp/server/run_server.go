@@ -11,6 +11,7 @@ import (
 	types "github.com/stacklok/toolhive/pkg/registry/registry"
 	"github.com/stacklok/toolhive/pkg/runner"
 	"github.com/stacklok/toolhive/pkg/runner/retriever"
+	"github.com/stacklok/toolhive/pkg/secrets"
 	transporttypes "github.com/stacklok/toolhive/pkg/transport/types"
 )
 
@@ -181,13 +182,13 @@ func prepareSecrets(secretMappings []SecretMapping) []string {
 		return nil
 	}
 
-	secrets := make([]string, len(secretMappings))
+	result := make([]string, len(secretMappings))
 	for i, mapping := range secretMappings {
 		// Convert to the format expected by runner: "secret_name,target=ENV_VAR_NAME"
-		secrets[i] = fmt.Sprintf("%s,target=%s", mapping.Name, mapping.Target)
+		result[i] = secrets.SecretParameter{Name: mapping.Name, Target: mapping.Target}.ToCLIString()
 	}
 
-	return secrets
+	return result
 }
 
 // saveAndRunServer saves the configuration and runs the server
@@ -102,6 +102,47 @@ func IsLocalhost(host string) bool {
 		host == "[::1]"
 }
 
+// ValidateListenHost checks that host is an IP address or a valid hostname a server can bind to.
+// Ports, schemes and paths are not accepted.
+func ValidateListenHost(host string) error {
+	if host == "" {
+		return errors.New("host cannot be empty")
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+
+	if len(host) > 253 {
+		return fmt.Errorf("hostname is longer than 253 characters")
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if !isValidHostnameLabel(label) {
+			return fmt.Errorf("%q is not a valid IP address or hostname", host)
+		}
+	}
+	return nil
+}
+
+// isValidHostnameLabel checks a single DNS label as defined by RFC 1123
+func isValidHostnameLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		isAlphaNum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlphaNum && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsUnspecifiedHost checks if a host binds to all interfaces (0.0.0.0 or ::)
+func IsUnspecifiedHost(host string) bool {
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
 // IsURL checks if the input is a valid HTTP or HTTPS URL
 func IsURL(input string) bool {
 	parsedURL, err := url.Parse(input)
@@ -487,3 +487,37 @@ func TestValidateEndpointURL(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateListenHost(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		host        string
+		expectError bool
+	}{
+		{name: "IPv4 all interfaces", host: "0.0.0.0", expectError: false},
+		{name: "IPv4 loopback", host: "127.0.0.1", expectError: false},
+		{name: "IPv6 loopback", host: "::1", expectError: false},
+		{name: "localhost", host: "localhost", expectError: false},
+		{name: "fully qualified hostname", host: "mcp.example.com", expectError: false},
+		{name: "empty host", host: "", expectError: true},
+		{name: "host with port", host: "localhost:8080", expectError: true},
+		{name: "URL", host: "http://localhost", expectError: true},
+		{name: "label starting with hyphen", host: "-bad.example.com", expectError: true},
+		{name: "invalid characters", host: "bad_host!", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateListenHost(tt.host)
+			if tt.expectError {
+				assert.Error(t, err, "Expected error for host: %s", tt.host)
+			} else {
+				assert.NoError(t, err, "Expected no error for host: %s", tt.host)
+			}
+		})
+	}
+}
@@ -2,10 +2,19 @@
 package runner
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/yaml"
 
 	"github.com/stacklok/toolhive/pkg/audit"
 	"github.com/stacklok/toolhive/pkg/auth"
@@ -24,6 +33,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/secrets"
 	"github.com/stacklok/toolhive/pkg/state"
 	"github.com/stacklok/toolhive/pkg/telemetry"
+	"github.com/stacklok/toolhive/pkg/transport"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 	workloadtypes "github.com/stacklok/toolhive/pkg/workloads/types"
 )
@@ -32,6 +42,9 @@ import (
 // TODO: Set to "v1.0.0" when we clean up the middleware configuration.
 const CurrentSchemaVersion = "v0.1.0"
 
+// maxConcurrentSecretResolutions bounds the number of secrets resolved in parallel by WithSecrets
+const maxConcurrentSecretResolutions = 8
+
 // RunConfig contains all the configuration needed to run an MCP server
 // It is serializable to JSON and YAML
 // NOTE: This format is importable and exportable, and as a result should be
@@ -88,6 +101,21 @@ type RunConfig struct {
 	// EnvFileDir is the directory path to load environment files from
 	EnvFileDir string `json:"env_file_dir,omitempty" yaml:"env_file_dir,omitempty"`
 
+	// EnvFileParseOptions controls how KEY=VALUE environment files are parsed.
+	// Defaults to DefaultEnvFileParseOptions if not set.
+	EnvFileParseOptions *EnvFileParseOptions `json:"env_file_parse_options,omitempty" yaml:"env_file_parse_options,omitempty"`
+
+	// EnvFileTopLevelOnly ignores nested objects in JSON environment files instead of flattening them
+	EnvFileTopLevelOnly bool `json:"env_file_top_level_only,omitempty" yaml:"env_file_top_level_only,omitempty"`
+
+	// EnvFileMaxSize is the maximum size in bytes of a single environment file.
+	// Larger files are skipped. Defaults to DefaultEnvFileMaxSize if not set.
+	EnvFileMaxSize int64 `json:"env_file_max_size,omitempty" yaml:"env_file_max_size,omitempty"`
+
+	// EnvFileMaxFiles is the maximum number of environment files processed from EnvFileDir.
+	// Defaults to DefaultEnvFileMaxFiles if not set.
+	EnvFileMaxFiles int `json:"env_file_max_files,omitempty" yaml:"env_file_max_files,omitempty"`
+
 	// Debug indicates whether debug mode is enabled
 	Debug bool `json:"debug,omitempty" yaml:"debug,omitempty"`
 
@@ -142,6 +170,10 @@ type RunConfig struct {
 	// ProxyMode is the proxy mode for stdio transport ("sse" or "streamable-http")
 	ProxyMode types.ProxyMode `json:"proxy_mode,omitempty" yaml:"proxy_mode,omitempty"`
 
+	// ShutdownGracePeriod is how long the proxy waits for active client connections to drain
+	// on shutdown before forcibly closing them. Defaults to transport.DefaultShutdownGracePeriod if not set.
+	ShutdownGracePeriod time.Duration `json:"shutdown_grace_period,omitempty" yaml:"shutdown_grace_period,omitempty"`
+
 	// ThvCABundle is the path to the CA certificate bundle for ToolHive HTTP operations
 	ThvCABundle string `json:"thv_ca_bundle,omitempty" yaml:"thv_ca_bundle,omitempty"`
 
@@ -163,6 +195,10 @@ type RunConfig struct {
 	// MiddlewareConfigs contains the list of middleware to apply to the transport
 	// and the configuration for each middleware.
 	MiddlewareConfigs []types.MiddlewareConfig `json:"middleware_configs,omitempty" yaml:"middleware_configs,omitempty"`
+
+	// secretEnvVars are the names of the environment variables whose values were resolved from secrets,
+	// which are masked by Redacted
+	secretEnvVars map[string]bool
 }
 
 // WriteJSON serializes the RunConfig to JSON and writes it to the provided writer
@@ -215,6 +251,81 @@ func ReadJSON(r io.Reader) (*RunConfig, error) {
 	return &config, nil
 }
 
+// RunConfigFormat is a serialization format of the RunConfig
+type RunConfigFormat string
+
+const (
+	// RunConfigFormatJSON is the JSON serialization format
+	RunConfigFormatJSON RunConfigFormat = "json"
+	// RunConfigFormatYAML is the YAML serialization format
+	RunConfigFormatYAML RunConfigFormat = "yaml"
+)
+
+// RunConfigFormatFromPath returns the serialization format of a RunConfig file from its extension,
+// YAML for .yaml and .yml files and JSON otherwise
+func RunConfigFormatFromPath(path string) RunConfigFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return RunConfigFormatYAML
+	default:
+		return RunConfigFormatJSON
+	}
+}
+
+// MarshalRunConfig serializes the RunConfig in the given format. The YAML serialization is converted from
+// the JSON one, so that both formats use the same field names and encodings.
+func MarshalRunConfig(c *RunConfig, format RunConfigFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.WriteJSON(&buf); err != nil {
+		return nil, fmt.Errorf("failed to marshal run configuration: %w", err)
+	}
+
+	switch format {
+	case RunConfigFormatJSON:
+		return buf.Bytes(), nil
+	case RunConfigFormatYAML:
+		data, err := yaml.JSONToYAML(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert run configuration to YAML: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported run configuration format: %s", format)
+	}
+}
+
+// UnmarshalRunConfig deserializes a RunConfig in the given format, as ReadJSON does, and validates it
+func UnmarshalRunConfig(data []byte, format RunConfigFormat) (*RunConfig, error) {
+	switch format {
+	case RunConfigFormatJSON:
+	case RunConfigFormatYAML:
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert run configuration from YAML: %w", err)
+		}
+		data = converted
+	default:
+		return nil, fmt.Errorf("unsupported run configuration format: %s", format)
+	}
+
+	config, err := ReadJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal run configuration: %w", err)
+	}
+
+	if config.Transport != "" {
+		transport, err := types.ParseTransportType(string(config.Transport))
+		if err != nil {
+			return nil, fmt.Errorf("invalid run configuration transport %q: %w", config.Transport, err)
+		}
+		config.Transport = transport
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid run configuration: %w", err)
+	}
+	return config, nil
+}
+
 // migrateOAuthClientSecret migrates plain text OAuth client secrets to CLI format
 // This handles the transition from storing plain text secrets to CLI format references
 func migrateOAuthClientSecret(config *RunConfig) error {
@@ -343,6 +454,21 @@ func (c *RunConfig) WithEnvironmentVariables(envVars map[string]string) (*RunCon
 	return c, nil
 }
 
+// Validate checks that the RunConfig settings are usable by the proxy
+func (c *RunConfig) Validate() error {
+	if c.Host != "" {
+		if err := networking.ValidateListenHost(c.Host); err != nil {
+			return fmt.Errorf("invalid proxy host: %w", err)
+		}
+		if networking.IsUnspecifiedHost(c.Host) {
+			logger.Warnf("Proxy is listening on all interfaces (%s); use %s to only accept local connections",
+				c.Host, transport.LocalhostIPv4)
+		}
+	}
+
+	return nil
+}
+
 // ValidateSecrets checks if the secrets can be parsed and are valid
 func (c *RunConfig) ValidateSecrets(ctx context.Context, secretManager secrets.Provider) error {
 	if len(c.Secrets) > 0 {
@@ -361,11 +487,29 @@ func (c *RunConfig) ValidateSecrets(ctx context.Context, secretManager secrets.P
 	return nil
 }
 
-// WithSecrets processes secrets and adds them to environment variables
+// WithSecrets processes secrets and adds them to environment variables.
+// Binary secrets cannot be injected into environment variables, resolving them fails.
+// The `${secret:<ref>}` references of the environment variable values are replaced with the secrets
+// they name, except on Kubernetes where the Kubernetes runtime reads them from Kubernetes Secrets.
 func (c *RunConfig) WithSecrets(ctx context.Context, secretManager secrets.Provider) (*RunConfig, error) {
+	// References are resolved before the secrets are added, so that secret values are never expanded
+	if !rt.IsKubernetesRuntime() {
+		for name, value := range c.EnvVars {
+			if !secrets.HasSecretReferences(value) {
+				continue
+			}
+			resolved, err := secrets.ResolveSecretReferences(ctx, value, secretManager)
+			if err != nil {
+				return c, fmt.Errorf("failed to resolve environment variable %s: %w", name, err)
+			}
+			c.EnvVars[name] = resolved
+			c.markSecretEnvVar(name)
+		}
+	}
+
 	// Process regular secrets if provided
 	if len(c.Secrets) > 0 {
-		secretVariables, err := environment.ParseSecretParameters(ctx, c.Secrets, secretManager)
+		secretVariables, err := resolveSecretParameters(ctx, c.Secrets, secretManager)
 		if err != nil {
 			return c, fmt.Errorf("failed to get secrets: %v", err)
 		}
@@ -378,6 +522,7 @@ func (c *RunConfig) WithSecrets(ctx context.Context, secretManager secrets.Provi
 		// Add secret variables to environment variables
 		for key, value := range secretVariables {
 			c.EnvVars[key] = value
+			c.markSecretEnvVar(key)
 		}
 	}
 
@@ -386,7 +531,7 @@ func (c *RunConfig) WithSecrets(ctx context.Context, secretManager secrets.Provi
 		// Check if it's in CLI format (contains ",target=")
 		if secretParam, err := secrets.ParseSecretParameter(c.RemoteAuthConfig.ClientSecret); err == nil {
 			// It's in CLI format, resolve the actual secret value
-			actualSecret, err := secretManager.GetSecret(ctx, secretParam.Name)
+			actualSecret, err := secretParam.Resolve(ctx, secretManager)
 			if err != nil {
 				return c, fmt.Errorf("failed to resolve OAuth client secret '%s': %w", secretParam.Name, err)
 			}
@@ -399,6 +544,206 @@ func (c *RunConfig) WithSecrets(ctx context.Context, secretManager secrets.Provi
 	return c, nil
 }
 
+// markSecretEnvVar records that the value of the environment variable was resolved from secrets
+func (c *RunConfig) markSecretEnvVar(name string) {
+	if c.secretEnvVars == nil {
+		c.secretEnvVars = make(map[string]bool)
+	}
+	c.secretEnvVars[name] = true
+}
+
+// Redacted returns a deep copy of the effective RunConfig which is safe to print: the values of the environment
+// variables resolved from secrets, including those loaded from EnvFileDir, the client secrets and the OTLP
+// headers are masked, including those in the middleware parameters.
+func (c *RunConfig) Redacted() (*RunConfig, error) {
+	redacted, err := c.deepCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	masked := make(map[string]bool, len(c.secretEnvVars)+len(c.Secrets))
+	for name := range c.secretEnvVars {
+		masked[name] = true
+	}
+	// The targets of the secret parameters are masked even if the config was not resolved by this process
+	for _, param := range c.Secrets {
+		if parameter, err := secrets.ParseSecretParameter(param); err == nil {
+			masked[parameter.Target] = true
+		}
+	}
+
+	for name, value := range redacted.EnvVars {
+		if masked[name] {
+			redacted.EnvVars[name] = authoauth.Redact(value)
+		}
+	}
+	redacted.secretEnvVars = nil
+
+	// Secret parameters in CLI format are references, not secret values
+	if redacted.RemoteAuthConfig != nil {
+		if _, err := secrets.ParseSecretParameter(redacted.RemoteAuthConfig.ClientSecret); err != nil {
+			redacted.RemoteAuthConfig.ClientSecret = authoauth.Redact(redacted.RemoteAuthConfig.ClientSecret)
+		}
+	}
+	if redacted.OIDCConfig != nil {
+		redacted.OIDCConfig.ClientSecret = authoauth.Redact(redacted.OIDCConfig.ClientSecret)
+	}
+	if redacted.TokenExchangeConfig != nil {
+		redacted.TokenExchangeConfig.ClientSecret = authoauth.Redact(redacted.TokenExchangeConfig.ClientSecret)
+	}
+	// The OTLP headers carry credentials such as API keys
+	if redacted.TelemetryConfig != nil {
+		for name, value := range redacted.TelemetryConfig.Headers {
+			redacted.TelemetryConfig.Headers[name] = authoauth.Redact(value)
+		}
+	}
+	for i, middleware := range redacted.MiddlewareConfigs {
+		parameters, err := redactMiddlewareParameters(middleware.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redact the parameters of the %s middleware: %w", middleware.Type, err)
+		}
+		redacted.MiddlewareConfigs[i].Parameters = parameters
+	}
+
+	return redacted, nil
+}
+
+// redactMiddlewareParameters masks the client secrets and the header values found at any depth of
+// the middleware parameters, such as those of the authentication, token exchange and telemetry middleware.
+func redactMiddlewareParameters(parameters json.RawMessage) (json.RawMessage, error) {
+	if len(parameters) == 0 {
+		return parameters, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(parameters))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactSecretValues(value))
+}
+
+// redactSecretValues masks in place the client secrets and the header values of a decoded JSON value
+func redactSecretValues(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			switch strings.ToLower(strings.ReplaceAll(key, "_", "")) {
+			case "clientsecret":
+				if secret, ok := nested.(string); ok {
+					v[key] = authoauth.Redact(secret)
+				}
+			case "headers":
+				if headers, ok := nested.(map[string]any); ok {
+					for name, header := range headers {
+						if headerValue, ok := header.(string); ok {
+							headers[name] = authoauth.Redact(headerValue)
+						}
+					}
+				}
+			default:
+				v[key] = redactSecretValues(nested)
+			}
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = redactSecretValues(nested)
+		}
+	}
+	return value
+}
+
+// deepCopy returns a copy of c which shares no maps, slices or nested configurations with it.
+// The copy is made through the JSON representation used to persist the RunConfig, the fields
+// which are not persisted are copied separately and the deployer is shared.
+func (c *RunConfig) deepCopy() (*RunConfig, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy run configuration: %w", err)
+	}
+	var copied RunConfig
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, fmt.Errorf("failed to copy run configuration: %w", err)
+	}
+
+	copied.Deployer = c.Deployer
+	copied.buildContext = c.buildContext
+	copied.secretEnvVars = maps.Clone(c.secretEnvVars)
+	return &copied, nil
+}
+
+// hasEnvVarSecretReferences returns true if any of the environment variable values references
+// secrets with `${secret:<ref>}` tokens
+func hasEnvVarSecretReferences(envVars map[string]string) bool {
+	for _, value := range envVars {
+		if secrets.HasSecretReferences(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// needsSecretsProvider returns true if any of the secret parameters must be resolved through
+// the secrets provider. Unparsable parameters count, so that their errors are still reported.
+func needsSecretsProvider(parameters []string) bool {
+	for _, param := range parameters {
+		parameter, err := secrets.ParseSecretParameter(param)
+		if err != nil || !parameter.IsVault() {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSecretParameters resolves the values of `<name>,target=<target>` secret parameters using up to
+// maxConcurrentSecretResolutions concurrent GetSecret calls, and returns them keyed by target.
+// If the same target is used several times, the last parameter wins, as with sequential resolution.
+// The first error cancels the context passed to the remaining calls.
+// Vault-typed parameters are not resolved: their values are rendered by the Vault Agent into
+// EnvFileDir and loaded as environment variables.
+func resolveSecretParameters(
+	ctx context.Context,
+	parameters []string,
+	secretManager secrets.Provider,
+) (map[string]string, error) {
+	// Parse every parameter up front so that a malformed one fails before any secret is resolved
+	parsed := make([]secrets.SecretParameter, 0, len(parameters))
+	for _, param := range parameters {
+		parameter, err := secrets.ParseSecretParameter(param)
+		if err != nil {
+			return nil, err
+		}
+		if parameter.IsVault() {
+			continue
+		}
+		parsed = append(parsed, parameter)
+	}
+
+	values := make([]string, len(parsed))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentSecretResolutions)
+	for i, parameter := range parsed {
+		g.Go(func() error {
+			value, err := parameter.Resolve(ctx, secretManager)
+			if err != nil {
+				return err
+			}
+			values[i] = value
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	secretVariables := make(map[string]string, len(parsed))
+	for i, parameter := range parsed {
+		secretVariables[parameter.Target] = values[i]
+	}
+	return secretVariables, nil
+}
+
 // mergeEnvVars is a helper method to merge environment variables into RunConfig
 func (c *RunConfig) mergeEnvVars(envVars map[string]string) *RunConfig {
 	// Initialize EnvVars if it's nil
@@ -415,18 +760,57 @@ func (c *RunConfig) mergeEnvVars(envVars map[string]string) *RunConfig {
 }
 
 // WithEnvFilesFromDirectory processes environment files from a directory and adds them to environment variables
-func (c *RunConfig) WithEnvFilesFromDirectory(dirPath string) (*RunConfig, error) {
-	envVars, err := processEnvFilesDirectory(dirPath)
+func (c *RunConfig) WithEnvFilesFromDirectory(ctx context.Context, dirPath string) (*RunConfig, error) {
+	if _, _, err := c.WithEnvFilesFromDirectoryDetailed(ctx, dirPath); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// WithEnvFilesFromDirectoryDetailed processes environment files from a directory like
+// WithEnvFilesFromDirectory, and reports the keys that were loaded and, among them, the keys
+// that replaced an environment variable already set on the config. Both lists are sorted.
+// Only keys are reported, never values, so the result is safe to log.
+func (c *RunConfig) WithEnvFilesFromDirectoryDetailed(
+	ctx context.Context,
+	dirPath string,
+) (loaded []string, overridden []string, err error) {
+	envVars, err := processEnvFilesDirectory(ctx, dirPath, c.envFileOptions())
 	if err != nil {
-		return c, fmt.Errorf("failed to process env files from %s: %w", dirPath, err)
+		return nil, nil, fmt.Errorf("failed to process env files from %s: %w", dirPath, err)
 	}
 
-	return c.mergeEnvVars(envVars), nil
+	loaded = make([]string, 0, len(envVars))
+	for key := range envVars {
+		loaded = append(loaded, key)
+		if _, exists := c.EnvVars[key]; exists {
+			overridden = append(overridden, key)
+		}
+	}
+	slices.Sort(loaded)
+	slices.Sort(overridden)
+
+	// The env files of the directory hold secrets, e.g. those rendered by the Vault Agent
+	c.mergeEnvVars(envVars)
+	for key := range envVars {
+		c.markSecretEnvVar(key)
+	}
+	return loaded, overridden, nil
+}
+
+// envFileOptions returns the options used to process environment files
+func (c *RunConfig) envFileOptions() envFileOptions {
+	return envFileOptions{
+		parse:            c.EnvFileParseOptions,
+		jsonTopLevelOnly: c.EnvFileTopLevelOnly,
+		maxFileSize:      c.EnvFileMaxSize,
+		maxFiles:         c.EnvFileMaxFiles,
+	}
 }
 
 // WithEnvFile processes a single environment file and adds it to environment variables
 func (c *RunConfig) WithEnvFile(filePath string) (*RunConfig, error) {
-	envVars, err := processEnvFile(filePath)
+	envVars, err := processEnvFile(filePath, c.envFileOptions())
 	if err != nil {
 		return c, fmt.Errorf("failed to process env file %s: %w", filePath, err)
 	}
@@ -496,6 +880,21 @@ func LoadState(ctx context.Context, name string) (*RunConfig, error) {
 	return state.LoadRunConfig(ctx, name, ReadJSON)
 }
 
+// SaveEffectiveState records the redacted copy of the effective run configuration, once the secrets and
+// env files are resolved, so that it can be inspected with LoadEffectiveState
+func (c *RunConfig) SaveEffectiveState(ctx context.Context) error {
+	redacted, err := c.Redacted()
+	if err != nil {
+		return err
+	}
+	return state.SaveEffectiveRunConfig(ctx, redacted)
+}
+
+// LoadEffectiveState loads the redacted effective run configuration recorded by the runner of a workload
+func LoadEffectiveState(ctx context.Context, name string) (*RunConfig, error) {
+	return state.LoadEffectiveRunConfig(ctx, name, ReadJSON)
+}
+
 // ToolOverride represents a tool override.
 // Both Name and Description can be overridden independently, but
 // they can't be both empty.
@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/stacklok/toolhive/pkg/audit"
 	"github.com/stacklok/toolhive/pkg/auth"
@@ -38,6 +39,8 @@ const (
 // runConfigBuilder provides a fluent interface for building RunConfig instances
 type runConfigBuilder struct {
 	config *RunConfig
+	// ctx is the context the RunConfig is built with, for options doing I/O
+	ctx context.Context
 	// Store transport string separately to avoid type confusion
 	transportString string
 	// Store ports separately for proper validation
@@ -256,6 +259,14 @@ func WithProxyMode(mode types.ProxyMode) RunConfigBuilderOption {
 	}
 }
 
+// WithShutdownGracePeriod sets how long the proxy waits for active client connections to drain on shutdown
+func WithShutdownGracePeriod(gracePeriod time.Duration) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		b.config.ShutdownGracePeriod = gracePeriod
+		return nil
+	}
+}
+
 // WithGroup sets the group name for the workload
 func WithGroup(groupName string) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
@@ -712,6 +723,7 @@ func internalRunConfigBuilder(
 ) (*RunConfig, error) {
 	// Set the build context on the config to control validation behavior
 	b.config.buildContext = b.buildContext
+	b.ctx = ctx
 
 	// Apply all the options
 	for _, option := range runConfigOptions {
@@ -757,6 +769,10 @@ func (b *runConfigBuilder) validateConfig(imageMetadata *regtypes.ImageMetadata)
 	c := b.config
 	var err error
 
+	if err = c.Validate(); err != nil {
+		return err
+	}
+
 	// The old logic claimed to override the name with the name from the registry
 	// but didn't. Instead, it used the name passed in from the CLI.
 	// See: https://github.com/stacklok/toolhive/blob/2873152b62bf61698cbcdd0aba1707a046151e67/cmd/thv/app/run.go#L425
@@ -1038,7 +1054,7 @@ func WithEnvFile(filePath string) RunConfigBuilderOption {
 // WithEnvFilesFromDirectory adds environment variables from all files in a directory
 func WithEnvFilesFromDirectory(dirPath string) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
-		if _, err := b.config.WithEnvFilesFromDirectory(dirPath); err != nil {
+		if _, err := b.config.WithEnvFilesFromDirectory(b.ctx, dirPath); err != nil {
 			return err
 		}
 		return nil
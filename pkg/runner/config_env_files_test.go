@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -176,7 +177,7 @@ func TestRunConfig_WithEnvFilesFromDirectory(t *testing.T) {
 			}
 
 			// Call WithEnvFilesFromDirectory
-			result, err := config.WithEnvFilesFromDirectory(dirPath)
+			result, err := config.WithEnvFilesFromDirectory(t.Context(), dirPath)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -203,3 +204,85 @@ func TestRunConfig_WithEnvFile_ErrorHandling(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to process env file")
 }
+
+func TestRunConfig_WithEnvFilesFromDirectoryDetailed(t *testing.T) {
+	t.Parallel()
+
+	// Initialize logger to prevent nil pointer dereference
+	logger.Initialize()
+
+	tests := []struct {
+		name               string
+		initialEnvVars     map[string]string
+		files              map[string]string // filename -> content
+		expectedLoaded     []string
+		expectedOverridden []string
+		expectedEnvVars    map[string]string
+	}{
+		{
+			name:           "loaded keys without existing env vars",
+			initialEnvVars: nil,
+			files: map[string]string{
+				"app.env": "API_KEY=test123",
+				"db.env":  "DB_URL=postgres://localhost:5432/db",
+			},
+			expectedLoaded:  []string{"API_KEY", "DB_URL"},
+			expectedEnvVars: map[string]string{"API_KEY": "test123", "DB_URL": "postgres://localhost:5432/db"},
+		},
+		{
+			name:               "existing env vars are reported as overridden",
+			initialEnvVars:     map[string]string{"API_KEY": "original", "EXISTING": "value"},
+			files:              map[string]string{"app.env": "API_KEY=test123\nTOKEN=abc"},
+			expectedLoaded:     []string{"API_KEY", "TOKEN"},
+			expectedOverridden: []string{"API_KEY"},
+			expectedEnvVars:    map[string]string{"API_KEY": "test123", "EXISTING": "value", "TOKEN": "abc"},
+		},
+		{
+			name:            "keys overridden between files are loaded once",
+			initialEnvVars:  nil,
+			files:           map[string]string{"01-base.env": "API_KEY=original", "02-override.env": "API_KEY=overridden"},
+			expectedLoaded:  []string{"API_KEY"},
+			expectedEnvVars: map[string]string{"API_KEY": "overridden"},
+		},
+		{
+			name:            "empty directory",
+			initialEnvVars:  map[string]string{"EXISTING": "value"},
+			files:           map[string]string{},
+			expectedLoaded:  []string{},
+			expectedEnvVars: map[string]string{"EXISTING": "value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			for filename, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(tmpDir, filename), []byte(content), 0644))
+			}
+
+			config := &RunConfig{EnvVars: tt.initialEnvVars}
+
+			loaded, overridden, err := config.WithEnvFilesFromDirectoryDetailed(t.Context(), tmpDir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLoaded, loaded)
+			assert.Equal(t, tt.expectedOverridden, overridden)
+			assert.Equal(t, tt.expectedEnvVars, config.EnvVars)
+		})
+	}
+
+	t.Run("error does not modify env vars", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		config := &RunConfig{EnvVars: map[string]string{"EXISTING": "value"}}
+		loaded, overridden, err := config.WithEnvFilesFromDirectoryDetailed(ctx, t.TempDir())
+		require.Error(t, err)
+		assert.Nil(t, loaded)
+		assert.Nil(t, overridden)
+		assert.Equal(t, map[string]string{"EXISTING": "value"}, config.EnvVars)
+	})
+}
@@ -3,15 +3,24 @@ package runner
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/stacklok/toolhive/pkg/auth"
+	authoauth "github.com/stacklok/toolhive/pkg/auth/oauth"
+	"github.com/stacklok/toolhive/pkg/auth/remote"
+	"github.com/stacklok/toolhive/pkg/auth/tokenexchange"
 	"github.com/stacklok/toolhive/pkg/authz"
 	runtimemocks "github.com/stacklok/toolhive/pkg/container/runtime/mocks"
 	"github.com/stacklok/toolhive/pkg/ignore"
@@ -161,6 +170,62 @@ func TestRunConfig_WithPorts(t *testing.T) {
 	}
 }
 
+func TestRunConfig_Validate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		host        string
+		expectError bool
+	}{
+		{
+			name:        "All interfaces",
+			host:        "0.0.0.0",
+			expectError: false,
+		},
+		{
+			name:        "Loopback address",
+			host:        "127.0.0.1",
+			expectError: false,
+		},
+		{
+			name:        "Localhost hostname",
+			host:        "localhost",
+			expectError: false,
+		},
+		{
+			name:        "Unset host",
+			host:        "",
+			expectError: false,
+		},
+		{
+			name:        "Invalid host",
+			host:        "not a host!",
+			expectError: true,
+		},
+		{
+			name:        "Host with port",
+			host:        "127.0.0.1:8080",
+			expectError: true,
+		},
+	}
+
+	logger.Initialize()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			config := &RunConfig{Host: tc.host}
+
+			err := config.Validate()
+			if tc.expectError {
+				assert.Error(t, err, "Validate should return an error for host %q", tc.host)
+			} else {
+				assert.NoError(t, err, "Validate should not return an error for host %q", tc.host)
+			}
+		})
+	}
+}
+
 func TestRunConfig_WithEnvironmentVariables(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -326,6 +391,50 @@ func TestRunConfig_WithSecrets(t *testing.T) {
 				"ENV_VAR1": "new_value",
 			},
 		},
+		{
+			name: "Vault secrets are not resolved through the provider",
+			config: &RunConfig{EnvVars: map[string]string{
+				"GITHUB_TOKEN": "from_env_file",
+			}},
+			secrets: []string{
+				"secret1,target=ENV_VAR1",
+				"workload/github,target=GITHUB_TOKEN,type=vault",
+			},
+			mockSecrets: map[string]string{
+				"secret1": "value1",
+			},
+			expectError: false,
+			expected: map[string]string{
+				"ENV_VAR1":     "value1",
+				"GITHUB_TOKEN": "from_env_file",
+			},
+		},
+		{
+			name:   "Present secret wins over its default",
+			config: &RunConfig{EnvVars: map[string]string{}},
+			secrets: []string{
+				"secret1:-fallback,target=ENV_VAR1",
+			},
+			mockSecrets: map[string]string{
+				"secret1": "value1",
+			},
+			expectError: false,
+			expected: map[string]string{
+				"ENV_VAR1": "value1",
+			},
+		},
+		{
+			name:   "Missing secret uses its default",
+			config: &RunConfig{EnvVars: map[string]string{}},
+			secrets: []string{
+				"nonexistent:-fallback,target=ENV_VAR",
+			},
+			mockSecrets: map[string]string{},
+			expectError: false,
+			expected: map[string]string{
+				"ENV_VAR": "fallback",
+			},
+		},
 		{
 			name:   "Invalid secret format",
 			config: &RunConfig{EnvVars: map[string]string{}},
@@ -385,6 +494,265 @@ func TestRunConfig_WithSecrets(t *testing.T) {
 	}
 }
 
+func TestRunConfig_WithSecrets_EnvVarReferences(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	t.Setenv("TOOLHIVE_RUNTIME", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	ctrl := gomock.NewController(t)
+	secretManager := secretsmocks.NewMockProvider(ctrl)
+	secretManager.EXPECT().GetSecret(gomock.Any(), "token/value").Return("abc123", nil)
+	// The value of a secret is never expanded, even if it looks like a reference
+	secretManager.EXPECT().GetSecret(gomock.Any(), "tricky").Return("${secret:token/value}", nil)
+
+	config := &RunConfig{
+		EnvVars: map[string]string{
+			"AUTH_HEADER": "Bearer ${secret:token/value}",
+			"PLAIN":       "no references",
+		},
+		Secrets: []string{"tricky,target=TRICKY"},
+	}
+	require.True(t, hasEnvVarSecretReferences(config.EnvVars))
+
+	_, err := config.WithSecrets(context.Background(), secretManager)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"AUTH_HEADER": "Bearer abc123",
+		"PLAIN":       "no references",
+		"TRICKY":      "${secret:token/value}",
+	}, config.EnvVars)
+
+	secretManager.EXPECT().GetSecret(gomock.Any(), "missing").Return("", errors.New("secret not found: missing"))
+	config = &RunConfig{EnvVars: map[string]string{"AUTH_HEADER": "Bearer ${secret:missing}"}}
+	_, err = config.WithSecrets(context.Background(), secretManager)
+	assert.ErrorContains(t, err, "failed to resolve environment variable AUTH_HEADER")
+}
+
+func TestRunConfig_Redacted(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	t.Setenv("TOOLHIVE_RUNTIME", "")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	logger.Initialize()
+
+	ctrl := gomock.NewController(t)
+	secretManager := secretsmocks.NewMockProvider(ctrl)
+	secretManager.EXPECT().GetSecret(gomock.Any(), "token/value").Return("abc123", nil)
+	secretManager.EXPECT().GetSecret(gomock.Any(), "github").Return("ghp_secret", nil)
+
+	envFileDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(envFileDir, "vault.env"), []byte("VAULT_TOKEN=s.vault"), 0600))
+
+	config := &RunConfig{
+		Name:    "github",
+		Image:   "ghcr.io/github/github-mcp-server",
+		CmdArgs: []string{"stdio"},
+		EnvVars: map[string]string{
+			"AUTH_HEADER": "Bearer ${secret:token/value}",
+			"LOG_LEVEL":   "debug",
+		},
+		Secrets: []string{"github,target=GITHUB_TOKEN"},
+		RemoteAuthConfig: &remote.Config{
+			ClientID:     "client",
+			ClientSecret: "plain-client-secret",
+		},
+		OIDCConfig: &auth.TokenValidatorConfig{
+			Issuer:       "https://issuer.example.com",
+			ClientSecret: "oidc-client-secret",
+		},
+		TokenExchangeConfig: &tokenexchange.Config{
+			TokenURL:     "https://sts.example.com/token",
+			ClientID:     "exchange-client",
+			ClientSecret: "exchange-client-secret",
+		},
+		TelemetryConfig: &telemetry.Config{
+			Endpoint: "otel-collector:4318",
+			Headers:  map[string]string{"x-honeycomb-team": "otel-api-key"},
+		},
+	}
+	authMiddleware, err := types.NewMiddlewareConfig(auth.MiddlewareType, auth.MiddlewareParams{
+		OIDCConfig: &auth.TokenValidatorConfig{Issuer: "https://issuer.example.com", ClientSecret: "oidc-client-secret"},
+	})
+	require.NoError(t, err)
+	tokenExchangeMiddleware, err := types.NewMiddlewareConfig(tokenexchange.MiddlewareType, tokenexchange.MiddlewareParams{
+		TokenExchangeConfig: config.TokenExchangeConfig,
+	})
+	require.NoError(t, err)
+	telemetryMiddleware, err := types.NewMiddlewareConfig(telemetry.MiddlewareType, telemetry.FactoryMiddlewareParams{
+		Config:     config.TelemetryConfig,
+		ServerName: "github",
+	})
+	require.NoError(t, err)
+	config.MiddlewareConfigs = []types.MiddlewareConfig{*authMiddleware, *tokenExchangeMiddleware, *telemetryMiddleware}
+
+	_, err = config.WithSecrets(context.Background(), secretManager)
+	require.NoError(t, err)
+	_, err = config.WithEnvFilesFromDirectory(t.Context(), envFileDir)
+	require.NoError(t, err)
+
+	redacted, err := config.Redacted()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"AUTH_HEADER":  authoauth.RedactedValue,
+		"GITHUB_TOKEN": authoauth.RedactedValue,
+		"VAULT_TOKEN":  authoauth.RedactedValue,
+		"LOG_LEVEL":    "debug",
+	}, redacted.EnvVars)
+	assert.Equal(t, authoauth.RedactedValue, redacted.RemoteAuthConfig.ClientSecret)
+	assert.Equal(t, "client", redacted.RemoteAuthConfig.ClientID)
+	assert.Equal(t, authoauth.RedactedValue, redacted.OIDCConfig.ClientSecret)
+	assert.Equal(t, "https://issuer.example.com", redacted.OIDCConfig.Issuer)
+	assert.Equal(t, authoauth.RedactedValue, redacted.TokenExchangeConfig.ClientSecret)
+	assert.Equal(t, "exchange-client", redacted.TokenExchangeConfig.ClientID)
+	assert.Equal(t, map[string]string{"x-honeycomb-team": authoauth.RedactedValue}, redacted.TelemetryConfig.Headers)
+	assert.Equal(t, "otel-collector:4318", redacted.TelemetryConfig.Endpoint)
+
+	// The secrets of the middleware parameters are masked too
+	require.Len(t, redacted.MiddlewareConfigs, 3)
+	var authParams auth.MiddlewareParams
+	require.NoError(t, json.Unmarshal(redacted.MiddlewareConfigs[0].Parameters, &authParams))
+	assert.Equal(t, authoauth.RedactedValue, authParams.OIDCConfig.ClientSecret)
+	assert.Equal(t, "https://issuer.example.com", authParams.OIDCConfig.Issuer)
+	var tokenExchangeParams tokenexchange.MiddlewareParams
+	require.NoError(t, json.Unmarshal(redacted.MiddlewareConfigs[1].Parameters, &tokenExchangeParams))
+	assert.Equal(t, authoauth.RedactedValue, tokenExchangeParams.TokenExchangeConfig.ClientSecret)
+	assert.Equal(t, "https://sts.example.com/token", tokenExchangeParams.TokenExchangeConfig.TokenURL)
+	var telemetryParams telemetry.FactoryMiddlewareParams
+	require.NoError(t, json.Unmarshal(redacted.MiddlewareConfigs[2].Parameters, &telemetryParams))
+	assert.Equal(t, map[string]string{"x-honeycomb-team": authoauth.RedactedValue}, telemetryParams.Config.Headers)
+	assert.Equal(t, "otel-collector:4318", telemetryParams.Config.Endpoint)
+	assert.Equal(t, "github", telemetryParams.ServerName)
+	assert.Equal(t, "github", redacted.Name)
+	assert.Equal(t, "ghcr.io/github/github-mcp-server", redacted.Image)
+	assert.Equal(t, []string{"stdio"}, redacted.CmdArgs)
+	assert.Equal(t, []string{"github,target=GITHUB_TOKEN"}, redacted.Secrets)
+
+	// The effective config itself is left intact
+	assert.Equal(t, "Bearer abc123", config.EnvVars["AUTH_HEADER"])
+	assert.Equal(t, "ghp_secret", config.EnvVars["GITHUB_TOKEN"])
+	assert.Equal(t, "s.vault", config.EnvVars["VAULT_TOKEN"])
+	assert.Equal(t, "plain-client-secret", config.RemoteAuthConfig.ClientSecret)
+	assert.Equal(t, "oidc-client-secret", config.OIDCConfig.ClientSecret)
+	assert.Equal(t, "exchange-client-secret", config.TokenExchangeConfig.ClientSecret)
+	assert.Equal(t, "otel-api-key", config.TelemetryConfig.Headers["x-honeycomb-team"])
+	assert.Contains(t, string(config.MiddlewareConfigs[2].Parameters), "otel-api-key")
+
+	var buf bytes.Buffer
+	require.NoError(t, redacted.WriteJSON(&buf))
+	for _, secret := range []string{
+		"abc123", "ghp_secret", "s.vault", "plain-client-secret", "oidc-client-secret",
+		"exchange-client-secret", "otel-api-key",
+	} {
+		assert.NotContains(t, buf.String(), secret)
+	}
+}
+
+func TestRunConfig_Redacted_SecretParameterReference(t *testing.T) {
+	t.Parallel()
+
+	// A client secret in CLI format is a reference to the secret, not its value
+	config := &RunConfig{
+		RemoteAuthConfig: &remote.Config{ClientSecret: "github-oauth,target=oauth_secret"},
+	}
+	redacted, err := config.Redacted()
+	require.NoError(t, err)
+	assert.Equal(t, "github-oauth,target=oauth_secret", redacted.RemoteAuthConfig.ClientSecret)
+}
+
+func TestRunConfig_Redacted_DeepCopy(t *testing.T) {
+	t.Parallel()
+
+	config := &RunConfig{
+		Name:              "github",
+		CmdArgs:           []string{"stdio"},
+		RemoteAuthConfig:  &remote.Config{Scopes: []string{"repo"}, OAuthParams: map[string]string{"prompt": "consent"}},
+		PermissionProfile: &permissions.Profile{Read: []permissions.MountDeclaration{"/data"}},
+		ToolsOverride:     map[string]ToolOverride{"search": {Name: "find"}},
+	}
+
+	redacted, err := config.Redacted()
+	require.NoError(t, err)
+	assert.Equal(t, config.RemoteAuthConfig, redacted.RemoteAuthConfig)
+	assert.Equal(t, config.PermissionProfile, redacted.PermissionProfile)
+
+	// Modifying the redacted copy leaves the effective config intact
+	redacted.CmdArgs[0] = "sse"
+	redacted.RemoteAuthConfig.Scopes[0] = "admin"
+	redacted.RemoteAuthConfig.OAuthParams["prompt"] = "none"
+	redacted.PermissionProfile.Read[0] = "/"
+	redacted.ToolsOverride["search"] = ToolOverride{Name: "lookup"}
+
+	assert.Equal(t, []string{"stdio"}, config.CmdArgs)
+	assert.Equal(t, []string{"repo"}, config.RemoteAuthConfig.Scopes)
+	assert.Equal(t, map[string]string{"prompt": "consent"}, config.RemoteAuthConfig.OAuthParams)
+	assert.Equal(t, []permissions.MountDeclaration{"/data"}, config.PermissionProfile.Read)
+	assert.Equal(t, ToolOverride{Name: "find"}, config.ToolsOverride["search"])
+}
+
+func TestRunConfig_WithSecrets_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolves all secrets", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		secretManager := secretsmocks.NewMockProvider(ctrl)
+
+		const count = 50
+		config := &RunConfig{}
+		expected := make(map[string]string, count)
+		for i := 0; i < count; i++ {
+			name := fmt.Sprintf("secret%d", i)
+			target := fmt.Sprintf("ENV_VAR%d", i)
+			config.Secrets = append(config.Secrets, name+",target="+target)
+			expected[target] = "value-" + name
+			secretManager.EXPECT().GetSecret(gomock.Any(), name).Return("value-"+name, nil)
+		}
+		// The same target used twice keeps the value of the last parameter
+		config.Secrets = append(config.Secrets, "override,target=ENV_VAR0")
+		expected["ENV_VAR0"] = "value-override"
+		secretManager.EXPECT().GetSecret(gomock.Any(), "override").Return("value-override", nil)
+
+		_, err := config.WithSecrets(context.Background(), secretManager)
+		require.NoError(t, err)
+		assert.Equal(t, expected, config.EnvVars)
+	})
+
+	t.Run("error cancels in-flight resolutions", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		secretManager := secretsmocks.NewMockProvider(ctrl)
+
+		var cancelled atomic.Int32
+		secretManager.EXPECT().GetSecret(gomock.Any(), gomock.Not("failing")).
+			DoAndReturn(func(ctx context.Context, _ string) (string, error) {
+				<-ctx.Done()
+				cancelled.Add(1)
+				return "", ctx.Err()
+			}).Times(3)
+		secretManager.EXPECT().GetSecret(gomock.Any(), "failing").Return("", errors.New("backend unavailable"))
+
+		config := &RunConfig{Secrets: []string{
+			"slow1,target=SLOW1",
+			"slow2,target=SLOW2",
+			"slow3,target=SLOW3",
+			"failing,target=FAILING",
+		}}
+
+		_, err := config.WithSecrets(context.Background(), secretManager)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "backend unavailable")
+		assert.Equal(t, int32(3), cancelled.Load())
+		assert.Empty(t, config.EnvVars)
+	})
+}
+
+func TestNeedsSecretsProvider(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, needsSecretsProvider(nil))
+	assert.False(t, needsSecretsProvider([]string{"workload/github,target=GITHUB_TOKEN,type=vault"}))
+	assert.True(t, needsSecretsProvider([]string{"workload/github,target=GITHUB_TOKEN,type=vault", "secret1,target=ENV_VAR1"}))
+	assert.True(t, needsSecretsProvider([]string{"invalid-format"}))
+}
+
 func TestRunConfig_WithContainerName(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -704,6 +1072,135 @@ func TestRunConfig_WriteJSON_ReadJSON(t *testing.T) {
 	assert.Equal(t, originalConfig.EnvVars, readConfig.EnvVars, "EnvVars should match")
 }
 
+func TestMarshalRunConfig_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	originalConfig := &RunConfig{
+		SchemaVersion:       CurrentSchemaVersion,
+		Image:               "ghcr.io/github/github-mcp-server",
+		CmdArgs:             []string{"stdio", "--read-only"},
+		Name:                "github",
+		ContainerName:       "github",
+		BaseName:            "github",
+		Transport:           types.TransportTypeStreamableHTTP,
+		Host:                "127.0.0.1",
+		Port:                60000,
+		TargetPort:          60001,
+		EnvVars:             map[string]string{"LOG_LEVEL": "debug", "AUTH_HEADER": "Bearer ${secret:token}"},
+		Volumes:             []string{"/tmp/data:/data:ro"},
+		ContainerLabels:     map[string]string{"toolhive": "true"},
+		Secrets:             []string{"github,target=GITHUB_TOKEN"},
+		ProxyMode:           types.ProxyModeSSE,
+		ShutdownGracePeriod: 30 * time.Second,
+		ToolsFilter:         []string{"create_issue"},
+		RemoteAuthConfig: &remote.Config{
+			ClientID:     "client",
+			ClientSecret: "oauth,target=oauth_secret",
+			Scopes:       []string{"openid"},
+		},
+		TelemetryConfig: &telemetry.Config{
+			Endpoint:       "otel-collector:4318",
+			ServiceName:    "github",
+			TracingEnabled: true,
+			SamplingRate:   0.25,
+			Headers:        map[string]string{"X-API-Key": "abc"},
+		},
+	}
+
+	for _, format := range []RunConfigFormat{RunConfigFormatJSON, RunConfigFormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			data, err := MarshalRunConfig(originalConfig, format)
+			require.NoError(t, err)
+
+			readConfig, err := UnmarshalRunConfig(data, format)
+			require.NoError(t, err)
+			assert.Equal(t, originalConfig, readConfig)
+		})
+	}
+
+	yamlData, err := MarshalRunConfig(originalConfig, RunConfigFormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(yamlData), "transport: streamable-http")
+	assert.Contains(t, string(yamlData), "- github,target=GITHUB_TOKEN")
+}
+
+func TestUnmarshalRunConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		data              string
+		format            RunConfigFormat
+		expectedTransport types.TransportType
+		expectedError     string
+	}{
+		{
+			name:              "YAML config",
+			data:              "name: fetch\nimage: ghcr.io/stackloklabs/gofetch/server\ntransport: SSE\nsecrets:\n- token,target=TOKEN\n",
+			format:            RunConfigFormatYAML,
+			expectedTransport: types.TransportTypeSSE,
+		},
+		{
+			name:              "JSON config read as YAML",
+			data:              `{"name": "fetch", "transport": "stdio"}`,
+			format:            RunConfigFormatYAML,
+			expectedTransport: types.TransportTypeStdio,
+		},
+		{
+			name:          "invalid transport",
+			data:          `{"name": "fetch", "transport": "carrier-pigeon"}`,
+			format:        RunConfigFormatJSON,
+			expectedError: "invalid run configuration transport",
+		},
+		{
+			name:          "invalid host",
+			data:          "name: fetch\nhost: not a host\n",
+			format:        RunConfigFormatYAML,
+			expectedError: "invalid run configuration",
+		},
+		{
+			name:          "invalid YAML",
+			data:          "name: [fetch",
+			format:        RunConfigFormatYAML,
+			expectedError: "failed to convert run configuration from YAML",
+		},
+		{
+			name:          "unsupported format",
+			data:          `{"name": "fetch"}`,
+			format:        "toml",
+			expectedError: "unsupported run configuration format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := UnmarshalRunConfig([]byte(tt.data), tt.format)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "fetch", config.Name)
+			assert.Equal(t, tt.expectedTransport, config.Transport)
+			assert.NotNil(t, config.EnvVars)
+			assert.NotNil(t, config.Secrets)
+		})
+	}
+}
+
+func TestRunConfigFormatFromPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, RunConfigFormatYAML, RunConfigFormatFromPath("/tmp/github.yaml"))
+	assert.Equal(t, RunConfigFormatYAML, RunConfigFormatFromPath("github.YML"))
+	assert.Equal(t, RunConfigFormatJSON, RunConfigFormatFromPath("github.json"))
+	assert.Equal(t, RunConfigFormatJSON, RunConfigFormatFromPath("github"))
+}
+
 func TestCommaSeparatedEnvVars(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
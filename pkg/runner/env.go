@@ -218,8 +218,8 @@ func addAsSecret(
 		logger.Debugf("Added environment variable (secret fallback): %s", envVar.Name)
 	} else {
 		// Create secret reference for RunConfig
-		secretEntry := fmt.Sprintf("%s,target=%s", secretName, envVar.Name)
-		*secretsList = append(*secretsList, secretEntry)
+		secretEntry := secrets.SecretParameter{Name: secretName, Target: envVar.Name}
+		*secretsList = append(*secretsList, secretEntry.ToCLIString())
 		if envVar.Required {
 			logger.Debugf("Created secret for %s: %s", envVar.Name, secretName)
 		} else {
@@ -1,56 +1,173 @@
 package runner
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/stacklok/toolhive/pkg/environment"
 	"github.com/stacklok/toolhive/pkg/logger"
 )
 
+const (
+	// DefaultEnvFileMaxSize is the default maximum size of a single environment file
+	DefaultEnvFileMaxSize int64 = 1 * 1024 * 1024 // 1 MB
+	// DefaultEnvFileMaxFiles is the default maximum number of environment files processed from a directory
+	DefaultEnvFileMaxFiles = 100
+
+	// atomicWriterDataDir is the symlink to the current files of a directory written atomically
+	atomicWriterDataDir = "..data"
+)
+
+// EnvFileParseOptions controls how KEY=VALUE environment files are parsed
+type EnvFileParseOptions struct {
+	// AllowExportPrefix accepts lines prefixed with "export " as written by shell env files
+	AllowExportPrefix bool `json:"allow_export_prefix,omitempty" yaml:"allow_export_prefix,omitempty"`
+	// Strict fails on malformed lines instead of skipping them
+	Strict bool `json:"strict,omitempty" yaml:"strict,omitempty"`
+	// TrimValues removes whitespace around keys and values
+	TrimValues bool `json:"trim_values,omitempty" yaml:"trim_values,omitempty"`
+}
+
+// DefaultEnvFileParseOptions returns the options used when none are configured
+func DefaultEnvFileParseOptions() EnvFileParseOptions {
+	return EnvFileParseOptions{
+		AllowExportPrefix: true,
+	}
+}
+
+// envFileOptions controls how environment files are processed
+type envFileOptions struct {
+	// parse controls how KEY=VALUE files are parsed, DefaultEnvFileParseOptions if nil
+	parse *EnvFileParseOptions
+	// jsonTopLevelOnly ignores nested objects in JSON files instead of flattening them
+	jsonTopLevelOnly bool
+	// maxFileSize is the maximum size of a single file, DefaultEnvFileMaxSize if zero
+	maxFileSize int64
+	// maxFiles is the maximum number of files processed from a directory, DefaultEnvFileMaxFiles if zero
+	maxFiles int
+}
+
+// withDefaults returns the options with safe defaults applied to unset limits
+func (o envFileOptions) withDefaults() envFileOptions {
+	if o.maxFileSize <= 0 {
+		o.maxFileSize = DefaultEnvFileMaxSize
+	}
+	if o.maxFiles <= 0 {
+		o.maxFiles = DefaultEnvFileMaxFiles
+	}
+	if o.parse == nil {
+		parse := DefaultEnvFileParseOptions()
+		o.parse = &parse
+	}
+	return o
+}
+
 // processEnvFilesDirectory detects and processes environment files from a directory
 // Returns a map of environment variables to be merged with RunConfig.EnvVars
-func processEnvFilesDirectory(dirPath string) (map[string]string, error) {
+// Files are processed in lexical order of their names, and a key defined in several
+// files takes the value from the last one (e.g. 20-override.env wins over 10-base.env)
+// Cancellation is checked between files, so a slow filesystem cannot block the caller indefinitely.
+func processEnvFilesDirectory(ctx context.Context, dirPath string, opts envFileOptions) (map[string]string, error) {
+	return processEnvFilesFS(ctx, os.DirFS(dirPath), dirPath, opts)
+}
+
+// processEnvFilesFS processes the environment files at the root of fsys, as described for
+// processEnvFilesDirectory. dirPath only identifies the directory in logs and errors, which
+// allows tests to exercise the directory walk with an in-memory filesystem.
+func processEnvFilesFS(ctx context.Context, fsys fs.FS, dirPath string, opts envFileOptions) (map[string]string, error) {
+	opts = opts.withDefaults()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("processing env files directory %s was interrupted: %w", dirPath, err)
+	}
+
 	// Check if directory exists
-	entries, err := os.ReadDir(dirPath)
+	entries, err := fs.ReadDir(fsys, ".")
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			logger.Debugf("Env files directory %s does not exist", dirPath)
 			return make(map[string]string), nil // Return empty map, not an error
 		}
 		return nil, fmt.Errorf("failed to read env files directory %s: %w", dirPath, err)
 	}
 
+	// Directories written atomically (Kubernetes projected volumes, Vault Agent) expose the current
+	// files through a "..data" symlink, and the top-level entries are links into it. Reading "..data"
+	// directly processes each file once and ignores the timestamped bookkeeping directories.
+	filesDir := "."
+	if slices.ContainsFunc(entries, func(entry fs.DirEntry) bool { return entry.Name() == atomicWriterDataDir }) {
+		filesDir = atomicWriterDataDir
+		entries, err = fs.ReadDir(fsys, filesDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read env files directory %s: %w", filepath.Join(dirPath, filesDir), err)
+		}
+	}
+
+	// Make the override order explicit rather than relying on the order returned by the OS
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
 	logger.Infof("Env files directory %s detected, processing environment files", dirPath)
+	ensureEnvFileMetrics()
 
 	allEnvVars := make(map[string]string)
 	processedCount := 0
+	failedCount := 0
 
 	for _, entry := range entries {
-		// Skip directories
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("processing env files directory %s was interrupted: %w", dirPath, err)
+		}
+
+		// Skip directories, including symlinks to directories
 		if entry.IsDir() {
 			continue
 		}
+		name := path.Join(filesDir, entry.Name())
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if info, err := fs.Stat(fsys, name); err == nil && info.IsDir() {
+				continue
+			}
+		}
 
 		// Skip hidden files
 		if strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
-		filePath := filepath.Join(dirPath, entry.Name())
-		fileEnvVars, err := processEnvFile(filePath)
+		// Guard against a misconfigured agent flooding the directory
+		if processedCount+failedCount >= opts.maxFiles {
+			logger.Warnf("Env files directory %s has more than %d files, skipping the remaining files",
+				dirPath, opts.maxFiles)
+			break
+		}
+
+		fileEnvVars, err := processEnvFileFS(fsys, name, opts)
 		if err != nil {
 			logger.Warnf("Failed to process env file %s: %v", entry.Name(), err)
+			failedCount++
+			envFilesFailed.Add(1)
 			continue
 		}
 
-		// Merge env vars, with later files potentially overriding earlier ones
+		// Merge env vars, with later files (in lexical order) overriding earlier ones
 		for key, value := range fileEnvVars {
 			allEnvVars[key] = value
 		}
 		processedCount++
+		envFilesProcessed.Add(1)
 	}
 
 	logger.Infof("Processed %d env files, %d environment variables extracted", processedCount, len(allEnvVars))
@@ -59,18 +176,55 @@ func processEnvFilesDirectory(dirPath string) (map[string]string, error) {
 
 // processEnvFile reads and processes a single environment file
 // Uses existing ToolHive environment parsing utilities
-func processEnvFile(path string) (map[string]string, error) {
-	content, err := os.ReadFile(path) // #nosec G304 - path is controlled internally, validated by caller
+func processEnvFile(path string, opts envFileOptions) (map[string]string, error) {
+	return processEnvFileFS(os.DirFS(filepath.Dir(path)), filepath.Base(path), opts)
+}
+
+// processEnvFileFS reads and processes the environment file with the given name in fsys
+func processEnvFileFS(fsys fs.FS, name string, opts envFileOptions) (map[string]string, error) {
+	opts = opts.withDefaults()
+
+	content, err := readEnvFile(fsys, name, opts.maxFileSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, err
+	}
+
+	// Vault templates may render secrets as a JSON object instead of KEY=VALUE lines
+	if isJSONEnvFile(name, content) {
+		return processJSONEnvFile(name, content, opts.jsonTopLevelOnly)
+	}
+
+	// Convert content to slice of KEY=VALUE entries for existing parser
+	envLines, err := splitEnvFileLines(string(content), *opts.parse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment variables in %s: %w", path.Base(name), err)
+	}
+
+	if len(envLines) == 0 {
+		logger.Debugf("No environment variables found in %s", path.Base(name))
+		return make(map[string]string), nil
+	}
+
+	// Use existing ToolHive utility to parse KEY=VALUE format
+	envVars, err := environment.ParseEnvironmentVariables(envLines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment variables in %s: %w", path.Base(name), err)
 	}
 
-	// Convert content to slice of KEY=VALUE lines for existing parser
-	lines := strings.Split(string(content), "\n")
+	logger.Debugf("Extracted %d environment variables from %s", len(envVars), path.Base(name))
+	return envVars, nil
+}
+
+// splitEnvFileLines converts the content of a KEY=VALUE environment file into KEY=VALUE entries.
+// Values wrapped in single or double quotes have the quotes removed, and a quoted value may span
+// several lines (e.g. a PEM encoded key) until the closing quote, with the newlines preserved.
+// Malformed lines are skipped, or rejected when parseOpts.Strict is set.
+func splitEnvFileLines(content string, parseOpts EnvFileParseOptions) ([]string, error) {
+	lines := strings.Split(content, "\n")
 	var envLines []string
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
 
 		// Skip empty lines and comments
 		if line == "" || strings.HasPrefix(line, "#") {
@@ -78,25 +232,145 @@ func processEnvFile(path string) (map[string]string, error) {
 		}
 
 		// Handle export statements (common in shell env files)
-		line = strings.TrimPrefix(line, "export ")
+		if parseOpts.AllowExportPrefix {
+			line = strings.TrimPrefix(line, "export ")
+		}
 
 		// Only process lines that contain '=' (KEY=VALUE format)
-		if strings.Contains(line, "=") {
+		key, value, found := strings.Cut(line, "=")
+		if parseOpts.TrimValues {
+			key = strings.TrimSpace(key)
+			value = strings.TrimSpace(value)
+			line = key + "=" + value
+		}
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			if parseOpts.Strict {
+				return nil, fmt.Errorf("malformed line %d: expected KEY=VALUE", i+1)
+			}
+			continue
+		}
+
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
 			envLines = append(envLines, line)
+			continue
+		}
+
+		// Quoted value, closed on the same line
+		quote := value[:1]
+		if len(value) > 1 && strings.HasSuffix(value, quote) {
+			envLines = append(envLines, key+"="+value[1:len(value)-1])
+			continue
+		}
+
+		// Quoted value spanning several lines, keep reading until the closing quote
+		valueLines := []string{value[1:]}
+		closed := false
+		for i+1 < len(lines) {
+			i++
+			next := strings.TrimRight(lines[i], " \t\r")
+			if strings.HasSuffix(next, quote) {
+				valueLines = append(valueLines, strings.TrimSuffix(next, quote))
+				closed = true
+				break
+			}
+			valueLines = append(valueLines, next)
 		}
+		if !closed {
+			return nil, fmt.Errorf("unterminated quoted value for %s", key)
+		}
+		envLines = append(envLines, key+"="+strings.Join(valueLines, "\n"))
 	}
 
-	if len(envLines) == 0 {
-		logger.Debugf("No environment variables found in %s", filepath.Base(path))
-		return make(map[string]string), nil
+	return envLines, nil
+}
+
+// readEnvFile reads an environment file, refusing files larger than maxSize bytes
+func readEnvFile(fsys fs.FS, name string, maxSize int64) ([]byte, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	defer file.Close()
 
-	// Use existing ToolHive utility to parse KEY=VALUE format
-	envVars, err := environment.ParseEnvironmentVariables(envLines)
+	info, err := file.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse environment variables in %s: %w", filepath.Base(path), err)
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("file size %d bytes exceeds the limit of %d bytes", info.Size(), maxSize)
+	}
+
+	// Read one byte past the limit in case the file grew after the size check
+	content, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("file size exceeds the limit of %d bytes", maxSize)
+	}
+
+	return content, nil
+}
+
+// isJSONEnvFile reports whether an environment file holds a JSON object,
+// either by its .json extension or by its content starting with '{'
+func isJSONEnvFile(path string, content []byte) bool {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(content), []byte("{"))
+}
+
+// processJSONEnvFile parses a JSON object into environment variables.
+// String values are used as is, numbers and booleans are converted to their JSON text,
+// while null values and arrays are rejected. Nested objects are flattened into
+// underscore-joined uppercase names (db.host becomes DB_HOST), unless topLevelOnly
+// is set, in which case they are skipped.
+func processJSONEnvFile(path string, content []byte, topLevelOnly bool) (map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON in %s: %w", filepath.Base(path), err)
+	}
+
+	envVars := make(map[string]string, len(raw))
+	if err := flattenJSONEnvVars(envVars, "", raw, topLevelOnly); err != nil {
+		return nil, fmt.Errorf("%w in %s", err, filepath.Base(path))
 	}
 
 	logger.Debugf("Extracted %d environment variables from %s", len(envVars), filepath.Base(path))
 	return envVars, nil
 }
+
+// flattenJSONEnvVars adds the values of a JSON object to envVars, prefixing nested keys
+// with the uppercased names of their parent objects
+func flattenJSONEnvVars(envVars map[string]string, prefix string, object map[string]any, topLevelOnly bool) error {
+	for key, value := range object {
+		name := key
+		if prefix != "" {
+			name = strings.ToUpper(prefix + "_" + key)
+		}
+
+		switch v := value.(type) {
+		case string:
+			envVars[name] = v
+		case json.Number:
+			envVars[name] = v.String()
+		case bool:
+			envVars[name] = strconv.FormatBool(v)
+		case map[string]any:
+			if topLevelOnly {
+				logger.Debugf("Skipping nested object for key %s", name)
+				continue
+			}
+			if err := flattenJSONEnvVars(envVars, name, v, topLevelOnly); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported value for key %s: expected string, number, boolean or object", name)
+		}
+	}
+	return nil
+}
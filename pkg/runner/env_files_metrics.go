@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+const envFilesInstrumentationName = "github.com/stacklok/toolhive/pkg/runner"
+
+var (
+	// envFilesProcessed counts the environment (e.g. Vault secret) files processed successfully
+	envFilesProcessed atomic.Int64
+	// envFilesFailed counts the environment (e.g. Vault secret) files that could not be processed
+	envFilesFailed atomic.Int64
+
+	registerEnvFileMetricsOnce sync.Once
+)
+
+// ensureEnvFileMetrics registers the env file metrics with the global meter provider.
+// Files are usually processed before telemetry is configured, so the counters are observed
+// from process-wide totals and become visible on the metrics endpoint once it is set up.
+func ensureEnvFileMetrics() {
+	registerEnvFileMetricsOnce.Do(func() {
+		if err := registerEnvFileMetrics(otel.GetMeterProvider().Meter(envFilesInstrumentationName)); err != nil {
+			logger.Warnf("Failed to register env file metrics: %v", err)
+		}
+	})
+}
+
+// registerEnvFileMetrics registers observable counters reporting the env file totals on the given meter
+func registerEnvFileMetrics(meter metric.Meter) error {
+	processed, err := meter.Int64ObservableCounter(
+		"toolhive_vault_secret_files_processed", // The exporter adds the _total suffix automatically
+		metric.WithDescription("Total number of vault secret files processed successfully"),
+	)
+	if err != nil {
+		return err
+	}
+
+	failed, err := meter.Int64ObservableCounter(
+		"toolhive_vault_secret_files_failed", // The exporter adds the _total suffix automatically
+		metric.WithDescription("Total number of vault secret files that failed to process"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		observer.ObserveInt64(processed, envFilesProcessed.Load())
+		observer.ObserveInt64(failed, envFilesFailed.Load())
+		return nil
+	}, processed, failed)
+	return err
+}
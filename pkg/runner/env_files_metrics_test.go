@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// gatherCounter returns the value of the named counter in the registry, or 0 if it is not present
+func gatherCounter(t *testing.T, registry *promclient.Registry, name string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var total float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			total += m.GetCounter().GetValue()
+		}
+	}
+	return total
+}
+
+//nolint:paralleltest // Counters are process-wide, so no other env files may be processed concurrently
+func TestEnvFileMetrics(t *testing.T) {
+	// Needed to prevent a nil pointer dereference in the logger.
+	logger.Initialize()
+
+	registry := promclient.NewRegistry()
+	exporter, err := prometheus.New(prometheus.WithRegisterer(registry))
+	require.NoError(t, err)
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter))
+	t.Cleanup(func() {
+		_ = meterProvider.Shutdown(t.Context())
+	})
+	require.NoError(t, registerEnvFileMetrics(meterProvider.Meter("test")))
+
+	processedBefore := gatherCounter(t, registry, "toolhive_vault_secret_files_processed_total")
+	failedBefore := gatherCounter(t, registry, "toolhive_vault_secret_files_failed_total")
+
+	// One good file and one that cannot be read (a dangling symlink)
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "good.env"), []byte("API_KEY=key456"), 0600))
+	require.NoError(t, os.Symlink(filepath.Join(tmpDir, "missing"), filepath.Join(tmpDir, "unreadable.env")))
+
+	envVars, err := processEnvFilesDirectory(t.Context(), tmpDir, envFileOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"API_KEY": "key456"}, envVars)
+
+	assert.Equal(t, processedBefore+1, gatherCounter(t, registry, "toolhive_vault_secret_files_processed_total"))
+	assert.Equal(t, failedBefore+1, gatherCounter(t, registry, "toolhive_vault_secret_files_failed_total"))
+}
@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// envFilesWatchDebounce is how long to wait for further file system events before
+// re-processing the directory, so that a multi-step rewrite produces a single emission
+const envFilesWatchDebounce = 100 * time.Millisecond
+
+// WatchEnvFiles watches EnvFileDir (e.g. the directory rendered by Vault Agent) and emits the
+// environment variables extracted from it each time its files change. The consumer decides how
+// to apply the new values. The channel is closed when the context is cancelled.
+func (c *RunConfig) WatchEnvFiles(ctx context.Context) (<-chan map[string]string, error) {
+	if c.EnvFileDir == "" {
+		return nil, errors.New("no env file directory configured")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err := watcher.Add(c.EnvFileDir); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch env files directory %s: %w", c.EnvFileDir, err)
+	}
+
+	updates := make(chan map[string]string, 1)
+	go func() {
+		defer close(updates)
+		defer watcher.Close()
+
+		// The debounce timer is only armed once an event has been received
+		debounce := time.NewTimer(envFilesWatchDebounce)
+		debounce.Stop()
+		defer debounce.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op == fsnotify.Chmod {
+					continue
+				}
+				logger.Debugf("Env files directory changed: %s", event)
+				debounce.Reset(envFilesWatchDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warnf("Error watching env files directory %s: %v", c.EnvFileDir, err)
+			case <-debounce.C:
+				envVars, err := processEnvFilesDirectory(ctx, c.EnvFileDir, c.envFileOptions())
+				if err != nil {
+					logger.Warnf("Failed to reload env files from %s: %v", c.EnvFileDir, err)
+					continue
+				}
+				select {
+				case updates <- envVars:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+func TestRunConfig_WatchEnvFiles(t *testing.T) {
+	t.Parallel()
+
+	// Needed to prevent a nil pointer dereference in the logger.
+	logger.Initialize()
+
+	tmpDir := t.TempDir()
+	config := &RunConfig{EnvFileDir: tmpDir}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := config.WatchEnvFiles(ctx)
+	require.NoError(t, err)
+
+	waitForUpdate := func() map[string]string {
+		t.Helper()
+		select {
+		case envVars := <-updates:
+			return envVars
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for env files update")
+			return nil
+		}
+	}
+
+	secretFile := filepath.Join(tmpDir, "api")
+	require.NoError(t, os.WriteFile(secretFile, []byte("API_KEY=initial"), 0600))
+	assert.Equal(t, map[string]string{"API_KEY": "initial"}, waitForUpdate())
+
+	require.NoError(t, os.WriteFile(secretFile, []byte("API_KEY=rotated\nAPI_URL=https://api.example.com"), 0600))
+	assert.Equal(t, map[string]string{"API_KEY": "rotated", "API_URL": "https://api.example.com"}, waitForUpdate())
+
+	// The channel is closed once the context is cancelled
+	cancel()
+	select {
+	case _, ok := <-updates:
+		assert.False(t, ok, "channel should be closed after cancellation")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+}
+
+func TestRunConfig_WatchEnvFiles_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := (&RunConfig{}).WatchEnvFiles(context.Background())
+	require.Error(t, err)
+
+	_, err = (&RunConfig{EnvFileDir: filepath.Join(t.TempDir(), "missing")}).WatchEnvFiles(context.Background())
+	require.Error(t, err)
+}
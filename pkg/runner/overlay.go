@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"maps"
+	"reflect"
+)
+
+// MergeRunConfig returns a new RunConfig layering overlay over base, e.g. an environment-specific overlay
+// over a shared base configuration. The fields set in overlay win, a field being set if it is not the
+// zero value, so an overlay cannot reset a field of base, e.g. disable a boolean. The maps, such as
+// EnvVars, are merged with the entries of overlay winning, and the non-empty slices, such as CmdArgs,
+// replace those of base as a whole. The nested configurations, such as OIDCConfig, are replaced as a
+// whole too. The merged RunConfig is a deep copy, which shares no maps, slices or nested configurations
+// with base or overlay, except for the values held by interfaces, such as the Deployer.
+func MergeRunConfig(base, overlay *RunConfig) *RunConfig {
+	if base == nil {
+		base = &RunConfig{}
+	}
+	if overlay == nil {
+		overlay = &RunConfig{}
+	}
+
+	merged := *base
+	mergedValue := reflect.ValueOf(&merged).Elem()
+	baseValue := reflect.ValueOf(base).Elem()
+	overlayValue := reflect.ValueOf(overlay).Elem()
+	for i := range mergedValue.NumField() {
+		if !mergedValue.Type().Field(i).IsExported() {
+			continue
+		}
+
+		field := mergedValue.Field(i)
+		baseField := baseValue.Field(i)
+		overlayField := overlayValue.Field(i)
+		switch field.Kind() {
+		case reflect.Map:
+			field.Set(deepCopyValue(mergeMapValues(baseField, overlayField)))
+		case reflect.Slice:
+			source := baseField
+			if overlayField.Len() > 0 {
+				source = overlayField
+			}
+			field.Set(deepCopyValue(source))
+		default:
+			source := baseField
+			if !overlayField.IsZero() {
+				source = overlayField
+			}
+			field.Set(deepCopyValue(source))
+		}
+	}
+
+	// Both sets of environment variables resolved from secrets are kept, so that Redacted masks them
+	if base.secretEnvVars != nil || overlay.secretEnvVars != nil {
+		merged.secretEnvVars = make(map[string]bool, len(base.secretEnvVars)+len(overlay.secretEnvVars))
+		maps.Copy(merged.secretEnvVars, base.secretEnvVars)
+		maps.Copy(merged.secretEnvVars, overlay.secretEnvVars)
+	}
+
+	return &merged
+}
+
+// mergeMapValues returns a new map with the entries of base and overlay, those of overlay winning,
+// or base if both maps are nil
+func mergeMapValues(base, overlay reflect.Value) reflect.Value {
+	if base.IsNil() && overlay.IsNil() {
+		return base
+	}
+
+	merged := reflect.MakeMapWithSize(base.Type(), base.Len()+overlay.Len())
+	for _, source := range []reflect.Value{base, overlay} {
+		iter := source.MapRange()
+		for iter.Next() {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	return merged
+}
+
+// deepCopyValue returns a copy of v which shares no maps, slices or pointers with it. The unexported
+// fields of the structs and the values held by interfaces are not copied deeply.
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(deepCopyValue(v.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				copied.Field(i).Set(deepCopyValue(v.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			copied.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+		return copied
+	default:
+		return v
+	}
+}
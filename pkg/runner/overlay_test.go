@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stacklok/toolhive/pkg/auth/remote"
+	"github.com/stacklok/toolhive/pkg/telemetry"
+	"github.com/stacklok/toolhive/pkg/transport/types"
+)
+
+func TestMergeRunConfig(t *testing.T) {
+	t.Parallel()
+
+	newBase := func() *RunConfig {
+		return &RunConfig{
+			Image:           "ghcr.io/example/server:latest",
+			Name:            "server",
+			Transport:       types.TransportTypeSSE,
+			Port:            8080,
+			Debug:           true,
+			CmdArgs:         []string{"--verbose"},
+			Volumes:         []string{"/data:/data"},
+			EnvVars:         map[string]string{"LOG_LEVEL": "info", "REGION": "eu"},
+			ContainerLabels: map[string]string{"team": "platform"},
+			ToolsOverride:   map[string]ToolOverride{"fetch": {Name: "get"}},
+			TelemetryConfig: &telemetry.Config{Endpoint: "base-collector:4317"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		overlay  *RunConfig
+		validate func(*testing.T, *RunConfig)
+	}{
+		{
+			name: "scalar fields set in the overlay win",
+			overlay: &RunConfig{
+				Image:     "ghcr.io/example/server:v2",
+				Transport: types.TransportTypeStreamableHTTP,
+				Port:      9090,
+			},
+			validate: func(t *testing.T, merged *RunConfig) {
+				t.Helper()
+				assert.Equal(t, "ghcr.io/example/server:v2", merged.Image)
+				assert.Equal(t, types.TransportTypeStreamableHTTP, merged.Transport)
+				assert.Equal(t, 9090, merged.Port)
+				// Unset fields of the overlay keep the base values
+				assert.Equal(t, "server", merged.Name)
+				assert.True(t, merged.Debug)
+			},
+		},
+		{
+			name: "maps are merged",
+			overlay: &RunConfig{
+				EnvVars:       map[string]string{"LOG_LEVEL": "debug", "FEATURE": "on"},
+				ToolsOverride: map[string]ToolOverride{"search": {Description: "Search the web"}},
+			},
+			validate: func(t *testing.T, merged *RunConfig) {
+				t.Helper()
+				assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "REGION": "eu", "FEATURE": "on"}, merged.EnvVars)
+				assert.Equal(t, map[string]string{"team": "platform"}, merged.ContainerLabels)
+				assert.Equal(t, map[string]ToolOverride{
+					"fetch":  {Name: "get"},
+					"search": {Description: "Search the web"},
+				}, merged.ToolsOverride)
+			},
+		},
+		{
+			name: "slices are replaced",
+			overlay: &RunConfig{
+				CmdArgs: []string{"--quiet", "--port=9090"},
+				Volumes: []string{},
+			},
+			validate: func(t *testing.T, merged *RunConfig) {
+				t.Helper()
+				assert.Equal(t, []string{"--quiet", "--port=9090"}, merged.CmdArgs)
+				// Empty slices of the overlay keep the base values
+				assert.Equal(t, []string{"/data:/data"}, merged.Volumes)
+			},
+		},
+		{
+			name: "nested configurations are replaced",
+			overlay: &RunConfig{
+				TelemetryConfig: &telemetry.Config{ServiceName: "overlay-service"},
+			},
+			validate: func(t *testing.T, merged *RunConfig) {
+				t.Helper()
+				assert.Equal(t, &telemetry.Config{ServiceName: "overlay-service"}, merged.TelemetryConfig)
+			},
+		},
+		{
+			name:    "nil overlay returns a copy of the base",
+			overlay: nil,
+			validate: func(t *testing.T, merged *RunConfig) {
+				t.Helper()
+				assert.Equal(t, newBase(), merged)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			base := newBase()
+			merged := MergeRunConfig(base, tt.overlay)
+			tt.validate(t, merged)
+
+			// The merge must not modify the base, nor share its maps, slices and nested configurations
+			merged.EnvVars["ADDED"] = "value"
+			merged.CmdArgs[0] = "--changed"
+			merged.TelemetryConfig.Endpoint = "changed-collector:4317"
+			assert.Equal(t, newBase(), base)
+		})
+	}
+}
+
+func TestMergeRunConfig_DoesNotShareOverlay(t *testing.T) {
+	t.Parallel()
+
+	overlay := &RunConfig{
+		TelemetryConfig:  &telemetry.Config{ServiceName: "overlay-service", Headers: map[string]string{"team": "a"}},
+		RemoteAuthConfig: &remote.Config{Scopes: []string{"repo"}},
+	}
+	merged := MergeRunConfig(&RunConfig{}, overlay)
+
+	merged.TelemetryConfig.ServiceName = "changed"
+	merged.TelemetryConfig.Headers["team"] = "b"
+	merged.RemoteAuthConfig.Scopes[0] = "admin"
+
+	assert.Equal(t, &telemetry.Config{ServiceName: "overlay-service", Headers: map[string]string{"team": "a"}},
+		overlay.TelemetryConfig)
+	assert.Equal(t, []string{"repo"}, overlay.RemoteAuthConfig.Scopes)
+}
@@ -6,9 +6,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -165,16 +167,21 @@ func (r *Runner) Run(ctx context.Context) error {
 	// Set proxy mode for stdio transport
 	transportConfig.ProxyMode = r.Config.ProxyMode
 
-	// Process secrets if provided (regular secrets or RemoteAuthConfig.ClientSecret in CLI format)
-	hasRegularSecrets := len(r.Config.Secrets) > 0
+	// Process secrets if provided (regular secrets, RemoteAuthConfig.ClientSecret in CLI format or
+	// secret references in environment variables)
+	// Vault-typed secrets are delivered through EnvFileDir and do not need the secrets provider
+	// On Kubernetes, the secret references of the environment variables are read from Kubernetes Secrets
+	hasRegularSecrets := needsSecretsProvider(r.Config.Secrets)
 	hasRemoteAuthSecret := r.Config.RemoteAuthConfig != nil && r.Config.RemoteAuthConfig.ClientSecret != ""
+	hasEnvVarSecretRefs := !rt.IsKubernetesRuntime() && hasEnvVarSecretReferences(r.Config.EnvVars)
 
-	logger.Debugf("Secret processing check: hasRegularSecrets=%v, hasRemoteAuthSecret=%v", hasRegularSecrets, hasRemoteAuthSecret)
+	logger.Debugf("Secret processing check: hasRegularSecrets=%v, hasRemoteAuthSecret=%v, hasEnvVarSecretRefs=%v",
+		hasRegularSecrets, hasRemoteAuthSecret, hasEnvVarSecretRefs)
 	if hasRemoteAuthSecret {
 		logger.Debugf("RemoteAuthConfig.ClientSecret: %s", r.Config.RemoteAuthConfig.ClientSecret)
 	}
 
-	if hasRegularSecrets || hasRemoteAuthSecret {
+	if hasRegularSecrets || hasRemoteAuthSecret || hasEnvVarSecretRefs {
 		logger.Debugf("Calling WithSecrets to process secrets")
 		cfgprovider := config.NewDefaultProvider()
 		cfg := cfgprovider.GetConfig()
@@ -195,11 +202,16 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	}
 
+	// Record the effective configuration for debugging, with the secret values masked
+	if err := r.Config.SaveEffectiveState(ctx); err != nil {
+		logger.Warnf("Failed to record the effective run configuration: %v", err)
+	}
+
 	// Set up the transport
 	logger.Infof("Setting up %s transport...", r.Config.Transport)
 
 	// Prepare transport options based on workload type
-	var transportOpts []transport.Option
+	transportOpts := []transport.Option{transport.WithShutdownGracePeriod(r.Config.ShutdownGracePeriod)}
 	var setupResult *runtime.SetupResult
 
 	if r.Config.RemoteURL == "" {
@@ -276,6 +288,14 @@ func (r *Runner) Run(ctx context.Context) error {
 
 	logger.Infof("MCP server %s started successfully", r.Config.ContainerName)
 
+	// Make sure the proxied MCP server accepts connections before reporting it as ready.
+	if err := ensureTargetReachable(ctx, setupResult, transportHandler, targetReachableTimeout); err != nil {
+		if cleanupErr := r.Cleanup(ctx); cleanupErr != nil {
+			logger.Warnf("Warning: Failed to cleanup telemetry: %v", cleanupErr)
+		}
+		return fmt.Errorf("MCP server %s failed to start: %w", r.Config.ContainerName, err)
+	}
+
 	// Wait for the MCP server to accept initialize requests before updating client configurations.
 	// This prevents timing issues where clients try to connect before the server is fully ready.
 	// We repeatedly call initialize until it succeeds (up to 5 minutes).
@@ -354,6 +374,9 @@ func (r *Runner) Run(ctx context.Context) error {
 		// We're a detached process running in foreground mode
 		// Write the PID to a file so the stop command can kill the process
 		logger.Infof("Running as detached process (PID: %d)", os.Getpid())
+		// A detached process has no terminal, so SIGHUP is free to reload the secrets providers it holds,
+		// e.g. the one of the OAuth token cache
+		secrets.ReloadProvidersOnSignal(ctx)
 	} else {
 		logger.Info("Press Ctrl+C to stop or wait for container to exit")
 	}
@@ -548,6 +571,72 @@ func (r *Runner) Cleanup(ctx context.Context) error {
 	return lastErr
 }
 
+// targetReachableTimeout is how long to wait for the proxied MCP server to accept connections
+const targetReachableTimeout = 2 * time.Minute
+
+// ensureTargetReachable waits for the proxied MCP server of setupResult to accept connections.
+// If it never does, the started transport is stopped and an error is returned, so that the proxy
+// is not reported as ready. There is nothing to wait for without a target, e.g. for stdio servers.
+func ensureTargetReachable(
+	ctx context.Context,
+	setupResult *runtime.SetupResult,
+	transportHandler types.Transport,
+	maxWaitTime time.Duration,
+) error {
+	if setupResult == nil || setupResult.TargetURI == "" {
+		return nil
+	}
+
+	targetAddr := net.JoinHostPort(setupResult.TargetHost, strconv.Itoa(setupResult.TargetPort))
+	if err := waitForTargetReachable(ctx, targetAddr, maxWaitTime); err != nil {
+		if stopErr := transportHandler.Stop(ctx); stopErr != nil {
+			logger.Warnf("Warning: Failed to stop transport: %v", stopErr)
+		}
+		return fmt.Errorf("MCP server is not reachable at %s: %w", targetAddr, err)
+	}
+	return nil
+}
+
+// waitForTargetReachable repeatedly tries to open a TCP connection to the proxied MCP server at addr
+// (host:port), using exponential backoff, until it succeeds or maxWaitTime has elapsed.
+func waitForTargetReachable(ctx context.Context, addr string, maxWaitTime time.Duration) error {
+	startTime := time.Now()
+	attempt := 0
+	delay := 100 * time.Millisecond
+	maxDelay := 2 * time.Second // Cap at 2 seconds between retries
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+
+	logger.Infof("Waiting for MCP server to accept connections at %s (timeout: %v)", addr, maxWaitTime)
+
+	for {
+		attempt++
+
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			_ = conn.Close()
+			logger.Infof("MCP server at %s is reachable after %v (attempt %d)", addr, time.Since(startTime), attempt)
+			return nil
+		}
+		logger.Debugf("Failed to connect to %s (attempt %d): %v", addr, attempt, err)
+
+		elapsed := time.Since(startTime)
+		if elapsed >= maxWaitTime {
+			return fmt.Errorf("target not reachable after %v (%d attempts): %w", elapsed, attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled while waiting for target: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
 // waitForInitializeSuccess repeatedly checks if the MCP server is ready to accept requests.
 // This prevents timing issues where clients try to connect before the server is fully ready.
 // It makes repeated attempts with exponential backoff up to a maximum timeout.
@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/runtime"
+	"github.com/stacklok/toolhive/pkg/transport/types/mocks"
+)
+
+func TestWaitForTargetReachable(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	t.Run("waits for a target that comes up after a delay", func(t *testing.T) {
+		t.Parallel()
+
+		// Reserve a port, then release it so that the target is initially down
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		const startDelay = 300 * time.Millisecond
+		started := make(chan net.Listener, 1)
+		go func() {
+			time.Sleep(startDelay)
+			target, err := net.Listen("tcp", addr)
+			if err != nil {
+				close(started)
+				return
+			}
+			started <- target
+		}()
+
+		start := time.Now()
+		err = waitForTargetReachable(t.Context(), addr, 10*time.Second)
+		elapsed := time.Since(start)
+
+		target, ok := <-started
+		require.True(t, ok, "fake target failed to start")
+		defer target.Close()
+
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, elapsed, startDelay, "readiness should wait for the target to come up")
+	})
+
+	t.Run("fails when the target never comes up", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		err = waitForTargetReachable(t.Context(), addr, 300*time.Millisecond)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "target not reachable")
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+		defer cancel()
+
+		err = waitForTargetReachable(ctx, addr, time.Minute)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestEnsureTargetReachable(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	// setupResultFor returns the setup result of a target listening at addr
+	setupResultFor := func(t *testing.T, addr string) *runtime.SetupResult {
+		t.Helper()
+		host, port, err := net.SplitHostPort(addr)
+		require.NoError(t, err)
+		targetPort, err := strconv.Atoi(port)
+		require.NoError(t, err)
+		return &runtime.SetupResult{
+			TargetURI:  "http://" + addr,
+			TargetHost: host,
+			TargetPort: targetPort,
+		}
+	}
+
+	t.Run("nothing to wait for without a target", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		transportHandler := mocks.NewMockTransport(ctrl)
+
+		require.NoError(t, ensureTargetReachable(t.Context(), nil, transportHandler, time.Second))
+		require.NoError(t, ensureTargetReachable(t.Context(), &runtime.SetupResult{}, transportHandler, time.Second))
+	})
+
+	t.Run("reachable target keeps the transport running", func(t *testing.T) {
+		t.Parallel()
+
+		target, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer target.Close()
+
+		ctrl := gomock.NewController(t)
+		transportHandler := mocks.NewMockTransport(ctrl)
+
+		err = ensureTargetReachable(t.Context(), setupResultFor(t, target.Addr().String()), transportHandler, 10*time.Second)
+		require.NoError(t, err)
+	})
+
+	t.Run("unreachable target stops the transport and fails", func(t *testing.T) {
+		t.Parallel()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		ctrl := gomock.NewController(t)
+		transportHandler := mocks.NewMockTransport(ctrl)
+		transportHandler.EXPECT().Stop(gomock.Any()).Return(nil).Times(1)
+
+		err = ensureTargetReachable(t.Context(), setupResultFor(t, addr), transportHandler, 300*time.Millisecond)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "MCP server is not reachable at "+addr)
+		assert.Contains(t, err.Error(), "target not reachable")
+	})
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/1password/onepassword-sdk-go"
+
 	"github.com/stacklok/toolhive/pkg/secrets/clients"
 )
 
@@ -23,12 +26,28 @@ type OnePasswordManager struct {
 
 var timeout = 5 * time.Second
 
+// onePasswordOTPField is the field name used in a reference of the form op://<vault>/<item>/otp
+// to request the current one-time password of an item.
+const onePasswordOTPField = "otp"
+
 // GetSecret retrieves a secret from 1Password.
+// Vaults and items may be referenced by title or by ID, e.g. op://My Vault/Database/password.
+// A reference of the form op://<vault>/<item>/otp returns the current one-time password
+// computed from the TOTP field of the item.
 func (o *OnePasswordManager) GetSecret(ctx context.Context, path string) (string, error) {
 	if !strings.Contains(path, "op://") {
 		return "", fmt.Errorf("invalid secret path: %s", path)
 	}
 
+	path, err := o.resolveTitles(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	if vaultID, itemID, ok := parseOTPReference(path); ok {
+		return o.getOTP(ctx, vaultID, itemID)
+	}
+
 	secret, err := o.client.Resolve(ctx, path)
 	if err != nil {
 		return "", fmt.Errorf("error resolving secret: %v", err)
@@ -37,6 +56,129 @@ func (o *OnePasswordManager) GetSecret(ctx context.Context, path string) (string
 	return secret, nil
 }
 
+// onePasswordIDPattern matches the format of 1Password vault and item IDs
+var onePasswordIDPattern = regexp.MustCompile(`^[a-z0-9]{26}$`)
+
+// resolveTitles rewrites a reference of the form op://<vault>/<item>/<field> that uses vault or item
+// titles (e.g. op://My Vault/Database/password) into one using their IDs. Segments that already look
+// like IDs are left unchanged. Field titles are resolved by 1Password itself.
+func (o *OnePasswordManager) resolveTitles(ctx context.Context, path string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "op://"), "/")
+	if !strings.HasPrefix(path, "op://") || len(parts) < 3 {
+		return path, nil
+	}
+
+	vaultID, err := o.resolveVaultID(ctx, parts[0])
+	if err != nil {
+		return "", err
+	}
+	itemID, err := o.resolveItemID(ctx, vaultID, parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	parts[0], parts[1] = vaultID, itemID
+	return "op://" + strings.Join(parts, "/"), nil
+}
+
+// resolveVaultID returns the ID of the vault with the given title, or the reference itself if it is an ID
+func (o *OnePasswordManager) resolveVaultID(ctx context.Context, vault string) (string, error) {
+	if onePasswordIDPattern.MatchString(vault) {
+		return vault, nil
+	}
+
+	vaults, err := o.client.ListVaults(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving vaults from 1password API: %v", err)
+	}
+	var ids []string
+	for _, v := range vaults {
+		if v.Title == vault {
+			ids = append(ids, v.ID)
+		}
+	}
+	return pickTitleMatch("vault", vault, ids)
+}
+
+// resolveItemID returns the ID of the item with the given title, or the reference itself if it is an ID
+func (o *OnePasswordManager) resolveItemID(ctx context.Context, vaultID, item string) (string, error) {
+	if onePasswordIDPattern.MatchString(item) {
+		return item, nil
+	}
+
+	items, err := o.client.ListItems(ctx, vaultID)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving secrets from 1password API: %v", err)
+	}
+	var ids []string
+	for _, i := range items {
+		if i.Title == item {
+			ids = append(ids, i.ID)
+		}
+	}
+	return pickTitleMatch("item", item, ids)
+}
+
+// pickTitleMatch returns the single ID matching a title, or an error if there is none or several
+func pickTitleMatch(kind, title string, ids []string) (string, error) {
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("1password %s not found: %s", kind, title)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("1password %s title %q is ambiguous, %d %ss share it: reference it by ID instead (one of %s)",
+			kind, title, len(ids), kind, strings.Join(ids, ", "))
+	}
+}
+
+// parseOTPReference returns the vault and item of a reference of the form op://<vault>/<item>/otp
+func parseOTPReference(path string) (vaultID, itemID string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "op://"), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != onePasswordOTPField {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// getOTP returns the current one-time password of an item. If the item has a field named otp
+// it must be a TOTP field, otherwise the first TOTP field of the item is used.
+func (o *OnePasswordManager) getOTP(ctx context.Context, vaultID, itemID string) (string, error) {
+	item, err := o.client.GetItem(ctx, vaultID, itemID)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving item details from 1password API: %v", err)
+	}
+
+	var otpField *onepassword.ItemField
+	for i, field := range item.Fields {
+		if strings.EqualFold(field.ID, onePasswordOTPField) || strings.EqualFold(field.Title, onePasswordOTPField) {
+			if field.FieldType != onepassword.ItemFieldTypeTOTP {
+				return "", fmt.Errorf("field %s of item %s is not a TOTP field", field.Title, itemID)
+			}
+			otpField = &item.Fields[i]
+			break
+		}
+		if otpField == nil && field.FieldType == onepassword.ItemFieldTypeTOTP {
+			otpField = &item.Fields[i]
+		}
+	}
+	if otpField == nil {
+		return "", fmt.Errorf("item %s does not have a TOTP field", itemID)
+	}
+
+	var details *onepassword.OTPFieldDetails
+	if otpField.Details != nil {
+		details = otpField.Details.OTP()
+	}
+	if details == nil || details.Code == nil {
+		if details != nil && details.ErrorMessage != nil {
+			return "", fmt.Errorf("unable to compute one-time password for item %s: %s", itemID, *details.ErrorMessage)
+		}
+		return "", fmt.Errorf("unable to compute one-time password for item %s", itemID)
+	}
+	return *details.Code, nil
+}
+
 // SetSecret is not supported for 1Password unless there is
 // demand for it.
 func (*OnePasswordManager) SetSecret(_ context.Context, _, _ string) error {
@@ -60,7 +202,16 @@ func (o *OnePasswordManager) ListSecrets(ctx context.Context) ([]SecretDescripti
 		return nil, fmt.Errorf("error retrieving vaults from 1password API: %v", err)
 	}
 
-	var secrets []SecretDescription
+	// Collect the details of every item first, so that the result can be allocated once
+	// using the total number of fields rather than grown one field at a time.
+	type itemFields struct {
+		vaultTitle string
+		item       onepassword.ItemOverview
+		fields     []onepassword.ItemField
+	}
+	var collected []itemFields
+	totalFields := 0
+
 	// For each vault...
 	for _, vault := range vaults {
 		items, err := o.client.ListItems(ctx, vault.ID)
@@ -74,21 +225,38 @@ func (o *OnePasswordManager) ListSecrets(ctx context.Context) ([]SecretDescripti
 			if err != nil {
 				return nil, fmt.Errorf("error retrieving item details from 1password API: %v", err)
 			}
-			// For each field in the item...
-			for _, field := range details.Fields {
-				// Create a path and human-readable name for each field.
-				description := SecretDescription{
-					Key:         fmt.Sprintf("op://%s/%s/%s", item.VaultID, item.ID, field.ID),
-					Description: fmt.Sprintf("%s :: %s :: %s", vault.Title, item.Title, field.Title),
-				}
-				secrets = append(secrets, description)
-			}
+			collected = append(collected, itemFields{vaultTitle: vault.Title, item: item, fields: details.Fields})
+			totalFields += len(details.Fields)
+		}
+	}
+
+	secrets := make([]SecretDescription, 0, totalFields)
+	for _, c := range collected {
+		// For each field in the item...
+		for _, field := range c.fields {
+			// Create a path and human-readable name for each field.
+			secrets = append(secrets, SecretDescription{
+				Key:         "op://" + c.item.VaultID + "/" + c.item.ID + "/" + field.ID,
+				Description: c.vaultTitle + " :: " + c.item.Title + " :: " + field.Title,
+			})
 		}
 	}
 
 	return secrets, nil
 }
 
+// CheckHealth verifies that the 1Password service account token is valid by listing the vaults
+// it has access to, which is cheaper than listing every item.
+func (o *OnePasswordManager) CheckHealth(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err := o.client.ListVaults(ctx); err != nil {
+		return fmt.Errorf("1password health check failed: %w", err)
+	}
+	return nil
+}
+
 // Cleanup is not needed for 1Password.
 func (*OnePasswordManager) Cleanup() error {
 	return nil
@@ -106,11 +274,16 @@ func (*OnePasswordManager) Capabilities() ProviderCapabilities {
 	}
 }
 
-// NewOnePasswordManager creates an instance of OnePasswordManager.
+// NewOnePasswordManager creates an instance of OnePasswordManager using the service account token
+// in OnePasswordTokenEnvVar.
 func NewOnePasswordManager() (Provider, error) {
-	token := os.Getenv("OP_SERVICE_ACCOUNT_TOKEN")
+	return NewOnePasswordManagerWithToken(os.Getenv(OnePasswordTokenEnvVar))
+}
+
+// NewOnePasswordManagerWithToken creates an instance of OnePasswordManager using the given service account token.
+func NewOnePasswordManagerWithToken(token string) (Provider, error) {
 	if token == "" {
-		return nil, fmt.Errorf("OP_SERVICE_ACCOUNT_TOKEN is not set")
+		return nil, fmt.Errorf("%s is not set", OnePasswordTokenEnvVar)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -1,12 +1,14 @@
 package secrets_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
 
 	"github.com/1password/onepassword-sdk-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/stacklok/toolhive/pkg/secrets"
@@ -27,6 +29,11 @@ func TestNewOnePasswordManager(t *testing.T) {
 	})
 }
 
+const (
+	testVaultID = "vaultaaaaaaaaaaaaaaaaaaa01"
+	testItemID  = "itemaaaaaaaaaaaaaaaaaaaa01"
+)
+
 func TestOnePasswordManager_GetSecret(t *testing.T) {
 	t.Parallel()
 
@@ -48,10 +55,10 @@ func TestOnePasswordManager_GetSecret(t *testing.T) {
 		},
 		{
 			name: "valid path format with success",
-			path: "op://vault/item/field",
+			path: "op://" + testVaultID + "/" + testItemID + "/field",
 			setupMock: func(mockClient *cm.MockOnePasswordClient) {
 				mockClient.EXPECT().
-					Resolve(gomock.Any(), "op://vault/item/field").
+					Resolve(gomock.Any(), "op://"+testVaultID+"/"+testItemID+"/field").
 					Return("test-secret-value", nil)
 			},
 			wantSecret:  "test-secret-value",
@@ -60,16 +67,122 @@ func TestOnePasswordManager_GetSecret(t *testing.T) {
 		},
 		{
 			name: "valid path format with error",
-			path: "op://vault/item/field",
+			path: "op://" + testVaultID + "/" + testItemID + "/field",
 			setupMock: func(mockClient *cm.MockOnePasswordClient) {
 				mockClient.EXPECT().
-					Resolve(gomock.Any(), "op://vault/item/field").
+					Resolve(gomock.Any(), "op://"+testVaultID+"/"+testItemID+"/field").
 					Return("", fmt.Errorf("secret not found"))
 			},
 			wantSecret:  "",
 			wantErr:     true,
 			errContains: "error resolving secret",
 		},
+		{
+			name: "otp reference returns the current one-time password",
+			path: "op://" + testVaultID + "/" + testItemID + "/otp",
+			setupMock: func(mockClient *cm.MockOnePasswordClient) {
+				mockClient.EXPECT().
+					GetItem(gomock.Any(), testVaultID, testItemID).
+					Return(onepassword.Item{
+						ID: "item",
+						Fields: []onepassword.ItemField{
+							{ID: "password", Title: "password", FieldType: onepassword.ItemFieldTypeConcealed},
+							otpItemField("one-time password", "123456"),
+						},
+					}, nil)
+			},
+			wantSecret: "123456",
+		},
+		{
+			name: "otp reference to a field that is not a TOTP field",
+			path: "op://" + testVaultID + "/" + testItemID + "/otp",
+			setupMock: func(mockClient *cm.MockOnePasswordClient) {
+				mockClient.EXPECT().
+					GetItem(gomock.Any(), testVaultID, testItemID).
+					Return(onepassword.Item{
+						ID: "item",
+						Fields: []onepassword.ItemField{
+							{ID: "otp", Title: "otp", FieldType: onepassword.ItemFieldTypeText},
+						},
+					}, nil)
+			},
+			wantErr:     true,
+			errContains: "is not a TOTP field",
+		},
+		{
+			name: "otp reference to an item without a TOTP field",
+			path: "op://" + testVaultID + "/" + testItemID + "/otp",
+			setupMock: func(mockClient *cm.MockOnePasswordClient) {
+				mockClient.EXPECT().
+					GetItem(gomock.Any(), testVaultID, testItemID).
+					Return(onepassword.Item{
+						ID: "item",
+						Fields: []onepassword.ItemField{
+							{ID: "password", Title: "password", FieldType: onepassword.ItemFieldTypeConcealed},
+						},
+					}, nil)
+			},
+			wantErr:     true,
+			errContains: "does not have a TOTP field",
+		},
+		{
+			name: "vault and item referenced by title",
+			path: "op://My Vault/Database/password",
+			setupMock: func(mockClient *cm.MockOnePasswordClient) {
+				mockClient.EXPECT().
+					ListVaults(gomock.Any()).
+					Return([]onepassword.VaultOverview{
+						{ID: "othervaultaaaaaaaaaaaaaa01", Title: "Other Vault"},
+						{ID: testVaultID, Title: "My Vault"},
+					}, nil)
+				mockClient.EXPECT().
+					ListItems(gomock.Any(), testVaultID, gomock.Any()).
+					Return([]onepassword.ItemOverview{
+						{ID: testItemID, Title: "Database", VaultID: testVaultID},
+						{ID: "otheritemaaaaaaaaaaaaaaa01", Title: "Cache", VaultID: testVaultID},
+					}, nil)
+				mockClient.EXPECT().
+					Resolve(gomock.Any(), "op://"+testVaultID+"/"+testItemID+"/password").
+					Return("db-password", nil)
+			},
+			wantSecret: "db-password",
+		},
+		{
+			name: "vault and item referenced by ID",
+			path: "op://" + testVaultID + "/" + testItemID + "/password",
+			setupMock: func(mockClient *cm.MockOnePasswordClient) {
+				// IDs are used as is, without listing vaults or items
+				mockClient.EXPECT().
+					Resolve(gomock.Any(), "op://"+testVaultID+"/"+testItemID+"/password").
+					Return("db-password", nil)
+			},
+			wantSecret: "db-password",
+		},
+		{
+			name: "ambiguous item title",
+			path: "op://" + testVaultID + "/Database/password",
+			setupMock: func(mockClient *cm.MockOnePasswordClient) {
+				mockClient.EXPECT().
+					ListItems(gomock.Any(), testVaultID, gomock.Any()).
+					Return([]onepassword.ItemOverview{
+						{ID: testItemID, Title: "Database", VaultID: testVaultID},
+						{ID: "otheritemaaaaaaaaaaaaaaa01", Title: "Database", VaultID: testVaultID},
+					}, nil)
+			},
+			wantErr:     true,
+			errContains: "is ambiguous",
+		},
+		{
+			name: "unknown vault title",
+			path: "op://Missing/Database/password",
+			setupMock: func(mockClient *cm.MockOnePasswordClient) {
+				mockClient.EXPECT().
+					ListVaults(gomock.Any()).
+					Return([]onepassword.VaultOverview{{ID: testVaultID, Title: "My Vault"}}, nil)
+			},
+			wantErr:     true,
+			errContains: "1password vault not found: Missing",
+		},
 	}
 
 	for _, tt := range tests {
@@ -108,6 +221,45 @@ func TestOnePasswordManager_GetSecret(t *testing.T) {
 	}
 }
 
+func TestOnePasswordManager_CheckHealth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("healthy when vaults can be listed", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockClient := cm.NewMockOnePasswordClient(ctrl)
+		mockClient.EXPECT().ListVaults(gomock.Any()).Return([]onepassword.VaultOverview{{ID: "vault1"}}, nil)
+
+		var checker secrets.HealthChecker = secrets.NewOnePasswordManagerWithClient(mockClient)
+		assert.NoError(t, checker.CheckHealth(t.Context()))
+	})
+
+	t.Run("unauthorized token is unhealthy", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockClient := cm.NewMockOnePasswordClient(ctrl)
+		mockClient.EXPECT().ListVaults(gomock.Any()).
+			Return(nil, fmt.Errorf("401 Unauthorized: invalid service account token"))
+
+		manager := secrets.NewOnePasswordManagerWithClient(mockClient)
+		err := secrets.NewFallbackProvider(manager).(secrets.HealthChecker).CheckHealth(t.Context())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1password health check failed")
+		assert.Contains(t, err.Error(), "401")
+	})
+}
+
+// otpItemField returns a TOTP item field whose current code is code
+func otpItemField(title, code string) onepassword.ItemField {
+	details := onepassword.NewItemFieldDetailsTypeVariantOTP(&onepassword.OTPFieldDetails{Code: &code})
+	return onepassword.ItemField{
+		ID:        "totp_field",
+		Title:     title,
+		FieldType: onepassword.ItemFieldTypeTOTP,
+		Details:   &details,
+	}
+}
+
 func TestOnePasswordManager_ListSecrets(t *testing.T) {
 	t.Parallel()
 
@@ -357,3 +509,97 @@ func TestOnePasswordManager_UnsupportedOperations(t *testing.T) {
 		assert.NoError(t, err, "Cleanup should return nil as it's not supported")
 	})
 }
+
+// fakeOnePasswordClient serves a fixed set of vaults, items and fields without mock bookkeeping,
+// so that benchmarks measure ListSecrets rather than the mock framework.
+type fakeOnePasswordClient struct {
+	vaults []onepassword.VaultOverview
+	items  map[string][]onepassword.ItemOverview
+	fields map[string][]onepassword.ItemField
+}
+
+func newFakeOnePasswordClient(vaults, itemsPerVault, fieldsPerItem int) *fakeOnePasswordClient {
+	client := &fakeOnePasswordClient{
+		items:  make(map[string][]onepassword.ItemOverview),
+		fields: make(map[string][]onepassword.ItemField),
+	}
+	for v := 0; v < vaults; v++ {
+		vaultID := fmt.Sprintf("vault%d", v)
+		client.vaults = append(client.vaults, onepassword.VaultOverview{ID: vaultID, Title: "Vault " + vaultID})
+		for i := 0; i < itemsPerVault; i++ {
+			itemID := fmt.Sprintf("%s-item%d", vaultID, i)
+			client.items[vaultID] = append(client.items[vaultID],
+				onepassword.ItemOverview{ID: itemID, Title: "Item " + itemID, VaultID: vaultID})
+			for f := 0; f < fieldsPerItem; f++ {
+				fieldID := fmt.Sprintf("field%d", f)
+				client.fields[itemID] = append(client.fields[itemID],
+					onepassword.ItemField{ID: fieldID, Title: "Field " + fieldID})
+			}
+		}
+	}
+	return client
+}
+
+func (c *fakeOnePasswordClient) ListVaults(_ context.Context) ([]onepassword.VaultOverview, error) {
+	return c.vaults, nil
+}
+
+func (c *fakeOnePasswordClient) ListItems(
+	_ context.Context, vaultID string, _ ...onepassword.ItemListFilter,
+) ([]onepassword.ItemOverview, error) {
+	return c.items[vaultID], nil
+}
+
+func (c *fakeOnePasswordClient) GetItem(_ context.Context, _, itemID string) (onepassword.Item, error) {
+	return onepassword.Item{ID: itemID, Fields: c.fields[itemID]}, nil
+}
+
+func (*fakeOnePasswordClient) Resolve(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+// listSecretsReference is the original, field-by-field implementation of ListSecrets,
+// kept to assert that the optimised implementation produces identical output.
+func listSecretsReference(ctx context.Context, client *fakeOnePasswordClient) []secrets.SecretDescription {
+	var result []secrets.SecretDescription
+	vaults, _ := client.ListVaults(ctx)
+	for _, vault := range vaults {
+		items, _ := client.ListItems(ctx, vault.ID)
+		for _, item := range items {
+			details, _ := client.GetItem(ctx, vault.ID, item.ID)
+			for _, field := range details.Fields {
+				result = append(result, secrets.SecretDescription{
+					Key:         fmt.Sprintf("op://%s/%s/%s", item.VaultID, item.ID, field.ID),
+					Description: fmt.Sprintf("%s :: %s :: %s", vault.Title, item.Title, field.Title),
+				})
+			}
+		}
+	}
+	return result
+}
+
+func TestOnePasswordManager_ListSecrets_MatchesReference(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeOnePasswordClient(3, 20, 5)
+	manager := secrets.NewOnePasswordManagerWithClient(client)
+
+	result, err := manager.ListSecrets(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, listSecretsReference(t.Context(), client), result)
+	assert.Len(t, result, 3*20*5)
+}
+
+func BenchmarkListSecrets(b *testing.B) {
+	client := newFakeOnePasswordClient(10, 100, 10)
+	manager := secrets.NewOnePasswordManagerWithClient(client)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := manager.ListSecrets(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
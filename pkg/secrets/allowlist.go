@@ -0,0 +1,108 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+)
+
+// ErrSecretAccessDenied is returned when a secret is not permitted by an AllowListProvider
+var ErrSecretAccessDenied = errors.New("access to secret denied")
+
+// AllowListProvider wraps a provider to restrict the secrets which can be accessed through it to
+// those matching a set of patterns, e.g. to limit an MCP server to its own secrets in multi-tenant
+// setups. Denied secrets are rejected without reaching the wrapped provider.
+type AllowListProvider struct {
+	Provider
+	patterns []string
+}
+
+// NewAllowListProvider creates a provider permitting only the secrets of provider whose name matches one
+// of patterns. Patterns use the path.Match syntax, in which `*` does not match the `/` separating the
+// segments of a name, e.g. `github/*` permits `github/token` but not `github/app/key`.
+func NewAllowListProvider(provider Provider, patterns []string) (*AllowListProvider, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid secret pattern %q: %w", pattern, err)
+		}
+	}
+	return &AllowListProvider{
+		Provider: provider,
+		patterns: patterns,
+	}, nil
+}
+
+// permitted returns true if the secret name matches one of the patterns
+func (a *AllowListProvider) permitted(name string) bool {
+	for _, pattern := range a.patterns {
+		// Patterns are validated on creation, so matching cannot fail
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPermitted returns ErrSecretAccessDenied if the secret is not permitted
+func (a *AllowListProvider) checkPermitted(name string) error {
+	if !a.permitted(name) {
+		return fmt.Errorf("%w: %s", ErrSecretAccessDenied, name)
+	}
+	return nil
+}
+
+// GetSecret retrieves a permitted secret from the wrapped provider
+func (a *AllowListProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	if err := a.checkPermitted(name); err != nil {
+		return "", err
+	}
+	return a.Provider.GetSecret(ctx, name)
+}
+
+// GetSecretBytes retrieves the raw value of a permitted secret from the wrapped provider
+func (a *AllowListProvider) GetSecretBytes(ctx context.Context, name string) ([]byte, error) {
+	if err := a.checkPermitted(name); err != nil {
+		return nil, err
+	}
+	return GetSecretBytes(ctx, a.Provider, name)
+}
+
+// SetSecret stores a permitted secret in the wrapped provider
+func (a *AllowListProvider) SetSecret(ctx context.Context, name, value string) error {
+	if err := a.checkPermitted(name); err != nil {
+		return err
+	}
+	return a.Provider.SetSecret(ctx, name, value)
+}
+
+// DeleteSecret deletes a permitted secret from the wrapped provider
+func (a *AllowListProvider) DeleteSecret(ctx context.Context, name string) error {
+	if err := a.checkPermitted(name); err != nil {
+		return err
+	}
+	return a.Provider.DeleteSecret(ctx, name)
+}
+
+// ListSecrets lists the permitted secrets of the wrapped provider
+func (a *AllowListProvider) ListSecrets(ctx context.Context) ([]SecretDescription, error) {
+	descriptions, err := a.Provider.ListSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permitted := make([]SecretDescription, 0, len(descriptions))
+	for _, description := range descriptions {
+		if a.permitted(description.Key) {
+			permitted = append(permitted, description)
+		}
+	}
+	return permitted, nil
+}
+
+// CheckHealth delegates to the wrapped provider if it supports health checks
+func (a *AllowListProvider) CheckHealth(ctx context.Context) error {
+	if checker, ok := a.Provider.(HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
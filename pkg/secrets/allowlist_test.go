@@ -0,0 +1,82 @@
+package secrets_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/secrets/mocks"
+)
+
+func TestAllowListProvider_GetSecret(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		secretName string
+		permitted  bool
+	}{
+		{name: "exact match", secretName: "shared-api-key", permitted: true},
+		{name: "glob match", secretName: "github-mcp/token", permitted: true},
+		{name: "glob does not cross segments", secretName: "github-mcp/app/key"},
+		{name: "denied reference", secretName: "slack-mcp/token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockProvider := mocks.NewMockProvider(ctrl)
+			if tt.permitted {
+				mockProvider.EXPECT().GetSecret(gomock.Any(), tt.secretName).Return("value", nil)
+			}
+			// Denied references must not reach the backend, which gomock enforces through the missing expectation
+
+			provider, err := secrets.NewAllowListProvider(mockProvider, []string{"github-mcp/*", "shared-api-key"})
+			require.NoError(t, err)
+
+			value, err := provider.GetSecret(t.Context(), tt.secretName)
+			if !tt.permitted {
+				assert.ErrorIs(t, err, secrets.ErrSecretAccessDenied)
+				assert.False(t, secrets.IsNotFoundError(err), "denied secrets must not fall back to defaults")
+				assert.Empty(t, value)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "value", value)
+		})
+	}
+}
+
+func TestAllowListProvider_ListSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockProvider := mocks.NewMockProvider(ctrl)
+	mockProvider.EXPECT().ListSecrets(gomock.Any()).Return([]secrets.SecretDescription{
+		{Key: "github-mcp/token"},
+		{Key: "slack-mcp/token"},
+	}, nil)
+
+	provider, err := secrets.NewAllowListProvider(mockProvider, []string{"github-mcp/*"})
+	require.NoError(t, err)
+
+	descriptions, err := provider.ListSecrets(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []secrets.SecretDescription{{Key: "github-mcp/token"}}, descriptions)
+
+	assert.ErrorIs(t, provider.SetSecret(t.Context(), "slack-mcp/token", "value"), secrets.ErrSecretAccessDenied)
+	assert.ErrorIs(t, provider.DeleteSecret(t.Context(), "slack-mcp/token"), secrets.ErrSecretAccessDenied)
+}
+
+func TestNewAllowListProvider_InvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	provider, err := secrets.NewAllowListProvider(secrets.NewFileProvider(t.TempDir()), []string{"github-mcp/["})
+	assert.Error(t, err)
+	assert.Nil(t, provider)
+}
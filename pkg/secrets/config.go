@@ -0,0 +1,104 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/adrg/xdg"
+)
+
+const (
+	// OnePasswordTokenEnvVar is the environment variable used to specify the 1Password service account token.
+	OnePasswordTokenEnvVar = "OP_SERVICE_ACCOUNT_TOKEN"
+
+	// DisableEnvFallbackEnvVar is the environment variable used to disable the environment variable fallback
+	// of the secrets providers.
+	DisableEnvFallbackEnvVar = "TOOLHIVE_DISABLE_ENV_FALLBACK"
+)
+
+// ProviderConfig configures the secrets providers programmatically.
+// Each empty field falls back to its environment variable, and then to its default.
+type ProviderConfig struct {
+	// EncryptedFilePath is the file the encrypted provider stores secrets in.
+	// Defaults to toolhive/secrets_encrypted in the XDG data directory.
+	EncryptedFilePath string
+
+	// OnePasswordToken is the service account token of the 1Password provider.
+	// Falls back to OnePasswordTokenEnvVar.
+	OnePasswordToken string
+
+	// FileRoot is the directory the file provider reads secrets from.
+	// Falls back to FileRootEnvVar, then DefaultFileRoot.
+	FileRoot string
+
+	// EnvPrefix is the prefix of the environment variables read by the environment provider
+	// and the environment variable fallback. Defaults to EnvVarPrefix.
+	EnvPrefix string
+
+	// DisableEnvFallback disables the environment variable fallback of the providers.
+	// The fallback is also disabled when DisableEnvFallbackEnvVar is set to "true".
+	DisableEnvFallback bool
+
+	// TraceResolution wraps the provider in a TracingProvider logging which provider resolves each secret.
+	TraceResolution bool
+
+	// AutoFallbackType is the provider AutoType resolves to outside Kubernetes.
+	// Defaults to NoneType, see ResolveAutoProviderType.
+	AutoFallbackType ProviderType
+}
+
+// providerConfigFrom returns the first non-nil config, or an empty config if there is none
+func providerConfigFrom(configs []*ProviderConfig) *ProviderConfig {
+	for _, cfg := range configs {
+		if cfg != nil {
+			return cfg
+		}
+	}
+	return &ProviderConfig{}
+}
+
+func (c *ProviderConfig) encryptedFilePath() (string, error) {
+	if c.EncryptedFilePath != "" {
+		return c.EncryptedFilePath, nil
+	}
+	secretsPath, err := xdg.DataFile("toolhive/secrets_encrypted")
+	if err != nil {
+		return "", fmt.Errorf("unable to access secrets file path %v", err)
+	}
+	return secretsPath, nil
+}
+
+func (c *ProviderConfig) onePasswordToken() string {
+	if c.OnePasswordToken != "" {
+		return c.OnePasswordToken
+	}
+	return os.Getenv(OnePasswordTokenEnvVar)
+}
+
+func (c *ProviderConfig) fileRoot() string {
+	if c.FileRoot != "" {
+		return c.FileRoot
+	}
+	if root := os.Getenv(FileRootEnvVar); root != "" {
+		return root
+	}
+	return DefaultFileRoot
+}
+
+func (c *ProviderConfig) envPrefix() string {
+	if c.EnvPrefix != "" {
+		return c.EnvPrefix
+	}
+	return EnvVarPrefix
+}
+
+// envFallbackEnabled determines if environment variable fallback should be enabled
+func (c *ProviderConfig) envFallbackEnabled() bool {
+	// Check for explicit opt-out
+	if c.DisableEnvFallback || os.Getenv(DisableEnvFallbackEnvVar) == "true" {
+		return false
+	}
+
+	// Enable by default for non-environment providers
+	return true
+}
@@ -21,6 +21,22 @@ type EncryptedManager struct {
 	// Key used to re-encrypt the secrets file if changes are needed.
 	key     []byte
 	secrets syncmap.Map // Thread-safe map for storing secrets
+
+	// envFallback enables reading secrets missing from the store from the process environment
+	envFallback bool
+	envPrefix   string
+}
+
+// EncryptedManagerOption configures an EncryptedManager.
+type EncryptedManagerOption func(*EncryptedManager)
+
+// WithEnvFallback makes GetSecret fall back to the environment variable prefix+name when a secret
+// is not found in the encrypted store. This is intended for local development and is off by default.
+func WithEnvFallback(prefix string) EncryptedManagerOption {
+	return func(e *EncryptedManager) {
+		e.envFallback = true
+		e.envPrefix = prefix
+	}
 }
 
 // fileStructure is the structure of the secrets file.
@@ -36,6 +52,11 @@ func (e *EncryptedManager) GetSecret(_ context.Context, name string) (string, er
 
 	value, ok := e.secrets.Load(name)
 	if !ok {
+		if e.envFallback {
+			if envValue := os.Getenv(e.envPrefix + name); envValue != "" {
+				return envValue, nil
+			}
+		}
 		return "", fmt.Errorf("secret not found: %s", name)
 	}
 	return value.(string), nil
@@ -67,7 +88,18 @@ func (e *EncryptedManager) DeleteSecret(_ context.Context, name string) error {
 	return e.updateFile()
 }
 
+// CountSecrets returns the number of secrets stored in the manager without exposing their values.
+func (e *EncryptedManager) CountSecrets() (int, error) {
+	count := 0
+	e.secrets.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count, nil
+}
+
 // ListSecrets returns a list of all secret names stored in the manager.
+// Only the names are returned, the descriptions never contain secret values.
 func (e *EncryptedManager) ListSecrets(_ context.Context) ([]SecretDescription, error) {
 	var secretNames []SecretDescription
 
@@ -113,6 +145,7 @@ func (e *EncryptedManager) updateFile() error {
 	}
 
 	encryptedContents, err := aes.Encrypt(contents, e.key)
+	Zero(contents)
 	if err != nil {
 		return fmt.Errorf("failed to encrypt secrets: %w", err)
 	}
@@ -125,7 +158,7 @@ func (e *EncryptedManager) updateFile() error {
 }
 
 // NewEncryptedManager creates an instance of EncryptedManager.
-func NewEncryptedManager(filePath string, key []byte) (Provider, error) {
+func NewEncryptedManager(filePath string, key []byte, opts ...EncryptedManagerOption) (Provider, error) {
 	if len(key) == 0 {
 		return nil, errors.New("key cannot be empty")
 	}
@@ -149,6 +182,9 @@ func NewEncryptedManager(filePath string, key []byte) (Provider, error) {
 		secrets:  syncmap.Map{},
 		key:      key,
 	}
+	for _, opt := range opts {
+		opt(manager)
+	}
 
 	// If the file is not empty, load the secrets into the syncmap.Map
 	if stat.Size() > 0 {
@@ -164,6 +200,7 @@ func NewEncryptedManager(filePath string, key []byte) (Provider, error) {
 
 		var contents fileStructure
 		err = json.Unmarshal(decryptedContents, &contents)
+		Zero(decryptedContents)
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode secrets file: %w", err)
 		}
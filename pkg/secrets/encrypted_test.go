@@ -238,6 +238,87 @@ func TestEncryptedManager_Cleanup(t *testing.T) {
 	assert.Empty(t, secrets, "There should be no secrets after cleanup")
 }
 
+func TestEncryptedManager_CountSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	tempFile := createTempFile(t)
+	defer os.Remove(tempFile)
+
+	manager := createEncryptedManager(t, tempFile, generateRandomKey(t))
+
+	count, err := manager.CountSecrets()
+	require.NoError(t, err)
+	assert.Zero(t, count, "There should be no secrets initially")
+
+	values := map[string]string{
+		"github-token": "ghp_supersecretvalue",
+		"db-password":  "hunter2-password",
+		"api-key":      "sk-verysecretkey",
+	}
+	for name, value := range values {
+		require.NoError(t, manager.SetSecret(ctx, name, value))
+	}
+
+	count, err = manager.CountSecrets()
+	require.NoError(t, err)
+	assert.Equal(t, len(values), count, "The count should match the number of stored keys")
+
+	descriptions, err := manager.ListSecrets(ctx)
+	require.NoError(t, err)
+	assert.Len(t, descriptions, count)
+	for _, description := range descriptions {
+		for _, value := range values {
+			assert.NotContains(t, description.Key, value, "Keys must not contain secret values")
+			assert.NotContains(t, description.Description, value, "Descriptions must not contain secret values")
+		}
+	}
+
+	require.NoError(t, manager.DeleteSecret(ctx, "api-key"))
+	count, err = manager.CountSecrets()
+	require.NoError(t, err)
+	assert.Equal(t, len(values)-1, count)
+}
+
+func TestEncryptedManager_WithEnvFallback(t *testing.T) { //nolint:paralleltest
+	tempFile := createTempFile(t)
+	defer os.Remove(tempFile)
+
+	manager, err := NewEncryptedManager(tempFile, generateRandomKey(t), WithEnvFallback("TEST_ENC_FALLBACK_"))
+	require.NoError(t, err)
+	require.NoError(t, manager.SetSecret(t.Context(), "IN_STORE", "store-value"))
+
+	t.Setenv("TEST_ENC_FALLBACK_IN_STORE", "env-value")
+	t.Setenv("TEST_ENC_FALLBACK_ONLY_IN_ENV", "env-only-value")
+
+	t.Run("hit in store", func(t *testing.T) { //nolint:paralleltest
+		value, err := manager.GetSecret(t.Context(), "IN_STORE")
+		require.NoError(t, err)
+		assert.Equal(t, "store-value", value, "The store should take precedence over the environment")
+	})
+
+	t.Run("fallback to environment", func(t *testing.T) { //nolint:paralleltest
+		value, err := manager.GetSecret(t.Context(), "ONLY_IN_ENV")
+		require.NoError(t, err)
+		assert.Equal(t, "env-only-value", value)
+	})
+
+	t.Run("miss everywhere", func(t *testing.T) { //nolint:paralleltest
+		_, err := manager.GetSecret(t.Context(), "MISSING")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "secret not found")
+	})
+
+	t.Run("fallback is off by default", func(t *testing.T) { //nolint:paralleltest
+		defaultFile := createTempFile(t)
+		defer os.Remove(defaultFile)
+		defaultManager := createEncryptedManager(t, defaultFile, generateRandomKey(t))
+		t.Setenv("ONLY_IN_ENV", "unprefixed-value")
+
+		_, err := defaultManager.GetSecret(t.Context(), "ONLY_IN_ENV")
+		require.Error(t, err)
+	})
+}
+
 func TestNewEncryptedManager(t *testing.T) {
 	t.Parallel()
 	ctx := t.Context()
@@ -14,8 +14,14 @@ type EnvironmentProvider struct {
 
 // NewEnvironmentProvider creates a new environment variable secrets provider
 func NewEnvironmentProvider() Provider {
+	return NewEnvironmentProviderWithPrefix(EnvVarPrefix)
+}
+
+// NewEnvironmentProviderWithPrefix creates a new environment variable secrets provider
+// reading the secret `name` from the environment variable `<prefix>name`
+func NewEnvironmentProviderWithPrefix(prefix string) Provider {
 	return &EnvironmentProvider{
-		prefix: EnvVarPrefix,
+		prefix: prefix,
 	}
 }
 
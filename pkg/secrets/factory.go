@@ -11,9 +11,10 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/adrg/xdg"
 	"golang.org/x/term"
 
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/env"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/process"
 	"github.com/stacklok/toolhive/pkg/secrets/keyring"
@@ -56,8 +57,37 @@ const (
 
 	// EnvironmentType represents the environment variable secret provider
 	EnvironmentType ProviderType = "environment"
+
+	// FileType represents the mounted file tree secret provider
+	FileType ProviderType = "file"
+
+	// AutoType selects the secret provider matching the current environment,
+	// see ResolveAutoProviderType.
+	AutoType ProviderType = "auto"
 )
 
+// ResolveAutoProviderType returns the provider type AutoType stands for in the current environment.
+// The type is resolved in the following order:
+//  1. In Kubernetes, secrets are injected into the workload as environment variables, so the environment
+//     provider is used, whatever the configured fallback is.
+//  2. Elsewhere, the configured fallback provider is used.
+//  3. Without a configured fallback, or if the fallback is AutoType itself, the none provider is used.
+func ResolveAutoProviderType(configured ProviderType) ProviderType {
+	return ResolveAutoProviderTypeWithEnv(&env.OSReader{}, configured)
+}
+
+// ResolveAutoProviderTypeWithEnv resolves AutoType using the provided environment reader.
+// This allows for dependency injection of environment variable access for testing.
+func ResolveAutoProviderTypeWithEnv(envReader env.Reader, configured ProviderType) ProviderType {
+	if runtime.IsKubernetesRuntimeWithEnv(envReader) {
+		return EnvironmentType
+	}
+	if configured == "" || configured == AutoType {
+		return NoneType
+	}
+	return configured
+}
+
 // ErrUnknownManagerType is returned when an invalid value for ProviderType is specified.
 var ErrUnknownManagerType = errors.New("unknown secret manager type")
 
@@ -104,6 +134,8 @@ func ValidateProviderWithPassword(ctx context.Context, providerType ProviderType
 		return validateNoneProvider(result)
 	case EnvironmentType:
 		return ValidateEnvironmentProvider(ctx, provider, result)
+	case FileType:
+		return validateFileProvider(ctx, provider, result)
 	default:
 		result.Error = fmt.Errorf("unknown provider type: %s", providerType)
 		result.Message = "Unknown provider type"
@@ -172,8 +204,13 @@ func validateEncryptedProvider(ctx context.Context, provider Provider, result *S
 
 // validateOnePasswordProvider tests 1Password provider connectivity
 func validateOnePasswordProvider(ctx context.Context, provider Provider, result *SetupResult) *SetupResult {
-	// Test basic functionality by attempting to list secrets
-	_, err := provider.ListSecrets(ctx)
+	// Prefer the cheap health check, falling back to listing secrets
+	var err error
+	if checker, ok := provider.(HealthChecker); ok {
+		err = checker.CheckHealth(ctx)
+	} else {
+		_, err = provider.ListSecrets(ctx)
+	}
 	if err != nil {
 		result.Error = fmt.Errorf("failed to connect to 1Password: %w", err)
 		result.Message = "Failed to connect to 1Password service"
@@ -185,6 +222,19 @@ func validateOnePasswordProvider(ctx context.Context, provider Provider, result
 	return result
 }
 
+// validateFileProvider tests that the file provider root directory can be listed
+func validateFileProvider(ctx context.Context, provider Provider, result *SetupResult) *SetupResult {
+	if _, err := provider.ListSecrets(ctx); err != nil {
+		result.Error = fmt.Errorf("failed to read secrets directory: %w", err)
+		result.Message = "File provider validation failed"
+		return result
+	}
+
+	result.Success = true
+	result.Message = "File provider validation successful"
+	return result
+}
+
 // validateNoneProvider validates the none provider (always succeeds)
 func validateNoneProvider(result *SetupResult) *SetupResult {
 	// None provider doesn't need validation, it always works
@@ -206,20 +256,36 @@ func IsKeyringAvailable() bool {
 }
 
 // CreateSecretProvider creates the specified type of secrets provider.
+// An optional ProviderConfig configures the provider, unset fields fall back to the environment variables.
 // TODO CREATE function does not actually create anything, refactor or rename
-func CreateSecretProvider(managerType ProviderType) (Provider, error) {
-	return CreateSecretProviderWithPassword(managerType, "")
+func CreateSecretProvider(managerType ProviderType, cfg ...*ProviderConfig) (Provider, error) {
+	return CreateSecretProviderWithPassword(managerType, "", cfg...)
 }
 
 // CreateSecretProviderWithPassword creates the specified type of secrets provider with an optional password.
 // If password is empty, it uses the current functionality (read from keyring or stdin).
 // If password is provided, it uses that password and stores it in the keyring if not already setup.
-func CreateSecretProviderWithPassword(managerType ProviderType, password string) (Provider, error) {
+// An optional ProviderConfig configures the provider, unset fields fall back to the environment variables.
+func CreateSecretProviderWithPassword(managerType ProviderType, password string, cfg ...*ProviderConfig) (Provider, error) {
+	config := providerConfigFrom(cfg)
+	provider, err := createSecretProvider(managerType, password, config)
+	// The provider AutoType resolves to is traced when it is created
+	if err != nil || !config.TraceResolution || managerType == AutoType {
+		return provider, err
+	}
+	return NewTracingProvider(provider, managerType), nil
+}
+
+func createSecretProvider(managerType ProviderType, password string, config *ProviderConfig) (Provider, error) {
 	// Create the primary provider
 	var primary Provider
 	var err error
 
 	switch managerType {
+	case AutoType:
+		resolved := ResolveAutoProviderType(config.AutoFallbackType)
+		logger.Debugf("Resolved %s secrets provider to %s", AutoType, resolved)
+		return CreateSecretProviderWithPassword(resolved, password, config)
 	case EncryptedType:
 		// Enforce keyring availability for encrypted provider
 		if !IsKeyringAvailable() {
@@ -232,21 +298,25 @@ func CreateSecretProviderWithPassword(managerType ProviderType, password string)
 		}
 		// Convert to 256-bit hash for use with AES-GCM.
 		key := sha256.Sum256(secretsPassword)
-		secretsPath, err := xdg.DataFile("toolhive/secrets_encrypted")
+		Zero(secretsPassword)
+		secretsPath, err := config.encryptedFilePath()
 		if err != nil {
-			return nil, fmt.Errorf("unable to access secrets file path %v", err)
+			return nil, err
 		}
 		primary, err = NewEncryptedManager(secretsPath, key[:])
 		if err != nil {
 			return nil, err
 		}
 	case OnePasswordType:
-		primary, err = NewOnePasswordManager()
+		primary, err = NewOnePasswordManagerWithToken(config.onePasswordToken())
 	case NoneType:
 		primary, err = NewNoneManager()
 	case EnvironmentType:
 		// Direct environment provider - no fallback needed
-		return NewEnvironmentProvider(), nil
+		return NewEnvironmentProviderWithPrefix(config.envPrefix()), nil
+	case FileType:
+		// Mounted secret files are the source of truth - no fallback needed
+		return NewFileProvider(config.fileRoot()), nil
 	default:
 		return nil, ErrUnknownManagerType
 	}
@@ -256,27 +326,17 @@ func CreateSecretProviderWithPassword(managerType ProviderType, password string)
 	}
 
 	// Wrap with fallback provider if enabled
-	if shouldEnableFallback() {
-		return NewFallbackProvider(primary), nil
+	if config.envFallbackEnabled() {
+		return newFallbackProvider(primary, managerType, config.envPrefix()), nil
 	}
 
 	return primary, nil
 }
 
-// shouldEnableFallback determines if environment variable fallback should be enabled
-func shouldEnableFallback() bool {
-	// Check for explicit opt-out
-	if os.Getenv("TOOLHIVE_DISABLE_ENV_FALLBACK") == "true" {
-		return false
-	}
-
-	// Enable by default for non-environment providers
-	return true
-}
-
 // GetSecretsPassword returns the password to use for encrypting and decrypting secrets.
 // If optionalPassword is provided and keyring is not yet setup, it uses that password and stores it.
 // Otherwise, it uses the current functionality (read from keyring or stdin).
+// Callers should pass the returned password to Zero once it is no longer needed.
 func GetSecretsPassword(optionalPassword string) ([]byte, error) {
 	provider := getKeyringProvider()
 
@@ -180,12 +180,165 @@ func TestFallbackIntegration(t *testing.T) { //nolint:paralleltest
 	})
 }
 
+func TestCreateSecretProvider_WithConfig(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	t.Run("file provider uses the configured root over the environment", func(t *testing.T) { //nolint:paralleltest
+		root := t.TempDir()
+		writeSecretFiles(t, root, map[string]string{"api-token": "configured-value"})
+		t.Setenv(secrets.FileRootEnvVar, t.TempDir())
+
+		provider, err := secrets.CreateSecretProvider(secrets.FileType, &secrets.ProviderConfig{FileRoot: root})
+		require.NoError(t, err)
+
+		value, err := provider.GetSecret(t.Context(), "api-token")
+		require.NoError(t, err)
+		assert.Equal(t, "configured-value", value)
+	})
+
+	t.Run("environment provider uses the configured prefix", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv("CUSTOM_PREFIX_api_token", "prefixed-value")
+
+		provider, err := secrets.CreateSecretProvider(secrets.EnvironmentType, &secrets.ProviderConfig{EnvPrefix: "CUSTOM_PREFIX_"})
+		require.NoError(t, err)
+
+		value, err := provider.GetSecret(t.Context(), "api_token")
+		require.NoError(t, err)
+		assert.Equal(t, "prefixed-value", value)
+	})
+
+	t.Run("fallback uses the configured prefix", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv("TOOLHIVE_DISABLE_ENV_FALLBACK", "")
+		t.Setenv("CUSTOM_PREFIX_api_token", "prefixed-value")
+
+		provider, err := secrets.CreateSecretProvider(secrets.NoneType, &secrets.ProviderConfig{EnvPrefix: "CUSTOM_PREFIX_"})
+		require.NoError(t, err)
+
+		value, err := provider.GetSecret(t.Context(), "api_token")
+		require.NoError(t, err)
+		assert.Equal(t, "prefixed-value", value)
+	})
+
+	t.Run("fallback disabled by config", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv("TOOLHIVE_DISABLE_ENV_FALLBACK", "")
+		t.Setenv(secrets.EnvVarPrefix+"api_token", testSecretValue)
+
+		provider, err := secrets.CreateSecretProvider(secrets.NoneType, &secrets.ProviderConfig{DisableEnvFallback: true})
+		require.NoError(t, err)
+
+		_, err = provider.GetSecret(t.Context(), "api_token")
+		assert.Error(t, err)
+	})
+
+	t.Run("1password without token", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv(secrets.OnePasswordTokenEnvVar, "")
+
+		provider, err := secrets.CreateSecretProvider(secrets.OnePasswordType, &secrets.ProviderConfig{})
+		assert.ErrorContains(t, err, "OP_SERVICE_ACCOUNT_TOKEN is not set")
+		assert.Nil(t, provider)
+	})
+
+	t.Run("nil config uses the environment", func(t *testing.T) { //nolint:paralleltest
+		root := t.TempDir()
+		writeSecretFiles(t, root, map[string]string{"api-token": "env-value"})
+		t.Setenv(secrets.FileRootEnvVar, root)
+
+		provider, err := secrets.CreateSecretProvider(secrets.FileType, nil)
+		require.NoError(t, err)
+
+		value, err := provider.GetSecret(t.Context(), "api-token")
+		require.NoError(t, err)
+		assert.Equal(t, "env-value", value)
+	})
+}
+
+func TestResolveAutoProviderType(t *testing.T) { //nolint:paralleltest
+	tests := []struct {
+		name                  string
+		kubernetesServiceHost string
+		configured            secrets.ProviderType
+		expected              secrets.ProviderType
+	}{
+		{
+			name:                  "kubernetes uses the environment provider",
+			kubernetesServiceHost: "10.96.0.1",
+			configured:            secrets.EncryptedType,
+			expected:              secrets.EnvironmentType,
+		},
+		{
+			name:       "local uses the configured provider",
+			configured: secrets.EncryptedType,
+			expected:   secrets.EncryptedType,
+		},
+		{
+			name:     "local without configured provider uses none",
+			expected: secrets.NoneType,
+		},
+		{
+			name:       "local with auto configured uses none",
+			configured: secrets.AutoType,
+			expected:   secrets.NoneType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { //nolint:paralleltest
+			t.Setenv("TOOLHIVE_RUNTIME", "")
+			t.Setenv("KUBERNETES_SERVICE_HOST", tt.kubernetesServiceHost)
+
+			assert.Equal(t, tt.expected, secrets.ResolveAutoProviderType(tt.configured))
+		})
+	}
+}
+
+func TestCreateSecretProvider_Auto(t *testing.T) { //nolint:paralleltest
+	t.Run("kubernetes", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv("TOOLHIVE_RUNTIME", "")
+		t.Setenv("KUBERNETES_SERVICE_HOST", "10.96.0.1")
+
+		provider, err := secrets.CreateSecretProvider(secrets.AutoType)
+		require.NoError(t, err)
+		assert.IsType(t, secrets.NewEnvironmentProvider(), provider)
+	})
+
+	t.Run("local", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv("TOOLHIVE_RUNTIME", "")
+		t.Setenv("KUBERNETES_SERVICE_HOST", "")
+		t.Setenv("TOOLHIVE_DISABLE_ENV_FALLBACK", "true")
+
+		provider, err := secrets.CreateSecretProvider(secrets.AutoType)
+		require.NoError(t, err)
+		expected, err := secrets.NewNoneManager()
+		require.NoError(t, err)
+		assert.IsType(t, expected, provider)
+	})
+
+	t.Run("local with a configured fallback", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv("TOOLHIVE_RUNTIME", "")
+		t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+		provider, err := secrets.CreateSecretProvider(secrets.AutoType,
+			&secrets.ProviderConfig{AutoFallbackType: secrets.FileType})
+		require.NoError(t, err)
+		assert.IsType(t, secrets.NewFileProvider(t.TempDir()), provider)
+	})
+
+	t.Run("kubernetes ignores the configured fallback", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv("TOOLHIVE_RUNTIME", "")
+		t.Setenv("KUBERNETES_SERVICE_HOST", "10.96.0.1")
+
+		provider, err := secrets.CreateSecretProvider(secrets.AutoType,
+			&secrets.ProviderConfig{AutoFallbackType: secrets.FileType})
+		require.NoError(t, err)
+		assert.IsType(t, secrets.NewEnvironmentProvider(), provider)
+	})
+}
+
 func TestProviderTypes(t *testing.T) { //nolint:paralleltest
 	t.Run("all provider types are valid strings", func(t *testing.T) { //nolint:paralleltest
 		assert.Equal(t, "encrypted", string(secrets.EncryptedType))
 		assert.Equal(t, "1password", string(secrets.OnePasswordType))
 		assert.Equal(t, "none", string(secrets.NoneType))
 		assert.Equal(t, "environment", string(secrets.EnvironmentType))
+		assert.Equal(t, "auto", string(secrets.AutoType))
 	})
 }
 
@@ -10,15 +10,23 @@ import (
 // FallbackProvider wraps a primary provider with environment variable fallback
 type FallbackProvider struct {
 	primary     Provider
+	primaryType ProviderType
 	envProvider Provider
 }
 
 // NewFallbackProvider creates a new provider with environment variable fallback
 func NewFallbackProvider(primary Provider) Provider {
+	return newFallbackProvider(primary, "", EnvVarPrefix)
+}
+
+// newFallbackProvider creates a new provider falling back to the environment variables with the prefix.
+// primaryType is reported as the source of the secrets read from the primary provider, if known.
+func newFallbackProvider(primary Provider, primaryType ProviderType, prefix string) Provider {
 	return &FallbackProvider{
-		primary: primary,
+		primary:     primary,
+		primaryType: primaryType,
 		envProvider: &EnvironmentProvider{
-			prefix: EnvVarPrefix,
+			prefix: prefix,
 		},
 	}
 }
@@ -26,26 +34,33 @@ func NewFallbackProvider(primary Provider) Provider {
 // GetSecret attempts to get a secret from the primary provider,
 // falling back to environment variables if not found
 func (f *FallbackProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	value, _, err := f.GetSecretWithSource(ctx, name)
+	return value, err
+}
+
+// GetSecretWithSource attempts to get a secret like GetSecret, and also returns EnvironmentType
+// when the secret was read from the environment variable fallback
+func (f *FallbackProvider) GetSecretWithSource(ctx context.Context, name string) (string, ProviderType, error) {
 	// First, try the primary provider
 	value, err := f.primary.GetSecret(ctx, name)
 	if err == nil {
-		return value, nil
+		return value, f.primaryType, nil
 	}
 
 	// Check if it's a "not found" error
 	if !IsNotFoundError(err) {
-		return "", err
+		return "", "", err
 	}
 
 	// Try environment variable fallback
 	envValue, envErr := f.envProvider.GetSecret(ctx, name)
 	if envErr == nil {
 		logger.Debugf("Secret '%s' retrieved from environment variable fallback", name)
-		return envValue, nil
+		return envValue, EnvironmentType, nil
 	}
 
 	// Return the original error if no fallback found
-	return "", err
+	return "", "", err
 }
 
 // SetSecret always uses the primary provider (no env var writes)
@@ -74,6 +89,14 @@ func (f *FallbackProvider) Capabilities() ProviderCapabilities {
 	return f.primary.Capabilities()
 }
 
+// CheckHealth delegates to the primary provider if it supports health checks
+func (f *FallbackProvider) CheckHealth(ctx context.Context) error {
+	if checker, ok := f.primary.(HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
+
 // IsNotFoundError checks if an error indicates a secret was not found
 func IsNotFoundError(err error) bool {
 	if err == nil {
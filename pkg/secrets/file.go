@@ -0,0 +1,187 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// FileRootEnvVar is the environment variable used to specify the directory the file provider reads secrets from.
+	FileRootEnvVar = "TOOLHIVE_SECRETS_FILE_ROOT"
+
+	// DefaultFileRoot is the directory the file provider reads secrets from when FileRootEnvVar is not set.
+	// It matches the mount path used in the Secrets Store CSI driver examples.
+	DefaultFileRoot = "/mnt/secrets-store"
+)
+
+// FileProvider reads secrets from files in a mounted directory tree, such as the
+// volumes populated by the Secrets Store CSI driver. The secret `name` is read from
+// `<root>/name` and the secret `name/subkey` from `<root>/name/subkey`.
+type FileProvider struct {
+	root string
+}
+
+// NewFileProvider creates a new file secrets provider reading secrets below root
+func NewFileProvider(root string) Provider {
+	return &FileProvider{
+		root: root,
+	}
+}
+
+// NewFileProviderFromEnv creates a new file secrets provider reading secrets below the
+// directory in FileRootEnvVar, or DefaultFileRoot if it is not set.
+func NewFileProviderFromEnv() Provider {
+	return NewFileProvider((&ProviderConfig{}).fileRoot())
+}
+
+// GetSecret retrieves a secret from the file named after it below the root directory.
+// A single trailing newline is removed from the file contents.
+func (f *FileProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	value, err := f.GetSecretBytes(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(value), "\n"), nil
+}
+
+// GetSecretBytes retrieves the unmodified contents of the file named after the secret below the
+// root directory, for binary secrets.
+func (f *FileProvider) GetSecretBytes(_ context.Context, name string) ([]byte, error) {
+	path, err := f.secretPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("secret not found: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("secret %s is a directory, not a file", name)
+	}
+
+	// #nosec G304 - path is validated to be below the root directory
+	value, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+	return value, nil
+}
+
+// secretPath returns the path of the file holding the secret
+func (f *FileProvider) secretPath(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("secret name cannot be empty")
+	}
+	// Secret names are slash separated paths which must stay below the root directory
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("invalid secret name: %s", name)
+	}
+	return filepath.Join(f.root, filepath.FromSlash(name)), nil
+}
+
+// SetSecret is not supported for mounted secret files
+func (*FileProvider) SetSecret(_ context.Context, name, _ string) error {
+	if name == "" {
+		return errors.New("secret name cannot be empty")
+	}
+	return errors.New("file provider is read-only")
+}
+
+// DeleteSecret is not supported for mounted secret files
+func (*FileProvider) DeleteSecret(_ context.Context, name string) error {
+	if name == "" {
+		return errors.New("secret name cannot be empty")
+	}
+	return errors.New("file provider is read-only")
+}
+
+// ListSecrets lists the files below the root directory, keyed by their slash separated
+// path relative to it. Hidden entries, such as the `..data` directory of atomically
+// written volumes, are skipped.
+func (f *FileProvider) ListSecrets(_ context.Context) ([]SecretDescription, error) {
+	var secrets []SecretDescription
+	err := filepath.WalkDir(f.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == f.root {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		// Mounted volumes usually expose their files as symlinks, only list the ones pointing to files
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(f.root, path)
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, SecretDescription{Key: filepath.ToSlash(rel)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets in %s: %w", f.root, err)
+	}
+
+	return secrets, nil
+}
+
+// ListSecretsSummary lists the secrets below the root directory along with their number of keys.
+// A file directly below the root directory is a secret with a single key, while a directory, such as
+// a mounted Kubernetes Secret, is a secret whose keys are the files below it.
+func (f *FileProvider) ListSecretsSummary(ctx context.Context) ([]SecretSummary, error) {
+	descriptions, err := f.ListSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []SecretSummary
+	index := make(map[string]int)
+	for _, description := range descriptions {
+		name, _, _ := strings.Cut(description.Key, "/")
+		i, ok := index[name]
+		if !ok {
+			i = len(summaries)
+			index[name] = i
+			summaries = append(summaries, SecretSummary{Name: name})
+		}
+		summaries[i].KeyCount++
+	}
+	return summaries, nil
+}
+
+// Cleanup is a no-op for file provider
+func (*FileProvider) Cleanup() error {
+	return nil
+}
+
+// Capabilities returns the capabilities of the file provider
+func (*FileProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{
+		CanRead:    true,
+		CanWrite:   false,
+		CanDelete:  false,
+		CanList:    true,
+		CanCleanup: false,
+	}
+}
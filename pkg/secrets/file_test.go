@@ -0,0 +1,216 @@
+package secrets_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+// writeSecretFiles creates the given slash separated files below root
+func writeSecretFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, value := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(value), 0o600))
+	}
+}
+
+func TestFileProvider_GetSecret(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeSecretFiles(t, root, map[string]string{
+		"api-token":           "token-value\n",
+		"github/token":        "github-token",
+		"github/nested/value": "nested-value",
+		"multiline":           "line1\nline2\n\n",
+	})
+	provider := secrets.NewFileProvider(root)
+
+	tests := []struct {
+		name        string
+		secret      string
+		expected    string
+		expectedErr string
+	}{
+		{name: "top level file", secret: "api-token", expected: "token-value"},
+		{name: "subkey file", secret: "github/token", expected: "github-token"},
+		{name: "nested subkey file", secret: "github/nested/value", expected: "nested-value"},
+		{name: "only the last newline is trimmed", secret: "multiline", expected: "line1\nline2\n"},
+		{name: "missing file", secret: "missing", expectedErr: "secret not found: missing"},
+		{name: "missing subkey", secret: "github/missing", expectedErr: "secret not found: github/missing"},
+		{name: "directory", secret: "github", expectedErr: "is a directory"},
+		{name: "empty name", secret: "", expectedErr: "secret name cannot be empty"},
+		{name: "path traversal", secret: "../outside", expectedErr: "invalid secret name"},
+		{name: "absolute path", secret: "/etc/passwd", expectedErr: "invalid secret name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := provider.GetSecret(t.Context(), tt.secret)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+
+	_, err := provider.GetSecret(t.Context(), "missing")
+	assert.True(t, secrets.IsNotFoundError(err), "missing files should be reported as not found")
+}
+
+func TestFileProvider_GetSecretBytes(t *testing.T) {
+	t.Parallel()
+
+	// A gzip header followed by invalid UTF-8 and a trailing newline, as found in binary keystores
+	binary := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe, 0xc3, 0x28, 0x00, '\n'}
+	require.False(t, utf8.Valid(binary))
+
+	root := t.TempDir()
+	writeSecretFiles(t, root, map[string]string{"keystore/data": string(binary)})
+	provider := secrets.NewFileProvider(root)
+
+	value, err := provider.(secrets.BinaryReader).GetSecretBytes(t.Context(), "keystore/data")
+	require.NoError(t, err)
+	assert.Equal(t, binary, value)
+
+	value, err = secrets.GetSecretBytes(t.Context(), provider, "keystore/data")
+	require.NoError(t, err)
+	assert.Equal(t, binary, value)
+
+	reloadable, err := secrets.NewReloadableProvider(secrets.FileType, &secrets.ProviderConfig{FileRoot: root})
+	require.NoError(t, err)
+	defer reloadable.Close()
+	value, err = secrets.GetSecretBytes(t.Context(), reloadable, "keystore/data")
+	require.NoError(t, err)
+	assert.Equal(t, binary, value)
+
+	// GetSecret removes the trailing newline, which alters binary secrets
+	text, err := provider.GetSecret(t.Context(), "keystore/data")
+	require.NoError(t, err)
+	assert.Equal(t, binary[:len(binary)-1], []byte(text))
+
+	_, err = secrets.GetSecretBytes(t.Context(), provider, "missing")
+	assert.True(t, secrets.IsNotFoundError(err))
+}
+
+func TestGetSecretBytes_WithoutBinaryReader(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	t.Setenv(secrets.EnvVarPrefix+"api_token", "token-value")
+
+	value, err := secrets.GetSecretBytes(t.Context(), secrets.NewEnvironmentProvider(), "api_token")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("token-value"), value)
+}
+
+func TestFileProvider_ListSecrets(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeSecretFiles(t, root, map[string]string{
+		"api-token":                 "token-value",
+		"github/token":              "github-token",
+		"github/nested/value":       "nested-value",
+		"..2025_01_01/api-token":    "token-value",
+		".hidden":                   "hidden",
+		"github/.hidden/unexpected": "hidden",
+	})
+	// Atomically written volumes expose their files as symlinks into a hidden data directory
+	require.NoError(t, os.Symlink(filepath.Join(root, "..2025_01_01", "api-token"), filepath.Join(root, "linked")))
+
+	provider := secrets.NewFileProvider(root)
+	descriptions, err := provider.ListSecrets(t.Context())
+	require.NoError(t, err)
+
+	keys := make([]string, 0, len(descriptions))
+	for _, description := range descriptions {
+		keys = append(keys, description.Key)
+	}
+	assert.ElementsMatch(t, []string{"api-token", "github/token", "github/nested/value", "linked"}, keys)
+
+	value, err := provider.GetSecret(t.Context(), "linked")
+	require.NoError(t, err)
+	assert.Equal(t, "token-value", value)
+}
+
+func TestFileProvider_ListSecretsSummary(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeSecretFiles(t, root, map[string]string{
+		"api-token":              "token-value",
+		"github/token":           "github-token",
+		"github/nested/value":    "nested-value",
+		"slack/token":            "slack-token",
+		"slack/signing-secret":   "signing-secret",
+		"slack/.hidden":          "hidden",
+		"..2025_01_01/api-token": "token-value",
+	})
+
+	provider := secrets.NewFileProvider(root)
+	summarizer, ok := provider.(secrets.SecretSummarizer)
+	require.True(t, ok, "file provider should implement SecretSummarizer")
+
+	summaries, err := summarizer.ListSecretsSummary(t.Context())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []secrets.SecretSummary{
+		{Name: "api-token", KeyCount: 1},
+		{Name: "github", KeyCount: 2},
+		{Name: "slack", KeyCount: 2},
+	}, summaries)
+
+	// The summary counts match the per-key listing
+	descriptions, err := provider.ListSecrets(t.Context())
+	require.NoError(t, err)
+	total := 0
+	for _, summary := range summaries {
+		total += summary.KeyCount
+	}
+	assert.Equal(t, len(descriptions), total)
+
+	_, err = secrets.NewFileProvider(filepath.Join(root, "missing")).(secrets.SecretSummarizer).
+		ListSecretsSummary(t.Context())
+	assert.Error(t, err)
+}
+
+func TestFileProvider_ListSecretsMissingRoot(t *testing.T) {
+	t.Parallel()
+
+	provider := secrets.NewFileProvider(filepath.Join(t.TempDir(), "missing"))
+	_, err := provider.ListSecrets(t.Context())
+	assert.Error(t, err)
+}
+
+func TestFileProvider_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	provider := secrets.NewFileProvider(t.TempDir())
+	assert.Error(t, provider.SetSecret(t.Context(), "name", "value"))
+	assert.Error(t, provider.DeleteSecret(t.Context(), "name"))
+	assert.True(t, provider.Capabilities().IsReadOnly())
+	assert.True(t, provider.Capabilities().CanList)
+}
+
+func TestNewFileProviderFromEnv(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	root := t.TempDir()
+	writeSecretFiles(t, root, map[string]string{"api-token": "token-value"})
+	t.Setenv(secrets.FileRootEnvVar, root)
+
+	provider, err := secrets.CreateSecretProvider(secrets.FileType)
+	require.NoError(t, err)
+
+	value, err := provider.GetSecret(t.Context(), "api-token")
+	require.NoError(t, err)
+	assert.Equal(t, "token-value", value)
+}
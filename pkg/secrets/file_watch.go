@@ -0,0 +1,104 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// fileWatchDebounce is how long to wait for further file system events before re-reading
+// a secret, so that a multi-step rotation produces a single emission
+const fileWatchDebounce = 100 * time.Millisecond
+
+// WatchSecret watches the file holding the secret and emits its new value each time it changes.
+//
+// The Secrets Store CSI driver and Kubernetes volumes rotate files atomically: the new files are
+// written to a fresh hidden directory, and a `..data` symlink is then renamed over to point at it.
+// Watching the file itself would miss these renames, so the directories containing it are watched
+// instead and the secret is re-read on any change. The channel is closed when the context is cancelled.
+func (f *FileProvider) WatchSecret(ctx context.Context, name string) (<-chan string, error) {
+	path, err := f.secretPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	// The root directory holds the `..data` symlink swapped on rotation, and the parent directory
+	// holds the file itself for secrets with subkeys
+	dirs := []string{f.root}
+	if dir := filepath.Dir(path); dir != filepath.Clean(f.root) {
+		dirs = append(dirs, dir)
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("failed to watch secrets directory %s: %w", dir, err)
+		}
+	}
+
+	// Only changes to the value are emitted, a missing secret is not an initial value
+	last, _ := f.GetSecret(ctx, name)
+
+	updates := make(chan string, 1)
+	go func() {
+		defer close(updates)
+		defer watcher.Close()
+
+		// The debounce timer is only armed once an event has been received
+		debounce := time.NewTimer(fileWatchDebounce)
+		debounce.Stop()
+		defer debounce.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op == fsnotify.Chmod {
+					continue
+				}
+				logger.Debugf("Secrets directory changed: %s", event)
+				debounce.Reset(fileWatchDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warnf("Error watching secret %s: %v", name, err)
+			case <-debounce.C:
+				// A parent directory reached through a symlink is replaced on rotation, so it is
+				// watched again to follow the new directory
+				for _, dir := range dirs[1:] {
+					_ = watcher.Add(dir)
+				}
+
+				value, err := f.GetSecret(ctx, name)
+				if err != nil {
+					logger.Warnf("Failed to reload secret %s: %v", name, err)
+					continue
+				}
+				if value == last {
+					continue
+				}
+				last = value
+				select {
+				case updates <- value:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,91 @@
+package secrets_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+// waitForSecretUpdate returns the next value emitted by a secret watch
+func waitForSecretUpdate(t *testing.T, updates <-chan string) string {
+	t.Helper()
+	select {
+	case value := <-updates:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for secret update")
+		return ""
+	}
+}
+
+func TestFileProvider_WatchSecret(t *testing.T) {
+	t.Parallel()
+
+	// Needed to prevent a nil pointer dereference in the logger.
+	logger.Initialize()
+
+	root := t.TempDir()
+	writeSecretFiles(t, root, map[string]string{"github/token": "initial"})
+	provider := secrets.NewFileProvider(root).(secrets.Watcher)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	updates, err := provider.WatchSecret(ctx, "github/token")
+	require.NoError(t, err)
+
+	writeSecretFiles(t, root, map[string]string{"github/token": "rotated"})
+	assert.Equal(t, "rotated", waitForSecretUpdate(t, updates))
+
+	// The channel is closed once the context is cancelled
+	cancel()
+	select {
+	case _, ok := <-updates:
+		assert.False(t, ok, "channel should be closed after cancellation")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+}
+
+func TestFileProvider_WatchSecretAtomicRename(t *testing.T) {
+	t.Parallel()
+
+	// Needed to prevent a nil pointer dereference in the logger.
+	logger.Initialize()
+
+	// Reproduce the layout of atomically written volumes: the secret is a symlink through
+	// `..data`, which is swapped to a new timestamped directory on rotation
+	root := t.TempDir()
+	writeSecretFiles(t, root, map[string]string{"..2025_01_01/api-token": "initial"})
+	require.NoError(t, os.Symlink("..2025_01_01", filepath.Join(root, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "api-token"), filepath.Join(root, "api-token")))
+	provider := secrets.NewFileProvider(root).(secrets.Watcher)
+
+	updates, err := provider.WatchSecret(t.Context(), "api-token")
+	require.NoError(t, err)
+
+	writeSecretFiles(t, root, map[string]string{"..2025_01_02/api-token": "rotated"})
+	require.NoError(t, os.Symlink("..2025_01_02", filepath.Join(root, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(root, "..data_tmp"), filepath.Join(root, "..data")))
+	assert.Equal(t, "rotated", waitForSecretUpdate(t, updates))
+}
+
+func TestFileProvider_WatchSecretErrors(t *testing.T) {
+	t.Parallel()
+
+	provider := secrets.NewFileProvider(filepath.Join(t.TempDir(), "missing")).(secrets.Watcher)
+
+	_, err := provider.WatchSecret(t.Context(), "../outside")
+	assert.Error(t, err)
+
+	_, err = provider.WatchSecret(t.Context(), "api-token")
+	assert.Error(t, err, "watching a missing root directory should fail")
+}
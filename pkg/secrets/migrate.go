@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// Migrate copies every secret listed by src into dst, e.g. when switching from the encrypted
+// provider to 1Password. Secrets that dst refuses to store are skipped with a warning rather than
+// aborting the migration. It returns the number of secrets written to dst. Secrets are not removed
+// from src.
+func Migrate(ctx context.Context, src, dst Provider) (int, error) {
+	if !src.Capabilities().CanList {
+		return 0, errors.New("source secrets provider does not support listing secrets")
+	}
+	if !dst.Capabilities().CanWrite {
+		return 0, errors.New("destination secrets provider does not support writing secrets")
+	}
+
+	descriptions, err := src.ListSecrets(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list source secrets: %w", err)
+	}
+
+	migrated := 0
+	var skipped []string
+	for _, description := range descriptions {
+		value, err := src.GetSecret(ctx, description.Key)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to read secret %s from source: %w", description.Key, err)
+		}
+
+		if err := dst.SetSecret(ctx, description.Key, value); err != nil {
+			logger.Warnf("Skipping secret %s: destination rejected it: %v", description.Key, err)
+			skipped = append(skipped, description.Key)
+			continue
+		}
+		migrated++
+	}
+
+	if len(skipped) > 0 {
+		logger.Warnf("Migrated %d of %d secrets, skipped: %v", migrated, len(descriptions), skipped)
+	} else {
+		logger.Infof("Migrated %d secrets", migrated)
+	}
+	return migrated, nil
+}
@@ -0,0 +1,117 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// mapProvider is an in-memory provider used to exercise Migrate
+type mapProvider struct {
+	secrets map[string]string
+	// reject makes SetSecret fail for keys with this prefix
+	reject       string
+	capabilities ProviderCapabilities
+}
+
+func newMapProvider(secrets map[string]string) *mapProvider {
+	if secrets == nil {
+		secrets = make(map[string]string)
+	}
+	return &mapProvider{
+		secrets:      secrets,
+		capabilities: ProviderCapabilities{CanRead: true, CanWrite: true, CanDelete: true, CanList: true},
+	}
+}
+
+func (p *mapProvider) GetSecret(_ context.Context, name string) (string, error) {
+	value, ok := p.secrets[name]
+	if !ok {
+		return "", errors.New("secret not found: " + name)
+	}
+	return value, nil
+}
+
+func (p *mapProvider) SetSecret(_ context.Context, name, value string) error {
+	if p.reject != "" && strings.HasPrefix(name, p.reject) {
+		return errors.New("invalid secret name: " + name)
+	}
+	p.secrets[name] = value
+	return nil
+}
+
+func (p *mapProvider) DeleteSecret(_ context.Context, name string) error {
+	delete(p.secrets, name)
+	return nil
+}
+
+func (p *mapProvider) ListSecrets(_ context.Context) ([]SecretDescription, error) {
+	descriptions := make([]SecretDescription, 0, len(p.secrets))
+	for key := range p.secrets {
+		descriptions = append(descriptions, SecretDescription{Key: key})
+	}
+	return descriptions, nil
+}
+
+func (*mapProvider) Cleanup() error {
+	return nil
+}
+
+func (p *mapProvider) Capabilities() ProviderCapabilities {
+	return p.capabilities
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	t.Run("copies all secrets and skips rejected keys", func(t *testing.T) {
+		t.Parallel()
+
+		src := newMapProvider(map[string]string{
+			"github-token": "ghp_123",
+			"db-password":  "hunter2",
+			"op://vault/x": "rejected",
+		})
+		dst := newMapProvider(map[string]string{"existing": "kept"})
+		dst.reject = "op://"
+
+		migrated, err := Migrate(t.Context(), src, dst)
+		require.NoError(t, err)
+		assert.Equal(t, 2, migrated)
+		assert.Equal(t, map[string]string{
+			"github-token": "ghp_123",
+			"db-password":  "hunter2",
+			"existing":     "kept",
+		}, dst.secrets)
+		assert.Len(t, src.secrets, 3, "source secrets must not be removed")
+	})
+
+	t.Run("source without listing support", func(t *testing.T) {
+		t.Parallel()
+
+		src := newMapProvider(nil)
+		src.capabilities.CanList = false
+
+		_, err := Migrate(t.Context(), src, newMapProvider(nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support listing")
+	})
+
+	t.Run("read-only destination", func(t *testing.T) {
+		t.Parallel()
+
+		dst := newMapProvider(nil)
+		dst.capabilities.CanWrite = false
+
+		_, err := Migrate(t.Context(), newMapProvider(map[string]string{"a": "b"}), dst)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support writing")
+	})
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// secretReferencePattern matches the `${secret:<ref>}` references to secrets in environment variable values
+var secretReferencePattern = regexp.MustCompile(`\$\{secret:([^}]*)\}`)
+
+// HasSecretReferences returns true if the value references secrets with `${secret:<ref>}` tokens
+func HasSecretReferences(value string) bool {
+	return secretReferencePattern.MatchString(value)
+}
+
+// SecretReferences returns the secrets referenced by the `${secret:<ref>}` tokens of value,
+// in order of appearance and without duplicates
+func SecretReferences(value string) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, match := range secretReferencePattern.FindAllStringSubmatch(value, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			refs = append(refs, match[1])
+		}
+	}
+	return refs
+}
+
+// ExpandSecretReferences replaces the `${secret:<ref>}` tokens of value with the result of mapping
+// each ref. The first error returned by mapping is returned. Empty refs are rejected.
+func ExpandSecretReferences(value string, mapping func(ref string) (string, error)) (string, error) {
+	var expandErr error
+	expanded := secretReferencePattern.ReplaceAllStringFunc(value, func(token string) string {
+		if expandErr != nil {
+			return token
+		}
+		ref := secretReferencePattern.FindStringSubmatch(token)[1]
+		if ref == "" {
+			expandErr = fmt.Errorf("empty secret reference in %s", token)
+			return token
+		}
+		replacement, err := mapping(ref)
+		if err != nil {
+			expandErr = err
+			return token
+		}
+		return replacement
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return expanded, nil
+}
+
+// ResolveSecretReferences replaces the `${secret:<ref>}` tokens of value with the secrets named ref
+// read from provider. Each referenced secret is read once.
+func ResolveSecretReferences(ctx context.Context, value string, provider Provider) (string, error) {
+	resolved := make(map[string]string)
+	return ExpandSecretReferences(value, func(ref string) (string, error) {
+		if secret, ok := resolved[ref]; ok {
+			return secret, nil
+		}
+		secret, err := provider.GetSecret(ctx, ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve secret reference %s: %w", ref, err)
+		}
+		if err := CheckTextSecret(ref, secret); err != nil {
+			return "", err
+		}
+		resolved[ref] = secret
+		return secret, nil
+	})
+}
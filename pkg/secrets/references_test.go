@@ -0,0 +1,55 @@
+package secrets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/secrets/mocks"
+)
+
+func TestResolveSecretReferences(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockProvider := mocks.NewMockProvider(ctrl)
+	// Each referenced secret is read once
+	mockProvider.EXPECT().GetSecret(gomock.Any(), "token/value").Return("abc123", nil).Times(1)
+	mockProvider.EXPECT().GetSecret(gomock.Any(), "user").Return("alice", nil).Times(1)
+
+	value := "Bearer ${secret:token/value} for ${secret:user}, again ${secret:token/value}"
+	assert.True(t, secrets.HasSecretReferences(value))
+	assert.Equal(t, []string{"token/value", "user"}, secrets.SecretReferences(value))
+
+	resolved, err := secrets.ResolveSecretReferences(t.Context(), value, mockProvider)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer abc123 for alice, again abc123", resolved)
+}
+
+func TestResolveSecretReferences_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockProvider := mocks.NewMockProvider(ctrl)
+	mockProvider.EXPECT().GetSecret(gomock.Any(), "missing").Return("", errors.New("secret not found: missing"))
+
+	_, err := secrets.ResolveSecretReferences(t.Context(), "Bearer ${secret:missing}", mockProvider)
+	assert.ErrorContains(t, err, "failed to resolve secret reference missing")
+
+	_, err = secrets.ResolveSecretReferences(t.Context(), "Bearer ${secret:}", mockProvider)
+	assert.ErrorContains(t, err, "empty secret reference")
+
+	mockProvider.EXPECT().GetSecret(gomock.Any(), "keystore").Return(string([]byte{0x1f, 0x8b, 0xff}), nil)
+	_, err = secrets.ResolveSecretReferences(t.Context(), "${secret:keystore}", mockProvider)
+	assert.ErrorContains(t, err, "secret keystore holds binary data")
+
+	// Values without references are returned as is, without reading any secret
+	assert.False(t, secrets.HasSecretReferences("Bearer $TOKEN ${TOKEN}"))
+	resolved, err := secrets.ResolveSecretReferences(t.Context(), "Bearer ${TOKEN}", mockProvider)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer ${TOKEN}", resolved)
+}
@@ -0,0 +1,181 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+var (
+	reloadableProviders   = make(map[*ReloadableProvider]struct{})
+	reloadableProvidersMu sync.Mutex
+)
+
+// ReloadableProvider is a provider which can be rebuilt from its current configuration while in use,
+// e.g. to pick up a rotated token. The rebuilt provider atomically replaces the previous one, so
+// concurrent calls are served by either of them, never by a partially configured provider.
+// Reloadable providers are registered for ReloadProviders until they are closed.
+type ReloadableProvider struct {
+	providerType ProviderType
+	// mu serializes the reloads
+	mu      sync.Mutex
+	config  *ProviderConfig
+	current atomic.Pointer[Provider]
+}
+
+// NewReloadableProvider creates a reloadable provider of the specified type.
+// An optional ProviderConfig configures the provider, unset fields fall back to the environment
+// variables, which are read again on each reload.
+func NewReloadableProvider(providerType ProviderType, cfg ...*ProviderConfig) (*ReloadableProvider, error) {
+	r := &ReloadableProvider{
+		providerType: providerType,
+		config:       providerConfigFrom(cfg),
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	reloadableProvidersMu.Lock()
+	reloadableProviders[r] = struct{}{}
+	reloadableProvidersMu.Unlock()
+	return r, nil
+}
+
+// Reload rebuilds the provider from its current configuration.
+// If the provider cannot be rebuilt, the previous one is kept and the error is returned.
+func (r *ReloadableProvider) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reload(r.config)
+}
+
+// Reconfigure rebuilds the provider from a new configuration, which is kept for the next reloads
+// if the provider could be rebuilt.
+func (r *ReloadableProvider) Reconfigure(cfg *ProviderConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reload(providerConfigFrom([]*ProviderConfig{cfg}))
+}
+
+func (r *ReloadableProvider) reload(cfg *ProviderConfig) error {
+	provider, err := CreateSecretProvider(r.providerType, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to reload %s secrets provider: %w", r.providerType, err)
+	}
+	r.config = cfg
+	r.current.Store(&provider)
+	return nil
+}
+
+// Close unregisters the provider from ReloadProviders. The provider remains usable.
+func (r *ReloadableProvider) Close() {
+	reloadableProvidersMu.Lock()
+	delete(reloadableProviders, r)
+	reloadableProvidersMu.Unlock()
+}
+
+func (r *ReloadableProvider) provider() Provider {
+	return *r.current.Load()
+}
+
+// GetSecret retrieves a secret from the current provider
+func (r *ReloadableProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	return r.provider().GetSecret(ctx, name)
+}
+
+// GetSecretWithSource retrieves a secret from the current provider, along with its source if
+// the current provider reports it
+func (r *ReloadableProvider) GetSecretWithSource(ctx context.Context, name string) (string, ProviderType, error) {
+	provider := r.provider()
+	if reporter, ok := provider.(SourceReporter); ok {
+		return reporter.GetSecretWithSource(ctx, name)
+	}
+	value, err := provider.GetSecret(ctx, name)
+	return value, "", err
+}
+
+// GetSecretBytes retrieves the raw value of a secret from the current provider
+func (r *ReloadableProvider) GetSecretBytes(ctx context.Context, name string) ([]byte, error) {
+	return GetSecretBytes(ctx, r.provider(), name)
+}
+
+// SetSecret stores a secret in the current provider
+func (r *ReloadableProvider) SetSecret(ctx context.Context, name, value string) error {
+	return r.provider().SetSecret(ctx, name, value)
+}
+
+// DeleteSecret deletes a secret from the current provider
+func (r *ReloadableProvider) DeleteSecret(ctx context.Context, name string) error {
+	return r.provider().DeleteSecret(ctx, name)
+}
+
+// ListSecrets lists the secrets of the current provider
+func (r *ReloadableProvider) ListSecrets(ctx context.Context) ([]SecretDescription, error) {
+	return r.provider().ListSecrets(ctx)
+}
+
+// Cleanup delegates to the current provider
+func (r *ReloadableProvider) Cleanup() error {
+	return r.provider().Cleanup()
+}
+
+// Capabilities returns the current provider's capabilities
+func (r *ReloadableProvider) Capabilities() ProviderCapabilities {
+	return r.provider().Capabilities()
+}
+
+// CheckHealth delegates to the current provider if it supports health checks
+func (r *ReloadableProvider) CheckHealth(ctx context.Context) error {
+	if checker, ok := r.provider().(HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
+
+// ReloadProviders reloads all the reloadable providers which have not been closed.
+// The providers which fail to reload keep their previous configuration, and their errors are returned.
+func ReloadProviders() error {
+	reloadableProvidersMu.Lock()
+	providers := make([]*ReloadableProvider, 0, len(reloadableProviders))
+	for r := range reloadableProviders {
+		providers = append(providers, r)
+	}
+	reloadableProvidersMu.Unlock()
+
+	var errs []error
+	for _, r := range providers {
+		if err := r.Reload(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReloadProvidersOnSignal reloads the reloadable providers each time the process receives SIGHUP,
+// until the context is cancelled.
+func ReloadProvidersOnSignal(ctx context.Context) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigCh:
+				logger.Info("Received SIGHUP, reloading secrets providers")
+				if err := ReloadProviders(); err != nil {
+					logger.Warnf("Failed to reload secrets providers: %v", err)
+				}
+			}
+		}
+	}()
+}
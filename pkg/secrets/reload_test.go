@@ -0,0 +1,68 @@
+package secrets_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+// assertSecretValue asserts that the provider returns the value for the secret
+func assertSecretValue(t *testing.T, provider secrets.Provider, name, expected string) {
+	t.Helper()
+	value, err := provider.GetSecret(t.Context(), name)
+	require.NoError(t, err)
+	assert.Equal(t, expected, value)
+}
+
+func TestReloadableProvider_Reconfigure(t *testing.T) {
+	t.Parallel()
+
+	oldRoot, newRoot := t.TempDir(), t.TempDir()
+	writeSecretFiles(t, oldRoot, map[string]string{"api-token": "old-token"})
+	writeSecretFiles(t, newRoot, map[string]string{"api-token": "new-token"})
+
+	provider, err := secrets.NewReloadableProvider(secrets.FileType, &secrets.ProviderConfig{FileRoot: oldRoot})
+	require.NoError(t, err)
+	defer provider.Close()
+	assertSecretValue(t, provider, "api-token", "old-token")
+
+	require.NoError(t, provider.Reconfigure(&secrets.ProviderConfig{FileRoot: newRoot}))
+	assertSecretValue(t, provider, "api-token", "new-token")
+
+	// The new configuration is kept for the next reloads
+	require.NoError(t, provider.Reload())
+	assertSecretValue(t, provider, "api-token", "new-token")
+}
+
+func TestReloadProviders(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	oldRoot, newRoot := t.TempDir(), t.TempDir()
+	writeSecretFiles(t, oldRoot, map[string]string{"api-token": "old-token"})
+	writeSecretFiles(t, newRoot, map[string]string{"api-token": "new-token"})
+	t.Setenv(secrets.FileRootEnvVar, oldRoot)
+
+	provider, err := secrets.NewReloadableProvider(secrets.FileType)
+	require.NoError(t, err)
+	closed, err := secrets.NewReloadableProvider(secrets.FileType)
+	require.NoError(t, err)
+	closed.Close()
+	defer provider.Close()
+
+	t.Setenv(secrets.FileRootEnvVar, newRoot)
+	// Nothing changes until the providers are reloaded
+	assertSecretValue(t, provider, "api-token", "old-token")
+
+	require.NoError(t, secrets.ReloadProviders())
+	assertSecretValue(t, provider, "api-token", "new-token")
+	assertSecretValue(t, closed, "api-token", "old-token")
+}
+
+func TestNewReloadableProvider_UnknownType(t *testing.T) {
+	t.Parallel()
+
+	provider, err := secrets.NewReloadableProvider(secrets.ProviderType("unknown"))
+	assert.ErrorIs(t, err, secrets.ErrUnknownManagerType)
+	assert.Nil(t, provider)
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package secrets_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+func TestReloadProvidersOnSignal(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	oldRoot, newRoot := t.TempDir(), t.TempDir()
+	writeSecretFiles(t, oldRoot, map[string]string{"api-token": "old-token"})
+	writeSecretFiles(t, newRoot, map[string]string{"api-token": "new-token"})
+	t.Setenv(secrets.FileRootEnvVar, oldRoot)
+
+	provider, err := secrets.NewReloadableProvider(secrets.FileType)
+	require.NoError(t, err)
+	defer provider.Close()
+
+	secrets.ReloadProvidersOnSignal(t.Context())
+	t.Setenv(secrets.FileRootEnvVar, newRoot)
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+
+	assert.Eventually(t, func() bool {
+		value, err := provider.GetSecret(t.Context(), "api-token")
+		return err == nil && value == "new-token"
+	}, 5*time.Second, 10*time.Millisecond)
+}
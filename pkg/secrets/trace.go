@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"context"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// TracingProvider wraps a provider to trace which provider resolves each secret, which helps
+// debugging setups where secrets can come from several providers, such as the auto provider or
+// the environment variable fallback. Only the secret names and the providers are logged, never the values.
+type TracingProvider struct {
+	Provider
+	providerType ProviderType
+}
+
+// NewTracingProvider creates a provider tracing the secrets resolved by provider, which is of type providerType
+func NewTracingProvider(provider Provider, providerType ProviderType) *TracingProvider {
+	return &TracingProvider{
+		Provider:     provider,
+		providerType: providerType,
+	}
+}
+
+// GetSecret retrieves a secret from the wrapped provider, logging which provider resolved it
+func (t *TracingProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	value, _, err := t.GetSecretWithSource(ctx, name)
+	return value, err
+}
+
+// GetSecretWithSource retrieves a secret from the wrapped provider along with the type of
+// the provider which resolved it, and logs the latter
+func (t *TracingProvider) GetSecretWithSource(ctx context.Context, name string) (string, ProviderType, error) {
+	var value string
+	var source ProviderType
+	var err error
+	if reporter, ok := t.Provider.(SourceReporter); ok {
+		value, source, err = reporter.GetSecretWithSource(ctx, name)
+	} else {
+		value, err = t.Provider.GetSecret(ctx, name)
+	}
+	if err != nil {
+		logger.Debugf("Secret '%s' could not be resolved by the %s provider: %v", name, t.providerType, err)
+		return "", "", err
+	}
+
+	if source == "" {
+		source = t.providerType
+	}
+	logger.Debugf("Secret '%s' resolved by the %s provider", name, source)
+	return value, source, nil
+}
+
+// CheckHealth delegates to the wrapped provider if it supports health checks
+func (t *TracingProvider) CheckHealth(ctx context.Context) error {
+	if checker, ok := t.Provider.(HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package secrets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/secrets/mocks"
+)
+
+func TestTracingProvider_GetSecretWithSource(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	t.Setenv(secrets.EnvVarPrefix+"env_secret", "env_value")
+
+	tests := []struct {
+		name           string
+		secretName     string
+		primaryValue   string
+		primaryErr     error
+		expectedValue  string
+		expectedSource secrets.ProviderType
+		expectErr      bool
+	}{
+		{
+			name:           "primary provider wins",
+			secretName:     "primary_secret",
+			primaryValue:   "primary_value",
+			expectedValue:  "primary_value",
+			expectedSource: secrets.OnePasswordType,
+		},
+		{
+			name:           "environment fallback wins",
+			secretName:     "env_secret",
+			primaryErr:     errors.New("secret not found: env_secret"),
+			expectedValue:  "env_value",
+			expectedSource: secrets.EnvironmentType,
+		},
+		{
+			name:       "no provider has the secret",
+			secretName: "missing_secret",
+			primaryErr: errors.New("secret not found: missing_secret"),
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { //nolint:paralleltest
+			ctrl := gomock.NewController(t)
+			mockPrimary := mocks.NewMockProvider(ctrl)
+			mockPrimary.EXPECT().GetSecret(gomock.Any(), tt.secretName).Return(tt.primaryValue, tt.primaryErr)
+
+			provider := secrets.NewTracingProvider(secrets.NewFallbackProvider(mockPrimary), secrets.OnePasswordType)
+
+			value, source, err := provider.GetSecretWithSource(t.Context(), tt.secretName)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Empty(t, value)
+				assert.Empty(t, source)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedValue, value)
+			assert.Equal(t, tt.expectedSource, source)
+		})
+	}
+}
+
+func TestTracingProvider_WithoutSourceReporter(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeSecretFiles(t, root, map[string]string{"api-token": "token-value"})
+	provider := secrets.NewTracingProvider(secrets.NewFileProvider(root), secrets.FileType)
+
+	value, source, err := provider.GetSecretWithSource(t.Context(), "api-token")
+	require.NoError(t, err)
+	assert.Equal(t, "token-value", value)
+	assert.Equal(t, secrets.FileType, source)
+
+	value, err = provider.GetSecret(t.Context(), "api-token")
+	require.NoError(t, err)
+	assert.Equal(t, "token-value", value)
+}
+
+func TestCreateSecretProvider_TraceResolution(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	t.Setenv("TOOLHIVE_DISABLE_ENV_FALLBACK", "")
+	t.Setenv(secrets.EnvVarPrefix+"env_secret", "env_value")
+
+	provider, err := secrets.CreateSecretProvider(secrets.NoneType, &secrets.ProviderConfig{TraceResolution: true})
+	require.NoError(t, err)
+	require.IsType(t, &secrets.TracingProvider{}, provider)
+
+	value, source, err := provider.(secrets.SourceReporter).GetSecretWithSource(t.Context(), "env_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "env_value", value)
+	assert.Equal(t, secrets.EnvironmentType, source)
+}
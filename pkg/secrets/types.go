@@ -5,15 +5,25 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
+	"unicode/utf8"
 )
 
 const (
 	// EnvVarPrefix is the prefix used for environment variable secrets
 	EnvVarPrefix = "TOOLHIVE_SECRET_"
+
+	// SecretParameterTypeVault marks a secret parameter whose value is rendered by the
+	// Vault Agent into the workload's env file directory instead of being read from the
+	// secrets provider.
+	SecretParameterTypeVault = "vault"
 )
 
-// regex to extract name and target from secret parameter, e.g. "name,target=target"
-var secretParamRegex = regexp.MustCompile(`^([^,]+),target=(.+)$`)
+// secretDefaultSeparator separates a secret name from its default value, e.g. "name:-default"
+const secretDefaultSeparator = ":-"
+
+// regex to extract name, target and optional type from secret parameter, e.g. "name,target=target,type=vault"
+var secretParamRegex = regexp.MustCompile(`^([^,]+),target=(.+?)(?:,type=([^,=]+))?$`)
 
 // ProviderCapabilities represents what operations a secrets provider supports.
 type ProviderCapabilities struct {
@@ -56,37 +66,147 @@ type Provider interface {
 	Capabilities() ProviderCapabilities
 }
 
+// HealthChecker is implemented by providers that can verify their backend is reachable
+// and their credentials are valid, e.g. before a server starts serving requests.
+type HealthChecker interface {
+	// CheckHealth returns an error if the provider cannot currently be used
+	CheckHealth(ctx context.Context) error
+}
+
+// Watcher is implemented by providers that can notify consumers when the value of a secret
+// changes, e.g. when a mounted secret is rotated.
+type Watcher interface {
+	// WatchSecret emits the new value of the secret each time it changes.
+	// The channel is closed when the context is cancelled.
+	WatchSecret(ctx context.Context, name string) (<-chan string, error)
+}
+
+// SourceReporter is implemented by providers combining several providers, which can tell
+// which one of them resolved a secret.
+type SourceReporter interface {
+	// GetSecretWithSource retrieves a secret along with the type of the provider it was read from.
+	// The source is empty when it is the provider wrapped by the reporter, whose type is unknown to it.
+	GetSecretWithSource(ctx context.Context, name string) (string, ProviderType, error)
+}
+
+// BinaryReader is implemented by providers which can hold binary secrets, such as a gzipped keystore,
+// whose values GetSecret alters, e.g. by trimming a trailing newline.
+// The secrets injected into MCP servers are environment variable values, so injecting a binary
+// secret fails instead, see CheckTextSecret.
+type BinaryReader interface {
+	// GetSecretBytes retrieves the raw value of a secret
+	GetSecretBytes(ctx context.Context, name string) ([]byte, error)
+}
+
+// GetSecretBytes retrieves the raw value of a secret from providers implementing BinaryReader,
+// and the value returned by GetSecret otherwise.
+func GetSecretBytes(ctx context.Context, provider Provider, name string) ([]byte, error) {
+	if reader, ok := provider.(BinaryReader); ok {
+		return reader.GetSecretBytes(ctx, name)
+	}
+	value, err := provider.GetSecret(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+// SecretSummarizer is implemented by providers whose secrets hold several keys, which can list
+// one entry per secret rather than one per key for a compact overview.
+type SecretSummarizer interface {
+	// ListSecretsSummary lists the secrets along with their number of keys
+	ListSecretsSummary(ctx context.Context) ([]SecretSummary, error)
+}
+
 // SecretParameter represents a parsed `--secret` parameter.
 type SecretParameter struct {
 	Name   string `json:"name"`
 	Target string `json:"target"`
+	// Type is empty for secrets resolved through the secrets provider,
+	// or SecretParameterTypeVault for secrets rendered by the Vault Agent.
+	Type string `json:"type,omitempty"`
+	// Default is the value used when the secret does not exist, nil if the secret is required
+	Default *string `json:"default,omitempty"`
 }
 
 // ParseSecretParameter creates an instance of SecretParameter from a string.
-// Expected format: `<Name>,target=<Target>`.
+// Expected format: `<Name>[:-<Default>],target=<Target>[,type=<Type>]`.
+// The default value cannot contain commas.
 func ParseSecretParameter(parameter string) (SecretParameter, error) {
 	if parameter == "" {
 		return SecretParameter{}, fmt.Errorf("secret parameter cannot be empty")
 	}
 
-	// extract name and target using secretParamRegex
+	// extract name, target and type using secretParamRegex
 	matches := secretParamRegex.FindStringSubmatch(parameter)
-	if len(matches) != 3 { // The first element is the full match, followed by capture groups
+	if len(matches) != 4 { // The first element is the full match, followed by capture groups
 		return SecretParameter{}, fmt.Errorf("invalid secret parameter format: %s", parameter)
 	}
 
-	name := matches[1]
+	name, defaultValue, hasDefault := strings.Cut(matches[1], secretDefaultSeparator)
+	if name == "" {
+		return SecretParameter{}, fmt.Errorf("invalid secret parameter format: %s", parameter)
+	}
 	target := matches[2]
+	secretType := matches[3]
+	if secretType != "" && secretType != SecretParameterTypeVault {
+		return SecretParameter{}, fmt.Errorf("invalid secret parameter type %q: %s", secretType, parameter)
+	}
 
-	return SecretParameter{
+	param := SecretParameter{
 		Name:   name,
 		Target: target,
-	}, nil
+		Type:   secretType,
+	}
+	if hasDefault {
+		param.Default = &defaultValue
+	}
+	return param, nil
 }
 
 // ToCLIString converts a SecretParameter to CLI format string
 func (sp SecretParameter) ToCLIString() string {
-	return fmt.Sprintf("%s,target=%s", sp.Name, sp.Target)
+	name := sp.Name
+	if sp.Default != nil {
+		name += secretDefaultSeparator + *sp.Default
+	}
+	if sp.Type != "" {
+		return fmt.Sprintf("%s,target=%s,type=%s", name, sp.Target, sp.Type)
+	}
+	return fmt.Sprintf("%s,target=%s", name, sp.Target)
+}
+
+// Resolve reads the value of the secret from the provider. If the secret does not exist
+// and the parameter has a default value, the default value is returned instead.
+// Binary secrets cannot be resolved, as the value is injected as text.
+func (sp SecretParameter) Resolve(ctx context.Context, provider Provider) (string, error) {
+	value, err := provider.GetSecret(ctx, sp.Name)
+	if err != nil {
+		if sp.Default != nil && IsNotFoundError(err) {
+			return *sp.Default, nil
+		}
+		return "", err
+	}
+	if err := CheckTextSecret(sp.Name, value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// CheckTextSecret returns an error if the value of the secret is binary data rather than text.
+// Binary secrets cannot be injected into MCP servers: environment variable values cannot hold NUL
+// bytes, and the RunConfig holding them is persisted as JSON, which replaces invalid UTF-8.
+func CheckTextSecret(name, value string) error {
+	if !utf8.ValidString(value) || strings.ContainsRune(value, 0) {
+		return fmt.Errorf("secret %s holds binary data, which cannot be injected as text", name)
+	}
+	return nil
+}
+
+// IsVault returns true if the secret is rendered by the Vault Agent rather than
+// read from the secrets provider.
+func (sp SecretParameter) IsVault() bool {
+	return sp.Type == SecretParameterTypeVault
 }
 
 // SecretParametersToCLI does the reverse of `ParseSecretParameter`
@@ -108,3 +228,11 @@ type SecretDescription struct {
 	// May be empty if no description is available.
 	Description string `json:"description"`
 }
+
+// SecretSummary is returned by `ListSecretsSummary`.
+type SecretSummary struct {
+	// Name is the name of the secret
+	Name string `json:"name"`
+	// KeyCount is the number of keys listed by `ListSecrets` for the secret
+	KeyCount int `json:"key_count"`
+}
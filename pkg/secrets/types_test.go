@@ -0,0 +1,162 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretParameter_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		param SecretParameter
+	}{
+		{
+			name:  "target differs from name",
+			param: SecretParameter{Name: "github-token", Target: "GITHUB_PERSONAL_ACCESS_TOKEN"},
+		},
+		{
+			name:  "target matches name",
+			param: SecretParameter{Name: "API_KEY", Target: "API_KEY"},
+		},
+		{
+			name:  "name with path separators",
+			param: SecretParameter{Name: "team/github/token", Target: "TOKEN"},
+		},
+		{
+			name:  "target with dots",
+			param: SecretParameter{Name: "token", Target: "github.GITHUB_TOKEN"},
+		},
+		{
+			name:  "default value",
+			param: SecretParameter{Name: "github-token", Target: "TOKEN", Default: ptr("fallback")},
+		},
+		{
+			name:  "empty default value",
+			param: SecretParameter{Name: "github-token", Target: "TOKEN", Default: ptr("")},
+		},
+		{
+			name:  "default value containing separators",
+			param: SecretParameter{Name: "team/token", Target: "TOKEN", Default: ptr("a:-b=c")},
+		},
+		{
+			name:  "vault type",
+			param: SecretParameter{Name: "workload-secrets/data/github", Target: "GITHUB_TOKEN", Type: SecretParameterTypeVault},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := ParseSecretParameter(tt.param.ToCLIString())
+			require.NoError(t, err)
+			assert.Equal(t, tt.param, parsed)
+		})
+	}
+}
+
+func TestParseSecretParameter_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, parameter := range []string{
+		"",
+		"github-token",
+		",target=TOKEN",
+		"github-token,target=",
+		"github-token,TOKEN",
+		"github-token,target=TOKEN,type=unknown",
+		":-fallback,target=TOKEN",
+	} {
+		t.Run(parameter, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseSecretParameter(parameter)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseSecretParameter_Type(t *testing.T) {
+	t.Parallel()
+
+	param, err := ParseSecretParameter("github-token,target=TOKEN")
+	require.NoError(t, err)
+	assert.Empty(t, param.Type)
+	assert.False(t, param.IsVault())
+
+	param, err = ParseSecretParameter("workload/github,target=TOKEN,type=vault")
+	require.NoError(t, err)
+	assert.Equal(t, SecretParameter{Name: "workload/github", Target: "TOKEN", Type: SecretParameterTypeVault}, param)
+	assert.True(t, param.IsVault())
+}
+
+func TestSecretParametersToCLI(t *testing.T) {
+	t.Parallel()
+
+	params := []SecretParameter{
+		{Name: "a", Target: "A"},
+		{Name: "b", Target: "B"},
+	}
+
+	cli := SecretParametersToCLI(params)
+	assert.Equal(t, []string{"a,target=A", "b,target=B"}, cli)
+
+	for i, s := range cli {
+		parsed, err := ParseSecretParameter(s)
+		require.NoError(t, err)
+		assert.Equal(t, params[i], parsed)
+	}
+}
+
+func TestSecretParameter_Resolve(t *testing.T) { //nolint:paralleltest // Uses environment variables
+	param, err := ParseSecretParameter("resolve-test:-fallback,target=TOKEN")
+	require.NoError(t, err)
+	require.NotNil(t, param.Default)
+	assert.Equal(t, "resolve-test", param.Name)
+	assert.Equal(t, "fallback", *param.Default)
+
+	t.Run("present secret wins over the default", func(t *testing.T) { //nolint:paralleltest
+		t.Setenv(EnvVarPrefix+"resolve-test", "from-provider")
+
+		value, err := param.Resolve(t.Context(), NewEnvironmentProvider())
+		require.NoError(t, err)
+		assert.Equal(t, "from-provider", value)
+	})
+
+	t.Run("missing secret uses the default", func(t *testing.T) { //nolint:paralleltest
+		value, err := param.Resolve(t.Context(), NewEnvironmentProvider())
+		require.NoError(t, err)
+		assert.Equal(t, "fallback", value)
+	})
+
+	t.Run("missing secret without default fails", func(t *testing.T) { //nolint:paralleltest
+		required := SecretParameter{Name: "resolve-test", Target: "TOKEN"}
+
+		_, err := required.Resolve(t.Context(), NewEnvironmentProvider())
+		assert.Error(t, err)
+	})
+
+	t.Run("binary secret fails", func(t *testing.T) { //nolint:paralleltest
+		// A gzip header followed by invalid UTF-8, as found in binary keystores
+		t.Setenv(EnvVarPrefix+"resolve-test", string([]byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe}))
+
+		_, err := param.Resolve(t.Context(), NewEnvironmentProvider())
+		assert.ErrorContains(t, err, "secret resolve-test holds binary data")
+	})
+}
+
+func TestCheckTextSecret(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, CheckTextSecret("text", "p@ssw0rd\nwith ünïcode"))
+	assert.Error(t, CheckTextSecret("invalid-utf8", string([]byte{0xc3, 0x28})))
+	assert.Error(t, CheckTextSecret("nul", "before\x00after"))
+}
+
+func ptr(s string) *string {
+	return &s
+}
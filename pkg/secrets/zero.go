@@ -0,0 +1,17 @@
+package secrets
+
+import "runtime"
+
+// Zero overwrites the contents of b with zeroes, so that plaintext secret material such as a
+// password or a decrypted secrets file does not linger in memory after it has been used.
+//
+// Zero can only scrub the buffer it is given. Go strings are immutable and cannot be cleared,
+// so a secret that has been converted to a string (for example to be stored or injected as an
+// environment variable) stays in memory until it is garbage collected, and copies made by the
+// runtime or by callers are not affected. Secret material should therefore be kept as []byte for
+// as long as possible and zeroed as soon as it is no longer needed.
+func Zero(b []byte) {
+	clear(b)
+	// Prevent the compiler from treating the writes as dead stores on a buffer that is not read again
+	runtime.KeepAlive(b)
+}
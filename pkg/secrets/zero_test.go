@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZero(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		buf  []byte
+	}{
+		{name: "password", buf: []byte("correct horse battery staple")},
+		{name: "single byte", buf: []byte{0xff}},
+		{name: "empty", buf: []byte{}},
+		{name: "nil", buf: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			Zero(tt.buf)
+			for i, b := range tt.buf {
+				assert.Zerof(t, b, "byte %d was not zeroed", i)
+			}
+		})
+	}
+
+	t.Run("zeroes the backing array seen through other slices", func(t *testing.T) {
+		t.Parallel()
+
+		buf := []byte("secret-password")
+		view := buf[7:]
+		Zero(buf)
+		assert.Equal(t, make([]byte, len(view)), view)
+	})
+}
@@ -10,6 +10,10 @@ const (
 
 	// GroupConfigsDir is the directory name for storing group configurations
 	GroupConfigsDir = "groups"
+
+	// EffectiveRunConfigsDir is the directory name for storing the redacted effective run configurations
+	// recorded by the runners
+	EffectiveRunConfigsDir = "effectiveconfigs"
 )
 
 // NewRunConfigStore creates a store for run configuration state
@@ -37,3 +41,11 @@ func NewGroupConfigStoreWithDetector(appName string) (Store, error) {
 	}
 	return NewLocalStore(appName, GroupConfigsDir)
 }
+
+// NewEffectiveRunConfigStore creates a store for the redacted effective run configurations
+func NewEffectiveRunConfigStore(appName string) (Store, error) {
+	if runtime.IsKubernetesRuntime() {
+		return NewKubernetesStore(), nil
+	}
+	return NewLocalStore(appName, EffectiveRunConfigsDir)
+}
@@ -42,3 +42,12 @@ func TestNewGroupConfigStore(t *testing.T) {
 	require.NoError(t, err)
 	assert.IsType(t, &LocalStore{}, store)
 }
+
+func TestNewEffectiveRunConfigStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewEffectiveRunConfigStore("toolhive")
+
+	require.NoError(t, err)
+	assert.IsType(t, &LocalStore{}, store)
+}
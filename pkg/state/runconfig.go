@@ -18,6 +18,11 @@ func LoadRunConfigJSON(ctx context.Context, name string) (io.ReadCloser, error)
 		return nil, fmt.Errorf("failed to create state store: %w", err)
 	}
 
+	return loadRunConfigJSONFromStore(ctx, store, name)
+}
+
+// loadRunConfigJSONFromStore returns the raw reader of a run configuration of the store
+func loadRunConfigJSONFromStore(ctx context.Context, store Store, name string) (io.ReadCloser, error) {
 	// Check if the configuration exists
 	exists, err := store.Exists(ctx, name)
 	if err != nil {
@@ -57,11 +62,27 @@ func DeleteSavedRunConfig(ctx context.Context, name string) error {
 	if err := store.Delete(ctx, name); err != nil {
 		return fmt.Errorf("failed to delete run configuration: %w", err)
 	}
+	deleteEffectiveRunConfig(ctx, name)
 
 	logger.Infof("Deleted run configuration for %s", name)
 	return nil
 }
 
+// deleteEffectiveRunConfig deletes the effective run configuration recorded for a workload, if any
+func deleteEffectiveRunConfig(ctx context.Context, name string) {
+	store, err := NewEffectiveRunConfigStore(DefaultAppName)
+	if err != nil {
+		logger.Debugf("Failed to create effective run configuration store: %v", err)
+		return
+	}
+	if exists, err := store.Exists(ctx, name); err != nil || !exists {
+		return
+	}
+	if err := store.Delete(ctx, name); err != nil {
+		logger.Warnf("Failed to delete effective run configuration for %s: %v", name, err)
+	}
+}
+
 // RunConfigPersister defines an interface for objects that can be persisted and loaded as JSON
 type RunConfigPersister interface {
 	// WriteJSON serializes the object to JSON and writes it to the provided writer
@@ -81,6 +102,50 @@ func SaveRunConfig[T RunConfigPersister](ctx context.Context, config T) error {
 		return fmt.Errorf("failed to create state store: %w", err)
 	}
 
+	if err := saveRunConfigToStore(ctx, store, config); err != nil {
+		return err
+	}
+
+	logger.Infof("Saved run configuration for %s", config.GetBaseName())
+	return nil
+}
+
+// SaveEffectiveRunConfig saves the effective run configuration of a workload, which must already be
+// redacted, to the effective run configuration store
+func SaveEffectiveRunConfig[T RunConfigPersister](ctx context.Context, config T) error {
+	store, err := NewEffectiveRunConfigStore(DefaultAppName)
+	if err != nil {
+		return fmt.Errorf("failed to create effective run configuration store: %w", err)
+	}
+
+	if err := saveRunConfigToStore(ctx, store, config); err != nil {
+		return err
+	}
+
+	logger.Debugf("Saved effective run configuration for %s", config.GetBaseName())
+	return nil
+}
+
+// LoadEffectiveRunConfig loads the effective run configuration recorded for a workload using the
+// provided reader function
+func LoadEffectiveRunConfig[T any](ctx context.Context, name string, readJSONFunc ReadJSONFunc[T]) (T, error) {
+	var zero T
+	store, err := NewEffectiveRunConfigStore(DefaultAppName)
+	if err != nil {
+		return zero, fmt.Errorf("failed to create effective run configuration store: %w", err)
+	}
+
+	reader, err := loadRunConfigJSONFromStore(ctx, store, name)
+	if err != nil {
+		return zero, err
+	}
+	defer reader.Close()
+
+	return readJSONFunc(reader)
+}
+
+// saveRunConfigToStore writes a run configuration to the store
+func saveRunConfigToStore[T RunConfigPersister](ctx context.Context, store Store, config T) error {
 	// Get a writer for the state
 	writer, err := store.GetWriter(ctx, config.GetBaseName())
 	if err != nil {
@@ -92,8 +157,6 @@ func SaveRunConfig[T RunConfigPersister](ctx context.Context, config T) error {
 	if err := config.WriteJSON(writer); err != nil {
 		return fmt.Errorf("failed to write run configuration: %w", err)
 	}
-
-	logger.Infof("Saved run configuration for %s", config.GetBaseName())
 	return nil
 }
 
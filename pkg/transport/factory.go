@@ -3,10 +3,16 @@
 package transport
 
 import (
+	"time"
+
 	"github.com/stacklok/toolhive/pkg/transport/errors"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 )
 
+// DefaultShutdownGracePeriod is how long a transport waits for active client
+// connections to drain before forcibly closing its proxy.
+const DefaultShutdownGracePeriod = 10 * time.Second
+
 // Factory creates transports
 type Factory struct{}
 
@@ -38,6 +44,17 @@ func WithTargetURI(targetURI string) Option {
 	}
 }
 
+// WithShutdownGracePeriod returns an option that sets how long a transport waits
+// for active client connections to drain when it is stopped
+func WithShutdownGracePeriod(gracePeriod time.Duration) Option {
+	return func(t types.Transport) error {
+		if setter, ok := t.(interface{ setShutdownGracePeriod(time.Duration) }); ok {
+			setter.setShutdownGracePeriod(gracePeriod)
+		}
+		return nil
+	}
+}
+
 // Create creates a transport based on the provided configuration
 func (*Factory) Create(config types.Config, opts ...Option) (types.Transport, error) {
 	var tr types.Transport
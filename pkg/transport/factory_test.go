@@ -0,0 +1,140 @@
+package transport
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/transport/streamable"
+	"github.com/stacklok/toolhive/pkg/transport/types"
+)
+
+func TestFactory_Create_StreamableHTTP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		config             types.Config
+		expectedHost       string
+		expectedTargetHost string
+	}{
+		{
+			name: "ports and target host from config",
+			config: types.Config{
+				Type:       types.TransportTypeStreamableHTTP,
+				Host:       "0.0.0.0",
+				ProxyPort:  8080,
+				TargetPort: 9090,
+				TargetHost: "mcp-server.internal",
+			},
+			expectedHost:       "0.0.0.0",
+			expectedTargetHost: "mcp-server.internal",
+		},
+		{
+			name: "hosts default to localhost",
+			config: types.Config{
+				Type:       types.TransportTypeStreamableHTTP,
+				ProxyPort:  8080,
+				TargetPort: 9090,
+			},
+			expectedHost:       LocalhostIPv4,
+			expectedTargetHost: LocalhostIPv4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tr, err := NewFactory().Create(tt.config)
+			require.NoError(t, err)
+
+			httpTransport, ok := tr.(*HTTPTransport)
+			require.True(t, ok, "streamable HTTP should be served by the HTTP transport")
+			assert.Equal(t, types.TransportTypeStreamableHTTP, httpTransport.Mode())
+			assert.Equal(t, tt.config.ProxyPort, httpTransport.ProxyPort())
+			assert.Equal(t, tt.config.TargetPort, httpTransport.targetPort)
+			assert.Equal(t, tt.expectedHost, httpTransport.host)
+			assert.Equal(t, tt.expectedTargetHost, httpTransport.targetHost)
+		})
+	}
+}
+
+func TestHTTPTransport_StreamableHTTPRoutes(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	var mu sync.Mutex
+	var received []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":{}}`)
+	}))
+	defer backend.Close()
+
+	proxyPort := freePort(t)
+	tr, err := NewFactory().Create(types.Config{
+		Type:      types.TransportTypeStreamableHTTP,
+		Host:      LocalhostIPv4,
+		ProxyPort: proxyPort,
+	})
+	require.NoError(t, err)
+	httpTransport := tr.(*HTTPTransport)
+	httpTransport.SetRemoteURL(backend.URL + "/" + streamable.HTTPStreamableHTTPEndpoint)
+
+	require.NoError(t, httpTransport.Start(t.Context()))
+	defer func() {
+		assert.NoError(t, httpTransport.Stop(t.Context()))
+	}()
+
+	endpoint := GenerateMCPServerURL(types.TransportTypeStreamableHTTP.String(), LocalhostIPv4, proxyPort, "", "")
+	require.Equal(t, fmt.Sprintf("http://%s:%d/mcp", LocalhostIPv4, proxyPort), endpoint)
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	// Streamable HTTP clients send JSON-RPC messages with POST
+	postResp, err := client.Post(endpoint, "application/json",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	require.NoError(t, err)
+	_ = postResp.Body.Close()
+	assert.Equal(t, http.StatusOK, postResp.StatusCode)
+
+	// and open the server-to-client stream with GET
+	getReq, err := http.NewRequestWithContext(t.Context(), http.MethodGet, endpoint, nil)
+	require.NoError(t, err)
+	getReq.Header.Set("Accept", "text/event-stream")
+	getResp, err := client.Do(getReq)
+	require.NoError(t, err)
+	_ = getResp.Body.Close()
+	assert.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"POST /mcp", "GET /mcp"}, received)
+}
+
+// freePort returns a TCP port that is free at the time of the call
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", LocalhostIPv4+":0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
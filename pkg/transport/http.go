@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"time"
 
 	"golang.org/x/oauth2"
 
@@ -57,6 +58,9 @@ type HTTPTransport struct {
 	// Shutdown channel
 	shutdownCh chan struct{}
 
+	// shutdownGracePeriod bounds how long the proxy drains active connections on stop
+	shutdownGracePeriod time.Duration
+
 	// Container monitor
 	monitor rt.Monitor
 	errorCh <-chan error
@@ -89,17 +93,18 @@ func NewHTTPTransport(
 	}
 
 	return &HTTPTransport{
-		transportType:     transportType,
-		host:              host,
-		proxyPort:         proxyPort,
-		middlewares:       middlewares,
-		targetPort:        targetPort,
-		targetHost:        targetHost,
-		deployer:          deployer,
-		debug:             debug,
-		prometheusHandler: prometheusHandler,
-		authInfoHandler:   authInfoHandler,
-		shutdownCh:        make(chan struct{}),
+		transportType:       transportType,
+		host:                host,
+		proxyPort:           proxyPort,
+		middlewares:         middlewares,
+		targetPort:          targetPort,
+		targetHost:          targetHost,
+		deployer:            deployer,
+		debug:               debug,
+		prometheusHandler:   prometheusHandler,
+		authInfoHandler:     authInfoHandler,
+		shutdownCh:          make(chan struct{}),
+		shutdownGracePeriod: DefaultShutdownGracePeriod,
 	}
 }
 
@@ -136,6 +141,16 @@ func (t *HTTPTransport) setContainerName(containerName string) {
 	t.containerName = containerName
 }
 
+// setShutdownGracePeriod configures how long the proxy drains active connections on stop.
+// This is an unexported method used by the option pattern.
+func (t *HTTPTransport) setShutdownGracePeriod(gracePeriod time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if gracePeriod > 0 {
+		t.shutdownGracePeriod = gracePeriod
+	}
+}
+
 // setTargetURI configures the transport with the target URI for proxying.
 // This is an unexported method used by the option pattern.
 func (t *HTTPTransport) setTargetURI(targetURI string) {
@@ -247,6 +262,16 @@ func (t *HTTPTransport) Stop(ctx context.Context) error {
 	// Signal shutdown
 	close(t.shutdownCh)
 
+	// Drain the transparent proxy before the container goes away so that
+	// in-flight requests and streams can complete
+	if t.proxy != nil {
+		drainCtx, cancel := context.WithTimeout(ctx, t.shutdownGracePeriod)
+		defer cancel()
+		if err := t.proxy.Stop(drainCtx); err != nil {
+			logger.Warnf("Warning: Failed to stop proxy: %v", err)
+		}
+	}
+
 	// For remote MCP servers, we don't need container monitoring
 	if t.remoteURL == "" {
 		// Stop the monitor if it's running
@@ -263,13 +288,6 @@ func (t *HTTPTransport) Stop(ctx context.Context) error {
 		}
 	}
 
-	// Stop the transparent proxy
-	if t.proxy != nil {
-		if err := t.proxy.Stop(ctx); err != nil {
-			logger.Warnf("Warning: Failed to stop proxy: %v", err)
-		}
-	}
-
 	return nil
 }
 
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// sequenceTokenSource hands out a new access token on every call
+type sequenceTokenSource struct {
+	mu     sync.Mutex
+	tokens []*oauth2.Token
+	calls  int
+}
+
+func (s *sequenceTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.calls >= len(s.tokens) {
+		return nil, errors.New("no more tokens")
+	}
+	token := s.tokens[s.calls]
+	s.calls++
+	return token, nil
+}
+
+// errorTokenSource always fails to produce a token
+type errorTokenSource struct{}
+
+func (errorTokenSource) Token() (*oauth2.Token, error) {
+	return nil, errors.New("token unavailable")
+}
+
+// serveWithMiddleware sends a request through the middleware and returns the
+// Authorization header seen by the next handler and the response recorder.
+func serveWithMiddleware(t *testing.T, tokenSource oauth2.TokenSource) (string, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	var authHeader string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	rec := httptest.NewRecorder()
+	CreateTokenInjectionMiddleware(tokenSource)(next).ServeHTTP(rec, req)
+
+	return authHeader, rec
+}
+
+func TestCreateTokenInjectionMiddleware(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	t.Run("adds bearer token", func(t *testing.T) {
+		t.Parallel()
+		tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access-token"})
+
+		authHeader, rec := serveWithMiddleware(t, tokenSource)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Bearer access-token", authHeader)
+	})
+
+	t.Run("refreshes expired token", func(t *testing.T) {
+		t.Parallel()
+		expired := &oauth2.Token{AccessToken: "expired-token", Expiry: time.Now().Add(-time.Minute)}
+		refreshed := &oauth2.Token{AccessToken: "refreshed-token", Expiry: time.Now().Add(time.Hour)}
+		base := &sequenceTokenSource{tokens: []*oauth2.Token{refreshed}}
+		tokenSource := oauth2.ReuseTokenSource(expired, base)
+
+		authHeader, rec := serveWithMiddleware(t, tokenSource)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Bearer refreshed-token", authHeader)
+
+		// A valid token is reused without another refresh
+		authHeader, _ = serveWithMiddleware(t, tokenSource)
+		assert.Equal(t, "Bearer refreshed-token", authHeader)
+		require.Equal(t, 1, base.calls)
+	})
+
+	t.Run("rejects request when token is unavailable", func(t *testing.T) {
+		t.Parallel()
+
+		authHeader, rec := serveWithMiddleware(t, errorTokenSource{})
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Empty(t, authHeader)
+	})
+
+	t.Run("passes through without token source", func(t *testing.T) {
+		t.Parallel()
+
+		authHeader, rec := serveWithMiddleware(t, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, authHeader)
+	})
+}
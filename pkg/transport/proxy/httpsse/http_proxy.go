@@ -4,6 +4,7 @@ package httpsse
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -197,8 +198,10 @@ func (p *HTTPSSEProxy) Start(_ context.Context) error {
 }
 
 // Stop stops the HTTP SSE proxy.
+// Active SSE streams are sent a close event and given until the context is done
+// to finish before their connections are forcibly closed.
 func (p *HTTPSSEProxy) Stop(ctx context.Context) error {
-	// Signal shutdown
+	// Signal shutdown, which also tells active SSE streams to drain
 	close(p.shutdownCh)
 
 	// Stop the session manager cleanup routine
@@ -208,7 +211,17 @@ func (p *HTTPSSEProxy) Stop(ctx context.Context) error {
 		}
 	}
 
-	// Disconnect all active sessions
+	// Stop accepting new connections and wait for active ones to drain
+	var err error
+	if p.server != nil {
+		err = p.server.Shutdown(ctx)
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			logger.Warnf("Grace period expired with active SSE connections, forcing close")
+			err = p.server.Close()
+		}
+	}
+
+	// Disconnect all remaining sessions
 	p.sessionManager.Range(func(_, value interface{}) bool {
 		if sess, ok := value.(*session.SSESession); ok {
 			sess.Disconnect()
@@ -216,12 +229,7 @@ func (p *HTTPSSEProxy) Stop(ctx context.Context) error {
 		return true
 	})
 
-	// Stop the HTTP server
-	if p.server != nil {
-		return p.server.Shutdown(ctx)
-	}
-
-	return nil
+	return err
 }
 
 // GetMessageChannel returns the channel for messages to/from the destination.
@@ -274,6 +282,14 @@ func (p *HTTPSSEProxy) ForwardResponseToClients(_ context.Context, msg jsonrpc2.
 
 // handleSSEConnection handles an SSE connection.
 func (p *HTTPSSEProxy) handleSSEConnection(w http.ResponseWriter, r *http.Request) {
+	// Refuse new streams once the proxy has started draining
+	select {
+	case <-p.shutdownCh:
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	default:
+	}
+
 	// Set headers for SSE
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -338,6 +354,9 @@ func (p *HTTPSSEProxy) handleSSEConnection(w http.ResponseWriter, r *http.Reques
 		select {
 		case <-ctx.Done():
 			return
+		case <-p.shutdownCh:
+			drainSSEClient(w, flusher, messageCh)
+			return
 		case msg, ok := <-messageCh:
 			if !ok {
 				return
@@ -352,6 +371,26 @@ func (p *HTTPSSEProxy) handleSSEConnection(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// drainSSEClient writes any messages already queued for a client and then sends a
+// close event so the client knows the stream is ending because the proxy is shutting down.
+func drainSSEClient(w io.Writer, flusher http.Flusher, messageCh <-chan string) {
+drain:
+	for {
+		select {
+		case msg, ok := <-messageCh:
+			if !ok {
+				break drain
+			}
+			fmt.Fprint(w, msg)
+		default:
+			break drain
+		}
+	}
+
+	fmt.Fprint(w, ssecommon.NewSSEMessage(ssecommon.CloseEventType, "server shutting down").ToSSEString())
+	flusher.Flush()
+}
+
 // handlePostRequest handles a POST request with a JSON-RPC message.
 func (p *HTTPSSEProxy) handlePostRequest(w http.ResponseWriter, r *http.Request) {
 	// Only accept POST requests
@@ -1,6 +1,7 @@
 package httpsse
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
@@ -620,3 +621,78 @@ func TestStartStop(t *testing.T) {
 		t.Fatal("Shutdown channel was not closed")
 	}
 }
+
+// TestStopDrainsActiveSSEStream tests that an active SSE stream receives a close event on shutdown
+//
+//nolint:paralleltest // Test starts/stops HTTP server
+func TestStopDrainsActiveSSEStream(t *testing.T) {
+	proxy := NewHTTPSSEProxy("localhost", 0, false, nil)
+	ctx := context.Background()
+
+	require.NoError(t, proxy.Start(ctx))
+
+	// Open an SSE stream and wait for the endpoint event
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", proxy.server.Addr, ssecommon.HTTPSSEEndpoint))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: endpoint\n", line)
+
+	// Stop the proxy with a grace window and read the rest of the stream
+	gracePeriod := 2 * time.Second
+	stopCtx, cancel := context.WithTimeout(ctx, gracePeriod)
+	defer cancel()
+
+	stopErrCh := make(chan error, 1)
+	go func() {
+		stopErrCh <- proxy.Stop(stopCtx)
+	}()
+
+	closeEventCh := make(chan bool, 1)
+	go func() {
+		for {
+			line, err := reader.ReadString('\n')
+			if line == fmt.Sprintf("event: %s\n", ssecommon.CloseEventType) {
+				closeEventCh <- true
+				return
+			}
+			if err != nil {
+				closeEventCh <- false
+				return
+			}
+		}
+	}()
+
+	select {
+	case received := <-closeEventCh:
+		assert.True(t, received, "Stream ended without a close event")
+	case <-time.After(gracePeriod):
+		t.Fatal("Timed out waiting for close event")
+	}
+
+	select {
+	case err := <-stopErrCh:
+		assert.NoError(t, err)
+	case <-time.After(gracePeriod):
+		t.Fatal("Timed out waiting for proxy to stop")
+	}
+}
+
+// TestHandleSSEConnection_DuringShutdown tests that new SSE streams are refused while draining
+//
+//nolint:paralleltest // Test modifies shared proxy state
+func TestHandleSSEConnection_DuringShutdown(t *testing.T) {
+	proxy := NewHTTPSSEProxy("localhost", 8080, false, nil)
+	close(proxy.shutdownCh)
+
+	req := httptest.NewRequest("GET", ssecommon.HTTPSSEEndpoint, nil)
+	w := httptest.NewRecorder()
+
+	proxy.handleSSEConnection(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, 0, proxy.sessionManager.Count())
+}
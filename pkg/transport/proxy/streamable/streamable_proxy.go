@@ -121,6 +121,8 @@ func (p *HTTPProxy) Start(_ context.Context) error {
 }
 
 // Stop gracefully shuts down the HTTPProxy server.
+// In-flight requests are given until the context is done to complete before their
+// connections are forcibly closed.
 func (p *HTTPProxy) Stop(ctx context.Context) error {
 	var err error
 
@@ -143,6 +145,10 @@ func (p *HTTPProxy) Stop(ctx context.Context) error {
 		if p.server != nil {
 			if e := p.server.Shutdown(ctx); e != nil {
 				err = e
+				if errors.Is(e, context.DeadlineExceeded) || errors.Is(e, context.Canceled) {
+					logger.Warnf("Grace period expired with active streamable HTTP connections, forcing close")
+					err = p.server.Close()
+				}
 			}
 		}
 	})
@@ -28,6 +28,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/healthcheck"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/transport/session"
+	"github.com/stacklok/toolhive/pkg/transport/ssecommon"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 )
 
@@ -238,10 +239,22 @@ func (p *TransparentProxy) modifyForSessionID(resp *http.Response) error {
 	originalBody := resp.Body
 	resp.Body = pr
 
+	// On shutdown, the upstream body is closed to interrupt the stream, which is then ended with
+	// a close event, so that the proxy server can shut down without waiting for the grace period
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-p.shutdownCh:
+			_ = originalBody.Close()
+		case <-done:
+		}
+	}()
+
 	// NOTE: it would be better to have a proper function instead of a goroutine, as this
 	// makes it harder to debug and test.
 	go func() {
 		defer pw.Close()
+		defer close(done)
 		scanner := bufio.NewScanner(originalBody)
 		// NOTE: The following line mitigates the issue of the response body being too large.
 		// By default, the maximum token size of the scanner is 64KB, which is too small in
@@ -249,9 +262,11 @@ func (p *TransparentProxy) modifyForSessionID(resp *http.Response) error {
 		// not a proper fix.
 		scanner.Buffer(make([]byte, 0, 1024), 1024*1024*1)
 		found := false
+		atEventBoundary := true
 
 		for scanner.Scan() {
 			line := scanner.Bytes()
+			atEventBoundary = len(line) == 0
 			if !found {
 				if m := sessionRe.FindSubmatch(line); m != nil {
 					sid := string(m[1])
@@ -271,6 +286,11 @@ func (p *TransparentProxy) modifyForSessionID(resp *http.Response) error {
 			}
 		}
 
+		if p.isShuttingDown() {
+			drainSSEStream(pw, atEventBoundary)
+			return
+		}
+
 		// NOTE: this line is always necessary since scanner.Scan() will return false
 		// in case of an error.
 		if err := scanner.Err(); err != nil {
@@ -286,6 +306,26 @@ func (p *TransparentProxy) modifyForSessionID(resp *http.Response) error {
 	return nil
 }
 
+// isShuttingDown reports whether the proxy is stopping
+func (p *TransparentProxy) isShuttingDown() bool {
+	select {
+	case <-p.shutdownCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// drainSSEStream ends an SSE stream interrupted by the proxy shutdown with a close event, so the client
+// knows the stream is ending because the proxy is shutting down. An event interrupted before its end
+// is terminated first, so that the close event is not merged into it.
+func drainSSEStream(w io.Writer, atEventBoundary bool) {
+	if !atEventBoundary {
+		_, _ = io.WriteString(w, "\n")
+	}
+	_, _ = io.WriteString(w, ssecommon.NewSSEMessage(ssecommon.CloseEventType, "server shutting down").ToSSEString())
+}
+
 // Start starts the transparent proxy.
 // nolint:gocyclo // This function handles multiple startup scenarios and is complex by design
 func (p *TransparentProxy) Start(ctx context.Context) error {
@@ -452,10 +492,15 @@ func (p *TransparentProxy) Stop(ctx context.Context) error {
 	// Signal shutdown
 	close(p.shutdownCh)
 
-	// Stop the HTTP server
+	// Stop the HTTP server, forcing any connections still active once the
+	// context is done to close
 	if p.server != nil {
 		err := p.server.Shutdown(ctx)
-		if err != nil && err != http.ErrServerClosed && err != context.DeadlineExceeded {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			logger.Warnf("Grace period expired with active connections for %s, forcing close", p.targetURI)
+			err = p.server.Close()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			logger.Warnf("Error during proxy shutdown: %v", err)
 			return err
 		}
@@ -3,6 +3,7 @@ package transparent
 import (
 	"bufio"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	tracenoop "go.opentelemetry.io/otel/trace/noop"
@@ -395,3 +397,42 @@ func TestTransparentProxy_StopWithoutStart(t *testing.T) {
 	// The key is it shouldn't panic
 	_ = err
 }
+
+func TestTransparentProxy_StopDrainsActiveStream(t *testing.T) {
+	t.Parallel()
+
+	// The target keeps the stream open until the client goes away
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("event: message\ndata: hello\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer target.Close()
+
+	proxy := NewTransparentProxy("127.0.0.1", 0, target.URL, nil, nil, false, false, "sse")
+	require.NoError(t, proxy.Start(t.Context()))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+proxy.listener.Addr().String()+"/sse", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: message\n", line)
+
+	gracePeriod := 10 * time.Second
+	stopCtx, cancel := context.WithTimeout(t.Context(), gracePeriod)
+	defer cancel()
+	start := time.Now()
+	require.NoError(t, proxy.Stop(stopCtx))
+	assert.Less(t, time.Since(start), gracePeriod/2, "an open stream must not hold the shutdown for the grace period")
+
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(rest), "event: close\ndata: server shutting down\n")
+}
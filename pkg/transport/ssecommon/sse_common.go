@@ -13,6 +13,10 @@ const (
 	HTTPSSEEndpoint = "/sse"
 	// HTTPMessagesEndpoint is the endpoint for JSON-RPC messages
 	HTTPMessagesEndpoint = "/messages"
+
+	// CloseEventType is the event sent to SSE clients when the proxy is shutting down
+	// and their stream is about to end
+	CloseEventType = "close"
 )
 
 // SSEMessage represents a Server-Sent Event message
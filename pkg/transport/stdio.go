@@ -83,6 +83,9 @@ type StdioTransport struct {
 
 	// Retry configuration (for testing)
 	retryConfig *retryConfig
+
+	// shutdownGracePeriod bounds how long the proxy drains active connections on stop
+	shutdownGracePeriod time.Duration
 }
 
 // retryConfig holds configuration for retry behavior
@@ -112,16 +115,17 @@ func NewStdioTransport(
 	middlewares ...types.NamedMiddleware,
 ) *StdioTransport {
 	return &StdioTransport{
-		host:              host,
-		proxyPort:         proxyPort,
-		deployer:          deployer,
-		debug:             debug,
-		trustProxyHeaders: trustProxyHeaders,
-		middlewares:       middlewares,
-		prometheusHandler: prometheusHandler,
-		shutdownCh:        make(chan struct{}),
-		proxyMode:         types.ProxyModeSSE, // default to SSE for backward compatibility
-		retryConfig:       defaultRetryConfig(),
+		host:                host,
+		proxyPort:           proxyPort,
+		deployer:            deployer,
+		debug:               debug,
+		trustProxyHeaders:   trustProxyHeaders,
+		middlewares:         middlewares,
+		prometheusHandler:   prometheusHandler,
+		shutdownCh:          make(chan struct{}),
+		proxyMode:           types.ProxyModeSSE, // default to SSE for backward compatibility
+		retryConfig:         defaultRetryConfig(),
+		shutdownGracePeriod: DefaultShutdownGracePeriod,
 	}
 }
 
@@ -148,6 +152,16 @@ func (t *StdioTransport) setContainerName(containerName string) {
 	t.containerName = containerName
 }
 
+// setShutdownGracePeriod configures how long the proxy drains active connections on stop.
+// This is an unexported method used by the option pattern.
+func (t *StdioTransport) setShutdownGracePeriod(gracePeriod time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if gracePeriod > 0 {
+		t.shutdownGracePeriod = gracePeriod
+	}
+}
+
 // setTargetURI configures the transport with the target URI for proxying.
 // For stdio transport, this is a no-op as stdio doesn't use a target URI.
 // This is an unexported method used by the option pattern.
@@ -255,9 +269,11 @@ func (t *StdioTransport) Stop(ctx context.Context) error {
 		t.monitor = nil
 	}
 
-	// Stop the HTTP proxy
+	// Stop the HTTP proxy, giving active client streams time to drain
 	if t.httpProxy != nil {
-		if err := t.httpProxy.Stop(ctx); err != nil {
+		drainCtx, cancel := context.WithTimeout(ctx, t.shutdownGracePeriod)
+		defer cancel()
+		if err := t.httpProxy.Stop(drainCtx); err != nil {
 			logger.Warnf("Warning: Failed to stop HTTP proxy: %v", err)
 		}
 	}
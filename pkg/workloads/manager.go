@@ -555,6 +555,7 @@ func (d *DefaultManager) RunWorkloadDetached(ctx context.Context, runConfig *run
 			return fmt.Errorf("failed to get secrets password: %v", err)
 		}
 		detachedCmd.Env = append(detachedCmd.Env, fmt.Sprintf("%s=%s", secrets.PasswordEnvVar, password))
+		secrets.Zero(password)
 	}
 
 	// Redirect stdout and stderr to the log file if it was created successfully